@@ -42,6 +42,8 @@ type action struct {
 	index       int
 	moveToIndex int
 	slide       *Slide
+	notesOnly   bool   // only the speaker note is changed (update action only)
+	objectID    string // object ID of the page the requests were prepared for (append and update actions only)
 }
 
 func generateActions(before, after Slides) (_ []*action, err error) {
@@ -59,12 +61,8 @@ func generateActions(before, after Slides) (_ []*action, err error) {
 		return nil, fmt.Errorf("failed to adjust slide count: %w", err)
 	}
 
-	// Prevent actions from being generated from indexes that should be frozen.
-	for i, afterSlide := range adjustedAfter {
-		if afterSlide.Freeze {
-			adjustedBefore[i] = copySlide(afterSlide)
-		}
-	}
+	// Prevent actions from being generated for the pages that should be frozen.
+	pinFrozenSlides(adjustedBefore, adjustedAfter)
 
 	// Map slides algorithm
 	mapping, err := mapSlides(adjustedBefore, adjustedAfter)
@@ -465,6 +463,7 @@ func adjustShorterBefore(before, after Slides) (_ Slides, _ Slides, err error) {
 	for _, idx := range indicesToAdd {
 		slideToAdd := copySlide(after[idx])
 		slideToAdd.new = true
+		slideToAdd.hash = "" // not applied yet
 		before = append(before, slideToAdd)
 	}
 
@@ -503,6 +502,9 @@ func copySlide(slide *Slide) *Slide {
 	// Copy unexported fields manually
 	copied.new = slide.new
 	copied.delete = slide.delete
+	copied.page = slide.page
+	copied.pin = slide.pin
+	copied.hash = slide.hash
 
 	return copied
 }
@@ -532,10 +534,10 @@ func getSimilarity(beforeSlide, afterSlide *Slide) int {
 	if beforeSlide.Layout == afterSlide.Layout && beforeSlide.Layout != "" {
 		score += 50 // Increased layout base score from 10 to 50
 
-		if len(beforeSlide.Titles) > 0 && slices.Equal(beforeSlide.Titles, afterSlide.Titles) {
+		if len(beforeSlide.Titles) > 0 && textsEqual(beforeSlide.Titles, afterSlide.Titles) {
 			score += 80
 		}
-		if len(beforeSlide.Subtitles) > 0 && slices.Equal(beforeSlide.Subtitles, afterSlide.Subtitles) {
+		if len(beforeSlide.Subtitles) > 0 && textsEqual(beforeSlide.Subtitles, afterSlide.Subtitles) {
 			score += 20
 		}
 		if len(beforeSlide.Bodies) > 0 && bodiesEqual(beforeSlide.Bodies, afterSlide.Bodies) {
@@ -554,8 +556,32 @@ func getSimilarity(beforeSlide, afterSlide *Slide) int {
 	return score
 }
 
+// keyMatchScore is the similarity of slides with the same key, which is higher than any similarity of contents.
+const keyMatchScore = 1000
+
 // getSimilarityForMapping: similarity calculation for mapping (with position bonus).
 func getSimilarityForMapping(beforeSlide, afterSlide *Slide, beforeIndex, afterIndex int) int {
+	// Frozen slides are matched only with the pages pinned to them
+	if beforeSlide.pin != 0 || afterSlide.pin != 0 {
+		if beforeSlide.pin == afterSlide.pin {
+			return keyMatchScore
+		}
+		return 0
+	}
+
+	// Slides with keys are matched by the keys rather than by the contents
+	if beforeSlide.Key != "" && afterSlide.Key != "" {
+		if beforeSlide.Key == afterSlide.Key {
+			return keyMatchScore
+		}
+		return 0
+	}
+
+	// Pages applied from the same slide are matched exactly by the hash stored in their metadata
+	if beforeSlide.hash != "" && beforeSlide.hash == afterSlide.hash {
+		return keyMatchScore
+	}
+
 	// Get base similarity
 	baseScore := getSimilarity(beforeSlide, afterSlide)
 
@@ -612,6 +638,7 @@ func generateUpdateActions(before, after Slides, mapping map[int]int) []*action
 				actionType: actionTypeUpdate,
 				index:      beforeIdx,
 				slide:      afterSlide,
+				notesOnly:  !beforeSlide.new && speakerNoteOnlyChanged(beforeSlide, afterSlide),
 			})
 		}
 	}
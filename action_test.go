@@ -1646,6 +1646,62 @@ var tests = []struct {
 				Freeze:      true,
 			},
 		},
+		want: Slides{
+			{
+				Layout:      "title",
+				Titles:      []string{"Slide A"},
+				TitleBodies: toBodies([]string{"Slide A"}),
+			},
+			{
+				Layout:      "title",
+				Titles:      []string{"Slide C"},
+				TitleBodies: toBodies([]string{"Slide C"}),
+			},
+			{
+				Layout:      "title",
+				Titles:      []string{"Slide B"},
+				TitleBodies: toBodies([]string{"Slide B"}),
+			},
+		},
+	},
+	{
+		name: "freeze position slide moved",
+		before: Slides{
+			{
+				Layout:      "title",
+				Titles:      []string{"Slide A"},
+				TitleBodies: toBodies([]string{"Slide A"}),
+			},
+			{
+				Layout:      "title",
+				Titles:      []string{"Slide B"},
+				TitleBodies: toBodies([]string{"Slide B"}),
+			},
+			{
+				Layout:      "title",
+				Titles:      []string{"Slide C"},
+				TitleBodies: toBodies([]string{"Slide C"}),
+			},
+		},
+		after: Slides{
+			{
+				Layout:      "title",
+				Titles:      []string{"Slide A"},
+				TitleBodies: toBodies([]string{"Slide A"}),
+			},
+			{
+				Layout:      "title",
+				Titles:      []string{"Slide C"},
+				TitleBodies: toBodies([]string{"Slide C"}),
+			},
+			{
+				Layout:         "title",
+				Titles:         []string{"Slide B"},
+				TitleBodies:    toBodies([]string{"Slide B"}),
+				Freeze:         true,
+				FreezePosition: true,
+			},
+		},
 		want: Slides{
 			{
 				Layout:      "title",
@@ -1664,6 +1720,59 @@ var tests = []struct {
 			},
 		},
 	},
+	{
+		name: "freeze slide edited in the presentation",
+		before: Slides{
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Slide A"},
+				TitleBodies: toBodies([]string{"Slide A"}),
+				Bodies:      toBodies([]string{"Edited in the presentation"}),
+			},
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Slide B"},
+				TitleBodies: toBodies([]string{"Slide B"}),
+			},
+		},
+		after: Slides{
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Slide New"},
+				TitleBodies: toBodies([]string{"Slide New"}),
+			},
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Slide A"},
+				TitleBodies: toBodies([]string{"Slide A"}),
+				Bodies:      toBodies([]string{"Written in markdown"}),
+				Freeze:      true,
+			},
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Slide B"},
+				TitleBodies: toBodies([]string{"Slide B"}),
+			},
+		},
+		want: Slides{
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Slide New"},
+				TitleBodies: toBodies([]string{"Slide New"}),
+			},
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Slide A"},
+				TitleBodies: toBodies([]string{"Slide A"}),
+				Bodies:      toBodies([]string{"Edited in the presentation"}),
+			},
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Slide B"},
+				TitleBodies: toBodies([]string{"Slide B"}),
+			},
+		},
+	},
 	{
 		name: "freeze slide with append",
 		before: Slides{
@@ -1699,6 +1808,30 @@ var tests = []struct {
 			},
 		},
 	},
+	{
+		name: "right-to-left slide to left-to-right slide",
+		before: Slides{
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"مرحبا"},
+				TitleBodies: toBodies([]string{"مرحبا"}),
+				Bodies: []*Body{{Paragraphs: []*Paragraph{
+					{Fragments: []*Fragment{{Value: "عنصر"}}, Bullet: BulletDash},
+					{Fragments: []*Fragment{{Value: "Item"}}, Bullet: BulletDash},
+				}}},
+			},
+		},
+		after: Slides{
+			{
+				Layout:      "title-and-body",
+				Titles:      []string{"Hello"},
+				TitleBodies: toBodies([]string{"Hello"}),
+				Bodies: []*Body{{Paragraphs: []*Paragraph{
+					{Fragments: []*Fragment{{Value: "Item"}}, Bullet: BulletDash},
+				}}},
+			},
+		},
+	},
 }
 
 func TestGenerateActions(t *testing.T) {
@@ -2054,6 +2187,30 @@ func TestMapSlides(t *testing.T) {
 			},
 			expected: map[int]int{0: 1, 1: 0},
 		},
+		{
+			name: "keys take precedence over identical titles",
+			before: Slides{
+				{Layout: "title", Titles: []string{"Same"}, Key: "a"},
+				{Layout: "title", Titles: []string{"Same"}, Key: "b"},
+			},
+			after: Slides{
+				{Layout: "title", Titles: []string{"Same"}, Key: "b"},
+				{Layout: "title", Titles: []string{"Same"}, Key: "a"},
+			},
+			expected: map[int]int{0: 1, 1: 0},
+		},
+		{
+			name: "keys take precedence over contents",
+			before: Slides{
+				{Layout: "title", Titles: []string{"Intro"}, Key: "intro"},
+				{Layout: "title", Titles: []string{"Summary"}},
+			},
+			after: Slides{
+				{Layout: "title", Titles: []string{"Summary"}},
+				{Layout: "title", Titles: []string{"Introduction"}, Key: "intro"},
+			},
+			expected: map[int]int{0: 1, 1: 0},
+		},
 	}
 
 	for _, tt := range tests {
@@ -3141,3 +3298,40 @@ func FuzzGenerateActions(f *testing.F) {
 		}
 	})
 }
+
+func TestGenerateActionsNotesOnly(t *testing.T) {
+	body := []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "body"}}}}}}
+	tests := []struct {
+		name          string
+		before        Slides
+		after         Slides
+		wantNotesOnly bool
+	}{
+		{
+			name:          "only speaker note changed",
+			before:        Slides{{Layout: "title", Titles: []string{"A"}, Bodies: body, SpeakerNote: "old"}},
+			after:         Slides{{Layout: "title", Titles: []string{"A"}, Bodies: body, SpeakerNote: "new"}},
+			wantNotesOnly: true,
+		},
+		{
+			name:          "title and speaker note changed",
+			before:        Slides{{Layout: "title", Titles: []string{"A"}, Bodies: body, SpeakerNote: "old"}},
+			after:         Slides{{Layout: "title", Titles: []string{"B"}, Bodies: body, SpeakerNote: "new"}},
+			wantNotesOnly: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions, err := generateActions(tt.before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(actions) != 1 || actions[0].actionType != actionTypeUpdate {
+				t.Fatalf("want a single update action, got %v", actions)
+			}
+			if got := actions[0].notesOnly; got != tt.wantNotesOnly {
+				t.Errorf("got notesOnly %v, want %v", got, tt.wantNotesOnly)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package deck
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"regexp"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/k1LoW/deck/textmeasure"
 	"github.com/k1LoW/errors"
 	"google.golang.org/api/slides/v1"
 )
@@ -20,6 +22,7 @@ const (
 	descriptionImageFromMarkdown             = "Image generated from markdown"
 	descriptionTextboxFromMarkdown           = "Textbox generated from markdown"
 	descriptionBlockquoteTextboxFromMarkdown = "Blockquote textbox generated from markdown"
+	descriptionKeyFromMarkdown               = "Page key from markdown" // the key is stored in the title of the alt text
 )
 
 // Apply the markdown slides to the presentation.
@@ -35,24 +38,50 @@ func (d *Deck) Apply(ctx context.Context, slides Slides) (err error) {
 }
 
 // ApplyPages applies the markdown slides to the presentation with the specified pages.
+// If the presentation is edited by others during the apply, the apply is planned again from the edited
+// presentation up to the number of WithEditRetries.
 func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	defer d.startUsage()()
 	if slices.ContainsFunc(pages, func(page int) bool {
 		return page < 1 || page > len(ss)
 	}) {
 		return fmt.Errorf("invalid page number in pages: %v", pages)
 	}
+	return d.applyWithEditRetries(ctx, ss, func() error {
+		return d.applyPages(ctx, ss, pages)
+	})
+}
 
+func (d *Deck) applyPages(ctx context.Context, ss Slides, pages []int) (err error) {
 	if err := d.refresh(ctx); err != nil {
 		return fmt.Errorf("failed to refresh presentation: %w", err)
 	}
+	// The batch updates require the revision that the actions are planned against.
+	d.requiredRevisionID = d.presentation.RevisionId
+	defer func() {
+		d.requiredRevisionID = ""
+	}()
 
 	// Validate layouts before processing
 	if err := d.validateLayouts(ss); err != nil {
 		return fmt.Errorf("layout validation failed: %w", err)
 	}
+	if err := d.validatePageSize(); err != nil {
+		return err
+	}
+	d.warnValidationIssues(ss, pages)
+
+	// Optimize images before comparing them with the current ones, which were optimized when uploaded.
+	targets := make(Slides, 0, len(pages))
+	for _, page := range pages {
+		targets = append(targets, ss[page-1])
+	}
+	if err := d.optimizeImages(targets); err != nil {
+		return err
+	}
 
 	layoutObjectIdMap := map[string]*slides.Page{}
 	for _, l := range d.presentation.Layouts {
@@ -70,10 +99,13 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 		before[i] = slide
 		after[i] = slide
 	}
+	resolvePageLinks(before, d.presentation.Slides)
 
 	for _, page := range pages {
 		i := page - 1
 		slide := ss[i]
+		slide.page = page
+		slide.fillBodies()
 		if slide.Layout == "" {
 			if i == 0 {
 				slide.Layout = d.defaultTitleLayout
@@ -81,6 +113,7 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 				slide.Layout = d.defaultLayout
 			}
 		}
+		slide.hash = slide.contentHash()
 		if i < len(after) {
 			after[i] = slide
 		} else {
@@ -91,10 +124,18 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 		after = after[:len(ss)]
 	}
 
+	if err := d.loadDriveImages(ctx, ss, pages); err != nil {
+		return fmt.Errorf("failed to load images on Google Drive: %w", err)
+	}
+
 	actions, err := generateActions(before, after)
 	if err != nil {
 		return fmt.Errorf("failed to generate actions: %w", err)
 	}
+	if err := d.checkDeletions(actions); err != nil {
+		return err
+	}
+	d.progress.startPages(actions)
 
 	// Pre-fetch current images in parallel for only the slides that will be updated
 	currentImages, err := d.preloadCurrentImages(ctx, actions)
@@ -105,8 +146,8 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 	// Start uploading new images in parallel (don't wait for completion)
 	uploadedCh := d.startUploadingImages(ctx, actions, currentImages)
 	defer func() {
-		// Clean up uploaded images in parallel
-		if cleanupErr := d.cleanupUploadedImages(ctx, uploadedCh); cleanupErr != nil {
+		// Clean up uploaded images in parallel, even if the apply is canceled.
+		if cleanupErr := d.cleanupUploadedImages(context.WithoutCancel(ctx), uploadedCh); cleanupErr != nil {
 			if err == nil {
 				err = fmt.Errorf("failed to cleanup uploaded images: %w", cleanupErr)
 			} else {
@@ -116,6 +157,18 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 	}()
 
 	d.logger.Info("applying actions", slog.Any("actions", toActionLogs(actions)))
+	if d.journal != nil {
+		if err := d.journal.start(d.id, d.presentation.RevisionId); err != nil {
+			return err
+		}
+	}
+	d.changes = pageChanges(actions)
+	d.summary = summarizeActions(actions)
+	for _, c := range d.changes {
+		if c.Owner != "" {
+			d.logger.Info("owned page changed", slog.Int("page", c.Page), slog.String("owner", c.Owner))
+		}
+	}
 
 	var layoutsForAppendPages []string
 	for _, action := range actions {
@@ -139,23 +192,64 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 		if err := d.preparePages(ctx, currentSlidesLen, layoutObjectIDs); err != nil {
 			return fmt.Errorf("failed to create pages: %w", err)
 		}
+		if err := d.recordJournal(nil); err != nil {
+			return err
+		}
 	}
 
 	// add sentinel action to flush remaining requests
 	actions = append(actions, &action{actionType: actionTypeSentinel})
 	var (
 		nextAppendingIndex = currentSlidesLen
-		deletingIndices    []int
 		applyRequests      []*slides.Request
 		appendingCount     = 0
 		applyingCount      = 0
+		movingCount        = 0
+		deletingCount      = 0
+		applyingPages      []int
 	)
-	for _, action := range actions {
-		if action.actionType != actionTypeAppend && action.actionType != actionTypeUpdate &&
-			len(applyRequests) > 0 {
-
+	var (
+		requestPages []pageRequests // pages of applyRequests to retry them one by one with WithContinueOnError
+		pageErrs     PageErrors
+	)
+	// failPage records the error of the page to report it at the end with WithContinueOnError, or returns it.
+	failPage := func(page int, err error) error {
+		if !d.continueOnError {
+			return err
+		}
+		d.logger.Error("failed to apply page", slog.Int("page", page), slog.Any("error", err))
+		pageErrs = append(pageErrs, &PageError{Page: page, Err: err})
+		return nil
+	}
+	addRequests := func(page int, reqs ...*slides.Request) {
+		if len(reqs) == 0 {
+			return
+		}
+		requestPages = append(requestPages, pageRequests{page: page, start: len(applyRequests)})
+		applyRequests = append(applyRequests, reqs...)
+	}
+	// Moves and deletes are sent in the same batches as the requests of the pages, since the pages are
+	// moved and deleted in the snapshot in advance, so that the indices of the following actions still resolve.
+	flush := func() error {
+		if len(applyRequests) > 0 {
 			if err := d.batchUpdate(ctx, applyRequests); err != nil {
-				return fmt.Errorf("failed to apply pages in batches: %w", err)
+				if _, ok := errors.AsType[*ConcurrentEditError](err); ok || !d.continueOnError {
+					return fmt.Errorf("failed to apply pages in batches: %w", err)
+				}
+				failed, err := d.retryPages(ctx, applyRequests, requestPages, err)
+				if err != nil {
+					return fmt.Errorf("failed to apply pages in batches: %w", err)
+				}
+				pageErrs = append(pageErrs, failed...)
+				for _, pe := range failed {
+					applyingPages = slices.DeleteFunc(applyingPages, func(page int) bool { return page == pe.Page })
+					// The tables of the failed pages are not created.
+					for _, action := range actions {
+						if action.slide != nil && action.slide.page == pe.Page {
+							action.objectID = ""
+						}
+					}
+				}
 			}
 
 			// Fill table content for updated/appended slides
@@ -170,43 +264,185 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 				d.logger.Info("applied pages", slog.Int("count", applyingCount))
 				applyingCount = 0
 			}
+			if movingCount > 0 {
+				d.logger.Info("moved pages", slog.Int("count", movingCount))
+				movingCount = 0
+			}
+			if deletingCount > 0 {
+				d.logger.Info("deleted pages", slog.Int("count", deletingCount))
+				deletingCount = 0
+			}
+			if err := d.recordJournal(applyingPages); err != nil {
+				return err
+			}
 			applyRequests = nil
+			applyingPages = nil
+			requestPages = nil
 		}
-		if action.actionType != actionTypeDelete && len(deletingIndices) > 0 {
-			// The indexes of consecutive delete actions are sorted in descending order,
-			// so no position adjustment is necessary.
-			if err := d.DeletePages(ctx, deletingIndices); err != nil {
-				return fmt.Errorf("failed to delete pages: %w", err)
+		// Report the pages of the batch just sent, including pages without any requests.
+		d.progress.flush()
+		return nil
+	}
+	appendPage := func(action *action, index int) error {
+		d.logger.Info("preparing to append new page")
+		reqs, err := d.withPageTimeout(ctx, func(ctx context.Context) ([]*slides.Request, error) {
+			return d.prepareToApplyPage(ctx, index, action.slide, nil)
+		})
+		if err != nil {
+			action.objectID = ""
+			return failPage(action.slide.page, fmt.Errorf("failed to apply page: %w", err))
+		}
+		addRequests(action.slide.page, reqs...)
+		action.objectID = d.presentation.Slides[index].ObjectId
+		d.progress.page(ProgressAppend, action.slide.page, index, true)
+		appendingCount++
+		applyingPages = append(applyingPages, action.slide.page)
+		return nil
+	}
+	updatePage := func(action *action, index int, preloaded *currentImageData) error {
+		if action.notesOnly {
+			d.logger.Info("preparing to apply speaker note", slog.Int("index", index))
+			reqs, err := d.prepareToApplySpeakerNote(index, action.slide)
+			if err != nil {
+				return failPage(action.slide.page, fmt.Errorf("failed to apply speaker note: %w", err))
+			}
+			addRequests(action.slide.page, reqs...)
+			d.progress.page(ProgressUpdate, action.slide.page, index, true)
+			applyingCount++
+			applyingPages = append(applyingPages, action.slide.page)
+			return nil
+		}
+		if d.hotSwap && !action.slide.Freeze {
+			// Flush the pending requests first, since hot-swapping adds and deletes a page around the index.
+			if err := flush(); err != nil {
+				return err
 			}
-			deletingIndices = nil
+			d.logger.Info("hot-swapping page", slog.Int("index", index))
+			// A failed hot swap is fatal even with WithContinueOnError, since it may leave the hidden page
+			// being built next to the page, which shifts the pages planned to be applied after it.
+			if err := d.hotSwapPage(ctx, index, action.slide); err != nil {
+				return fmt.Errorf("failed to hot-swap page %d: %w", action.slide.page, err)
+			}
+			// The original page has been deleted, and the tables of the swapped page have already been filled.
+			action.objectID = ""
+			if err := d.recordJournal([]int{action.slide.page}); err != nil {
+				return err
+			}
+			d.progress.page(ProgressUpdate, action.slide.page, index, false)
+			applyingCount++
+			return nil
+		}
+		d.logger.Info("preparing to apply page", slog.Int("index", index))
+		reqs, err := d.withPageTimeout(ctx, func(ctx context.Context) ([]*slides.Request, error) {
+			return d.prepareToApplyPage(ctx, index, action.slide, preloaded)
+		})
+		if err != nil {
+			action.objectID = ""
+			return failPage(action.slide.page, fmt.Errorf("failed to apply page: %w", err))
+		}
+		addRequests(action.slide.page, reqs...)
+		action.objectID = d.presentation.Slides[index].ObjectId
+		d.progress.page(ProgressUpdate, action.slide.page, index, true)
+		applyingCount++
+		applyingPages = append(applyingPages, action.slide.page)
+		return nil
+	}
+	// Pages with links to other pages are applied after all the pages are moved and deleted,
+	// so that the links point to the pages at their final positions.
+	var deferred []*action
+	for _, action := range actions {
+		// Abort between batches if canceled. The batches already sent are recorded to the journal.
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("apply canceled: %w", err)
 		}
 		switch action.actionType {
 		case actionTypeAppend:
-			d.logger.Info("preparing to append new page")
-			if reqs, err := d.prepareToApplyPage(ctx, nextAppendingIndex, action.slide, nil); err != nil {
-				return fmt.Errorf("failed to apply page: %w", err)
-			} else if len(reqs) > 0 {
-				applyRequests = append(applyRequests, reqs...)
+			if action.slide.hasPageLinks() {
+				action.objectID = d.presentation.Slides[nextAppendingIndex].ObjectId
+				deferred = append(deferred, action)
+			} else if err := appendPage(action, nextAppendingIndex); err != nil {
+				return err
 			}
-			appendingCount++
 			nextAppendingIndex++
 		case actionTypeUpdate:
-			d.logger.Info("preparing to apply page", slog.Int("index", action.index))
-			if reqs, err := d.prepareToApplyPage(ctx, action.index, action.slide, currentImages[action.index]); err != nil {
-				return fmt.Errorf("failed to apply page: %w", err)
-			} else if len(reqs) > 0 {
-				applyRequests = append(applyRequests, reqs...)
+			if !action.notesOnly && action.slide.hasPageLinks() {
+				action.objectID = d.presentation.Slides[action.index].ObjectId
+				deferred = append(deferred, action)
+				continue
+			}
+			if err := updatePage(action, action.index, currentImages[action.index]); err != nil {
+				return err
 			}
-			applyingCount++
 		case actionTypeMove:
-			if err := d.MovePage(ctx, action.index, action.moveToIndex); err != nil {
-				return fmt.Errorf("failed to move page: %w", err)
+			d.logger.Info("preparing to move page", slog.Int("from_index", action.index), slog.Int("to_index", action.moveToIndex))
+			if req := d.movePageRequest(action.index, action.moveToIndex); req != nil {
+				addRequests(0, req)
+			}
+			var page int
+			if action.slide != nil {
+				page = action.slide.page
 			}
+			d.progress.page(ProgressMove, page, action.index, true)
+			movingCount++
 		case actionTypeDelete:
-			deletingIndices = append(deletingIndices, action.index)
+			// The indexes of consecutive delete actions are sorted in descending order,
+			// so no position adjustment is necessary.
+			d.logger.Info("preparing to delete page", slog.Int("index", action.index))
+			if req := d.deletePageRequest(action.index); req != nil {
+				addRequests(0, req)
+			}
+			d.progress.page(ProgressDelete, 0, action.index, true)
+			deletingCount++
+		case actionTypeSentinel:
+			for _, action := range deferred {
+				index := slices.IndexFunc(d.presentation.Slides, func(p *slides.Page) bool {
+					return p.ObjectId == action.objectID
+				})
+				if index < 0 {
+					return fmt.Errorf("page to apply not found: %s", action.objectID)
+				}
+				if action.actionType == actionTypeAppend {
+					if err := appendPage(action, index); err != nil {
+						return err
+					}
+				} else if err := updatePage(action, index, currentImages[action.index]); err != nil {
+					return err
+				}
+			}
+			if err := flush(); err != nil {
+				return err
+			}
 		}
 	}
-	return d.refresh(ctx)
+	// The failed pages have not been changed.
+	d.excludeFailedPages(pageErrs)
+	if d.notifyOwners {
+		if err := d.notifyOwnersOfChanges(ctx); err != nil {
+			return fmt.Errorf("failed to notify owners: %w", err)
+		}
+	}
+	// The journal is kept if some pages failed, so that they can be applied again with the journal.
+	if d.journal != nil && len(pageErrs) == 0 {
+		if err := d.journal.Remove(); err != nil {
+			return err
+		}
+	}
+	if err := d.refresh(ctx); err != nil {
+		return err
+	}
+	if len(pageErrs) > 0 {
+		slices.SortFunc(pageErrs, func(a, b *PageError) int { return a.Page - b.Page })
+		return pageErrs
+	}
+	return nil
+}
+
+// recordJournal records the pages applied by the last batch update to the journal, if any.
+func (d *Deck) recordJournal(pages []int) error {
+	if d.journal == nil {
+		return nil
+	}
+	return d.journal.complete(d.lastRevisionID, pages)
 }
 
 type actionLog struct {
@@ -233,24 +469,22 @@ var apiErrReg = regexp.MustCompile(`googleapi: Error 400: Invalid requests\[([0-
 
 func (d *Deck) batchUpdate(ctx context.Context, requests []*slides.Request) error {
 	d.logger.Info("batch updating presentation request", slog.Int("count", len(requests)))
-	d.fresh = false
-	// Although there is no explicit request limit specified in the Google Slides API specifications,
-	// we will set an upper limit as a precaution.
-	// After testing several times, it handles around 1,000 requests without any issues so that we will
-	// set the upper limit at that point for now.
-	// This limit corresponds to approximately 100 pages of presentation requests.
-	const reqCountLimit = 1000
-	reqLen := len(requests)
-	var groups [][]*slides.Request
-	for i := 0; i < reqLen; i += reqCountLimit {
-		end := min(i+reqCountLimit, reqLen)
-		groups = append(groups, requests[i:end])
-	}
+	d.markDirty(requests)
+	groups := splitRequests(requests, reqCountLimit, reqSizeLimit)
+	sent := 0
 	for _, requests := range groups {
 		req := &slides.BatchUpdatePresentationRequest{
 			Requests: requests,
 		}
-		if _, err := d.srv.Presentations.BatchUpdate(d.id, req).Context(ctx).Do(); err != nil {
+		if d.requiredRevisionID != "" {
+			req.WriteControl = &slides.WriteControl{RequiredRevisionId: d.requiredRevisionID}
+		}
+		d.usage.addBatchRequests(len(requests))
+		res, err := d.srv.Presentations.BatchUpdate(d.id, req).Context(ctx).Do()
+		if err != nil {
+			if ceErr := d.checkRevision(ctx); ceErr != nil {
+				return &batchUpdateError{sent: sent, err: ceErr}
+			}
 			errMsg := err.Error()
 			if matches := apiErrReg.FindStringSubmatch(errMsg); len(matches) == 2 {
 				errIndex, err := strconv.Atoi(matches[1])
@@ -259,12 +493,62 @@ func (d *Deck) batchUpdate(ctx context.Context, requests []*slides.Request) erro
 					d.logger.Debug("invalid request found in batchUpdate", slog.Any("request", errReq), slog.Int("index", errIndex))
 				}
 			}
-			return fmt.Errorf("failed to batch update presentation: %w", err)
+			return &batchUpdateError{sent: sent, err: fmt.Errorf("failed to batch update presentation: %w", err)}
+		}
+		sent += len(requests)
+		if res.WriteControl != nil {
+			d.lastRevisionID = res.WriteControl.RequiredRevisionId
+			if d.requiredRevisionID != "" {
+				d.requiredRevisionID = d.lastRevisionID
+			}
 		}
 	}
 	return nil
 }
 
+const (
+	// Although there is no explicit request limit specified in the Google Slides API specifications,
+	// we will set an upper limit as a precaution.
+	// After testing several times, it handles around 1,000 requests without any issues so that we will
+	// set the upper limit at that point for now.
+	// This limit corresponds to approximately 100 pages of presentation requests.
+	reqCountLimit = 1000
+	// Pages with long texts or many tables make large requests, so batches are also split by the estimated
+	// size of the payload to stay well below the size limit of the request body.
+	reqSizeLimit = 2 * 1024 * 1024
+)
+
+// splitRequests splits requests into batches of at most countLimit requests and sizeLimit bytes of JSON, keeping their order.
+// A request larger than sizeLimit is sent in a batch of its own.
+func splitRequests(requests []*slides.Request, countLimit, sizeLimit int) [][]*slides.Request {
+	var (
+		groups [][]*slides.Request
+		start  int
+		size   int
+	)
+	for i, r := range requests {
+		s := requestSize(r)
+		if i > start && (i-start >= countLimit || size+s > sizeLimit) {
+			groups = append(groups, requests[start:i])
+			start, size = i, 0
+		}
+		size += s
+	}
+	if start < len(requests) {
+		groups = append(groups, requests[start:])
+	}
+	return groups
+}
+
+// requestSize returns the estimated size of the request in the payload of the batch update.
+func requestSize(r *slides.Request) int {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return 0
+	}
+	return len(b) + 1 // separator
+}
+
 func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide, preloaded *currentImageData) (
 	requests []*slides.Request, err error) {
 
@@ -305,6 +589,10 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		currentBlockquoteIDs      []string
 		currentTextBoxObjectIDMap = map[*textBox]string{} // key: *textBox, value: objectID
 		currentTables             []*slides.PageElement
+		currentKeys               = map[string]string{} // key: objectID, value: key of the page
+		currentMetadata           = map[string]string{} // key: objectID, value: metadata of the page as JSON
+		currentElements           = map[string]string{} // key: objectID, value: element as JSON
+		currentCredits            []string              // objectIDs of the captions of image credits
 	)
 
 	// Use preloaded image data if available, otherwise fetch on demand
@@ -316,6 +604,10 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 	currentSlide = d.presentation.Slides[index]
 	for _, element := range currentSlide.PageElements {
 		switch {
+		case element.Description == descriptionElementFromMarkdown:
+			currentElements[element.ObjectId] = element.Title
+		case element.Description == descriptionCreditTextboxFromMarkdown:
+			currentCredits = append(currentCredits, element.ObjectId)
 		case element.Shape != nil && element.Shape.Placeholder != nil:
 			switch element.Shape.Placeholder.Type {
 			case "CENTERED_TITLE", "TITLE":
@@ -333,18 +625,24 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 				})
 				requests = append(requests, d.clearPlaceholderRequests(element)...)
 			case "BODY":
+				width, height := elementSize(element)
 				bodies = append(bodies, placeholder{
 					objectID: element.ObjectId,
 					x:        element.Transform.TranslateX,
 					y:        element.Transform.TranslateY,
+					width:    width,
+					height:   height,
 				})
 				requests = append(requests, d.clearPlaceholderRequests(element)...)
 			}
 		case element.Image != nil && element.Image.Placeholder != nil:
+			width, height := elementSize(element)
 			imagePlaceholders = append(imagePlaceholders, placeholder{
 				objectID: element.ObjectId,
 				x:        element.Transform.TranslateX,
 				y:        element.Transform.TranslateY,
+				width:    width,
+				height:   height,
 			})
 		case element.Image != nil && preloaded == nil:
 			// Only fetch images on demand if preloaded data is not available
@@ -352,7 +650,7 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 				image *Image
 				err   error
 			)
-			if element.Description == descriptionImageFromMarkdown {
+			if isImageFromMarkdown(element.Description) {
 				image, err = NewImageFromMarkdown(element.Image.ContentUrl)
 				if err != nil {
 					return nil, fmt.Errorf("failed to create image from code block %s: %w", element.Image.ContentUrl, err)
@@ -363,8 +661,13 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 					return nil, fmt.Errorf("failed to create image from %s: %w", element.Image.ContentUrl, err)
 				}
 			}
+			image.setAltText(element.Title, element.Description)
 			currentImages = append(currentImages, image)
 			currentImageObjectIDMap[image] = element.ObjectId
+		case element.Shape != nil && element.Description == descriptionKeyFromMarkdown:
+			currentKeys[element.ObjectId] = element.Title
+		case element.Shape != nil && element.Description == descriptionMetadataFromMarkdown:
+			currentMetadata[element.ObjectId] = element.Title
 		case element.Shape != nil && element.Shape.ShapeType == "TEXT_BOX" && element.Shape.Text != nil:
 			tb := &textBox{}
 			tb.fromMarkdown = element.Description == descriptionTextboxFromMarkdown ||
@@ -403,10 +706,11 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		if len(titles) <= i {
 			break
 		}
-		reqs, styleReqs, err := d.applyParagraphsRequests(titles[i].objectID, b.Paragraphs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to apply paragraphs for title: %w", err)
+		if len(b.Paragraphs) == 0 {
+			// The placeholder is left empty for a heading placed in a later placeholder.
+			continue
 		}
+		reqs, styleReqs := d.requestBuilder(withDirection(slide.Direction)).ParagraphsRequests(titles[i].objectID, b.Paragraphs)
 		requests = append(requests, reqs...)
 		requests = append(requests, styleReqs...)
 	}
@@ -422,10 +726,11 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		if len(subtitles) <= i {
 			break
 		}
-		reqs, styleReqs, err := d.applyParagraphsRequests(subtitles[i].objectID, b.Paragraphs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to apply paragraphs for subtitle: %w", err)
+		if len(b.Paragraphs) == 0 {
+			// The placeholder is left empty for a heading placed in a later placeholder.
+			continue
 		}
+		reqs, styleReqs := d.requestBuilder(withDirection(slide.Direction)).ParagraphsRequests(subtitles[i].objectID, b.Paragraphs)
 		requests = append(requests, reqs...)
 		requests = append(requests, styleReqs...)
 	}
@@ -449,13 +754,25 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		if len(bodies) <= i {
 			continue
 		}
-		reqs, styleReqs, err := d.applyParagraphsRequests(bodies[i].objectID, body.Paragraphs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to apply paragraphs: %w", err)
-		}
+		reqs, styleReqs := d.requestBuilder(withDirection(slide.Direction)).ParagraphsRequests(bodies[i].objectID, body.Paragraphs)
 		requests = append(requests, reqs...)
+		if len(body.Paragraphs) > 0 {
+			// The spacing of the body is set before the styles, so that the paragraph styles of styled paragraphs take precedence.
+			if r := paragraphSpacingRequest(bodies[i].objectID, slide.ParagraphSpacing); r != nil {
+				requests = append(requests, r)
+			}
+		}
 		requests = append(requests, styleReqs...)
 	}
+	for _, body := range bodies {
+		r, err := autofitRequest(body.objectID, slide.Autofit)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			requests = append(requests, r)
+		}
+	}
 
 	// set images
 	sort.Slice(imagePlaceholders, func(i, j int) bool {
@@ -464,10 +781,40 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		}
 		return imagePlaceholders[i].y < imagePlaceholders[j].y
 	})
+	imagePlaceholderIndices := assignImagePlaceholders(slide.Images, len(imagePlaceholders))
+	imageCells := d.tiledImageCells(slide, imagePlaceholderIndices, bodies)
+	var (
+		creditReqs []*slides.Request
+		backImages []string // objectIDs of the images to send behind the other elements
+	)
 	for i, image := range slide.Images {
-		if slices.ContainsFunc(currentImages, func(currentImage *Image) bool {
+		if j := slices.IndexFunc(currentImages, func(currentImage *Image) bool {
 			return currentImage.Equivalent(image)
-		}) {
+		}); j >= 0 {
+			// Keep the image, but update its alt text if it has been changed.
+			currentImage := currentImages[j]
+			imageObjectID := currentImageObjectIDMap[currentImage]
+			if image.fromMarkdown && (currentImage.alt != image.alt || currentImage.title != image.title || currentImage.zOrder != image.zOrder) {
+				if r := image.altTextRequest(imageObjectID); r != nil {
+					requests = append(requests, r)
+				}
+			}
+			if image.fromMarkdown && currentImage.zOrder != image.zOrder {
+				if image.zOrder == ZOrderBack {
+					backImages = append(backImages, imageObjectID)
+				} else {
+					requests = append(requests, zOrderRequest([]string{imageObjectID}, image.zOrder))
+				}
+			}
+			if image.credit != "" {
+				if k := slices.IndexFunc(currentSlide.PageElements, func(e *slides.PageElement) bool {
+					return e.ObjectId == imageObjectID
+				}); k >= 0 {
+					if r, ok := elementRect(currentSlide.PageElements[k]); ok {
+						creditReqs = append(creditReqs, d.requestBuilder().creditRequests(currentSlide.ObjectId, imageObjectID, image.credit, r)...)
+					}
+				}
+			}
 			continue
 		}
 
@@ -480,14 +827,14 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 			return nil, fmt.Errorf("image not uploaded or webContentLink is empty")
 		}
 		var imageObjectID string
-		if len(imagePlaceholders) > i {
+		if k := imagePlaceholderIndices[i]; k >= 0 {
 			imageReplaceMethod := "CENTER_CROP"
 			if info.codeBlock {
 				// In the case of code blocks, it is important that the entire image can be seen
 				// without being cropped, so switch the replace method.
 				imageReplaceMethod = "CENTER_INSIDE"
 			}
-			imageObjectID = imagePlaceholders[i].objectID
+			imageObjectID = imagePlaceholders[k].objectID
 			requests = append(requests, &slides.Request{
 				ReplaceImage: &slides.ReplaceImageRequest{
 					ImageObjectId:      imageObjectID,
@@ -495,6 +842,10 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 					Url:                info.url,
 				},
 			})
+			if p := imagePlaceholders[k]; image.credit != "" && p.width > 0 && p.height > 0 {
+				creditReqs = append(creditReqs, d.requestBuilder().creditRequests(
+					currentSlide.ObjectId, imageObjectID, image.credit, rect{x: p.x, y: p.y, width: p.width, height: p.height})...)
+			}
 		} else {
 			imageObjectID = fmt.Sprintf("image-%s", uuid.New().String())
 			imageReq := &slides.CreateImageRequest{
@@ -511,9 +862,29 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 				},
 				Url: info.url,
 			}
+			cell, ok := imageCells[i]
+			if !ok && image.credit != "" {
+				// The size is given to anchor the credit beneath the image, as the size of the image inserted without size.
+				if width, height, known := image.intrinsicSize(); known {
+					cell = rect{x: imageReq.ElementProperties.Transform.TranslateX, y: imageReq.ElementProperties.Transform.TranslateY, width: width, height: height}
+					ok = true
+				}
+			}
+			if ok {
+				// The image is scaled to fit the size keeping the aspect ratio, and centered in it.
+				imageReq.ElementProperties.Transform.TranslateX = cell.x
+				imageReq.ElementProperties.Transform.TranslateY = cell.y
+				imageReq.ElementProperties.Size = &slides.Size{
+					Width:  &slides.Dimension{Magnitude: cell.width, Unit: "EMU"},
+					Height: &slides.Dimension{Magnitude: cell.height, Unit: "EMU"},
+				}
+			}
 			requests = append(requests, &slides.Request{
 				CreateImage: imageReq,
 			})
+			if ok && image.credit != "" {
+				creditReqs = append(creditReqs, d.requestBuilder().creditRequests(currentSlide.ObjectId, imageObjectID, image.credit, cell)...)
+			}
 		}
 		if info.link != "" {
 			requests = append(requests, &slides.Request{
@@ -528,15 +899,27 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 				},
 			})
 		}
-		if image.fromMarkdown {
-			requests = append(requests, &slides.Request{
-				UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
-					ObjectId:    imageObjectID,
-					Description: descriptionImageFromMarkdown,
-				},
-			})
+		if r := image.altTextRequest(imageObjectID); r != nil {
+			requests = append(requests, r)
+		}
+		if image.fromMarkdown && image.zOrder == ZOrderBack {
+			backImages = append(backImages, imageObjectID)
 		}
 	}
+	// send the images behind the placeholders, keeping the order of the images
+	if r := zOrderRequest(backImages, ZOrderBack); r != nil {
+		requests = append(requests, r)
+	}
+
+	// recreate the captions of image credits beneath the images
+	for _, objectID := range currentCredits {
+		requests = append(requests, &slides.Request{
+			DeleteObject: &slides.DeleteObjectRequest{
+				ObjectId: objectID,
+			},
+		})
+	}
+	requests = append(requests, creditReqs...)
 
 	// set tables - compare with existing and only create/update as needed
 	tableRequests, err := d.handleTableUpdates(currentSlide.ObjectId, slide.Tables, currentTables)
@@ -546,12 +929,19 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 	requests = append(requests, tableRequests...)
 
 	blockquoteReqs, reuseBlockquotes, err := d.handleBlockquotes(
-		currentSlide.ObjectId, slide.BlockQuotes, currentTextBoxes, currentBlockquoteIDs)
+		currentSlide.ObjectId, slide.BlockQuotes, currentTextBoxes, currentBlockquoteIDs, slide.Direction)
 	if err != nil {
 		return nil, err
 	}
 	requests = append(requests, blockquoteReqs...)
 
+	// set custom elements
+	elementReqs, err := d.elementRequests(ctx, currentSlide.ObjectId, slide.Elements, currentElements)
+	if err != nil {
+		return nil, err
+	}
+	requests = append(requests, elementReqs...)
+
 	// set skip flag to slide
 	requests = append(requests, &slides.Request{
 		UpdateSlideProperties: &slides.UpdateSlidePropertiesRequest{
@@ -563,6 +953,10 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		},
 	})
 
+	// set key
+	requests = append(requests, keyRequests(currentSlide.ObjectId, slide.Key, currentKeys)...)
+	requests = append(requests, metadataRequests(currentSlide.ObjectId, slide, currentMetadata)...)
+
 	// prune unmatched images via markdown
 	for _, currentImage := range currentImages {
 		if !currentImage.fromMarkdown || slices.ContainsFunc(slide.Images, func(image *Image) bool {
@@ -605,115 +999,95 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 	return requests, nil
 }
 
-func (d *Deck) applyParagraphsRequests(objectID string, paragraphs []*Paragraph) (reqs []*slides.Request, styleReqs []*slides.Request, err error) {
-	defer func() {
-		err = errors.WithStack(err)
-	}()
-
-	bulletRanges := map[int]*bulletRange{}
-	count := int64(0)
-	var textBuilder strings.Builder
-	bulletStartIndex := int64(0) // reset per body
-	bulletEndIndex := int64(0)   // reset per body
-	currentBullet := BulletNone
-	for j, paragraph := range paragraphs {
-		plen := 0
-		if paragraph.Bullet != BulletNone {
-			if paragraph.Nesting > 0 {
-				textBuilder.WriteString(strings.Repeat("\t", paragraph.Nesting))
-				plen += paragraph.Nesting
-			}
-		}
-		for _, fragment := range paragraph.Fragments {
-			// In Google Slides, pressing Enter creates a paragraph break, and pressing Shift + Enter
-			// creates an inline line break. The inline line break seems to be treated as a vertical
-			// tab around API data, so convert it to a vertical tab.
-			fValue := strings.ReplaceAll(fragment.Value, "\n", "\v")
-			flen := countString(fragment.Value)
-
-			if r := d.getInlineStyleRequest(fragment); r != nil {
-				startIndex := count + int64(plen)
-				styleReqs = append(styleReqs, &slides.Request{
-					UpdateTextStyle: &slides.UpdateTextStyleRequest{
-						ObjectId: objectID,
-						Style:    r.Style,
-						Fields:   r.Fields,
-						TextRange: &slides.Range{
-							Type:       "FIXED_RANGE",
-							StartIndex: new(startIndex),
-							EndIndex:   new(startIndex + int64(flen)),
-						},
+// prepareToApplySpeakerNote returns requests to rewrite only the speaker note of the page at index,
+// leaving the other placeholders untouched.
+func (d *Deck) prepareToApplySpeakerNote(index int, slide *Slide) ([]*slides.Request, error) {
+	if len(d.presentation.Slides) <= index {
+		return nil, fmt.Errorf("index out of range: %d", index)
+	}
+	if slide.Freeze {
+		d.logger.Info("skip applying page. because freeze:true", slog.Int("index", index))
+		return nil, nil
+	}
+	currentSlide := d.presentation.Slides[index]
+	var requests []*slides.Request
+	for _, element := range currentSlide.SlideProperties.NotesPage.PageElements {
+		if element.Shape == nil || element.Shape.Placeholder == nil || element.Shape.Placeholder.Type != "BODY" {
+			continue
+		}
+		if element.Shape.Text != nil {
+			requests = append(requests, &slides.Request{
+				DeleteText: &slides.DeleteTextRequest{
+					ObjectId: element.ObjectId,
+					TextRange: &slides.Range{
+						Type: "ALL",
 					},
-				})
-			}
-			plen += flen
-			textBuilder.WriteString(fValue)
+				},
+			})
 		}
-
-		if len(paragraphs) > j+1 {
-			textBuilder.WriteString("\n")
-			plen++
+		if slide.SpeakerNote != "" {
+			requests = append(requests, &slides.Request{
+				InsertText: &slides.InsertTextRequest{
+					ObjectId: element.ObjectId,
+					Text:     slide.SpeakerNote,
+				},
+			})
 		}
+		return requests, nil
+	}
+	return nil, fmt.Errorf("speaker notes not found")
+}
 
-		if paragraph.Bullet != BulletNone {
-			if paragraph.Nesting == 0 && currentBullet != paragraph.Bullet {
-				bulletStartIndex = count
-				bulletEndIndex = count
-				bulletRanges[int(bulletStartIndex)] = &bulletRange{
-					bullet: paragraph.Bullet,
-					start:  bulletStartIndex,
-					end:    bulletEndIndex,
-				}
-			}
-			bulletEndIndex += int64(plen)
-			bulletRanges[int(bulletStartIndex)].end = bulletEndIndex
+// assignImagePlaceholders returns the index of the picture placeholder for each image, or -1 if the image
+// has no placeholder to be placed in. Images targeting a placeholder take it first, and the other images
+// fill the remaining placeholders in order. Targets out of range or already taken are placed in order.
+func assignImagePlaceholders(images []*Image, n int) []int {
+	indices := make([]int, len(images))
+	taken := make([]bool, n)
+	for i, image := range images {
+		indices[i] = -1
+		if p := image.placeholder; p >= 1 && p <= n && !taken[p-1] {
+			indices[i] = p - 1
+			taken[p-1] = true
 		}
-		currentBullet = paragraph.Bullet
-		count += int64(plen)
 	}
-
-	reqs = append(reqs, &slides.Request{
-		InsertText: &slides.InsertTextRequest{
-			ObjectId: objectID,
-			Text:     textBuilder.String(),
-		},
-	})
-	var bulletRangeSlice []*bulletRange
-	for _, r := range bulletRanges {
-		bulletRangeSlice = append(bulletRangeSlice, r)
-	}
-	// reverse sort
-	// Because the Range changes each time it is converted to a list, convert from the end to a list.
-	sort.Slice(bulletRangeSlice, func(i, j int) bool {
-		return bulletRangeSlice[i].start > bulletRangeSlice[j].start
-	})
-	for _, r := range bulletRangeSlice {
-		startIndex := r.start
-		endIndex := r.end - 1
-		if startIndex <= endIndex {
-			endIndex++
-		}
-		styleReqs = append(styleReqs, &slides.Request{
-			CreateParagraphBullets: &slides.CreateParagraphBulletsRequest{
-				ObjectId:     objectID,
-				BulletPreset: convertBullet(r.bullet),
-				TextRange: &slides.Range{
-					Type:       "FIXED_RANGE",
-					StartIndex: new(startIndex),
-					EndIndex:   new(endIndex),
-				},
-			},
-		})
+	next := 0
+	for i := range images {
+		if indices[i] >= 0 {
+			continue
+		}
+		for next < n && taken[next] {
+			next++
+		}
+		if next < n {
+			indices[i] = next
+			taken[next] = true
+		}
 	}
-
-	return reqs, styleReqs, nil
+	return indices
 }
 
 func (d *Deck) clearPlaceholderRequests(elm *slides.PageElement) []*slides.Request {
 	if elm.Shape.Text == nil {
 		return nil
 	}
-	return []*slides.Request{{
+	var requests []*slides.Request
+	if hasRTLParagraph(elm.Shape.Text) {
+		// Deleting the text keeps the direction of the last paragraph, so reset it to the default.
+		requests = append(requests, &slides.Request{
+			UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+				ObjectId: elm.ObjectId,
+				Style: &slides.ParagraphStyle{
+					Direction: "LEFT_TO_RIGHT",
+				},
+				Fields: "direction",
+				TextRange: &slides.Range{
+					Type: "ALL",
+				},
+			},
+		})
+	}
+	return append(requests, []*slides.Request{{
 		UpdateTextStyle: &slides.UpdateTextStyleRequest{
 			ObjectId: elm.ObjectId,
 			Style: &slides.TextStyle{
@@ -739,21 +1113,13 @@ func (d *Deck) clearPlaceholderRequests(elm *slides.PageElement) []*slides.Reque
 				Type: "ALL",
 			},
 		},
-	}}
+	}}...)
 }
 
 // countString counts the number of characters in a string, considering UTF-16 surrogate pairs.
 // This is because Google Slides' character count is derived from JavaScript.
 func countString(s string) int {
-	length := 0
-	for _, r := range s {
-		if r <= 0xFFFF && (r < 0xD800 || r > 0xDFFF) {
-			length++
-		} else {
-			length += 2
-		}
-	}
-	return length
+	return textmeasure.UTF16Len(s)
 }
 
 func convertBullet(b Bullet) string {
@@ -796,13 +1162,14 @@ func (d *Deck) updateLayout(ctx context.Context, index int, slide *Slide) (err e
 		insertReqs []*slides.Request
 		styleReqs  []*slides.Request
 		bulletReqs []*slides.Request
+		backImages []string
 	)
 
 	for _, element := range currentSlide.PageElements {
 		// copy images from the current slide to the new slide
 		if element.Image != nil && element.Image.ContentUrl != "" {
 			var imageObjectID string
-			if element.Description == descriptionImageFromMarkdown {
+			if isImageFromMarkdown(element.Description) {
 				imageObjectID = fmt.Sprintf("image-%s", uuid.New().String())
 			}
 			reqs = append(reqs, &slides.Request{
@@ -820,13 +1187,20 @@ func (d *Deck) updateLayout(ctx context.Context, index int, slide *Slide) (err e
 				reqs = append(reqs, &slides.Request{
 					UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
 						ObjectId:    imageObjectID,
-						Description: descriptionImageFromMarkdown,
+						Title:       element.Title,
+						Description: element.Description,
 					},
 				})
+				if isBackImageFromMarkdown(element.Description) {
+					backImages = append(backImages, imageObjectID)
+				}
 			}
 		}
 		// copy shapes from the current slide to the new slide
-		if element.Shape != nil && element.Shape.Placeholder == nil && element.Description != descriptionTextboxFromMarkdown {
+		// Captions, keys and metadata are recreated on the new slide
+		if element.Shape != nil && element.Shape.Placeholder == nil && element.Description != descriptionTextboxFromMarkdown &&
+			element.Description != descriptionCreditTextboxFromMarkdown && element.Description != descriptionKeyFromMarkdown &&
+			element.Description != descriptionMetadataFromMarkdown {
 			type paragraphInfo struct {
 				startIndex   int64
 				endIndex     int64
@@ -980,6 +1354,9 @@ func (d *Deck) updateLayout(ctx context.Context, index int, slide *Slide) (err e
 			}
 		}
 	}
+	if r := zOrderRequest(backImages, ZOrderBack); r != nil {
+		reqs = append(reqs, r)
+	}
 	reqs = append(reqs, insertReqs...)
 	if len(reqs) > 0 {
 		if err := d.batchUpdate(ctx, reqs); err != nil {
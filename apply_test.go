@@ -0,0 +1,86 @@
+package deck
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestSplitRequests(t *testing.T) {
+	text := func(s string) *slides.Request {
+		return &slides.Request{InsertText: &slides.InsertTextRequest{ObjectId: "x", Text: s}}
+	}
+	size := requestSize(text("a"))
+	tests := []struct {
+		name       string
+		texts      []string
+		countLimit int
+		sizeLimit  int
+		want       []int
+	}{
+		{"empty", nil, 2, 1000, nil},
+		{"within limits", []string{"a", "b", "c"}, 3, 1000, []int{3}},
+		{"by count", []string{"a", "b", "c", "d", "e"}, 2, 1000, []int{2, 2, 1}},
+		{"by size", []string{"a", "b", "c", "d", "e"}, 10, size * 2, []int{2, 2, 1}},
+		{"larger than the size limit", []string{"a", strings.Repeat("b", 100), "c"}, 10, size * 2, []int{1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []*slides.Request
+			for _, s := range tt.texts {
+				requests = append(requests, text(s))
+			}
+			var got []int
+			var texts []string
+			for _, g := range splitRequests(requests, tt.countLimit, tt.sizeLimit) {
+				got = append(got, len(g))
+				for _, r := range g {
+					texts = append(texts, r.InsertText.Text)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.texts, texts); diff != "" {
+				t.Errorf("the order of the requests is changed: %s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyMovesAndDeletesInOneBatch(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := func(title string) *Slide {
+		return &Slide{Layout: "title-and-body", Titles: []string{title}, Bodies: toBodies([]string{title + " body"})}
+	}
+	if err := d.Apply(ctx, Slides{page("A"), page("B"), page("C"), page("D"), page("E")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Apply(ctx, Slides{page("E"), page("C"), page("A"), page("B")}); err != nil {
+		t.Fatal(err)
+	}
+	if u := d.APIUsage(); u.SlidesWrites != 1 {
+		t.Errorf("got %d batch updates for the moves and deletes, want 1: %+v", u.SlidesWrites, u)
+	}
+	got, err := d.DumpSlides(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, s := range got {
+		titles = append(titles, strings.Join(s.Titles, ""))
+	}
+	if diff := cmp.Diff([]string{"E", "C", "A", "B"}, titles); diff != "" {
+		t.Error(diff)
+	}
+}
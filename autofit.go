@@ -0,0 +1,57 @@
+package deck
+
+import (
+	"fmt"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// Autofit behaviors of the body placeholders.
+const (
+	AutofitNone   = "none"   // do not fit the text
+	AutofitShrink = "shrink" // shrink the text on overflow
+	AutofitResize = "resize" // resize the shape to fit the text
+)
+
+// ValidateAutofit returns an error if autofit is not a valid autofit behavior.
+// An empty autofit keeps the autofit of the placeholder as it is.
+func ValidateAutofit(autofit string) error {
+	_, err := autofitType(autofit)
+	return err
+}
+
+// autofitType returns the autofit type of the Slides API for autofit.
+func autofitType(autofit string) (string, error) {
+	switch autofit {
+	case "":
+		return "", nil
+	case AutofitNone:
+		return "NONE", nil
+	case AutofitShrink:
+		return "TEXT_AUTOFIT", nil
+	case AutofitResize:
+		return "SHAPE_AUTOFIT", nil
+	default:
+		return "", fmt.Errorf("invalid autofit: %q (must be %q, %q or %q)", autofit, AutofitNone, AutofitShrink, AutofitResize)
+	}
+}
+
+// autofitRequest returns the request to set the autofit of the shape, or nil if autofit is empty.
+// Since inserting text resets the autofit of the shape to NONE, the request must be sent after the text is inserted.
+func autofitRequest(objectID, autofit string) (*slides.Request, error) {
+	t, err := autofitType(autofit)
+	if err != nil || t == "" {
+		return nil, err
+	}
+	return &slides.Request{
+		UpdateShapeProperties: &slides.UpdateShapePropertiesRequest{
+			ObjectId: objectID,
+			ShapeProperties: &slides.ShapeProperties{
+				Autofit: &slides.Autofit{
+					AutofitType: t,
+				},
+			},
+			Fields: "autofit.autofitType",
+		},
+	}, nil
+}
@@ -0,0 +1,45 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestAutofitRequest(t *testing.T) {
+	tests := []struct {
+		autofit string
+		want    *slides.Request
+		wantErr bool
+	}{
+		{"", nil, false},
+		{AutofitNone, autofitTypeRequest("NONE"), false},
+		{AutofitShrink, autofitTypeRequest("TEXT_AUTOFIT"), false},
+		{AutofitResize, autofitTypeRequest("SHAPE_AUTOFIT"), false},
+		{"fit", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.autofit, func(t *testing.T) {
+			got, err := autofitRequest("body", tt.autofit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("autofitRequest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func autofitTypeRequest(t string) *slides.Request {
+	return &slides.Request{
+		UpdateShapeProperties: &slides.UpdateShapePropertiesRequest{
+			ObjectId: "body",
+			ShapeProperties: &slides.ShapeProperties{
+				Autofit: &slides.Autofit{AutofitType: t},
+			},
+			Fields: "autofit.autofitType",
+		},
+	}
+}
@@ -0,0 +1,934 @@
+package deck
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/deck/internal/requestbuilder"
+	"google.golang.org/api/slides/v1"
+)
+
+// requestBuilder builds Google Slides API requests from the deck model.
+// It does not access the network, so it can be used without a Deck instance.
+type requestBuilder struct {
+	styles          map[string]*slides.TextStyle
+	shapes          map[string]*slides.ShapeProperties
+	paragraphStyles map[string]*slides.ParagraphStyle
+	tableStyle      *TableStyle
+	direction       string
+	highlightColor  string
+}
+
+// requestBuilderOption is an option for newRequestBuilder.
+type requestBuilderOption func(*requestBuilder)
+
+// newRequestBuilder returns a new requestBuilder.
+func newRequestBuilder(opts ...requestBuilderOption) *requestBuilder {
+	b := &requestBuilder{
+		styles:          map[string]*slides.TextStyle{},
+		shapes:          map[string]*slides.ShapeProperties{},
+		paragraphStyles: map[string]*slides.ParagraphStyle{},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// withTextStyle sets the text style used for the style named name (e.g. "bold", "link", "blockquote").
+func withTextStyle(name string, style *slides.TextStyle) requestBuilderOption {
+	return func(b *requestBuilder) {
+		b.styles[name] = style
+	}
+}
+
+// withShapeProperties sets the shape properties used for the style named name (e.g. "blockquote").
+func withShapeProperties(name string, props *slides.ShapeProperties) requestBuilderOption {
+	return func(b *requestBuilder) {
+		b.shapes[name] = props
+	}
+}
+
+// withParagraphStyle sets the paragraph style (alignment and spacing) used for paragraphs of the style named name.
+func withParagraphStyle(name string, style *slides.ParagraphStyle) requestBuilderOption {
+	return func(b *requestBuilder) {
+		b.paragraphStyles[name] = style
+	}
+}
+
+// withTableStyle sets the table style applied to tables.
+func withTableStyle(ts *TableStyle) requestBuilderOption {
+	return func(b *requestBuilder) {
+		b.tableStyle = ts
+	}
+}
+
+// withDirection sets the text direction of paragraphs, DirectionLTR or DirectionRTL.
+// When it is empty (default), the direction of each paragraph is detected from its text.
+func withDirection(direction string) requestBuilderOption {
+	return func(b *requestBuilder) {
+		b.direction = direction
+	}
+}
+
+func init() {
+	requestbuilder.New = func(o *requestbuilder.Options) any {
+		b := newRequestBuilder(withDirection(o.Direction))
+		maps.Copy(b.styles, o.TextStyles)
+		maps.Copy(b.shapes, o.ShapeProperties)
+		maps.Copy(b.paragraphStyles, o.ParagraphStyles)
+		if ts, ok := o.TableStyle.(*TableStyle); ok {
+			b.tableStyle = ts
+		}
+		return b
+	}
+}
+
+// requestBuilder returns a requestBuilder using the styles of the presentation.
+func (d *Deck) requestBuilder(opts ...requestBuilderOption) *requestBuilder {
+	b := &requestBuilder{
+		styles:          d.styles,
+		shapes:          d.shapes,
+		paragraphStyles: d.paragraphStyles,
+		tableStyle:      d.tableStyle,
+		highlightColor:  d.highlightColor,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// blockQuoteIndent is the indentation of a text box per nesting level of block quotes in EMU.
+const blockQuoteIndent = 400000
+
+// BlockQuoteRequests returns requests to create a text box for bq on the page identified by pageObjectID.
+// index is the position of the block quote in the page and is used to offset the text box.
+// Nested block quotes are indented according to their nesting level.
+func (b *requestBuilder) BlockQuoteRequests(pageObjectID, textBoxObjectID string, bq *BlockQuote, index int) []*slides.Request {
+	indent := float64(bq.Nesting * blockQuoteIndent)
+	requests := []*slides.Request{{
+		CreateShape: &slides.CreateShapeRequest{
+			ObjectId: textBoxObjectID,
+			ElementProperties: &slides.PageElementProperties{
+				PageObjectId: pageObjectID,
+				Size: &slides.Size{
+					Height: &slides.Dimension{
+						Magnitude: float64(500000 * len(bq.Paragraphs)),
+						Unit:      "EMU",
+					},
+					Width: &slides.Dimension{
+						Magnitude: 5000000 - indent,
+						Unit:      "EMU",
+					},
+				},
+				Transform: &slides.AffineTransform{
+					ScaleX:     1.0,
+					ScaleY:     1.0,
+					TranslateX: float64(index+1)*100000 + indent,
+					TranslateY: float64(index+1) * 100000,
+					Unit:       "EMU",
+				},
+			},
+			ShapeType: "TEXT_BOX",
+		},
+	}}
+
+	if r := b.BlockQuoteShapeRequest(textBoxObjectID, bq); r != nil {
+		requests = append(requests, r)
+	}
+
+	return append(requests, b.BlockQuoteTextRequests(textBoxObjectID, bq)...)
+}
+
+// BlockQuoteShapeRequest returns a request to apply the shape properties of the style of bq
+// to the text box identified by textBoxObjectID. It returns nil if the style has no shape properties.
+func (b *requestBuilder) BlockQuoteShapeRequest(textBoxObjectID string, bq *BlockQuote) *slides.Request {
+	sp, ok := b.shapes[b.blockQuoteStyleName(bq)]
+	if !ok {
+		return nil
+	}
+	return &slides.Request{
+		UpdateShapeProperties: &slides.UpdateShapePropertiesRequest{
+			ObjectId:        textBoxObjectID,
+			ShapeProperties: sp,
+			// We want to specify `autofit.autofitType` (such as `SHAPE_AUTOFIT`), but we cannot specify it
+			// because there is a problem with the Google Slide API.
+			// See: https://issuetracker.google.com/issues/199176586
+			Fields: "shapeBackgroundFill,outline,shadow",
+		},
+	}
+}
+
+// BlockQuoteTextRequests returns requests to fill the empty text box identified by textBoxObjectID with bq.
+func (b *requestBuilder) BlockQuoteTextRequests(textBoxObjectID string, bq *BlockQuote) []*slides.Request {
+	reqs, styleReqs := b.ParagraphsRequests(textBoxObjectID, bq.Paragraphs)
+	requests := reqs
+
+	if s, ok := b.styles[b.blockQuoteStyleName(bq)]; ok {
+		r := buildCustomStyleRequest(s)
+		r.ObjectId = textBoxObjectID
+		requests = append(requests, &slides.Request{
+			UpdateTextStyle: r,
+		})
+	}
+
+	requests = append(requests, styleReqs...)
+
+	return append(requests, &slides.Request{
+		UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+			ObjectId:    textBoxObjectID,
+			Description: descriptionBlockquoteTextboxFromMarkdown,
+		},
+	})
+}
+
+// blockQuoteStyleName returns the name of the style for bq.
+// Nested block quotes use the style of their level such as "blockquote-2" if it is defined.
+// It falls back to "blockquote" when bq has no style name or the style is not defined.
+func (b *requestBuilder) blockQuoteStyleName(bq *BlockQuote) string {
+	if b.hasStyle(bq.StyleName) {
+		return bq.StyleName
+	}
+	if bq.Nesting > 0 {
+		if name := fmt.Sprintf("%s-%d", styleBlockQuote, bq.Nesting+1); b.hasStyle(name) {
+			return name
+		}
+	}
+	return styleBlockQuote
+}
+
+// hasStyle reports whether the text style or the shape properties named name are defined.
+func (b *requestBuilder) hasStyle(name string) bool {
+	if name == "" {
+		return false
+	}
+	_, hasStyle := b.styles[name]
+	_, hasShape := b.shapes[name]
+	return hasStyle || hasShape
+}
+
+// StyleRequest returns a request to apply the style named styleName.
+// Styles defined in the presentation take precedence over the default styles.
+// It returns nil if the style is not found.
+func (b *requestBuilder) StyleRequest(styleName string) *slides.UpdateTextStyleRequest {
+	if s, ok := b.styles[styleName]; ok {
+		return buildCustomStyleRequest(s)
+	}
+	if f, ok := defaultStyles[styleName]; ok {
+		return f()
+	}
+	return nil
+}
+
+// InlineStyleRequest returns a request to apply the inline styles of fragment.
+// ObjectId and TextRange of the returned request are not set.
+// It returns nil if fragment has no style.
+func (b *requestBuilder) InlineStyleRequest(fragment *Fragment) *slides.UpdateTextStyleRequest {
+	var reqs []*slides.UpdateTextStyleRequest
+
+	if fragment.Code {
+		reqs = append(reqs, b.StyleRequest(styleCode))
+	}
+
+	if fragment.Bold {
+		reqs = append(reqs, b.StyleRequest(styleBold))
+	}
+
+	if fragment.Italic {
+		reqs = append(reqs, b.StyleRequest(styleItalic))
+	}
+
+	if fragment.Underline {
+		reqs = append(reqs, underlineStyleFunc())
+	}
+
+	// The `mark` style defined in the presentation takes precedence over the highlight color.
+	if fragment.Highlight {
+		if s, ok := b.styles[styleMark]; ok {
+			reqs = append(reqs, buildCustomStyleRequest(s))
+		} else {
+			reqs = append(reqs, highlightStyleRequest(b.highlightColor))
+		}
+	}
+
+	if fragment.Link != "" {
+		s, ok := b.styles[styleLink]
+		if ok {
+			req := buildCustomStyleRequest(s)
+			req.Fields = "link,bold,italic,underline,foregroundColor,fontFamily,backgroundColor"
+			req.Style.Link = toLink(fragment.Link)
+			reqs = append(reqs, req)
+		} else {
+			reqs = append(reqs, &slides.UpdateTextStyleRequest{
+				Style: &slides.TextStyle{
+					Link: toLink(fragment.Link),
+				},
+				Fields: "link",
+			})
+		}
+	}
+
+	if fragment.StyleName != "" {
+		r := b.StyleRequest(fragment.StyleName)
+		if r != nil {
+			reqs = append(reqs, r)
+		}
+	}
+
+	// The color of the fragment takes precedence over the colors of the styles.
+	if fragment.Color != "" {
+		if c := rgbColor(fragment.Color); c != nil {
+			reqs = append(reqs, &slides.UpdateTextStyleRequest{
+				Style: &slides.TextStyle{
+					ForegroundColor: &slides.OptionalColor{
+						OpaqueColor: &slides.OpaqueColor{
+							RgbColor: c,
+						},
+					},
+				},
+				Fields: "foregroundColor",
+			})
+		}
+	}
+
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	var (
+		fields string
+		style  *slides.TextStyle
+	)
+	for _, r := range reqs {
+		// Merge elements with the latter taking priority.
+		fields = mergeFields(fields, r.Fields)
+		style = mergeStyles(style, r.Style, r.Fields)
+	}
+
+	return &slides.UpdateTextStyleRequest{
+		Style:  style,
+		Fields: fields,
+	}
+}
+
+// ParagraphsRequests returns requests to insert paragraphs into the shape identified by objectID.
+// reqs insert the text, and styleReqs apply inline styles, text directions and bullets to the inserted text.
+// styleReqs must be sent after reqs.
+func (b *requestBuilder) ParagraphsRequests(objectID string, paragraphs []*Paragraph) (reqs []*slides.Request, styleReqs []*slides.Request) {
+	bulletRanges := map[int]*bulletRange{}
+	count := int64(0)
+	var textBuilder strings.Builder
+	bulletStartIndex := int64(0) // reset per body
+	bulletEndIndex := int64(0)   // reset per body
+	currentBullet := BulletNone  // bullet of the list being built, BulletNone outside lists
+	var directions []*directionRange
+	for j, paragraph := range paragraphs {
+		plen := 0
+		var paragraphText strings.Builder
+		if paragraph.Bullet != BulletNone {
+			if paragraph.Nesting > 0 {
+				textBuilder.WriteString(strings.Repeat("\t", paragraph.Nesting))
+				plen += paragraph.Nesting
+			}
+		}
+		paragraphStart, fragmentReqs := count+int64(plen), len(styleReqs)
+		for _, fragment := range paragraph.Fragments {
+			// In Google Slides, pressing Enter creates a paragraph break, and pressing Shift + Enter
+			// creates an inline line break. The inline line break seems to be treated as a vertical
+			// tab around API data, so convert it to a vertical tab.
+			fValue := strings.ReplaceAll(fragment.Value, "\n", "\v")
+			flen := countString(fragment.Value)
+
+			if r := b.InlineStyleRequest(fragment); r != nil {
+				startIndex := count + int64(plen)
+				styleReqs = append(styleReqs, &slides.Request{
+					UpdateTextStyle: &slides.UpdateTextStyleRequest{
+						ObjectId: objectID,
+						Style:    r.Style,
+						Fields:   r.Fields,
+						TextRange: &slides.Range{
+							Type:       "FIXED_RANGE",
+							StartIndex: new(startIndex),
+							EndIndex:   new(startIndex + int64(flen)),
+						},
+					},
+				})
+			}
+			plen += flen
+			textBuilder.WriteString(fValue)
+			paragraphText.WriteString(fragment.Value)
+		}
+		if paragraph.StyleName != "" {
+			// The style of the paragraph is applied before the inline styles of its fragments, which take precedence.
+			styleReqs = slices.Insert(styleReqs, fragmentReqs, b.paragraphStyleRequests(objectID, paragraph.StyleName, paragraphStart, count+int64(plen))...)
+		}
+
+		if len(paragraphs) > j+1 {
+			textBuilder.WriteString("\n")
+			plen++
+		}
+
+		if paragraph.Bullet != BulletNone {
+			// A list spans from a top-level item to the next paragraph without a bullet, including nested items.
+			// Nested items are placed at the sub-levels of the list (e.g. a. and i. of NUMBERED_DIGIT_ALPHA_ROMAN),
+			// so that the numbering of the top-level items continues after nested lists of any bullet.
+			if currentBullet == BulletNone || (paragraph.Nesting == 0 && currentBullet != paragraph.Bullet) {
+				bulletStartIndex = count
+				bulletEndIndex = count
+				bulletRanges[int(bulletStartIndex)] = &bulletRange{
+					bullet: paragraph.Bullet,
+					start:  bulletStartIndex,
+					end:    bulletEndIndex,
+				}
+				currentBullet = paragraph.Bullet
+			}
+			bulletEndIndex += int64(plen)
+			bulletRanges[int(bulletStartIndex)].end = bulletEndIndex
+		} else {
+			currentBullet = BulletNone
+		}
+		directions = append(directions, &directionRange{
+			direction: paragraphDirection(b.direction, paragraphText.String()),
+			start:     count,
+			end:       count + int64(plen),
+		})
+		count += int64(plen)
+	}
+
+	reqs = append(reqs, &slides.Request{
+		InsertText: &slides.InsertTextRequest{
+			ObjectId: objectID,
+			Text:     textBuilder.String(),
+		},
+	})
+	// Directions are set before the bullets are created, since creating bullets removes the leading tabs.
+	styleReqs = append(styleReqs, b.directionRequests(objectID, directions)...)
+	var bulletRangeSlice []*bulletRange
+	for _, r := range bulletRanges {
+		bulletRangeSlice = append(bulletRangeSlice, r)
+	}
+	// reverse sort
+	// Because the Range changes each time it is converted to a list, convert from the end to a list.
+	sort.Slice(bulletRangeSlice, func(i, j int) bool {
+		return bulletRangeSlice[i].start > bulletRangeSlice[j].start
+	})
+	for _, r := range bulletRangeSlice {
+		startIndex := r.start
+		endIndex := r.end - 1
+		if startIndex <= endIndex {
+			endIndex++
+		}
+		styleReqs = append(styleReqs, &slides.Request{
+			CreateParagraphBullets: &slides.CreateParagraphBulletsRequest{
+				ObjectId:     objectID,
+				BulletPreset: convertBullet(r.bullet),
+				TextRange: &slides.Range{
+					Type:       "FIXED_RANGE",
+					StartIndex: new(startIndex),
+					EndIndex:   new(endIndex),
+				},
+			},
+		})
+	}
+
+	return reqs, styleReqs
+}
+
+// paragraphStyleRequests returns requests to apply the style named styleName to the text from start to end,
+// and the paragraph style of the style to the paragraphs of the text.
+// The default paragraph styles such as the style of citations are used if the style is not defined in the presentation.
+func (b *requestBuilder) paragraphStyleRequests(objectID, styleName string, start, end int64) []*slides.Request {
+	if start >= end {
+		return nil
+	}
+	var reqs []*slides.Request
+	r := b.StyleRequest(styleName)
+	s, ok := b.paragraphStyles[styleName]
+	if d, found := defaultParagraphStyles[styleName]; found && !b.hasStyle(styleName) {
+		r, s, ok = d.text(), d.paragraph, true
+	}
+	if r != nil {
+		r.ObjectId = objectID
+		r.TextRange = &slides.Range{
+			Type:       "FIXED_RANGE",
+			StartIndex: new(start),
+			EndIndex:   new(end),
+		}
+		reqs = append(reqs, &slides.Request{UpdateTextStyle: r})
+	}
+	if !ok || s == nil {
+		return reqs
+	}
+	style := &slides.ParagraphStyle{}
+	var fields []string
+	if s.Alignment != "" {
+		style.Alignment = s.Alignment
+		fields = append(fields, "alignment")
+	}
+	if s.LineSpacing != 0 {
+		style.LineSpacing = s.LineSpacing
+		fields = append(fields, "lineSpacing")
+	}
+	if s.SpaceAbove != nil {
+		style.SpaceAbove = s.SpaceAbove
+		fields = append(fields, "spaceAbove")
+	}
+	if s.SpaceBelow != nil {
+		style.SpaceBelow = s.SpaceBelow
+		fields = append(fields, "spaceBelow")
+	}
+	if len(fields) == 0 {
+		return reqs
+	}
+	return append(reqs, &slides.Request{
+		UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+			ObjectId: objectID,
+			Style:    style,
+			Fields:   strings.Join(fields, ","),
+			TextRange: &slides.Range{
+				Type:       "FIXED_RANGE",
+				StartIndex: new(start),
+				EndIndex:   new(end),
+			},
+		},
+	})
+}
+
+// TableStructureRequests returns requests to create only the table structure without content
+// on the page identified by pageObjectID.
+// index is the position of the table in the page and is used to offset the table.
+func (b *requestBuilder) TableStructureRequests(pageObjectID, tableObjectID string, table *Table, index int) []*slides.Request {
+	if len(table.Rows) == 0 {
+		return nil
+	}
+
+	// Calculate the number of rows and columns
+	rows := int64(len(table.Rows))
+	cols := int64(0)
+	for _, row := range table.Rows {
+		if int64(len(row.Cells)) > cols {
+			cols = int64(len(row.Cells))
+		}
+	}
+
+	if rows == 0 || cols == 0 {
+		return nil
+	}
+
+	// Create table request
+	createTableReq := &slides.CreateTableRequest{
+		ObjectId: tableObjectID,
+		ElementProperties: &slides.PageElementProperties{
+			PageObjectId: pageObjectID,
+			Size: &slides.Size{
+				Height: &slides.Dimension{
+					Magnitude: float64(rows * 100000), // 100,000 EMU per row
+					Unit:      "EMU",
+				},
+				Width: &slides.Dimension{
+					Magnitude: float64(cols * 1000000), // 1,000,000 EMU per column
+					Unit:      "EMU",
+				},
+			},
+			Transform: &slides.AffineTransform{
+				ScaleX:     1.0,
+				ScaleY:     1.0,
+				TranslateX: float64(index * 500000), // offset tables to avoid overlap
+				TranslateY: float64(index * 100000),
+				Unit:       "EMU",
+			},
+		},
+		Rows:    rows,
+		Columns: cols,
+	}
+
+	var requests []*slides.Request
+
+	// Create table
+	requests = append(requests, &slides.Request{
+		CreateTable: createTableReq,
+	})
+
+	// Set description to mark as markdown-generated table
+	requests = append(requests, &slides.Request{
+		UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+			ObjectId:    tableObjectID,
+			Title:       tableTitle(table),
+			Description: descriptionTableFromMarkdown,
+		},
+	})
+
+	return requests
+}
+
+// TableContentRequests returns requests to fill the content and styles of the table identified by tableObjectID.
+// The table must already exist and be empty.
+func (b *requestBuilder) TableContentRequests(tableObjectID string, table *Table) []*slides.Request {
+	var requests []*slides.Request
+
+	// Fill table cells with content
+	for rowIdx, row := range table.Rows {
+		for colIdx, cell := range row.Cells {
+			// Create text from fragments
+			var text strings.Builder
+			for _, fragment := range cell.Fragments {
+				text.WriteString(fragment.Value)
+			}
+
+			if text.String() == "" {
+				continue
+			}
+
+			cellLocation := &slides.TableCellLocation{
+				RowIndex:    int64(rowIdx),
+				ColumnIndex: int64(colIdx),
+			}
+
+			// Insert text into cell
+			requests = append(requests, &slides.Request{
+				InsertText: &slides.InsertTextRequest{
+					ObjectId:       tableObjectID,
+					CellLocation:   cellLocation,
+					Text:           text.String(),
+					InsertionIndex: 0,
+				},
+			})
+
+			// Apply base text style from tableStyle (before fragment styles)
+			textLength := int64(countString(text.String()))
+			if cellStyle := b.tableStyle.tableCellStyle(table, rowIdx, colIdx); cellStyle != nil && cellStyle.TextStyle != nil && textLength > 0 {
+				req := buildTableCellTextStyleRequest(cellStyle.TextStyle)
+				if req != nil {
+					requests = append(requests, &slides.Request{
+						UpdateTextStyle: &slides.UpdateTextStyleRequest{
+							ObjectId:     tableObjectID,
+							CellLocation: cellLocation,
+							Style:        req.Style,
+							TextRange: &slides.Range{
+								Type:       "FIXED_RANGE",
+								StartIndex: new(int64),
+								EndIndex:   new(textLength),
+							},
+							Fields: req.Fields,
+						},
+					})
+				}
+			}
+
+			// Apply formatting if needed
+			if len(cell.Fragments) > 0 {
+				startIndex := int64(0)
+				for _, fragment := range cell.Fragments {
+					flen := countString(fragment.Value)
+					if flen == 0 {
+						continue
+					}
+					endIndex := startIndex + int64(flen)
+
+					if r := b.InlineStyleRequest(fragment); r != nil {
+						requests = append(requests, &slides.Request{
+							UpdateTextStyle: &slides.UpdateTextStyleRequest{
+								ObjectId:     tableObjectID,
+								CellLocation: cellLocation,
+								Style:        r.Style,
+								TextRange: &slides.Range{
+									Type:       "FIXED_RANGE",
+									StartIndex: new(startIndex),
+									EndIndex:   new(endIndex),
+								},
+								Fields: r.Fields,
+							},
+						})
+					}
+					startIndex = endIndex
+				}
+			}
+
+			// Set text alignment and direction if specified.
+			// Alignments are relative to the direction, so START is right aligned in right-to-left cells.
+			style := &slides.ParagraphStyle{
+				Alignment: cell.Alignment,
+			}
+			var fields []string
+			if cell.Alignment != "" {
+				fields = append(fields, "alignment")
+			}
+			if direction := paragraphDirection(b.direction, text.String()); b.direction != "" || direction == "RIGHT_TO_LEFT" {
+				style.Direction = direction
+				fields = append(fields, "direction")
+			}
+			if len(fields) > 0 {
+				requests = append(requests, &slides.Request{
+					UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+						ObjectId:     tableObjectID,
+						CellLocation: cellLocation,
+						Style:        style,
+						Fields:       strings.Join(fields, ","),
+						TextRange: &slides.Range{
+							Type: "ALL",
+						},
+					},
+				})
+			}
+		}
+	}
+
+	// Apply cell styles from tableStyle
+	requests = append(requests, b.tableCellStyleRequests(tableObjectID, table)...)
+
+	// Apply border styles from tableStyle
+	requests = append(requests, b.tableBorderStyleRequests(tableObjectID, table)...)
+
+	return requests
+}
+
+// tableCellStyleRequests applies cell styles from b.tableStyle.
+func (b *requestBuilder) tableCellStyleRequests(tableObjectID string, table *Table) []*slides.Request {
+	var requests []*slides.Request
+
+	rows := len(table.Rows)
+	if rows == 0 {
+		return nil
+	}
+
+	cols := 0
+	for _, row := range table.Rows {
+		if len(row.Cells) > cols {
+			cols = len(row.Cells)
+		}
+	}
+
+	if cols == 0 {
+		return nil
+	}
+
+	for rowIdx := range rows {
+		for colIdx := range cols {
+			cellStyle := b.tableStyle.tableCellStyle(table, rowIdx, colIdx)
+			contentAlignment := ""
+			if cellStyle != nil {
+				contentAlignment = cellStyle.ContentAlignment
+			}
+			if row := table.Rows[rowIdx]; colIdx < len(row.Cells) && row.Cells[colIdx].VerticalAlignment != "" {
+				// The vertical alignment of the cell takes precedence over the table style.
+				contentAlignment = row.Cells[colIdx].VerticalAlignment
+			}
+			if cellStyle == nil {
+				cellStyle = &TableCellStyle{}
+			}
+
+			tableRange := &slides.TableRange{
+				Location: &slides.TableCellLocation{
+					RowIndex:    int64(rowIdx),
+					ColumnIndex: int64(colIdx),
+				},
+				RowSpan:    1,
+				ColumnSpan: 1,
+			}
+
+			// Apply background color and content alignment
+			if cellStyle.BackgroundFill != nil || contentAlignment != "" {
+				props := &slides.TableCellProperties{}
+				var fields []string
+
+				if cellStyle.BackgroundFill != nil {
+					props.TableCellBackgroundFill = cellStyle.BackgroundFill
+					fields = append(fields, "tableCellBackgroundFill")
+				}
+				if contentAlignment != "" {
+					props.ContentAlignment = contentAlignment
+					fields = append(fields, "contentAlignment")
+				}
+
+				requests = append(requests, &slides.Request{
+					UpdateTableCellProperties: &slides.UpdateTableCellPropertiesRequest{
+						ObjectId:            tableObjectID,
+						TableRange:          tableRange,
+						TableCellProperties: props,
+						Fields:              strings.Join(fields, ","),
+					},
+				})
+			}
+
+			// Apply paragraph style (horizontal alignment)
+			if cellStyle.ParagraphStyle != nil && cellStyle.ParagraphStyle.Alignment != "" {
+				requests = append(requests, &slides.Request{
+					UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+						ObjectId: tableObjectID,
+						CellLocation: &slides.TableCellLocation{
+							RowIndex:    int64(rowIdx),
+							ColumnIndex: int64(colIdx),
+						},
+						Style: &slides.ParagraphStyle{
+							Alignment: cellStyle.ParagraphStyle.Alignment,
+						},
+						Fields: "alignment",
+						TextRange: &slides.Range{
+							Type: "ALL",
+						},
+					},
+				})
+			}
+		}
+	}
+
+	return requests
+}
+
+// tableBorderStyleRequests applies border styles from b.tableStyle.BorderStyle.
+func (b *requestBuilder) tableBorderStyleRequests(tableObjectID string, table *Table) []*slides.Request {
+	if b.tableStyle == nil || b.tableStyle.BorderStyle == nil {
+		return nil
+	}
+
+	var requests []*slides.Request
+	bs := b.tableStyle.BorderStyle
+
+	rows := len(table.Rows)
+	if rows == 0 {
+		return nil
+	}
+
+	cols := 0
+	for _, row := range table.Rows {
+		if len(row.Cells) > cols {
+			cols = len(row.Cells)
+		}
+	}
+
+	if cols == 0 {
+		return nil
+	}
+
+	// Apply outer borders (top/bottom from OuterHorizontal, left/right from OuterVertical)
+	if bs.OuterHorizontal != nil {
+		outerH := prepareBorderProperties(bs.OuterHorizontal)
+		// Top border of entire table
+		requests = append(requests, &slides.Request{
+			UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
+				ObjectId:              tableObjectID,
+				BorderPosition:        "TOP",
+				TableBorderProperties: outerH,
+				Fields:                buildBorderFields(outerH),
+			},
+		})
+		// Bottom border of entire table
+		requests = append(requests, &slides.Request{
+			UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
+				ObjectId:              tableObjectID,
+				BorderPosition:        "BOTTOM",
+				TableBorderProperties: outerH,
+				Fields:                buildBorderFields(outerH),
+			},
+		})
+	}
+
+	if bs.OuterVertical != nil {
+		outerV := prepareBorderProperties(bs.OuterVertical)
+		// Left border of entire table
+		requests = append(requests, &slides.Request{
+			UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
+				ObjectId:              tableObjectID,
+				BorderPosition:        "LEFT",
+				TableBorderProperties: outerV,
+				Fields:                buildBorderFields(outerV),
+			},
+		})
+		// Right border of entire table
+		requests = append(requests, &slides.Request{
+			UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
+				ObjectId:              tableObjectID,
+				BorderPosition:        "RIGHT",
+				TableBorderProperties: outerV,
+				Fields:                buildBorderFields(outerV),
+			},
+		})
+	}
+
+	// Apply inner borders per cell based on position
+	for rowIdx := range rows {
+		for colIdx := range cols {
+			isHeaderRow := rowIdx == 0 && table.hasHeader()
+			isFirstCol := colIdx == 0
+			isLastRow := rowIdx == rows-1
+			isLastCol := colIdx == cols-1
+
+			tableRange := &slides.TableRange{
+				Location: &slides.TableCellLocation{
+					RowIndex:    int64(rowIdx),
+					ColumnIndex: int64(colIdx),
+				},
+				RowSpan:    1,
+				ColumnSpan: 1,
+			}
+
+			// Apply right border (skip outer right border)
+			if !isLastCol {
+				var srcProps *slides.TableBorderProperties
+				if isHeaderRow {
+					if isFirstCol {
+						srcProps = bs.HeaderFirstColRight
+					} else {
+						srcProps = bs.HeaderOtherColRight
+					}
+				} else {
+					if isFirstCol {
+						srcProps = bs.DataFirstColRight
+					} else {
+						srcProps = bs.DataOtherColRight
+					}
+				}
+
+				if srcProps != nil {
+					borderProps := prepareBorderProperties(srcProps)
+					requests = append(requests, &slides.Request{
+						UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
+							ObjectId:              tableObjectID,
+							TableRange:            tableRange,
+							BorderPosition:        "RIGHT",
+							TableBorderProperties: borderProps,
+							Fields:                buildBorderFields(borderProps),
+						},
+					})
+				}
+			}
+
+			// Apply bottom border (skip outer bottom border)
+			if !isLastRow {
+				var srcProps *slides.TableBorderProperties
+				if isHeaderRow {
+					if isFirstCol {
+						srcProps = bs.HeaderFirstColBottom
+					} else {
+						srcProps = bs.HeaderOtherColBottom
+					}
+				} else {
+					if isFirstCol {
+						srcProps = bs.DataFirstColBottom
+					} else {
+						srcProps = bs.DataOtherColBottom
+					}
+				}
+
+				if srcProps != nil {
+					borderProps := prepareBorderProperties(srcProps)
+					requests = append(requests, &slides.Request{
+						UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
+							ObjectId:              tableObjectID,
+							TableRange:            tableRange,
+							BorderPosition:        "BOTTOM",
+							TableBorderProperties: borderProps,
+							Fields:                buildBorderFields(borderProps),
+						},
+					})
+				}
+			}
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,258 @@
+package deck
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestParagraphsRequestsStyleName(t *testing.T) {
+	lead := &slides.TextStyle{Italic: true}
+	b := newRequestBuilder(
+		withTextStyle("lead", lead),
+		withParagraphStyle("lead", &slides.ParagraphStyle{
+			Alignment:  "CENTER",
+			SpaceBelow: &slides.Dimension{Magnitude: 12, Unit: "PT"},
+			Direction:  "LEFT_TO_RIGHT",
+		}),
+	)
+	paragraphs := []*Paragraph{
+		{Fragments: []*Fragment{{Value: "Hello"}}},
+		{Fragments: []*Fragment{{Value: "Lead "}, {Value: "bold", Bold: true}}, StyleName: "lead"},
+		{Fragments: []*Fragment{{Value: "Unknown"}}, StyleName: "unknown"},
+	}
+	_, got := b.ParagraphsRequests("shape", paragraphs)
+	r := func(start, end int64) *slides.Range {
+		return &slides.Range{Type: "FIXED_RANGE", StartIndex: new(start), EndIndex: new(end)}
+	}
+	leadStyle := buildCustomStyleRequest(lead)
+	bold := b.InlineStyleRequest(&Fragment{Value: "bold", Bold: true})
+	want := []*slides.Request{
+		{UpdateTextStyle: &slides.UpdateTextStyleRequest{
+			ObjectId:  "shape",
+			Style:     leadStyle.Style,
+			Fields:    leadStyle.Fields,
+			TextRange: r(6, 15),
+		}},
+		{UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+			ObjectId: "shape",
+			Style: &slides.ParagraphStyle{
+				Alignment:  "CENTER",
+				SpaceBelow: &slides.Dimension{Magnitude: 12, Unit: "PT"},
+			},
+			Fields:    "alignment,spaceBelow",
+			TextRange: r(6, 15),
+		}},
+		// The inline style of the fragment is applied after the style of the paragraph.
+		{UpdateTextStyle: &slides.UpdateTextStyleRequest{
+			ObjectId:  "shape",
+			Style:     bold.Style,
+			Fields:    bold.Fields,
+			TextRange: r(11, 15),
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestParagraphsRequestsBullets(t *testing.T) {
+	item := func(bullet Bullet, nesting int, text string) *Paragraph {
+		return NewBulletParagraph(bullet, nesting, text)
+	}
+	tests := []struct {
+		name       string
+		paragraphs []*Paragraph
+		want       []string
+	}{
+		{
+			"nested ordered list",
+			[]*Paragraph{item(BulletNumbered, 0, "A"), item(BulletNumbered, 1, "B"), item(BulletNumbered, 2, "C"), item(BulletNumbered, 0, "D")},
+			[]string{"NUMBERED_DIGIT_ALPHA_ROMAN 0-10"},
+		},
+		{
+			"ordered list continued after nested bullet list",
+			[]*Paragraph{item(BulletNumbered, 0, "A"), item(BulletDash, 1, "B"), item(BulletDash, 1, "C"), item(BulletNumbered, 0, "D")},
+			[]string{"NUMBERED_DIGIT_ALPHA_ROMAN 0-9"},
+		},
+		{
+			"bullet list with nested ordered list",
+			[]*Paragraph{item(BulletDash, 0, "A"), item(BulletNumbered, 1, "B"), item(BulletDash, 0, "C")},
+			[]string{"BULLET_DISC_CIRCLE_SQUARE 0-6"},
+		},
+		{
+			"top-level bullet changed",
+			[]*Paragraph{item(BulletNumbered, 0, "A"), item(BulletDash, 0, "B")},
+			[]string{"BULLET_DISC_CIRCLE_SQUARE 2-3", "NUMBERED_DIGIT_ALPHA_ROMAN 0-2"},
+		},
+		{
+			"lists separated by a paragraph",
+			[]*Paragraph{item(BulletNumbered, 0, "A"), NewParagraph("P"), item(BulletNumbered, 0, "C")},
+			[]string{"NUMBERED_DIGIT_ALPHA_ROMAN 4-5", "NUMBERED_DIGIT_ALPHA_ROMAN 0-2"},
+		},
+		{
+			"nested item after a paragraph",
+			[]*Paragraph{NewParagraph("P"), item(BulletDash, 1, "B"), item(BulletDash, 0, "C")},
+			[]string{"BULLET_DISC_CIRCLE_SQUARE 2-6"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, reqs := newRequestBuilder().ParagraphsRequests("shape", tt.paragraphs)
+			var got []string
+			for _, r := range reqs {
+				if r.CreateParagraphBullets == nil {
+					continue
+				}
+				got = append(got, fmt.Sprintf("%s %d-%d", r.CreateParagraphBullets.BulletPreset, *r.CreateParagraphBullets.TextRange.StartIndex, *r.CreateParagraphBullets.TextRange.EndIndex))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestInlineStyleRequestHighlight(t *testing.T) {
+	background := func(hex string) *slides.TextStyle {
+		return &slides.TextStyle{BackgroundColor: &slides.OptionalColor{OpaqueColor: &slides.OpaqueColor{RgbColor: rgbColor(hex)}}}
+	}
+	mark := &slides.TextStyle{BackgroundColor: background("#00ff00").BackgroundColor, Bold: true}
+	tests := []struct {
+		name string
+		opts []Option
+		b    func(d *Deck) *requestBuilder
+		want *slides.UpdateTextStyleRequest
+	}{
+		{
+			"default",
+			nil,
+			func(d *Deck) *requestBuilder { return d.requestBuilder() },
+			&slides.UpdateTextStyleRequest{Style: background(DefaultHighlightColor), Fields: "backgroundColor"},
+		},
+		{
+			"highlight color",
+			[]Option{WithHighlightColor("#FC0")},
+			func(d *Deck) *requestBuilder { return d.requestBuilder() },
+			&slides.UpdateTextStyleRequest{Style: background("#ffcc00"), Fields: "backgroundColor"},
+		},
+		{
+			"mark style",
+			[]Option{WithHighlightColor("#FC0")},
+			func(d *Deck) *requestBuilder { return d.requestBuilder(withTextStyle("mark", mark)) },
+			buildCustomStyleRequest(mark),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Deck{styles: map[string]*slides.TextStyle{}}
+			for _, opt := range tt.opts {
+				if err := opt(d); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := tt.b(d).InlineStyleRequest(&Fragment{Value: "marked", Highlight: true})
+			tt.want.Fields = mergeFields("", tt.want.Fields)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	if err := WithHighlightColor("not a color")(&Deck{}); err == nil {
+		t.Error("want error for an invalid highlight color")
+	}
+}
+
+func TestParagraphsRequestsCitation(t *testing.T) {
+	cite := &slides.TextStyle{Bold: true}
+	paragraphs := []*Paragraph{
+		{Fragments: []*Fragment{{Value: "Quote"}}},
+		{Fragments: []*Fragment{{Value: "— Author"}}, StyleName: styleCite},
+	}
+	r := &slides.Range{Type: "FIXED_RANGE", StartIndex: new(int64(6)), EndIndex: new(int64(14))}
+	tests := []struct {
+		name string
+		opts []requestBuilderOption
+		want []*slides.Request
+	}{
+		{
+			"default style",
+			nil,
+			[]*slides.Request{
+				{UpdateTextStyle: &slides.UpdateTextStyleRequest{
+					ObjectId:  "shape",
+					Style:     &slides.TextStyle{Italic: true, FontSize: &slides.Dimension{Magnitude: citeFontSize, Unit: "PT"}},
+					Fields:    "italic,fontSize",
+					TextRange: r,
+				}},
+				{UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+					ObjectId:  "shape",
+					Style:     &slides.ParagraphStyle{Alignment: "END"},
+					Fields:    "alignment",
+					TextRange: r,
+				}},
+			},
+		},
+		{
+			"style of the presentation",
+			[]requestBuilderOption{withTextStyle(styleCite, cite)},
+			[]*slides.Request{
+				{UpdateTextStyle: &slides.UpdateTextStyleRequest{
+					ObjectId:  "shape",
+					Style:     buildCustomStyleRequest(cite).Style,
+					Fields:    buildCustomStyleRequest(cite).Fields,
+					TextRange: r,
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := newRequestBuilder(tt.opts...).ParagraphsRequests("shape", paragraphs)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRequestsWideRunes(t *testing.T) {
+	tests := []struct {
+		name      string
+		fragments []*Fragment
+		wantStart int64
+		wantEnd   int64
+	}{
+		{"emoji", []*Fragment{{Value: "👍 "}, {Value: "ok", Bold: true}}, 3, 5},
+		{"emoji with skin tone", []*Fragment{{Value: "👍🏼"}, {Value: "ok", Bold: true}}, 4, 6},
+		{"ZWJ sequence", []*Fragment{{Value: "👨‍👩‍👧 "}, {Value: "family", Bold: true}}, 9, 15},
+		{"flags", []*Fragment{{Value: "🇯🇵"}, {Value: "🇺🇸", Bold: true}}, 4, 8},
+		{"combining accent", []*Fragment{{Value: "cafe\u0301 "}, {Value: "au lait", Bold: true}}, 6, 13},
+		{"CJK", []*Fragment{{Value: "日本語"}, {Value: "です", Bold: true}}, 3, 5},
+		{"CJK outside BMP", []*Fragment{{Value: "𠮷野家"}, {Value: "で", Bold: true}}, 4, 5},
+	}
+	boldRange := func(reqs []*slides.Request) []int64 {
+		for _, r := range reqs {
+			if r.UpdateTextStyle != nil && r.UpdateTextStyle.Style.Bold {
+				return []int64{*r.UpdateTextStyle.TextRange.StartIndex, *r.UpdateTextStyle.TextRange.EndIndex}
+			}
+		}
+		return nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []int64{tt.wantStart, tt.wantEnd}
+			b := newRequestBuilder()
+			_, got := b.ParagraphsRequests("shape", []*Paragraph{{Fragments: tt.fragments}})
+			if diff := cmp.Diff(want, boldRange(got)); diff != "" {
+				t.Errorf("paragraphs: %s", diff)
+			}
+			table := &Table{Rows: []*TableRow{{Cells: []*TableCell{{Fragments: tt.fragments}}}}}
+			if diff := cmp.Diff(want, boldRange(b.TableContentRequests("table", table))); diff != "" {
+				t.Errorf("table: %s", diff)
+			}
+		})
+	}
+}
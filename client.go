@@ -1,6 +1,7 @@
 package deck
 
 import (
+	"cmp"
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
@@ -22,6 +23,7 @@ import (
 	"github.com/pkg/browser"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
 	"google.golang.org/api/slides/v1"
 )
 
@@ -40,21 +42,49 @@ const (
 	// EnvAccessToken - Pre-existing OAuth2 access token for authentication.
 	// Useful for tasks >5 minutes since GitHub OIDC tokens expire in 5 minutes.
 	EnvAccessToken = "DECK_ACCESS_TOKEN"
+
+	// EnvImpersonateUser - Email address of the user to impersonate with a service account (domain-wide delegation).
+	EnvImpersonateUser = "DECK_IMPERSONATE_USER"
+
+	// EnvImpersonateServiceAccount - Email address of the service account to impersonate
+	// with Application Default Credentials (e.g. Workload Identity Federation).
+	// Setting it enables Application Default Credentials.
+	EnvImpersonateServiceAccount = "DECK_IMPERSONATE_SERVICE_ACCOUNT"
+
+	// EnvIsolateTokenCache - Enables caching OAuth2 tokens per presentation.
+	EnvIsolateTokenCache = "DECK_ISOLATE_TOKEN_CACHE"
 )
 
+var scopes = []string{
+	"https://www.googleapis.com/auth/presentations",
+	"https://www.googleapis.com/auth/drive",
+}
+
 var userAgent = "k1LoW-deck/" + version.Version + " (+https://github.com/k1LoW/deck)"
 
 // getHTTPClient returns the appropriate client option based on available credentials.
 func (d *Deck) getHTTPClient(ctx context.Context) (*http.Client, error) {
 	client, err := func(ctx context.Context) (*http.Client, error) {
+		impersonateUser := cmp.Or(d.impersonateUser, os.Getenv(EnvImpersonateUser))
 		if credsJSON := os.Getenv(EnvServiceAccountKey); credsJSON != "" {
-			d.logger.Debug("using service account key authentication")
-			return d.getServiceAccountHTTPClient(ctx, credsJSON)
+			d.logger.Debug("using service account key authentication", slog.Bool("impersonate", impersonateUser != ""))
+			return d.getServiceAccountHTTPClient(ctx, credsJSON, impersonateUser)
+		}
+		if sa := cmp.Or(d.impersonateServiceAccount, os.Getenv(EnvImpersonateServiceAccount)); sa != "" {
+			d.logger.Debug("using Application Default Credentials with service account impersonation")
+			ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+				TargetPrincipal: sa,
+				Scopes:          scopes,
+				Subject:         impersonateUser,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return oauth2.NewClient(ctx, ts), nil
 		}
 		if os.Getenv(EnvEnableADC) != "" {
 			d.logger.Debug("using Application Default Credentials")
-			return google.DefaultClient(ctx,
-				"https://www.googleapis.com/auth/presentations", "https://www.googleapis.com/auth/drive")
+			return google.DefaultClient(ctx, scopes...)
 		}
 		if token := os.Getenv(EnvAccessToken); token != "" {
 			d.logger.Debug("using access token authentication")
@@ -110,10 +140,7 @@ func (d *Deck) getDefaultHTTPClient(ctx context.Context) (_ *http.Client, err er
 		return nil, err
 	}
 
-	tokenPath := filepath.Join(config.StateHomePath(), "token.json")
-	if d.profile != "" {
-		tokenPath = filepath.Join(config.StateHomePath(), fmt.Sprintf("token-%s.json", d.profile))
-	}
+	tokenPath := d.tokenPath()
 	token, err := d.tokenFromFile(tokenPath)
 	if err != nil {
 		token, err = d.getTokenFromWeb(ctx, cfg)
@@ -160,6 +187,21 @@ func (d *Deck) getDefaultHTTPClient(ctx context.Context) (_ *http.Client, err er
 	return cfg.Client(ctx, token), nil
 }
 
+// tokenPath returns the path of the OAuth2 token cache.
+// With isolated token cache, tokens are cached per presentation.
+func (d *Deck) tokenPath() string {
+	name := "token"
+	if d.profile != "" {
+		name += "-" + d.profile
+	}
+	if d.isolateTokenCache || os.Getenv(EnvIsolateTokenCache) != "" {
+		if d.id != "" {
+			name += "-" + d.id
+		}
+	}
+	return filepath.Join(config.StateHomePath(), name+".json")
+}
+
 func (d *Deck) getTokenFromWeb(ctx context.Context, config *oauth2.Config) (_ *oauth2.Token, err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -305,14 +347,13 @@ func generateCodeChallenge(verifier string) string {
 }
 
 // getServiceAccountHTTPClient creates an HTTP client using service account credentials.
-func (d *Deck) getServiceAccountHTTPClient(ctx context.Context, credsJSON string) (*http.Client, error) {
-	config, err := google.JWTConfigFromJSON([]byte(credsJSON),
-		"https://www.googleapis.com/auth/presentations",
-		"https://www.googleapis.com/auth/drive",
-	)
+// If subject is not empty, the service account impersonates the user (domain-wide delegation).
+func (d *Deck) getServiceAccountHTTPClient(ctx context.Context, credsJSON, subject string) (*http.Client, error) {
+	config, err := google.JWTConfigFromJSON([]byte(credsJSON), scopes...)
 	if err != nil {
 		return nil, err
 	}
+	config.Subject = subject
 	return config.Client(ctx), nil
 }
 
@@ -22,8 +22,12 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -42,6 +46,7 @@ import (
 	"github.com/k1LoW/tail"
 	slogmulti "github.com/samber/slog-multi"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -52,36 +57,94 @@ var (
 	verbosity           int // 1: info, >=2: debug
 	logger              *slog.Logger
 	codeBlockToImageCmd string
+	noCodeImageCache    bool
 	applyFolderID       string
+	concurrency         int
+	notifyOwners        bool
+	changedOnly         bool
+	since               string
+	resume              bool
+	strict              bool
+	trustSource         bool
+	hotSwap             bool
+	allowDelete         bool
+	maxDeletions        int
+	printLinks          bool
+	linksFile           string
+	splitBy             string
+	asCopy              bool
+	watchDebounce       time.Duration
+	continueOnError     bool
+	pageTimeout         time.Duration
+	editRetries         int
+	verifyAfterApply    bool
+	applyOutput         string
 	tb                  = tail.New(30)
 )
 
 var applyCmd = &cobra.Command{
-	Use:   "apply DECK_FILE",
+	Use:   "apply DECK_FILE...",
 	Short: "apply desk written in markdown to Google Slides presentation",
-	Long:  `apply desk written in markdown to Google Slides presentation.`,
+	Long: `apply desk written in markdown to Google Slides presentation.
+
+Multiple markdown files are applied as one deck, concatenating their pages in the order of the arguments.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if page != "" && watch {
 			return fmt.Errorf("cannot use --page and --watch together")
 		}
-		if len(args) == 2 && presentationID != "" {
+		if changedOnly && (page != "" || watch) {
+			return fmt.Errorf("cannot use --changed-only with --page or --watch")
+		}
+		if since != "" && (page != "" || watch || changedOnly) {
+			return fmt.Errorf("cannot use --since with --page, --watch or --changed-only")
+		}
+		if watch && watchDebounce <= 0 {
+			return fmt.Errorf("--watch-debounce must be positive")
+		}
+		if resume && (page != "" || watch || changedOnly || since != "") {
+			return fmt.Errorf("cannot use --resume with --page, --watch, --changed-only or --since")
+		}
+		if asCopy && (watch || resume || splitBy != "") {
+			return fmt.Errorf("cannot use --as-copy with --watch, --resume or --split-by")
+		}
+		if verifyAfterApply && (watch || splitBy != "") {
+			return fmt.Errorf("cannot use --verify with --watch or --split-by")
+		}
+		if applyOutput != applyOutputText && applyOutput != applyOutputJSON {
+			return fmt.Errorf("invalid output: %s (text or json)", applyOutput)
+		}
+		if applyOutput == applyOutputJSON && (watch || splitBy != "") {
+			return fmt.Errorf("cannot use --output json with --watch or --split-by")
+		}
+		if deprecatedArgs(args) && presentationID != "" {
 			return fmt.Errorf("cannot use --presentation-id with two arguments")
 		}
-		return cobra.RangeArgs(1, 2)(cmd, args)
+		if len(args) > 1 && !deprecatedArgs(args) && (watch || changedOnly || since != "") {
+			return fmt.Errorf("cannot use --watch, --changed-only or --since with multiple markdown files")
+		}
+		if splitBy != "" {
+			if _, err := md.ParseSplitBy(splitBy); err != nil {
+				return err
+			}
+			if page != "" || watch || changedOnly || since != "" || resume || presentationID != "" || title != "" || linksFile != "" || deprecatedArgs(args) {
+				return fmt.Errorf("cannot use --split-by with --page, --watch, --changed-only, --since, --resume, --presentation-id, --title, --links-file or two arguments")
+			}
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		var f string
-
-		if len(args) == 1 {
-			// Single argument case: use frontmatter presentationID
-			f = args[0]
-		} else {
+		start := time.Now()
+		files := args
+		deprecated := deprecatedArgs(args)
+		if deprecated {
 			// Deprecated: Two argument case: traditional usage
 			cmd.Println(color.YellowString("WARNING: Two arguments are deprecated. Please use --presentation-id flag instead."))
 			presentationID = args[0]
-			f = args[1]
+			files = args[1:]
 		}
+		// The first file is the DECK_FILE of the deck, e.g. for the journal.
+		f := files[0]
 		cfg, err := config.Load(profile)
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
@@ -89,25 +152,25 @@ var applyCmd = &cobra.Command{
 
 		// Use flag applyFolderID if provided, otherwise use config folderID
 		targetFolderID := applyFolderID
-		if targetFolderID == "" && cfg.FolderID != "" {
-			targetFolderID = cfg.FolderID
+		if targetFolderID == "" {
+			targetFolderID = cfg.DefaultFolderID()
+		}
+		if isRemoteSource(f) && (watch || resume || since != "") {
+			return fmt.Errorf("cannot use --watch, --resume or --since with markdown from stdin or a URL")
 		}
-		m, err := md.ParseFile(f, cfg)
+		m, raw, baseDir, err := parseSources(ctx, files, cmd.InOrStdin(), cfg, trustSource, md.WithStrict(strict))
 		if err != nil {
 			return err
 		}
-		if len(args) == 1 {
+		if !deprecated {
 			if m.Frontmatter != nil {
 				if presentationID == "" && m.Frontmatter.PresentationID != "" {
 					presentationID = m.Frontmatter.PresentationID
 				}
-				if title == "" && m.Frontmatter.Title != "" {
-					title = m.Frontmatter.Title
-				}
 			}
 		}
 
-		if presentationID == "" {
+		if presentationID == "" && splitBy == "" {
 			return fmt.Errorf("presentation ID is required, please specify it with --presentation-id or in the frontmatter of the markdown file")
 		}
 
@@ -121,6 +184,18 @@ var applyCmd = &cobra.Command{
 		tailHandler := slog.NewJSONHandler(tb, &slog.HandlerOptions{
 			Level: slog.LevelDebug,
 		})
+		// With --output json, stdout is only for the result, and the logs and reports are written to stderr.
+		var (
+			logOut   io.Writer = os.Stdout
+			out                = cmd.OutOrStdout()
+			warnings           = &warningRecorder{}
+			handlers           = []slog.Handler{tailHandler}
+		)
+		if applyOutput == applyOutputJSON {
+			logOut = os.Stderr
+			out = cmd.ErrOrStderr()
+			handlers = append(handlers, warnings)
+		}
 		if verbosity > 0 {
 			var opt *slog.HandlerOptions
 			if verbosity > 1 {
@@ -130,66 +205,221 @@ var applyCmd = &cobra.Command{
 			}
 			logger = slog.New(
 				slogmulti.Fanout(
-					slog.NewJSONHandler(os.Stdout, opt),
-					tailHandler,
+					append([]slog.Handler{slog.NewJSONHandler(logOut, opt)}, handlers...)...,
 				),
 			)
 		} else {
-			h, err := dot.New(slog.NewTextHandler(os.Stdout, nil))
+			h, err := dot.New(slog.NewTextHandler(logOut, nil))
 			if err != nil {
 				return fmt.Errorf("failed to create dot handler: %w", err)
 			}
 			logger = slog.New(
 				slogmulti.Fanout(
-					h,
-					tailHandler,
+					append([]slog.Handler{h}, handlers...)...,
 				),
 			)
 		}
-		opts := []deck.Option{
-			deck.WithProfile(profile),
+		opts := append(authOptions(),
 			deck.WithPresentationID(presentationID),
 			deck.WithLogger(logger),
-		}
+		)
 		if targetFolderID != "" {
 			opts = append(opts, deck.WithFolderID(targetFolderID))
 		}
-		d, err := deck.New(ctx, opts...)
+		if concurrency == 0 {
+			concurrency = cfg.Concurrency
+		}
+		if concurrency != 0 {
+			opts = append(opts, deck.WithConcurrency(concurrency))
+		}
+		if notifyOwners {
+			opts = append(opts, deck.WithNotifyOwners(true))
+		}
+		if hotSwap {
+			opts = append(opts, deck.WithHotSwap(true))
+		}
+		if continueOnError {
+			opts = append(opts, deck.WithContinueOnError(true))
+		}
+		if pageTimeout != 0 {
+			opts = append(opts, deck.WithPageTimeout(pageTimeout))
+		}
+		opts = append(opts, deck.WithEditRetries(editRetries))
+		if !allowDelete {
+			opts = append(opts, deck.WithMaxDeletions(0))
+		} else if cmd.Flags().Changed("max-deletions") {
+			opts = append(opts, deck.WithMaxDeletions(maxDeletions))
+		} else if cfg.MaxDeletions != nil {
+			opts = append(opts, deck.WithMaxDeletions(*cfg.MaxDeletions))
+		}
+		uploadOpts, err := imageUploadOptions(cfg)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, uploadOpts...)
+		size, err := m.PageSize()
+		if err != nil {
+			return err
+		}
+		if size != nil {
+			opts = append(opts, deck.WithPageSize(size))
+		}
+		highlightColor, err := m.HighlightColor()
+		if err != nil {
+			return err
+		}
+		if highlightColor != "" {
+			opts = append(opts, deck.WithHighlightColor(highlightColor))
+		}
+		if splitBy != "" {
+			return applyParts(ctx, cmd, cfg, m, opts)
+		}
+		var journal *deck.Journal
+		if !watch && !asCopy && !isRemoteSource(f) {
+			// Record the progress so that an interrupted apply can be resumed with --resume.
+			if resume {
+				journal, err = loadJournalToResume(f)
+				if err != nil {
+					return err
+				}
+			} else {
+				journal = deck.NewJournal(journalPath(f), digest(raw))
+			}
+			opts = append(opts, deck.WithJournal(journal))
+		}
+		var d *deck.Deck
+		if asCopy {
+			// Apply to a copy of the presentation, leaving the presentation untouched.
+			d, err = deck.Copy(ctx, presentationID, "", opts...)
+		} else {
+			d, err = deck.New(ctx, opts...)
+		}
 		if err != nil {
 			if errors.Is(err, deck.HTTPClientError) {
 				cmd.Println(setupInstructionMessage)
 			}
 			return err
 		}
-		if title != "" {
-			if err := d.UpdateTitle(ctx, title); err != nil {
-				return err
+		if resume {
+			if err := journal.Verify(presentationID, d.RevisionID(), digest(raw)); err != nil {
+				return fmt.Errorf("cannot resume the interrupted apply: %w", err)
 			}
 		}
+		if err := updateMetadata(ctx, d, m); err != nil {
+			return err
+		}
 		if watch {
-			slides, err := m.ToSlides(ctx, codeBlockToImageCmd)
+			slides, err := m.ToSlides(ctx, codeBlockToImageCmd, toSlidesOptions()...)
 			if err != nil {
 				return fmt.Errorf("failed to convert markdown contents to slides: %w", err)
 			}
+			allPages, err := pageToPages("", len(slides))
+			if err != nil {
+				return err
+			}
+
+			if err := runHook(ctx, cfg, hookBeforeApply, presentationID, allPages, cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				return err
+			}
 			if err := d.Apply(ctx, slides); err != nil {
 				return err
 			}
 			logger.Info("initial apply completed", slog.String("presentation_id", presentationID))
+			if err := reportPageLinks(cmd.OutOrStdout(), d); err != nil {
+				return err
+			}
+			if err := saveSnapshot(presentationID, raw); err != nil {
+				return err
+			}
+			if err := runHook(ctx, cfg, hookAfterApply, presentationID, appliedPages(d), cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				return err
+			}
 
 			return watchFile(cmd.Context(), cfg, f, contents, d)
 		} else {
+			var timings applyTimings
+			// writeResult prints the result of the apply with --output json.
+			writeResult := func(err error) error {
+				if applyOutput != applyOutputJSON {
+					return nil
+				}
+				timings.TotalMS = elapsedMS(start)
+				return writeApplyResultJSON(cmd.OutOrStdout(), newApplyResult(d, timings, warnings.Messages(), err))
+			}
 			pages, err := pageToPages(page, len(contents))
 			if err != nil {
 				return err
 			}
-			slides, err := m.ToSlides(ctx, codeBlockToImageCmd)
+			if resume {
+				pages = journal.RemainingPages(len(contents))
+			}
+			if changedOnly {
+				pages, err = changedPagesSinceSnapshot(presentationID, baseDir, cfg, contents)
+				if err != nil {
+					return err
+				}
+				if len(pages) == 0 {
+					cmd.Println("no changes since the last apply")
+					return writeResult(nil)
+				}
+			}
+			if since != "" {
+				pages, err = changedPagesSinceRevision(since, f, baseDir, cfg, contents)
+				if err != nil {
+					return err
+				}
+				if len(pages) == 0 {
+					cmd.Printf("no changes since %s\n", since)
+					return writeResult(nil)
+				}
+			}
+			if asCopy {
+				// The hooks are run for the copy.
+				presentationID = d.ID()
+			}
+			convertStart := time.Now()
+			slides, err := m.ToSlides(ctx, codeBlockToImageCmd, toSlidesOptions()...)
 			if err != nil {
 				return fmt.Errorf("failed to convert markdown contents to slides: %w", err)
 			}
+			timings.ConvertMS = elapsedMS(convertStart)
+			if err := runHook(ctx, cfg, hookBeforeApply, presentationID, pages, out, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			applyStart := time.Now()
 			if err := d.ApplyPages(ctx, slides, pages); err != nil {
+				timings.ApplyMS = elapsedMS(applyStart)
+				if werr := writeResult(err); werr != nil {
+					logger.Error("failed to write the result", slog.String("error", werr.Error()))
+				}
 				return err
 			}
+			timings.ApplyMS = elapsedMS(applyStart)
 			logger.Info("apply completed", slog.String("presentation_id", presentationID), slog.Any("pages", pages))
+			cmd.PrintErrln("API usage:", d.APIUsage().String())
+			reportOwnedChanges(out, d)
+			if err := reportPageLinks(out, d); err != nil {
+				return err
+			}
+			if asCopy {
+				cmd.Println(deck.PresentationIDtoURL(d.ID()))
+			} else if len(files) == 1 && appliesAllPages(page, since, changedOnly, resume) {
+				// The snapshot of multiple files cannot be parsed as one markdown, so it is saved only for a file.
+				if err := saveSnapshot(presentationID, raw); err != nil {
+					return err
+				}
+			}
+			if err := runHook(ctx, cfg, hookAfterApply, presentationID, appliedPages(d), out, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			if verifyAfterApply {
+				if err := verifySlides(ctx, out, d, slides); err != nil {
+					return err
+				}
+			}
+			if err := writeResult(nil); err != nil {
+				return err
+			}
 		}
 		return nil
 	},
@@ -199,13 +429,159 @@ func init() {
 	rootCmd.AddCommand(applyCmd)
 	applyCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
 	applyCmd.Flags().StringVarP(&title, "title", "t", "", "title of the presentation")
-	applyCmd.Flags().StringVarP(&page, "page", "p", "", "page to apply")
+	applyCmd.Flags().StringVarP(&page, "page", "p", "", "pages to apply (e.g. 3,5-9,12). --pages is also accepted")
+	applyCmd.Flags().BoolVarP(&changedOnly, "changed-only", "", false, "apply only the pages changed since the last apply")
+	applyCmd.Flags().StringVarP(&since, "since", "", "", "apply only the pages changed since the git revision (e.g. HEAD~1). --from-rev is also accepted")
+	applyCmd.Flags().BoolVarP(&resume, "resume", "", false, "resume the interrupted apply from the journal")
+	applyCmd.Flags().BoolVarP(&strict, "strict", "", false, "fail on malformed frontmatter and page configs instead of ignoring them")
+	applyCmd.Flags().BoolVarP(&trustSource, "trust-source", "", false, "trust the markdown from stdin or a URL to run codeBlockToImageCommand of its frontmatter, expand its templates and read local files out of its directory")
+	applyCmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		switch name {
+		case "pages":
+			name = "page"
+		case "from-rev":
+			name = "since"
+		}
+		return pflag.NormalizedName(name)
+	})
 	applyCmd.Flags().StringVarP(&codeBlockToImageCmd, "code-block-to-image-command", "c", "", "command to convert code blocks to images")
+	applyCmd.Flags().BoolVarP(&noCodeImageCache, "no-code-image-cache", "", false, "run the command to convert code blocks to images without reusing cached images")
 	applyCmd.Flags().StringVarP(&applyFolderID, "folder-id", "", "", "folder id to upload temporary images to")
+	applyCmd.Flags().IntVarP(&concurrency, "concurrency", "", 0, "maximum number of concurrent image operations (default 4)")
+	applyCmd.Flags().BoolVarP(&notifyOwners, "notify-owners", "", false, "post a comment mentioning the owner when an owned page changes")
+	applyCmd.Flags().BoolVarP(&hotSwap, "hot-swap", "", false, "build updated pages on hidden duplicates and swap them into place to avoid flicker while presenting (the swapped pages get new object IDs)")
+	applyCmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "", false, "continue applying the remaining pages when some pages fail, and report the failed pages at the end")
+	applyCmd.Flags().DurationVarP(&pageTimeout, "page-timeout", "", 0, "time budget to prepare each page, including uploading its images (e.g. 30s). pages exceeding it fail")
+	applyCmd.Flags().IntVarP(&editRetries, "edit-retries", "", 2, "number of times to plan and apply again when the presentation is edited by others during apply. 0 aborts at the first edit")
+	applyCmd.Flags().BoolVarP(&verifyAfterApply, "verify", "", false, "verify that the presentation matches the markdown after applying, as deck verify does")
+	applyCmd.Flags().BoolVarP(&allowDelete, "allow-delete", "", true, "allow deleting pages. --allow-delete=false refuses to apply when pages would be deleted")
+	applyCmd.Flags().IntVarP(&maxDeletions, "max-deletions", "", 0, "refuse to apply when more pages than this would be deleted")
+	applyCmd.Flags().BoolVarP(&printLinks, "print-links", "", false, "print the URL of each page after applying")
+	applyCmd.Flags().StringVarP(&applyOutput, "output", "o", applyOutputText, "format of the result (text or json). json prints the result of the apply to stdout, and the logs to stderr")
+	applyCmd.Flags().StringVarP(&linksFile, "links-file", "", "", "write the URL of each page after applying to the JSON file")
+	applyCmd.Flags().StringVarP(&splitBy, "split-by", "", "", "apply each part of the markdown split by the heading level (e.g. h1) to the presentations listed in presentationIDs of the frontmatter")
+	applyCmd.Flags().BoolVarP(&asCopy, "as-copy", "", false, "copy the presentation and apply to the copy, leaving the presentation untouched, then print the URL of the copy")
 	applyCmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch for changes")
+	applyCmd.Flags().DurationVarP(&watchDebounce, "watch-debounce", "", time.Second, "wait until the file is not modified for this duration before applying in watch mode")
 	applyCmd.Flags().CountVarP(&verbosity, "verbose", "v", "verbose output (can be used multiple times for more verbosity)")
 }
 
+// reportOwnedChanges prints the changed pages that have an owner.
+func reportOwnedChanges(w io.Writer, d *deck.Deck) {
+	for _, c := range d.Changes() {
+		if c.Owner == "" {
+			continue
+		}
+		_, _ = fmt.Fprintln(w, c.String())
+	}
+}
+
+// reportPageLinks prints the URL of each page with --print-links and writes them to the file with --links-file.
+func reportPageLinks(w io.Writer, d *deck.Deck) error {
+	if !printLinks && linksFile == "" {
+		return nil
+	}
+	links := d.PageLinks()
+	if printLinks {
+		for _, l := range links {
+			_, _ = fmt.Fprintln(w, l.String())
+		}
+	}
+	if linksFile != "" {
+		b, err := json.MarshalIndent(links, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(linksFile, append(b, '\n'), 0600); err != nil {
+			return fmt.Errorf("failed to write page links: %w", err)
+		}
+	}
+	return nil
+}
+
+// imageUploadOptions returns deck options for the image upload pipeline from the config.
+func imageUploadOptions(cfg *config.Config) ([]deck.Option, error) {
+	if cfg.ImageUpload == nil {
+		return nil, nil
+	}
+	opts := []deck.Option{
+		deck.WithUploadParallelism(cfg.ImageUpload.Parallelism),
+		deck.WithHostParallelism(cfg.ImageUpload.PerHostParallelism),
+		deck.WithUploadBandwidth(cfg.ImageUpload.MaxBytesPerSecond),
+		deck.WithAdaptiveUpload(cfg.ImageUpload.Adaptive),
+		deck.WithImageFolderID(cfg.ImageUpload.FolderID),
+		deck.WithImageFileNamePrefix(cfg.ImageUpload.FileNamePrefix),
+		deck.WithKeepUploadedImages(cfg.ImageUpload.Keep),
+	}
+	if o := cfg.ImageUpload.Optimize; o != nil {
+		opts = append(opts, deck.WithImageOptimization(&deck.ImageOptimization{
+			MaxWidth:  o.MaxWidth,
+			MaxHeight: o.MaxHeight,
+			Format:    o.Format,
+			Quality:   o.Quality,
+		}))
+	}
+	if h := cfg.ImageUpload.Host; h != nil {
+		host, err := newImageHost(h)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image host: %w", err)
+		}
+		opts = append(opts, deck.WithImageHost(host))
+	}
+	return opts, nil
+}
+
+// newImageHost returns the image host of the config.
+func newImageHost(h *config.ImageHost) (deck.ImageHost, error) {
+	var expires time.Duration
+	if h.Expires != "" {
+		var err error
+		expires, err = time.ParseDuration(h.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expires: %w", err)
+		}
+	}
+	switch h.Type {
+	case "gcs":
+		f := h.CredentialsFile
+		if f == "" {
+			f = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+		}
+		if f == "" {
+			return nil, fmt.Errorf("credentialsFile or GOOGLE_APPLICATION_CREDENTIALS is required for gcs")
+		}
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		return deck.NewGCSImageHost(deck.GCSImageHostConfig{
+			Bucket:          h.Bucket,
+			Prefix:          h.Prefix,
+			CredentialsJSON: b,
+			Expires:         expires,
+		})
+	case "s3":
+		region := h.Region
+		if region == "" {
+			region = cmp.Or(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+		}
+		return deck.NewS3ImageHost(deck.S3ImageHostConfig{
+			Bucket:          h.Bucket,
+			Prefix:          h.Prefix,
+			Region:          region,
+			Endpoint:        h.Endpoint,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			Expires:         expires,
+		})
+	case "command":
+		return deck.NewCommandImageHost(h.BaseURL, h.Command, h.DeleteCommand)
+	default:
+		return nil, fmt.Errorf("unknown type: %q (gcs, s3 or command)", h.Type)
+	}
+}
+
 func pageToPages(page string, total int) ([]int, error) {
 	if page == "" {
 		// If no page is specified, return all pages
@@ -283,6 +659,8 @@ func pageToPages(page string, total int) ([]int, error) {
 }
 
 // watchFile watches for changes in the file and applies them to the presentation.
+// Rapid successive changes are coalesced until the file is quiet for watchDebounce, and an in-flight apply is
+// cancelled when it is superseded by newer content.
 func watchFile(ctx context.Context, cfg *config.Config, filePath string, oldContents md.Contents, d *deck.Deck) error {
 	// Get the absolute path of the file
 	absPath, err := filepath.Abs(filePath)
@@ -305,7 +683,13 @@ func watchFile(ctx context.Context, cfg *config.Config, filePath string, oldCont
 
 	logger.Info("watching for changes", slog.String("file", absPath))
 
-	var events []fsnotify.Event
+	var (
+		events   []fsnotify.Event
+		inflight *watchApply
+	)
+	defer func() {
+		inflight.supersede()
+	}()
 	for {
 		select {
 		case event, ok := <-watcher.Events:
@@ -323,7 +707,7 @@ func watchFile(ctx context.Context, cfg *config.Config, filePath string, oldCont
 
 		case <-ctx.Done():
 			return nil
-		case <-time.After(time.Second):
+		case <-time.After(watchDebounce):
 			fileModified := slices.ContainsFunc(events, func(event fsnotify.Event) bool {
 				return filepath.Base(event.Name) == fileName &&
 					(event.Op&fsnotify.Write == fsnotify.Write ||
@@ -335,11 +719,28 @@ func watchFile(ctx context.Context, cfg *config.Config, filePath string, oldCont
 			}
 			logger.Info("file modified", slog.String("file", fileName))
 
-			newMD, err := md.ParseFile(filePath, cfg)
+			raw, err := os.ReadFile(filePath)
+			if err != nil {
+				logger.Error("failed to read file", slog.String("error", err.Error()))
+				continue
+			}
+			if inflight.running(raw) {
+				// The content being applied has not changed (e.g. saved again without edits).
+				continue
+			}
+			newMD, err := md.ParseFile(filePath, cfg, md.WithStrict(strict))
 			if err != nil {
 				logger.Error("failed to parse file", slog.String("error", err.Error()))
 				continue
 			}
+
+			// Cancel the apply of the outdated content and apply the latest content instead.
+			if inflight.supersede() {
+				logger.Info("cancelled the apply superseded by newer changes")
+			}
+			if inflight != nil && inflight.applied != nil {
+				oldContents = inflight.applied
+			}
 			var newContents md.Contents
 			for _, content := range newMD.Contents {
 				if content.Ignore != nil && *content.Ignore {
@@ -355,23 +756,136 @@ func watchFile(ctx context.Context, cfg *config.Config, filePath string, oldCont
 			}
 
 			logger.Info("detected changes", slog.Any("pages", changedPages))
-			slides, err := newMD.ToSlides(ctx, codeBlockToImageCmd)
-			if err != nil {
-				logger.Error("failed to convert markdown contents to slides", slog.String("error", err.Error()))
-				continue
-			}
-			if err := d.ApplyPages(ctx, slides, changedPages); err != nil {
-				slogArgs := []any{slog.String("error", err.Error())}
-				if verbosity > 1 {
-					slogArgs = append(slogArgs, slog.String("stacktrace", errors.StackTraces(err).String()))
-				}
-				logger.Error("failed to apply changes", slogArgs...)
-				continue
-			}
+			inflight = startWatchApply(ctx, raw, newContents, func(ctx context.Context) bool {
+				return applyWatchedChanges(ctx, cfg, d, newMD, raw, changedPages)
+			})
+		}
+	}
+}
 
-			logger.Info("applied changes", slog.Any("pages", changedPages))
+// watchApply is an apply running in the background in watch mode.
+type watchApply struct {
+	raw      []byte
+	contents md.Contents // contents to apply
+	applied  md.Contents // contents if the apply succeeded
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
 
-			oldContents = newContents
+// startWatchApply runs apply of contents in the background. apply reports whether it succeeded.
+func startWatchApply(ctx context.Context, raw []byte, contents md.Contents, apply func(context.Context) bool) *watchApply {
+	ctx, cancel := context.WithCancel(ctx)
+	a := &watchApply{
+		raw:      raw,
+		contents: contents,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer close(a.done)
+		defer cancel()
+		if apply(ctx) {
+			a.applied = a.contents
 		}
+	}()
+	return a
+}
+
+// running reports whether the apply of raw is still running.
+func (a *watchApply) running(raw []byte) bool {
+	if a == nil {
+		return false
+	}
+	select {
+	case <-a.done:
+		return false
+	default:
+		return bytes.Equal(a.raw, raw)
+	}
+}
+
+// supersede cancels the apply and waits for it to finish.
+// It reports whether the apply was still running.
+func (a *watchApply) supersede() bool {
+	if a == nil {
+		return false
+	}
+	select {
+	case <-a.done:
+		return false
+	default:
+	}
+	a.cancel()
+	<-a.done
+	return true
+}
+
+// applyWatchedChanges applies the changed pages of the markdown in watch mode and reports whether it succeeded.
+func applyWatchedChanges(ctx context.Context, cfg *config.Config, d *deck.Deck, newMD *md.MD, raw []byte, changedPages []int) bool {
+	slides, err := newMD.ToSlides(ctx, codeBlockToImageCmd, toSlidesOptions()...)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("failed to convert markdown contents to slides", slog.String("error", err.Error()))
+		}
+		return false
+	}
+	if err := runHook(ctx, cfg, hookBeforeApply, d.ID(), changedPages, os.Stdout, os.Stderr); err != nil {
+		logger.Error("failed to run hook", slog.String("error", err.Error()))
+		return false
+	}
+	if err := d.ApplyPages(ctx, slides, changedPages); err != nil {
+		if ctx.Err() != nil {
+			// Superseded by newer changes. The pages are applied again with the latest content.
+			return false
+		}
+		slogArgs := []any{slog.String("error", err.Error())}
+		if verbosity > 1 {
+			slogArgs = append(slogArgs, slog.String("stacktrace", errors.StackTraces(err).String()))
+		}
+		logger.Error("failed to apply changes", slogArgs...)
+		return false
+	}
+
+	if err := updateMetadata(ctx, d, newMD); err != nil {
+		logger.Error("failed to update metadata", slog.String("error", err.Error()))
+		return false
+	}
+	logger.Info("applied changes", slog.Any("pages", changedPages))
+	reportOwnedChanges(os.Stdout, d)
+	if err := reportPageLinks(os.Stdout, d); err != nil {
+		logger.Error("failed to write page links", slog.String("error", err.Error()))
+	}
+	// The changed pages are the pages changed from the contents applied last, so all the pages of the markdown
+	// are applied once they have been applied.
+	if err := saveSnapshot(d.ID(), raw); err != nil {
+		logger.Error("failed to save snapshot", slog.String("error", err.Error()))
+	}
+	if err := runHook(ctx, cfg, hookAfterApply, d.ID(), appliedPages(d), os.Stdout, os.Stderr); err != nil {
+		logger.Error("failed to run hook", slog.String("error", err.Error()))
+	}
+	return true
+}
+
+// toSlidesOptions returns the options to convert markdown to slides.
+func toSlidesOptions() []md.ToSlidesOption {
+	if noCodeImageCache {
+		return nil
+	}
+	return []md.ToSlidesOption{md.WithCodeImageCacheDir(md.DefaultCodeImageCacheDir())}
+}
+
+// updateMetadata updates the title, description and properties of the presentation file
+// given by the frontmatter. The --title flag takes precedence over the title in the frontmatter.
+func updateMetadata(ctx context.Context, d *deck.Deck, m *md.MD) error {
+	meta, err := m.Metadata()
+	if err != nil {
+		return err
+	}
+	if title != "" {
+		meta.Title = title
+	}
+	if err := d.UpdateMetadata(ctx, meta); err != nil {
+		return fmt.Errorf("failed to update metadata: %w", err)
 	}
+	return nil
 }
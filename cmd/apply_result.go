@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/k1LoW/deck"
+)
+
+const (
+	applyOutputText = "text"
+	applyOutputJSON = "json"
+)
+
+// applyResult is the result of deck apply printed with --output json, so that automation such as notifiers
+// does not have to parse the logs.
+type applyResult struct {
+	PresentationID string             `json:"presentation_id"`
+	URL            string             `json:"url"`
+	Summary        deck.ApplySummary  `json:"summary"`
+	Changes        []*deck.PageChange `json:"changes,omitempty"`
+	Pages          []*deck.PageLink   `json:"pages,omitempty"`
+	Timings        applyTimings       `json:"timings"`
+	APIUsage       deck.APIUsage      `json:"api_usage"`
+	Warnings       []string           `json:"warnings,omitempty"`
+	Error          string             `json:"error,omitempty"`
+}
+
+// applyTimings is the time taken by the steps of deck apply in milliseconds.
+type applyTimings struct {
+	ConvertMS int64 `json:"convert_ms"` // converting the markdown to slides, including the images of code blocks
+	ApplyMS   int64 `json:"apply_ms"`   // applying the slides to the presentation
+	TotalMS   int64 `json:"total_ms"`   // the whole command
+}
+
+// newApplyResult returns the result of the last apply of d. err is the error of the apply, if any.
+func newApplyResult(d *deck.Deck, timings applyTimings, warnings []string, err error) *applyResult {
+	r := &applyResult{
+		PresentationID: d.ID(),
+		URL:            deck.PresentationIDtoURL(d.ID()),
+		Summary:        d.Summary(),
+		Changes:        d.Changes(),
+		Pages:          d.PageLinks(),
+		Timings:        timings,
+		APIUsage:       d.APIUsage(),
+		Warnings:       warnings,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+func writeApplyResultJSON(w io.Writer, r *applyResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// warningRecorder is a slog.Handler that records the messages of warnings and errors logged during apply.
+type warningRecorder struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (h *warningRecorder) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn
+}
+
+func (h *warningRecorder) Handle(_ context.Context, r slog.Record) error {
+	msg := r.Message
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "error" {
+			msg += ": " + a.Value.String()
+			return false
+		}
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, msg)
+	return nil
+}
+
+func (h *warningRecorder) WithAttrs(_ []slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *warningRecorder) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+// Messages returns the recorded messages.
+func (h *warningRecorder) Messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.messages...)
+}
+
+// elapsedMS returns the milliseconds elapsed since t.
+func elapsedMS(t time.Time) int64 {
+	return time.Since(t).Milliseconds()
+}
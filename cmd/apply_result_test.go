@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/fakeslides"
+)
+
+func TestApplyResult(t *testing.T) {
+	ctx := t.Context()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	d, err := deck.New(ctx, deck.WithEndpoint(srv.URL), deck.WithPresentationID(srv.CreatePresentation("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slides := deck.Slides{
+		deck.NewSlide("title-and-body", "A"),
+		deck.NewSlide("title-and-body", "B"),
+	}
+	if err := d.Apply(ctx, slides); err != nil {
+		t.Fatal(err)
+	}
+	slides[1] = deck.NewSlide("title-and-body", "C")
+	if err := d.Apply(ctx, slides); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	r := newApplyResult(d, applyTimings{TotalMS: 10}, []string{"page 2: warning"}, errors.New("failed"))
+	if err := writeApplyResultJSON(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["presentation_id"] != d.ID() || got["url"] != deck.PresentationIDtoURL(d.ID()) {
+		t.Errorf("unexpected presentation: %v %v", got["presentation_id"], got["url"])
+	}
+	want := map[string]any{"appended": 0.0, "updated": 1.0, "moved": 0.0, "deleted": 0.0}
+	if diff := cmp.Diff(want, got["summary"]); diff != "" {
+		t.Error(diff)
+	}
+	pages, ok := got["pages"].([]any)
+	if !ok || len(pages) != 2 {
+		t.Fatalf("unexpected pages: %v", got["pages"])
+	}
+	for _, p := range pages {
+		if p.(map[string]any)["objectID"] == "" {
+			t.Errorf("no object ID: %v", p)
+		}
+	}
+	if diff := cmp.Diff([]any{"page 2: warning"}, got["warnings"]); diff != "" {
+		t.Error(diff)
+	}
+	if got["error"] != "failed" {
+		t.Errorf("got error %v", got["error"])
+	}
+}
+
+func TestWarningRecorder(t *testing.T) {
+	h := &warningRecorder{}
+	l := slog.New(h).With(slog.String("presentation_id", "xxxxx"))
+	l.Info("applying actions")
+	l.Warn("page 1: title is empty", slog.Int("page", 1))
+	l.Error("failed to apply page", slog.Int("page", 2), slog.Any("error", errors.New("timeout")))
+	want := []string{"page 1: title is empty", "failed to apply page: timeout"}
+	if diff := cmp.Diff(want, h.Messages()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -59,7 +59,7 @@ var doctorCmd = &cobra.Command{
 		cmd.Print("🔐 Checking authentication ... ")
 
 		// Try to create deck client (this validates OAuth credentials)
-		err := deck.Doctor(ctx, deck.WithProfile(profile))
+		err := deck.Doctor(ctx, authOptions()...)
 		if err != nil {
 			cmd.Println(color.RedString("✗ AUTH FAILED"))
 			cmd.Printf("   Authentication error: %v\n", err)
@@ -71,10 +71,9 @@ var exportCmd = &cobra.Command{
 			out = "deck.pdf"
 		}
 
-		opts := []deck.Option{
-			deck.WithProfile(profile),
+		opts := append(authOptions(),
 			deck.WithPresentationID(presentationID),
-		}
+		)
 		d, err := deck.New(ctx, opts...)
 		if err != nil {
 			if errors.Is(err, deck.HTTPClientError) {
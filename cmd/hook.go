@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+)
+
+const (
+	hookBeforeApply = "beforeApply"
+	hookAfterApply  = "afterApply"
+)
+
+// runHook runs the hook command configured for name.
+// The presentation ID and the page numbers are passed as the environment variables
+// PRESENTATION_ID and CHANGED_PAGES (comma-separated).
+func runHook(ctx context.Context, cfg *config.Config, name, presentationID string, pages []int, stdout, stderr io.Writer) error {
+	if cfg == nil || cfg.Hooks == nil {
+		return nil
+	}
+	var command string
+	switch name {
+	case hookBeforeApply:
+		command = cfg.Hooks.BeforeApply
+	case hookAfterApply:
+		command = cfg.Hooks.AfterApply
+	}
+	if command == "" {
+		return nil
+	}
+	c := hookCommand(ctx, command)
+	c.Env = append(os.Environ(),
+		"PRESENTATION_ID="+presentationID,
+		"CHANGED_PAGES="+joinPages(pages),
+	)
+	c.Stdout = stdout
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to run %s hook: %w", name, err)
+	}
+	return nil
+}
+
+func hookCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/c", command)
+	}
+	sh := os.Getenv("SHELL")
+	if sh == "" {
+		sh = "sh"
+	}
+	return exec.CommandContext(ctx, sh, "-c", command) //nolint:gosec
+}
+
+// appliedPages returns the page numbers changed by the last apply.
+func appliedPages(d *deck.Deck) []int {
+	var pages []int
+	for _, c := range d.Changes() {
+		pages = append(pages, c.Page)
+	}
+	return pages
+}
+
+func joinPages(pages []int) string {
+	s := make([]string, len(pages))
+	for i, p := range pages {
+		s[i] = strconv.Itoa(p)
+	}
+	return strings.Join(s, ",")
+}
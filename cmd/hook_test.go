@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/k1LoW/deck/config"
+)
+
+func TestRunHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses POSIX shell syntax")
+	}
+	t.Setenv("SHELL", "sh")
+	cfg := &config.Config{
+		Hooks: &config.Hooks{
+			AfterApply: `echo "$PRESENTATION_ID:$CHANGED_PAGES"`,
+		},
+	}
+	var stdout, stderr bytes.Buffer
+	if err := runHook(t.Context(), cfg, hookAfterApply, "xxxxx", []int{1, 3}, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stdout.String(), "xxxxx:1,3\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// no hook configured
+	stdout.Reset()
+	if err := runHook(t.Context(), cfg, hookBeforeApply, "xxxxx", nil, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+
+	cfg.Hooks.BeforeApply = "exit 1"
+	if err := runHook(t.Context(), cfg, hookBeforeApply, "xxxxx", nil, &stdout, &stderr); err == nil {
+		t.Error("expected error from failing hook")
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/k1LoW/deck/md"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFrom   string
+	importOutput string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "convert markdown written for Marp or Slidev into markdown for deck",
+	Long: `convert markdown written for Marp or Slidev into markdown for deck.
+
+The frontmatter and per-slide directives of Marp and Slidev are mapped onto the frontmatter and page configs of deck:
+the class directive of Marp and the layout option of Slidev become the layout of the page, and hide or disabled of
+Slidev becomes skip. Warnings are printed for the directives that are not supported.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := md.ValidateImportFrom(importFrom); err != nil {
+			return err
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		result, err := md.Import(b, importFrom)
+		if err != nil {
+			return err
+		}
+		for _, w := range result.Warnings {
+			cmd.PrintErrln(color.YellowString("WARNING: %s", w))
+		}
+		if importOutput == "" {
+			_, err := cmd.OutOrStdout().Write(result.Markdown)
+			return err
+		}
+		if err := os.WriteFile(importOutput, result.Markdown, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", importOutput, err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVarP(&importFrom, "from", "", "", `format of the markdown: "marp" or "slidev" (default: detected from the frontmatter)`)
+	importCmd.Flags().StringVarP(&importOutput, "output", "o", "", "file to write the markdown to (default: stdout)")
+}
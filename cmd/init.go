@@ -0,0 +1,240 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/slides/v1"
+)
+
+const (
+	defaultInitMarkdownFile = "slide.md"
+	defaultInitTitle        = "Getting started with deck"
+)
+
+var (
+	initCredentials string
+	initForce       bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init [markdown-file]",
+	Short: "set up deck and create a sample presentation",
+	Long: `set up deck and create a sample presentation.
+
+deck init walks through the setup step by step:
+
+1. Writes the config file with all fields commented out, unless it already exists.
+2. Checks the OAuth client credentials. With --credentials, the downloaded credentials file is installed.
+3. Signs in to Google. A browser is opened for the consent on the first run.
+4. Creates a new presentation, or links the presentation of --presentation-id.
+5. Writes a sample markdown file (default: slide.md) demonstrating layouts, code blocks, tables and charts,
+   with the frontmatter of the presentation ID. Run deck apply with the file to see the slides.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		mdFile := defaultInitMarkdownFile
+		if len(args) > 0 {
+			mdFile = args[0]
+		}
+		if _, err := os.Stat(mdFile); err == nil && !initForce {
+			return fmt.Errorf("%s already exists. Use --force to overwrite it", mdFile)
+		}
+
+		// 1. Write config file
+		cmd.Print("🔧 Writing configuration file ... ")
+		cfgPath := config.Path(profile)
+		if _, err := os.Stat(cfgPath); err == nil && !initForce {
+			cmd.Println(color.GreenString("✓ ALREADY EXISTS"))
+		} else {
+			if err := writeFile(cfgPath, []byte(config.Template)); err != nil {
+				cmd.Println(color.RedString("✗ WRITE ERROR"))
+				return err
+			}
+			cmd.Println(color.GreenString("✓ CREATED"))
+		}
+		cmd.Printf("   Config file: %s\n", cfgPath)
+		cfg, err := config.Load(profile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// 2. Check credentials file
+		if oauthClientRequired() {
+			cmd.Print("🔍 Checking credentials file ... ")
+			credPath := deck.GetCredentialsPath(profile)
+			switch _, err := os.Stat(credPath); {
+			case initCredentials != "":
+				b, err := os.ReadFile(initCredentials)
+				if err != nil {
+					cmd.Println(color.RedString("✗ READ ERROR"))
+					return err
+				}
+				if _, err := google.ConfigFromJSON(b, slides.PresentationsScope, slides.DriveScope); err != nil {
+					cmd.Println(color.RedString("✗ INVALID OAUTH CONFIG"))
+					return fmt.Errorf("invalid credentials file %s: %w", initCredentials, err)
+				}
+				if err := writeFile(credPath, b); err != nil {
+					cmd.Println(color.RedString("✗ WRITE ERROR"))
+					return err
+				}
+				cmd.Println(color.GreenString("✓ INSTALLED"))
+			case os.IsNotExist(err):
+				cmd.Println(color.RedString("✗ NOT FOUND"))
+				cmd.Println()
+				showSetupHelp(cmd)
+				cmd.Println("Then run deck init again, or pass the downloaded file with --credentials.")
+				return nil
+			default:
+				cmd.Println(color.GreenString("✓ OK"))
+			}
+			cmd.Printf("   Credentials file: %s\n", credPath)
+		}
+
+		// 3. Sign in
+		cmd.Print("🔐 Signing in to Google ... ")
+		if err := deck.Doctor(ctx, authOptions()...); err != nil {
+			cmd.Println(color.RedString("✗ AUTH FAILED"))
+			cmd.Println(setupInstructionMessage)
+			return err
+		}
+		cmd.Println(color.GreenString("✓ OK"))
+
+		// 4. Create or link presentation
+		opts := authOptions()
+		var d *deck.Deck
+		if presentationID != "" {
+			cmd.Print("📄 Linking presentation ... ")
+			d, err = deck.New(ctx, append(opts, deck.WithPresentationID(presentationID))...)
+		} else {
+			cmd.Print("📄 Creating presentation ... ")
+			d, err = createInitPresentation(cmd, cfg, opts)
+		}
+		if err != nil {
+			cmd.Println(color.RedString("✗ FAILED"))
+			return err
+		}
+		cmd.Println(color.GreenString("✓ OK"))
+		cmd.Printf("   %s\n", deck.PresentationIDtoURL(d.ID()))
+
+		// 5. Write sample markdown
+		cmd.Print("📝 Writing sample markdown ... ")
+		placeholders := d.LayoutPlaceholders()
+		var layouts []md.ScaffoldLayout
+		for _, name := range d.ListLayouts() {
+			layouts = append(layouts, md.ScaffoldLayout{Name: name, Placeholders: placeholders[name]})
+		}
+		if err := os.WriteFile(mdFile, md.Sample(layouts), 0600); err != nil {
+			cmd.Println(color.RedString("✗ WRITE ERROR"))
+			return fmt.Errorf("failed to write %s: %w", mdFile, err)
+		}
+		if err := md.ApplyFrontmatterToMD(mdFile, title, d.ID()); err != nil {
+			cmd.Println(color.RedString("✗ WRITE ERROR"))
+			return err
+		}
+		cmd.Println(color.GreenString("✓ CREATED"))
+		cmd.Printf("   Markdown file: %s\n", mdFile)
+
+		cmd.Println()
+		cmd.Println("🎉 " + color.GreenString("deck is ready!"))
+		cmd.Println()
+		cmd.Println("Apply the sample markdown to the presentation and open it:")
+		cmd.Println(color.YellowString("  deck apply %s", mdFile))
+		cmd.Println(color.YellowString("  deck open %s", mdFile))
+		return nil
+	},
+}
+
+// oauthClientRequired reports whether the OAuth client credentials file is used for authentication,
+// that is, no other authentication is configured by the environment variables or flags.
+func oauthClientRequired() bool {
+	return os.Getenv(deck.EnvServiceAccountKey) == "" &&
+		os.Getenv(deck.EnvEnableADC) == "" &&
+		os.Getenv(deck.EnvAccessToken) == "" &&
+		os.Getenv(deck.EnvImpersonateServiceAccount) == "" &&
+		impersonateServiceAccount == ""
+}
+
+// createInitPresentation creates the presentation for deck init with the base presentation and the folder
+// of the flags or the config.
+func createInitPresentation(cmd *cobra.Command, cfg *config.Config, opts []deck.Option) (*deck.Deck, error) {
+	ctx := cmd.Context()
+	basePresentationID := cfg.BasePresentationID
+	if base != "" {
+		basePresentationID = base
+	}
+	if folderID == "" {
+		folderID = cfg.DefaultFolderID()
+	}
+	if folderID != "" {
+		opts = append(opts, deck.WithFolderID(folderID))
+	}
+	var (
+		d   *deck.Deck
+		err error
+	)
+	if basePresentationID != "" {
+		d, err = deck.CreateFrom(ctx, basePresentationID, opts...)
+	} else {
+		d, err = deck.Create(ctx, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		title = defaultInitTitle
+	}
+	if err := d.UpdateTitle(ctx, title); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// writeFile writes b to path, creating the directory of path if needed.
+func writeFile(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().StringVarP(&title, "title", "t", "", "title of the presentation (default: \""+defaultInitTitle+"\" for a new presentation)")
+	initCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "ID of an existing presentation to link instead of creating a new one")
+	initCmd.Flags().StringVarP(&base, "base", "b", "", "base presentation id that uses the theme you want to use")
+	initCmd.Flags().StringVarP(&folderID, "folder-id", "", "", "folder id to create the presentation in")
+	initCmd.Flags().StringVarP(&initCredentials, "credentials", "", "", "OAuth client credentials file downloaded from Google Cloud Console to install")
+	initCmd.Flags().BoolVarP(&initForce, "force", "", false, "overwrite the config file and the markdown file if they exist")
+}
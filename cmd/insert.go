@@ -0,0 +1,77 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	insertPage   int
+	insertLayout string
+	insertTitle  string
+)
+
+var insertCmd = &cobra.Command{
+	Use:   "insert [DECK_FILE]",
+	Short: "insert a page into the presentation",
+	Long:  `insert a page into the presentation without editing the markdown file.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		d, err := newDeckForPageCommand(cmd, args)
+		if err != nil {
+			return err
+		}
+		total := d.PageCount()
+		if insertPage == 0 {
+			insertPage = total + 1
+		}
+		if insertPage < 1 || insertPage > total+1 {
+			return fmt.Errorf("invalid page number: %d (must be between 1 and %d)", insertPage, total+1)
+		}
+		slide := newInsertSlide(insertLayout, insertTitle)
+		if insertPage == total+1 {
+			return d.AppendPage(ctx, slide)
+		}
+		return d.InsertPage(ctx, insertPage-1, slide)
+	},
+}
+
+// newInsertSlide returns the slide of the page to insert with the layout and the title, which may be empty.
+func newInsertSlide(layout, title string) *deck.Slide {
+	if title == "" {
+		return deck.NewSlide(layout)
+	}
+	return deck.NewSlide(layout, title)
+}
+
+func init() {
+	rootCmd.AddCommand(insertCmd)
+	insertCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	insertCmd.Flags().IntVarP(&insertPage, "page", "p", 0, "page number of the inserted page (default: append to the end)")
+	insertCmd.Flags().StringVarP(&insertLayout, "layout", "l", "", "layout of the inserted page (default: the default layout of the presentation)")
+	insertCmd.Flags().StringVarP(&insertTitle, "title", "t", "", "title of the inserted page")
+}
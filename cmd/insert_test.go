@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/fakeslides"
+)
+
+func TestInsertSlideTitle(t *testing.T) {
+	ctx := t.Context()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	id := srv.CreatePresentation("test")
+	d, err := deck.New(ctx, deck.WithEndpoint(srv.URL), deck.WithPresentationID(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AppendPage(ctx, newInsertSlide("title-and-body", "Appended")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InsertPage(ctx, 0, newInsertSlide("title-and-body", "Inserted")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AppendPage(ctx, newInsertSlide("title-and-body", "")); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range srv.Presentation(id).Slides {
+		var title strings.Builder
+		for _, e := range s.PageElements {
+			if e.Shape == nil || e.Shape.Placeholder == nil || e.Shape.Placeholder.Type != "TITLE" || e.Shape.Text == nil {
+				continue
+			}
+			for _, te := range e.Shape.Text.TextElements {
+				if te.TextRun != nil {
+					title.WriteString(te.TextRun.Content)
+				}
+			}
+		}
+		got = append(got, strings.TrimSpace(title.String()))
+	}
+	want := []string{"Inserted", "", "Appended", ""}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("titles of the pages mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/md"
+	"github.com/k1LoW/errors"
+	"github.com/spf13/cobra"
+)
+
+var layoutsOutput string
+
+var layoutsCmd = &cobra.Command{
+	Use:   "layouts",
+	Short: "work with layouts of Google Slides presentation",
+	Long:  `work with layouts of Google Slides presentation.`,
+}
+
+var layoutsExportCmd = &cobra.Command{
+	Use:   "export [DECK_FILE]",
+	Short: "generate markdown with an example page per layout",
+	Long: `generate markdown with an example page per layout of Google Slides presentation.
+
+Each page has the layout in its page config, and headings and bodies pre-filled with placeholder text
+for the title, subtitle and body placeholders of the layout, to show how the structure of markdown maps to each layout.
+To see the pages in a new presentation with the theme of the presentation, run deck new with --base.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if len(args) > 0 {
+			markdownData, err := md.ParseFile(args[0], nil)
+			if err != nil {
+				return err
+			}
+			if presentationID == "" && markdownData.Frontmatter != nil && markdownData.Frontmatter.PresentationID != "" {
+				presentationID = markdownData.Frontmatter.PresentationID
+			}
+		}
+		if presentationID == "" {
+			return fmt.Errorf("presentation ID is required. Use --presentation-id or set it in the frontmatter of the markdown file")
+		}
+		opts := append(authOptions(),
+			deck.WithPresentationID(presentationID),
+		)
+		d, err := deck.New(ctx, opts...)
+		if err != nil {
+			if errors.Is(err, deck.HTTPClientError) {
+				cmd.Println(setupInstructionMessage)
+			}
+			return err
+		}
+		placeholders := d.LayoutPlaceholders()
+		var layouts []md.ScaffoldLayout
+		for _, name := range d.ListLayouts() {
+			layouts = append(layouts, md.ScaffoldLayout{Name: name, Placeholders: placeholders[name]})
+		}
+		b := md.Scaffold(layouts)
+		if layoutsOutput == "" {
+			_, err := cmd.OutOrStdout().Write(b)
+			return err
+		}
+		if err := os.WriteFile(layoutsOutput, b, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", layoutsOutput, err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(layoutsCmd)
+	layoutsCmd.AddCommand(layoutsExportCmd)
+	layoutsExportCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	layoutsExportCmd.Flags().StringVarP(&layoutsOutput, "output", "o", "", "file to write the markdown to (default: stdout)")
+}
@@ -33,9 +33,7 @@ var lsCmd = &cobra.Command{
 	Short: "list Google Slides presentations",
 	Long:  `list Google Slides presentations.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		opts := []deck.Option{
-			deck.WithProfile(profile),
-		}
+		opts := authOptions()
 		slides, err := deck.List(cmd.Context(), opts...)
 		if err != nil {
 			return err
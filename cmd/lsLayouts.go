@@ -54,10 +54,9 @@ var lsLayoutsCmd = &cobra.Command{
 		if presentationID == "" {
 			return fmt.Errorf("presentation ID is required. Use --presentation-id or set it in the frontmatter of the markdown file")
 		}
-		opts := []deck.Option{
-			deck.WithProfile(profile),
+		opts := append(authOptions(),
 			deck.WithPresentationID(presentationID),
-		}
+		)
 		d, err := deck.New(ctx, opts...)
 		if err != nil {
 			if errors.Is(err, deck.HTTPClientError) {
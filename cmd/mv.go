@@ -0,0 +1,66 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck/config"
+	"github.com/spf13/cobra"
+)
+
+var mvFolderID string
+
+var mvCmd = &cobra.Command{
+	Use:   "mv [DECK_FILE]",
+	Short: "move the presentation to a folder",
+	Long: `move the presentation to a folder.
+
+The folder can be in a shared drive. The ID of a shared drive moves the presentation to the root folder of the shared drive.
+If --folder-id is not specified, folderID (or driveFolderID) in config.yml is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		folderID := mvFolderID
+		if folderID == "" {
+			cfg, err := config.Load(profile)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			folderID = cfg.DefaultFolderID()
+		}
+		if folderID == "" {
+			return fmt.Errorf("folder ID is required. Use --folder-id or set folderID in config.yml")
+		}
+		d, err := newDeckForPageCommand(cmd, args)
+		if err != nil {
+			return err
+		}
+		return d.MoveToFolder(ctx, folderID)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mvCmd)
+	mvCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	mvCmd.Flags().StringVarP(&mvFolderID, "folder-id", "", "", "folder ID (or shared drive ID) to move the presentation to")
+}
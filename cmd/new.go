@@ -36,6 +36,7 @@ var (
 	base     string
 	from     string
 	folderID string
+	pageSize string
 )
 
 var newCmd = &cobra.Command{
@@ -44,7 +45,10 @@ var newCmd = &cobra.Command{
 	Long: `create new presentation.
 
 If a markdown file is specified, frontmatter with title and presentationID will be added to the file.
-If the file doesn't exist, it will be created.`,
+If the file doesn't exist, it will be created.
+
+The presentation is created with the page size of --page-size or pageSize in the frontmatter of the file.
+With --base, the page size of the base presentation is validated instead, since it cannot be changed.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
@@ -63,15 +67,25 @@ If the file doesn't exist, it will be created.`,
 			basePresentationID = base
 		}
 		if folderID == "" {
-			folderID = cfg.FolderID
+			folderID = cfg.DefaultFolderID()
 		}
 
-		opts := []deck.Option{
-			deck.WithProfile(profile),
-		}
+		opts := authOptions()
 		if folderID != "" {
 			opts = append(opts, deck.WithFolderID(folderID))
 		}
+		var size *deck.PageSize
+		if pageSize != "" {
+			size, err = deck.ParsePageSize(pageSize)
+		} else if len(args) > 0 {
+			size, err = md.ReadPageSize(args[0])
+		}
+		if err != nil {
+			return err
+		}
+		if size != nil {
+			opts = append(opts, deck.WithPageSize(size))
+		}
 		d, err := func() (*deck.Deck, error) {
 			if basePresentationID != "" {
 				return deck.CreateFrom(ctx, basePresentationID, opts...)
@@ -112,4 +126,5 @@ func init() {
 	newCmd.Flags().StringVarP(&base, "base", "b", "", "base presentation id that uses the theme you want to use")
 	newCmd.Flags().StringVarP(&from, "from", "f", "", "(DEPRECATED, use --base/-b) presentation id that uses the theme you want to use")
 	newCmd.Flags().StringVarP(&folderID, "folder-id", "", "", "folder id to create the presentation in")
+	newCmd.Flags().StringVarP(&pageSize, "page-size", "", "", "page size of the presentation (16:9, 4:3 or custom(w,h) in points)")
 }
@@ -0,0 +1,176 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+	"github.com/spf13/cobra"
+)
+
+var notesCmd = &cobra.Command{
+	Use:   "notes",
+	Short: "sync speaker notes between the markdown file and Google Slides presentation",
+	Long:  `sync speaker notes between the markdown file and Google Slides presentation.`,
+}
+
+var notesApplyCmd = &cobra.Command{
+	Use:   "apply DECK_FILE",
+	Short: "apply only the speaker notes of the markdown file to the presentation",
+	Long: `apply only the speaker notes of the markdown file to the presentation.
+
+The speaker notes of the pages are rewritten, and the layouts, texts and images of the pages are left untouched,
+so it is faster than deck apply when only the speaker notes are edited. The pages of the markdown file must
+correspond to the pages of the presentation, as applied by deck apply.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := config.Load(profile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		m, err := md.ParseFile(args[0], cfg)
+		if err != nil {
+			return err
+		}
+		if presentationID == "" && m.Frontmatter != nil {
+			presentationID = m.Frontmatter.PresentationID
+		}
+		if presentationID == "" {
+			return fmt.Errorf("presentation ID is required, please specify it with --presentation-id or in the frontmatter of the markdown file")
+		}
+		// Code blocks are not converted to images, because only the speaker notes are applied.
+		if m.Frontmatter != nil {
+			m.Frontmatter.CodeBlockToImageCommand = ""
+		}
+		slides, err := m.ToSlides(ctx, "")
+		if err != nil {
+			return fmt.Errorf("failed to convert markdown contents to slides: %w", err)
+		}
+		d, err := newNotesDeck(cmd)
+		if err != nil {
+			return err
+		}
+		if err := d.ApplySpeakerNotes(ctx, slides); err != nil {
+			return err
+		}
+		pages := appliedPages(d)
+		if len(pages) == 0 {
+			cmd.Println("No speaker notes to apply.")
+			return nil
+		}
+		cmd.Printf("Applied speaker notes of pages: %v\n", pages)
+		return nil
+	},
+}
+
+var notesPullCmd = &cobra.Command{
+	Use:   "pull DECK_FILE",
+	Short: "take the speaker notes of the presentation into the markdown file",
+	Long: `take the speaker notes of the presentation into the markdown file.
+
+The speaker notes edited in the presentation, e.g. by reviewers, replace the comments of the pages of the
+markdown file, and the rest of the pages are left as they are. Pages whose speaker notes are written with ???,
+<details> or tagged notes are skipped and reported. Ignored and frozen pages are skipped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		f := args[0]
+		cfg, err := config.Load(profile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		local, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return err
+		}
+		baseDir := filepath.Dir(abs)
+		m, err := md.Parse(baseDir, local, cfg)
+		if err != nil {
+			return err
+		}
+		if presentationID == "" && m.Frontmatter != nil {
+			presentationID = m.Frontmatter.PresentationID
+		}
+		if presentationID == "" {
+			return fmt.Errorf("presentation ID is required, please specify it with --presentation-id or in the frontmatter of the markdown file")
+		}
+		d, err := newNotesDeck(cmd)
+		if err != nil {
+			return err
+		}
+		remote, err := d.DumpSlides(ctx)
+		if err != nil {
+			return err
+		}
+		result, err := md.PullSpeakerNotes(ctx, baseDir, local, remote, cfg)
+		if err != nil {
+			return err
+		}
+		if len(result.Skipped) > 0 {
+			cmd.Printf("Skipped pages with speaker notes not written in plain comments: %v\n", result.Skipped)
+		}
+		if len(result.Updated) == 0 {
+			cmd.Println("Already up to date.")
+			return nil
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(f, result.Markdown, info.Mode().Perm()); err != nil {
+			return err
+		}
+		cmd.Printf("Updated speaker notes of pages: %v\n", result.Updated)
+		return nil
+	},
+}
+
+// newNotesDeck returns the deck of the presentation for the notes commands.
+func newNotesDeck(cmd *cobra.Command) (*deck.Deck, error) {
+	d, err := deck.New(cmd.Context(), append(authOptions(), deck.WithPresentationID(presentationID))...)
+	if err != nil {
+		if errors.Is(err, deck.HTTPClientError) {
+			cmd.Println(setupInstructionMessage)
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+func init() {
+	rootCmd.AddCommand(notesCmd)
+	notesCmd.AddCommand(notesApplyCmd)
+	notesCmd.AddCommand(notesPullCmd)
+	notesApplyCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	notesPullCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+}
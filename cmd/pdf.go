@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/md"
+	"github.com/k1LoW/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pdfDeckFile    string
+	pdfPage        string
+	pdfWait        bool
+	pdfWaitTimeout time.Duration
+)
+
+var pdfCmd = &cobra.Command{
+	Use:   "pdf OUT_FILE",
+	Short: "export the presentation as PDF",
+	Long:  `export the presentation as PDF.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if pdfDeckFile != "" && presentationID == "" {
+			markdownData, err := md.ParseFile(pdfDeckFile, nil)
+			if err != nil {
+				return err
+			}
+			if markdownData.Frontmatter != nil {
+				presentationID = markdownData.Frontmatter.PresentationID
+			}
+		}
+		if presentationID == "" {
+			return fmt.Errorf("presentation ID is required. Use --presentation-id or --file with the presentation ID in the frontmatter")
+		}
+
+		opts := append(authOptions(),
+			deck.WithPresentationID(presentationID),
+		)
+		d, err := deck.New(ctx, opts...)
+		if err != nil {
+			if errors.Is(err, deck.HTTPClientError) {
+				cmd.Println(setupInstructionMessage)
+			}
+			return err
+		}
+		if pdfWait {
+			wctx, cancel := context.WithTimeout(ctx, pdfWaitTimeout)
+			defer cancel()
+			if _, err := d.WaitForStableRevision(wctx, 5*time.Second); err != nil {
+				return fmt.Errorf("failed to wait for the revision to be stable: %w", err)
+			}
+		}
+		var pages []int
+		if pdfPage != "" {
+			pages, err = pageToPages(pdfPage, d.PageCount())
+			if err != nil {
+				return err
+			}
+		}
+		return writeFileAtomically(args[0], func(w io.Writer) error {
+			return d.ExportPages(ctx, w, pages)
+		})
+	},
+}
+
+// writeFileAtomically writes the file name with write via a temporary file in the same directory,
+// so that the file is neither truncated nor left partially written if write fails.
+func writeFileAtomically(name string, write func(w io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(name), fmt.Sprintf(".%s.tmp-*", filepath.Base(name)))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	// os.CreateTemp creates the file readable only by the owner.
+	if err := f.Chmod(0644); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+func init() {
+	rootCmd.AddCommand(pdfCmd)
+	pdfCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	pdfCmd.Flags().StringVarP(&pdfDeckFile, "file", "f", "", "markdown file to read the presentation ID from")
+	pdfCmd.Flags().StringVarP(&pdfPage, "page", "p", "", "pages to export (e.g. 3,5-9,12)")
+	pdfCmd.Flags().BoolVarP(&pdfWait, "wait", "", false, "wait until the revision of the presentation stops changing before exporting")
+	pdfCmd.Flags().DurationVarP(&pdfWaitTimeout, "wait-timeout", "", 2*time.Minute, "timeout for --wait")
+}
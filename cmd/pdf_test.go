@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomically(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing bool
+		writeErr error
+		want     string
+		wantFile bool
+	}{
+		{"new file", false, nil, "new", true},
+		{"replace file", true, nil, "new", true},
+		{"failed export keeps file", true, errors.New("export failed"), "old", true},
+		{"failed export creates no file", false, errors.New("export failed"), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			p := filepath.Join(dir, "out.pdf")
+			if tt.existing {
+				if err := os.WriteFile(p, []byte("old"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := writeFileAtomically(p, func(w io.Writer) error {
+				if _, err := io.WriteString(w, "new"); err != nil {
+					return err
+				}
+				return tt.writeErr
+			})
+			if !errors.Is(err, tt.writeErr) {
+				t.Errorf("got error %v, want %v", err, tt.writeErr)
+			}
+			b, err := os.ReadFile(p)
+			if tt.wantFile != (err == nil) {
+				t.Fatalf("unexpected file state: %v", err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %q, want %q", b, tt.want)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 0
+			if tt.wantFile {
+				want = 1
+			}
+			if len(entries) != want {
+				t.Errorf("temporary files are left: %v", entries)
+			}
+		})
+	}
+}
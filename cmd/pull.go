@@ -0,0 +1,119 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+	"github.com/spf13/cobra"
+)
+
+var pullCmd = &cobra.Command{
+	Use:   "pull DECK_FILE",
+	Short: "merge edits made in the presentation into the markdown file",
+	Long: `merge edits made in the presentation into the markdown file.
+
+Pages edited in the presentation since the last apply are merged into the markdown file with a 3-way merge,
+using the markdown last applied as the base. Pages edited both in the presentation and in the markdown file
+are marked with conflict markers.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		f := args[0]
+		cfg, err := config.Load(profile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		local, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return err
+		}
+		baseDir := filepath.Dir(abs)
+		m, err := md.Parse(baseDir, local, cfg)
+		if err != nil {
+			return err
+		}
+		if presentationID == "" && m.Frontmatter != nil {
+			presentationID = m.Frontmatter.PresentationID
+		}
+		if presentationID == "" {
+			return fmt.Errorf("presentation ID is required, please specify it with --presentation-id or in the frontmatter of the markdown file")
+		}
+		base, err := os.ReadFile(snapshotPath(presentationID))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("no markdown has been applied to the presentation from here yet, please run deck apply first")
+			}
+			return fmt.Errorf("failed to read snapshot: %w", err)
+		}
+
+		d, err := deck.New(ctx, append(authOptions(), deck.WithPresentationID(presentationID))...)
+		if err != nil {
+			if errors.Is(err, deck.HTTPClientError) {
+				cmd.Println(setupInstructionMessage)
+			}
+			return err
+		}
+		remote, err := d.DumpSlides(ctx)
+		if err != nil {
+			return err
+		}
+		result, err := md.Merge(ctx, baseDir, base, local, remote, cfg)
+		if err != nil {
+			return err
+		}
+		if len(result.Updated) == 0 && len(result.Conflicts) == 0 {
+			cmd.Println("Already up to date.")
+			return nil
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(f, result.Markdown, info.Mode().Perm()); err != nil {
+			return err
+		}
+		if len(result.Updated) > 0 {
+			cmd.Printf("Updated pages: %v\n", result.Updated)
+		}
+		if len(result.Conflicts) > 0 {
+			return fmt.Errorf("conflicts in pages %v: resolve the conflict markers in %s before the next apply", result.Conflicts, f)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pullCmd)
+	pullCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+}
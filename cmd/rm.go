@@ -0,0 +1,98 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/md"
+	"github.com/k1LoW/errors"
+	"github.com/spf13/cobra"
+)
+
+var rmPage string
+
+var rmCmd = &cobra.Command{
+	Use:   "rm [DECK_FILE]",
+	Short: "delete pages from the presentation",
+	Long:  `delete pages from the presentation without editing the markdown file.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if rmPage == "" {
+			return fmt.Errorf("--page is required")
+		}
+		d, err := newDeckForPageCommand(cmd, args)
+		if err != nil {
+			return err
+		}
+		pages, err := pageToPages(rmPage, d.PageCount())
+		if err != nil {
+			return err
+		}
+		if len(pages) == d.PageCount() {
+			return fmt.Errorf("cannot delete all pages of the presentation")
+		}
+		indices := make([]int, 0, len(pages))
+		for _, p := range pages {
+			indices = append(indices, p-1)
+		}
+		slices.Sort(indices)
+		return d.DeletePages(ctx, slices.Compact(indices))
+	},
+}
+
+// newDeckForPageCommand returns the deck of the presentation given by --presentation-id
+// or by the frontmatter of the markdown file in args.
+func newDeckForPageCommand(cmd *cobra.Command, args []string) (*deck.Deck, error) {
+	if len(args) > 0 && presentationID == "" {
+		markdownData, err := md.ParseFile(args[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		if markdownData.Frontmatter != nil {
+			presentationID = markdownData.Frontmatter.PresentationID
+		}
+	}
+	if presentationID == "" {
+		return nil, fmt.Errorf("presentation ID is required. Use --presentation-id or set it in the frontmatter of the markdown file")
+	}
+	opts := append(authOptions(),
+		deck.WithPresentationID(presentationID),
+	)
+	d, err := deck.New(cmd.Context(), opts...)
+	if err != nil {
+		if errors.Is(err, deck.HTTPClientError) {
+			cmd.Println(setupInstructionMessage)
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+func init() {
+	rootCmd.AddCommand(rmCmd)
+	rmCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	rmCmd.Flags().StringVarP(&rmPage, "page", "p", "", "pages to delete (e.g. 3,5-9,12)")
+}
@@ -30,6 +30,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/k1LoW/deck"
 	"github.com/k1LoW/deck/config"
 	"github.com/k1LoW/deck/version"
 	"github.com/k1LoW/errors"
@@ -38,7 +39,12 @@ import (
 
 const setupInstructionMessage = "Run 'deck doctor' to check your setup or see https://github.com/k1LoW/deck?tab=readme-ov-file#setup for setup instructions"
 
-var profile string
+var (
+	profile                   string
+	impersonateUser           string
+	impersonateServiceAccount string
+	isolateTokenCache         bool
+)
 
 var rootCmd = &cobra.Command{
 	Use:          "deck",
@@ -107,4 +113,17 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&profile, "profile", "", "", "profile name")
+	rootCmd.PersistentFlags().StringVarP(&impersonateUser, "impersonate-user", "", "", "user to impersonate with a service account (domain-wide delegation)")
+	rootCmd.PersistentFlags().StringVarP(&impersonateServiceAccount, "impersonate-service-account", "", "", "service account to impersonate with Application Default Credentials")
+	rootCmd.PersistentFlags().BoolVarP(&isolateTokenCache, "isolate-token-cache", "", false, "cache OAuth tokens per presentation")
+}
+
+// authOptions returns deck options for authentication from the global flags.
+func authOptions() []deck.Option {
+	return []deck.Option{
+		deck.WithProfile(profile),
+		deck.WithImpersonateUser(impersonateUser),
+		deck.WithImpersonateServiceAccount(impersonateServiceAccount),
+		deck.WithIsolatedTokenCache(isolateTokenCache),
+	}
 }
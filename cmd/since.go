@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+)
+
+// changedPagesSinceRevision returns the pages of contents changed since the markdown file f at the git revision rev.
+// If the file does not exist at the revision, all pages are returned.
+// Relative paths (e.g. images) in the old markdown are resolved against baseDir of the current markdown.
+func changedPagesSinceRevision(rev, f, baseDir string, cfg *config.Config, contents md.Contents) ([]int, error) {
+	dir, name := filepath.Dir(f), filepath.Base(f)
+	if _, err := git(dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("invalid git revision %q: %w", rev, err)
+	}
+	// The path is relative to dir, since git resolves "./" against the working directory.
+	path := "./" + name
+	tracked, err := git(dir, "ls-tree", "--name-only", rev, "--", path)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(tracked)) == 0 {
+		return pageToPages("", len(contents))
+	}
+	b, err := git(dir, "show", rev+":"+path)
+	if err != nil {
+		return nil, err
+	}
+	old, err := md.Parse(baseDir, b, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s at %s: %w", f, rev, err)
+	}
+	return changedPages(old, contents), nil
+}
+
+// git runs the git command in dir and returns its output.
+func git(dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	c := exec.Command("git", append([]string{"-C", dir}, args...)...) //nolint:gosec
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, msg)
+		}
+		return nil, fmt.Errorf("git %s failed: %w", strings.Join(args, " "), err)
+	}
+	return out, nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/md"
+)
+
+func TestChangedPagesSinceRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	run := func(t *testing.T, args ...string) {
+		t.Helper()
+		c := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		c.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	commit := func(t *testing.T, name string, b []byte) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		run(t, "add", name)
+		run(t, "commit", "-q", "-m", "update "+name)
+	}
+	run(t, "init", "-q")
+	commit(t, "other.md", []byte("# Other\n"))
+	commit(t, "deck.md", []byte("# Page 1\n\n---\n\n# Page 2\n\n---\n\n# Page 3\n"))
+
+	f := filepath.Join(dir, "deck.md")
+	after := []byte("# Page 1\n\n---\n\n# Page 2 updated\n\n---\n\n# Page 3\n\n---\n\n# Page 4\n")
+	m, err := md.Parse(dir, after, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		rev     string
+		want    []int
+		wantErr bool
+	}{
+		{"HEAD", []int{2, 4}, false},
+		{"HEAD~1", []int{1, 2, 3, 4}, false}, // deck.md did not exist
+		{"no-such-revision", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rev, func(t *testing.T) {
+			got, err := changedPagesSinceRevision(tt.rev, f, dir, nil, m.Contents)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+)
+
+// snapshotPath returns the path of the snapshot of the markdown last applied to the presentation.
+func snapshotPath(presentationID string) string {
+	return filepath.Join(config.StateHomePath(), "snapshots", presentationID+".md")
+}
+
+// saveSnapshot saves the markdown applied to the presentation.
+func saveSnapshot(presentationID string, b []byte) error {
+	p := snapshotPath(presentationID)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(p, b, 0600); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// appliesAllPages reports whether the apply of the pages selected by the flags brings all the pages of the
+// markdown to the presentation, so that the markdown can be saved as the snapshot. The pages given by --page
+// or --since leave the other pages edited locally unapplied, and saving them would make deck pull and
+// --changed-only take them for applied. The pages changed since the snapshot with --changed-only and the
+// pages remaining in the journal with --resume complete the pages applied before.
+func appliesAllPages(page, since string, changedOnly, resume bool) bool {
+	if since != "" {
+		return false
+	}
+	return page == "" || changedOnly || resume
+}
+
+// changedPagesSinceSnapshot returns the pages of contents changed since the last applied snapshot.
+// If there is no snapshot, all pages are returned.
+// Relative paths (e.g. images) in the snapshot are resolved against baseDir of the current markdown.
+func changedPagesSinceSnapshot(presentationID, baseDir string, cfg *config.Config, contents md.Contents) ([]int, error) {
+	b, err := os.ReadFile(snapshotPath(presentationID))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return pageToPages("", len(contents))
+		}
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	old, err := md.Parse(baseDir, b, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return changedPages(old, contents), nil
+}
+
+// changedPages returns the pages of contents changed from the contents of old, excluding ignored pages.
+func changedPages(old *md.MD, contents md.Contents) []int {
+	var oldContents md.Contents
+	for _, content := range old.Contents {
+		if content.Ignore != nil && *content.Ignore {
+			continue
+		}
+		oldContents = append(oldContents, content)
+	}
+	return md.DiffContents(oldContents, contents)
+}
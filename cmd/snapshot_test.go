@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/md"
+)
+
+func TestChangedPagesSinceSnapshot(t *testing.T) {
+	presentationID := "test-snapshot-" + filepath.Base(t.TempDir())
+	t.Cleanup(func() {
+		_ = os.Remove(snapshotPath(presentationID))
+	})
+	f := filepath.Join(t.TempDir(), "deck.md")
+	parse := func(t *testing.T, b []byte) md.Contents {
+		t.Helper()
+		m, err := md.Parse(filepath.Dir(f), b, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m.Contents
+	}
+
+	before := []byte("# Page 1\n\n---\n\n# Page 2\n\n---\n\n# Page 3\n")
+	got, err := changedPagesSinceSnapshot(presentationID, filepath.Dir(f), nil, parse(t, before))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3}, got); diff != "" {
+		t.Errorf("all pages should be changed without snapshot (-want +got):\n%s", diff)
+	}
+
+	if err := saveSnapshot(presentationID, before); err != nil {
+		t.Fatal(err)
+	}
+	after := []byte("# Page 1\n\n---\n\n# Page 2 updated\n\n---\n\n# Page 3\n\n---\n\n# Page 4\n")
+	got, err = changedPagesSinceSnapshot(presentationID, filepath.Dir(f), nil, parse(t, after))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{2, 4}, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestAppliesAllPages(t *testing.T) {
+	tests := []struct {
+		name        string
+		page        string
+		since       string
+		changedOnly bool
+		resume      bool
+		want        bool
+	}{
+		{"all pages", "", "", false, false, true},
+		{"page", "3", "", false, false, false},
+		{"since", "", "HEAD~1", false, false, false},
+		{"changed only", "", "", true, false, true},
+		{"resume", "", "", false, true, true},
+		{"since with changed only", "", "HEAD~1", true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appliesAllPages(tt.page, tt.since, tt.changedOnly, tt.resume); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
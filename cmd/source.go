@@ -0,0 +1,174 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+)
+
+// stdinSource is the DECK_FILE to read the markdown from the standard input.
+const stdinSource = "-"
+
+// isRemoteSource reports whether the DECK_FILE f is the standard input or a URL instead of a local file.
+func isRemoteSource(f string) bool {
+	return f == stdinSource || isURL(f)
+}
+
+func isURL(f string) bool {
+	return strings.HasPrefix(f, "http://") || strings.HasPrefix(f, "https://")
+}
+
+// readSource reads the markdown from the DECK_FILE f, which is a local file, "-" for stdin or an HTTP(S) URL.
+// It returns the markdown and the base directory to resolve relative paths such as images.
+// The base directory of a URL is the URL of its directory, and that of stdin is the current directory.
+func readSource(ctx context.Context, f string, stdin io.Reader) (_ []byte, baseDir string, err error) {
+	switch {
+	case f == stdinSource:
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read markdown from stdin: %w", err)
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, "", err
+		}
+		return b, wd, nil
+	case isURL(f):
+		u, err := url.Parse(f)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid URL %s: %w", f, err)
+		}
+		b, err := fetchSource(ctx, f)
+		if err != nil {
+			return nil, "", err
+		}
+		base := *u
+		base.Path = path.Dir(u.Path) + "/"
+		base.RawPath = ""
+		base.RawQuery = ""
+		base.Fragment = ""
+		return b, base.String(), nil
+	default:
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, "", err
+		}
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return nil, "", err
+		}
+		return b, filepath.Dir(abs), nil
+	}
+}
+
+func fetchSource(ctx context.Context, u string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req) //nolint:gosec // The URL is given by the user.
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch markdown from %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch markdown from %s: status code %d", u, res.StatusCode)
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch markdown from %s: %w", u, err)
+	}
+	return b, nil
+}
+
+// deprecatedArgs reports whether args are the deprecated PRESENTATION_ID DECK_FILE arguments
+// rather than two DECK_FILEs. Presentation IDs are told apart from DECK_FILEs by not being files.
+func deprecatedArgs(args []string) bool {
+	if len(args) != 2 || isRemoteSource(args[0]) {
+		return false
+	}
+	_, err := os.Stat(args[0])
+	return err != nil
+}
+
+// parseSources reads and parses the DECK_FILEs as one deck, concatenating their pages in order.
+// Relative paths in each file are resolved against its own base directory.
+// The markdown from stdin or a URL is parsed as untrusted unless trust is true.
+// It returns the markdown of the files joined, which identifies what is applied, and the base directory of the first file.
+func parseSources(ctx context.Context, files []string, stdin io.Reader, cfg *config.Config, trust bool, opts ...md.ParseOption) (_ *md.MD, raw []byte, baseDir string, err error) {
+	defer func() {
+		if errors.Is(err, md.ErrUntrustedSource) {
+			err = fmt.Errorf("%w: use --trust-source if you trust the markdown", err)
+		}
+	}()
+	if len(files) == 1 {
+		raw, baseDir, err := readSource(ctx, files[0], stdin)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		m, err := md.Parse(baseDir, raw, cfg, append(opts, md.WithUntrusted(isRemoteSource(files[0]) && !trust))...)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return m, raw, baseDir, nil
+	}
+	var (
+		srcs      []md.Source
+		raws      [][]byte
+		readStdin bool
+	)
+	for _, f := range files {
+		if f == stdinSource {
+			if readStdin {
+				return nil, nil, "", fmt.Errorf("cannot read markdown from stdin more than once")
+			}
+			readStdin = true
+		}
+		b, dir, err := readSource(ctx, f, stdin)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		srcs = append(srcs, md.Source{Name: f, BaseDir: dir, B: b, Untrusted: isRemoteSource(f) && !trust})
+		raws = append(raws, b)
+	}
+	m, err := md.ParseSources(srcs, cfg, opts...)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return m, bytes.Join(raws, []byte{0}), srcs[0].BaseDir, nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/deck/md"
+)
+
+func TestReadSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/decks/deck.md" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("# From URL\n"))
+	}))
+	t.Cleanup(ts.Close)
+	dir := t.TempDir()
+	f := filepath.Join(dir, "deck.md")
+	if err := os.WriteFile(f, []byte("# From file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		f           string
+		wantB       string
+		wantBaseDir string
+		wantErr     bool
+	}{
+		{f, "# From file\n", dir, false},
+		{"-", "# From stdin\n", wd, false},
+		{ts.URL + "/decks/deck.md?token=secret", "# From URL\n", ts.URL + "/decks/", false},
+		{ts.URL + "/decks/missing.md", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.f, func(t *testing.T) {
+			b, baseDir, err := readSource(t.Context(), tt.f, strings.NewReader("# From stdin\n"))
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("want error")
+			}
+			if string(b) != tt.wantB {
+				t.Errorf("got %q, want %q", b, tt.wantB)
+			}
+			if baseDir != tt.wantBaseDir {
+				t.Errorf("got base directory %q, want %q", baseDir, tt.wantBaseDir)
+			}
+		})
+	}
+}
+
+func TestDeprecatedArgs(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.md")
+	b := filepath.Join(dir, "b.md")
+	for _, f := range []string{a, b} {
+		if err := os.WriteFile(f, []byte("# Page\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{a}, false},
+		{[]string{"xxxxxXXXXxxxxx", a}, true},
+		{[]string{a, b}, false},
+		{[]string{"-", a}, false},
+		{[]string{a, b, "xxxxxXXXXxxxxx"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := deprecatedArgs(tt.args); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSources(t *testing.T) {
+	dir := t.TempDir()
+	intro := filepath.Join(dir, "intro.md")
+	chapter := filepath.Join(dir, "chapters", "one.md")
+	if err := os.MkdirAll(filepath.Dir(chapter), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(intro, []byte("---\npresentationID: xxxxx\n---\n# Intro\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(chapter, []byte("# Chapter\n\n---\n\n# Details\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m, raw, baseDir, err := parseSources(t.Context(), []string{intro, chapter, "-"}, strings.NewReader("# Closing\n"), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(m.Contents); got != 4 {
+		t.Errorf("got %d pages, want 4", got)
+	}
+	if m.Frontmatter == nil || m.Frontmatter.PresentationID != "xxxxx" {
+		t.Error("want the frontmatter of the first file")
+	}
+	if baseDir != dir {
+		t.Errorf("got base directory %q, want %q", baseDir, dir)
+	}
+	if !strings.Contains(string(raw), "# Details") || !strings.Contains(string(raw), "# Closing") {
+		t.Errorf("got %q, want the markdown of all the files", raw)
+	}
+	if _, _, _, err := parseSources(t.Context(), []string{"-", "-"}, strings.NewReader("# Page\n"), nil, false); err == nil {
+		t.Error("want error for reading stdin twice")
+	}
+}
+
+func TestParseSourcesUntrusted(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "marker")
+	deckMD := "---\ncodeBlockToImageCommand: touch " + marker + "\n---\n# Code\n\n```go\npackage main\n```\n"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(deckMD))
+	}))
+	t.Cleanup(ts.Close)
+
+	for _, f := range []string{"-", ts.URL + "/deck.md"} {
+		t.Run(f, func(t *testing.T) {
+			m, _, _, err := parseSources(t.Context(), []string{f}, strings.NewReader(deckMD), nil, false)
+			if !errors.Is(err, md.ErrUntrustedSource) {
+				t.Fatalf("got error %v, want %v", err, md.ErrUntrustedSource)
+			}
+			if m != nil {
+				t.Error("want no markdown to convert")
+			}
+			if _, err := os.Stat(marker); err == nil {
+				t.Error("the command of the untrusted markdown has been run")
+			}
+
+			// With --trust-source, the command is run.
+			m, _, _, err = parseSources(t.Context(), []string{f}, strings.NewReader(deckMD), nil, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = m.ToSlides(t.Context(), "")
+			if _, err := os.Stat(marker); err != nil {
+				t.Errorf("the command of the trusted markdown has not been run: %v", err)
+			}
+			_ = os.Remove(marker)
+		})
+	}
+}
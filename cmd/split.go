@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+	"github.com/spf13/cobra"
+)
+
+// applyParts applies each part of the markdown split by --split-by to its own presentation.
+func applyParts(ctx context.Context, cmd *cobra.Command, cfg *config.Config, m *md.MD, opts []deck.Option) error {
+	level, err := md.ParseSplitBy(splitBy)
+	if err != nil {
+		return err
+	}
+	parts, err := m.Split(level)
+	if err != nil {
+		return err
+	}
+	for i, p := range parts {
+		logger.Info("applying part", slog.Int("part", i+1), slog.String("presentation_id", p.PresentationID), slog.String("title", p.Title))
+		d, err := deck.New(ctx, append(slices.Clone(opts), deck.WithPresentationID(p.PresentationID))...)
+		if err != nil {
+			return fmt.Errorf("failed to open the presentation of part %d: %w", i+1, err)
+		}
+		if err := updateMetadata(ctx, d, p.MD); err != nil {
+			return err
+		}
+		slides, err := p.MD.ToSlides(ctx, codeBlockToImageCmd, toSlidesOptions()...)
+		if err != nil {
+			return fmt.Errorf("failed to convert markdown contents to slides: %w", err)
+		}
+		pages, err := pageToPages("", len(slides))
+		if err != nil {
+			return err
+		}
+		if err := runHook(ctx, cfg, hookBeforeApply, p.PresentationID, pages, cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+		if err := d.ApplyPages(ctx, slides, pages); err != nil {
+			return fmt.Errorf("failed to apply part %d: %w", i+1, err)
+		}
+		logger.Info("apply completed", slog.String("presentation_id", p.PresentationID), slog.Any("pages", pages))
+		reportOwnedChanges(cmd.OutOrStdout(), d)
+		if err := reportPageLinks(cmd.OutOrStdout(), d); err != nil {
+			return err
+		}
+		if err := runHook(ctx, cfg, hookAfterApply, p.PresentationID, appliedPages(d), cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+	"github.com/k1LoW/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsRemote bool
+	statsFormat string
+	statsLimits deck.StatsLimits
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [DECK_FILE]",
+	Short: "report metrics of the deck",
+	Long: `report metrics of the deck such as words and bullets per page.
+If thresholds are specified, it exits with an error when any page exceeds them.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if statsFormat != "text" && statsFormat != "json" {
+			return fmt.Errorf("invalid format: %s (text or json)", statsFormat)
+		}
+		var (
+			m   *md.MD
+			err error
+		)
+		if len(args) > 0 {
+			cfg, err := config.Load(profile)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			m, err = md.ParseFile(args[0], cfg)
+			if err != nil {
+				return err
+			}
+			if presentationID == "" && m.Frontmatter != nil && m.Frontmatter.PresentationID != "" {
+				presentationID = m.Frontmatter.PresentationID
+			}
+		}
+		var slides deck.Slides
+		if statsRemote {
+			if presentationID == "" {
+				return fmt.Errorf("presentation ID is required. Use --presentation-id or set it in the frontmatter of the markdown file")
+			}
+			opts := append(authOptions(),
+				deck.WithPresentationID(presentationID),
+			)
+			d, err := deck.New(ctx, opts...)
+			if err != nil {
+				if errors.Is(err, deck.HTTPClientError) {
+					cmd.Println(setupInstructionMessage)
+				}
+				return err
+			}
+			slides, err = d.DumpSlides(ctx)
+			if err != nil {
+				return err
+			}
+		} else {
+			if m == nil {
+				return fmt.Errorf("DECK_FILE is required unless --remote is specified")
+			}
+			// Code blocks are not converted to images, because running the command is not needed for metrics.
+			if m.Frontmatter != nil {
+				m.Frontmatter.CodeBlockToImageCommand = ""
+			}
+			slides, err = m.ToSlides(ctx, "")
+			if err != nil {
+				return err
+			}
+		}
+
+		stats := deck.NewStats(slides)
+		violations := stats.Check(statsLimits)
+		switch statsFormat {
+		case "json":
+			if err := writeStatsJSON(cmd.OutOrStdout(), stats, violations); err != nil {
+				return err
+			}
+		default:
+			if err := writeStatsText(cmd.OutOrStdout(), stats, violations); err != nil {
+				return err
+			}
+		}
+		if len(violations) > 0 {
+			return fmt.Errorf("%d violations of the thresholds found", len(violations))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	statsCmd.Flags().BoolVarP(&statsRemote, "remote", "r", false, "report metrics of the presentation instead of the markdown")
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "", "text", "output format (text or json)")
+	statsCmd.Flags().IntVarP(&statsLimits.MaxBullets, "max-bullets", "", 0, "fail if any page has more bullets than this")
+	statsCmd.Flags().IntVarP(&statsLimits.MaxWords, "max-words", "", 0, "fail if any page has more words than this")
+	statsCmd.Flags().IntVarP(&statsLimits.MaxSpeakerNoteLength, "max-note-length", "", 0, "fail if any speaker note has more characters than this")
+	statsCmd.Flags().BoolVarP(&statsLimits.RequireAlt, "require-alt", "", false, "fail if any image has no alt text")
+}
+
+func writeStatsJSON(w io.Writer, stats *deck.Stats, violations []string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		*deck.Stats
+		Violations []string `json:"violations,omitempty"`
+	}{stats, violations})
+}
+
+func writeStatsText(w io.Writer, stats *deck.Stats, violations []string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PAGE\tWORDS\tBULLETS\tIMAGES\tNO ALT\tNOTE\tTABLES\tTITLE")
+	for _, st := range stats.Slides {
+		var tables []string
+		for _, t := range st.Tables {
+			tables = append(tables, fmt.Sprintf("%dx%d", t.Rows, t.Columns))
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%d\t%s\t%s\n",
+			st.Page, st.Words, st.Bullets, st.Images, st.ImagesWithoutAlt, st.SpeakerNoteLength, strings.Join(tables, ","), st.Title)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\npages: %d, words: %d", stats.Pages, stats.Words)
+	if stats.LongestSpeakerNotePage > 0 {
+		fmt.Fprintf(w, ", longest speaker note: page %d", stats.LongestSpeakerNotePage)
+	}
+	fmt.Fprintln(w)
+	for _, v := range violations {
+		fmt.Fprintln(w, v)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/md"
+	"github.com/k1LoW/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	thumbnailsOutDir string
+	thumbnailsSize   string
+	thumbnailsPage   string
+)
+
+var thumbnailsCmd = &cobra.Command{
+	Use:   "thumbnails [DECK_FILE]",
+	Short: "export thumbnails of pages as PNG images",
+	Long:  `export thumbnails of pages as PNG images.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if len(args) > 0 {
+			markdownData, err := md.ParseFile(args[0], nil)
+			if err != nil {
+				return err
+			}
+			if presentationID == "" && markdownData.Frontmatter != nil && markdownData.Frontmatter.PresentationID != "" {
+				presentationID = markdownData.Frontmatter.PresentationID
+			}
+		}
+		if presentationID == "" {
+			return fmt.Errorf("presentation ID is required. Use --presentation-id or set it in the frontmatter of the markdown file")
+		}
+		size := deck.ThumbnailSize(strings.ToUpper(thumbnailsSize))
+		switch size {
+		case deck.ThumbnailSizeSmall, deck.ThumbnailSizeMedium, deck.ThumbnailSizeLarge:
+		default:
+			return fmt.Errorf("invalid size: %s (small, medium or large)", thumbnailsSize)
+		}
+
+		opts := append(authOptions(),
+			deck.WithPresentationID(presentationID),
+		)
+		if concurrency > 0 {
+			opts = append(opts, deck.WithConcurrency(concurrency))
+		}
+		d, err := deck.New(ctx, opts...)
+		if err != nil {
+			if errors.Is(err, deck.HTTPClientError) {
+				cmd.Println(setupInstructionMessage)
+			}
+			return err
+		}
+		pages, err := pageToPages(thumbnailsPage, d.PageCount())
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(thumbnailsOutDir, 0755); err != nil { //nolint:gosec
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+
+		// Thumbnails are cached by revision ID, so unchanged presentations are not fetched again.
+		cacheDir := thumbnailCacheDir(presentationID, d.RevisionID(), size)
+		var missing []int
+		for _, p := range pages {
+			objectID, err := d.PageObjectID(p)
+			if err != nil {
+				return err
+			}
+			b, err := os.ReadFile(filepath.Join(cacheDir, objectID+".png"))
+			if err != nil {
+				missing = append(missing, p)
+				continue
+			}
+			if err := writeThumbnail(p, b); err != nil {
+				return err
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		thumbnails, err := d.Thumbnails(ctx, missing, size)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(cacheDir, 0700); err != nil {
+			return fmt.Errorf("failed to create cache directory: %w", err)
+		}
+		for _, t := range thumbnails {
+			var buf bytes.Buffer
+			if err := d.DownloadThumbnail(ctx, t, &buf); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(cacheDir, t.ObjectID+".png"), buf.Bytes(), 0600); err != nil {
+				return fmt.Errorf("failed to cache thumbnail: %w", err)
+			}
+			if err := writeThumbnail(t.Page, buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(thumbnailsCmd)
+	thumbnailsCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	thumbnailsCmd.Flags().StringVarP(&thumbnailsOutDir, "out-dir", "o", "thumbnails", "output directory")
+	thumbnailsCmd.Flags().StringVarP(&thumbnailsSize, "size", "s", "large", "thumbnail size (small: 200px, medium: 800px, large: 1600px wide)")
+	thumbnailsCmd.Flags().StringVarP(&thumbnailsPage, "page", "p", "", "pages to export (e.g. 3,5-9,12)")
+	thumbnailsCmd.Flags().IntVarP(&concurrency, "concurrency", "", 0, "maximum number of concurrent requests (default 4)")
+}
+
+// thumbnailCacheDir returns the directory to cache thumbnails of the revision of the presentation.
+func thumbnailCacheDir(presentationID, revisionID string, size deck.ThumbnailSize) string {
+	return filepath.Join(config.StateHomePath(), "thumbnails", presentationID, revisionID, strings.ToLower(string(size)))
+}
+
+// thumbnailPath returns the output path of the thumbnail of the page.
+func thumbnailPath(page int) string {
+	return filepath.Join(thumbnailsOutDir, fmt.Sprintf("page-%03d.png", page))
+}
+
+func writeThumbnail(page int, b []byte) error {
+	if err := os.WriteFile(thumbnailPath(page), b, 0644); err != nil { //nolint:gosec
+		return fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	return nil
+}
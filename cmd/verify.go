@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/errors"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify DECK_FILE...",
+	Short: "verify that the presentation matches the markdown",
+	Long: `verify that the pages of the presentation match the markdown.
+
+It prints the differences of the pages and exits with an error when the presentation has drifted from the markdown,
+e.g. when it has been edited by hand or an apply has failed halfway. This is useful to assert the presentation in CI after publishing.
+Multiple markdown files are verified as one deck, as with apply.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := config.Load(profile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		m, _, _, err := parseSources(ctx, args, cmd.InOrStdin(), cfg, trustSource)
+		if err != nil {
+			return err
+		}
+		if presentationID == "" && m.Frontmatter != nil {
+			presentationID = m.Frontmatter.PresentationID
+		}
+		if presentationID == "" {
+			return fmt.Errorf("presentation ID is required, please specify it with --presentation-id or in the frontmatter of the markdown file")
+		}
+		slides, err := m.ToSlides(ctx, codeBlockToImageCmd, toSlidesOptions()...)
+		if err != nil {
+			return fmt.Errorf("failed to convert markdown contents to slides: %w", err)
+		}
+		opts := append(authOptions(),
+			deck.WithPresentationID(presentationID),
+		)
+		d, err := deck.New(ctx, opts...)
+		if err != nil {
+			if errors.Is(err, deck.HTTPClientError) {
+				cmd.Println(setupInstructionMessage)
+			}
+			return err
+		}
+		return verifySlides(ctx, cmd.OutOrStdout(), d, slides)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().StringVarP(&presentationID, "presentation-id", "i", "", "Google Slides presentation ID")
+	verifyCmd.Flags().StringVarP(&codeBlockToImageCmd, "code-block-to-image-command", "c", "", "command to convert code blocks to images")
+	verifyCmd.Flags().BoolVarP(&trustSource, "trust-source", "", false, "trust the markdown from stdin or a URL to run codeBlockToImageCommand of its frontmatter, expand its templates and read local files out of its directory")
+	verifyCmd.Flags().BoolVarP(&noCodeImageCache, "no-code-image-cache", "", false, "run the command to convert code blocks to images without reusing cached images")
+}
+
+// verifySlides prints the differences between the slides and the pages of the presentation,
+// and returns an error if any.
+func verifySlides(ctx context.Context, w io.Writer, d *deck.Deck, slides deck.Slides) error {
+	drifts, err := d.Verify(ctx, slides)
+	if err != nil {
+		return err
+	}
+	if len(drifts) == 0 {
+		fmt.Fprintln(w, "the presentation matches the markdown")
+		return nil
+	}
+	for _, drift := range drifts {
+		fmt.Fprint(w, drift.String())
+	}
+	return fmt.Errorf("the presentation has drifted from the markdown: %d differences found", len(drifts))
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k1LoW/deck/md"
+)
+
+func TestWatchApplySupersede(t *testing.T) {
+	started := make(chan struct{})
+	contents := md.Contents{{Titles: []string{"a"}}}
+	a := startWatchApply(t.Context(), []byte("a"), contents, func(ctx context.Context) bool {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err() == nil
+	})
+	<-started
+	if !a.running([]byte("a")) {
+		t.Error("want running for the same content")
+	}
+	if a.running([]byte("b")) {
+		t.Error("want not running for other content")
+	}
+	if !a.supersede() {
+		t.Error("want superseded")
+	}
+	if a.applied != nil {
+		t.Errorf("cancelled apply should not be applied: %v", a.applied)
+	}
+	if a.supersede() {
+		t.Error("finished apply should not be superseded again")
+	}
+
+	b := startWatchApply(t.Context(), []byte("b"), contents, func(ctx context.Context) bool {
+		return true
+	})
+	<-b.done
+	if b.running([]byte("b")) {
+		t.Error("finished apply should not be running")
+	}
+	if len(b.applied) != 1 {
+		t.Errorf("want applied contents, got %v", b.applied)
+	}
+
+	var nilApply *watchApply
+	if nilApply.running([]byte("a")) || nilApply.supersede() {
+		t.Error("nil apply should be neither running nor superseded")
+	}
+}
@@ -0,0 +1,63 @@
+package deck
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/slides/v1"
+)
+
+var hexColorReg = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// namedColors are the basic color keywords of CSS.
+var namedColors = map[string]string{
+	"black":   "#000000",
+	"silver":  "#c0c0c0",
+	"gray":    "#808080",
+	"grey":    "#808080",
+	"white":   "#ffffff",
+	"maroon":  "#800000",
+	"red":     "#ff0000",
+	"purple":  "#800080",
+	"fuchsia": "#ff00ff",
+	"green":   "#008000",
+	"lime":    "#00ff00",
+	"olive":   "#808000",
+	"yellow":  "#ffff00",
+	"navy":    "#000080",
+	"blue":    "#0000ff",
+	"teal":    "#008080",
+	"aqua":    "#00ffff",
+	"orange":  "#ffa500",
+}
+
+// NormalizeColor returns the color written as a hex code (e.g. "#f00" or "#ff0000") or a basic color keyword of CSS
+// (e.g. "red") in the form of "#rrggbb".
+func NormalizeColor(color string) (string, error) {
+	c := strings.ToLower(strings.TrimSpace(color))
+	if hex, ok := namedColors[c]; ok {
+		return hex, nil
+	}
+	if !hexColorReg.MatchString(c) {
+		return "", fmt.Errorf("invalid color: %q", color)
+	}
+	if len(c) == 4 {
+		c = string([]byte{'#', c[1], c[1], c[2], c[2], c[3], c[3]})
+	}
+	return c, nil
+}
+
+// rgbColor converts a color in the form of "#rrggbb" to an RGB color of Google Slides.
+func rgbColor(hex string) *slides.RgbColor {
+	v, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(hex) != 7 {
+		return nil
+	}
+	return &slides.RgbColor{
+		Red:   float64((v>>16)&0xff) / 255,
+		Green: float64((v>>8)&0xff) / 255,
+		Blue:  float64(v&0xff) / 255,
+	}
+}
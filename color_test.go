@@ -0,0 +1,30 @@
+package deck
+
+import "testing"
+
+func TestNormalizeColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"#ff0000", "#ff0000", false},
+		{"#F00", "#ff0000", false},
+		{" Red ", "#ff0000", false},
+		{"grey", "#808080", false},
+		{"#ff00", "", true},
+		{"rgb(255, 0, 0)", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NormalizeColor(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
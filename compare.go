@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"slices"
 	"strings"
+
+	"github.com/k1LoW/deck/textmeasure"
 )
 
 func (s Slides) Equal(other Slides) bool { //nostyle:recvtype
@@ -19,13 +21,68 @@ func (s *Slide) Equal(other *Slide) bool {
 		return s == other
 	}
 	return s.Layout == other.Layout &&
-		slices.Equal(s.Titles, other.Titles) &&
-		slices.Equal(s.Subtitles, other.Subtitles) &&
+		textsEqual(s.Titles, other.Titles) &&
+		textsEqual(s.Subtitles, other.Subtitles) &&
 		bodiesEqual(s.Bodies, other.Bodies) &&
 		imagesEquivalent(s.Images, other.Images) &&
 		blockQuotesEqual(s.BlockQuotes, other.BlockQuotes) &&
 		tablesEqual(s.Tables, other.Tables) &&
-		s.SpeakerNote == other.SpeakerNote
+		elementsEqual(s.Elements, other.Elements) &&
+		textmeasure.Equal(s.SpeakerNote, other.SpeakerNote) &&
+		s.Key == other.Key &&
+		s.Autofit == other.Autofit &&
+		s.Direction == other.Direction &&
+		paragraphSpacingEqual(s.ParagraphSpacing, other.ParagraphSpacing) &&
+		s.ImageColumns == other.ImageColumns &&
+		s.ImageRows == other.ImageRows
+}
+
+// paragraphSpacingEqual reports whether the paragraph spacings are the same. Unset spacings are equal to
+// the spacings without any fields set.
+func paragraphSpacingEqual(a, b *ParagraphSpacing) bool {
+	if a == nil {
+		a = &ParagraphSpacing{}
+	}
+	if b == nil {
+		b = &ParagraphSpacing{}
+	}
+	ptrEqual := func(x, y *float64) bool {
+		return x == nil && y == nil || x != nil && y != nil && *x == *y
+	}
+	return ptrEqual(a.LineSpacing, b.LineSpacing) && ptrEqual(a.SpaceAbove, b.SpaceAbove) && ptrEqual(a.SpaceBelow, b.SpaceBelow)
+}
+
+// speakerNoteOnlyChanged reports whether before and after differ only in the speaker note.
+func speakerNoteOnlyChanged(before, after *Slide) bool {
+	if before == nil || after == nil || textmeasure.Equal(before.SpeakerNote, after.SpeakerNote) {
+		return false
+	}
+	b := *before
+	b.SpeakerNote = after.SpeakerNote
+	return b.Equal(after)
+}
+
+// elementsEqual reports whether the elements are the same regardless of their order.
+func elementsEqual(elements1, elements2 []*Element) bool {
+	if len(elements1) != len(elements2) {
+		return false
+	}
+	encode := func(elements []*Element) []string {
+		var s []string
+		for _, e := range elements {
+			b, _ := json.Marshal(e)
+			s = append(s, string(b))
+		}
+		slices.Sort(s)
+		return s
+	}
+	return slices.Equal(encode(elements1), encode(elements2))
+}
+
+// textsEqual reports whether the texts are the same regardless of their normalization forms,
+// since text with combining marks may be read back from the presentation with precomposed characters.
+func textsEqual(texts1, texts2 []string) bool {
+	return slices.EqualFunc(texts1, texts2, textmeasure.Equal)
 }
 
 func bodiesEqual(bodies1, bodies2 []*Body) bool {
@@ -51,7 +108,7 @@ func imagesEquivalent(images1, images2 []*Image) bool {
 	slices.SortFunc(sorted2, f)
 
 	return slices.EqualFunc(sorted1, sorted2, func(a, b *Image) bool {
-		return a.Equivalent(b)
+		return a.Equivalent(b) && a.alt == b.alt && a.title == b.title && a.credit == b.credit && a.zOrder == b.zOrder
 	})
 }
 
@@ -72,6 +129,7 @@ func blockQuotesEqual(bq1, bq2 []*BlockQuote) bool {
 			return a == b
 		}
 		return a.Nesting == b.Nesting &&
+			a.StyleName == b.StyleName &&
 			slices.EqualFunc(a.Paragraphs, b.Paragraphs, paragraphEqual)
 	})
 }
@@ -80,14 +138,14 @@ func paragraphEqual(paragraph1, paragraph2 *Paragraph) bool {
 	if paragraph1 == nil || paragraph2 == nil {
 		return paragraph1 == paragraph2
 	}
-	if paragraph1.Bullet != paragraph2.Bullet || paragraph1.Nesting != paragraph2.Nesting {
+	if paragraph1.Bullet != paragraph2.Bullet || paragraph1.Nesting != paragraph2.Nesting || paragraph1.StyleName != paragraph2.StyleName {
 		return false
 	}
 	merged1 := mergeFragments(paragraph1.Fragments)
 	merged2 := mergeFragments(paragraph2.Fragments)
 
 	return slices.EqualFunc(merged1, merged2, func(a, b *Fragment) bool {
-		return strings.TrimRight(a.Value, "\n") == strings.TrimRight(b.Value, "\n") &&
+		return textmeasure.Equal(strings.TrimRight(a.Value, "\n"), strings.TrimRight(b.Value, "\n")) &&
 			a.StylesEqual(b)
 	})
 }
@@ -110,9 +168,12 @@ func mergeFragments(in []*Fragment) []*Fragment {
 			Value:     in[i].Value,
 			Bold:      in[i].Bold,
 			Italic:    in[i].Italic,
+			Underline: in[i].Underline,
+			Highlight: in[i].Highlight,
 			Link:      in[i].Link,
 			Code:      in[i].Code,
 			StyleName: in[i].StyleName,
+			Color:     in[i].Color,
 		})
 	}
 	return merged
@@ -141,8 +202,12 @@ func tableCellEqual(cell1, cell2 *TableCell) bool {
 	if cell1.Alignment != cell2.Alignment || cell1.IsHeader != cell2.IsHeader {
 		return false
 	}
+	// The vertical alignment is compared only when both are given, since it is left to the table style otherwise.
+	if cell1.VerticalAlignment != "" && cell2.VerticalAlignment != "" && cell1.VerticalAlignment != cell2.VerticalAlignment {
+		return false
+	}
 	return slices.EqualFunc(cell1.Fragments, cell2.Fragments, func(a, b *Fragment) bool {
-		return strings.TrimRight(a.Value, "\n") == strings.TrimRight(b.Value, "\n") &&
+		return textmeasure.Equal(strings.TrimRight(a.Value, "\n"), strings.TrimRight(b.Value, "\n")) &&
 			a.StylesEqual(b)
 	})
 }
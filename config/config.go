@@ -16,10 +16,93 @@ type Config struct {
 	Defaults []DefaultCondition `yaml:"defaults,omitempty" json:"defaults,omitempty"`
 	// command to convert code blocks to images
 	CodeBlockToImageCommand string `yaml:"codeBlockToImageCommand,omitempty" json:"codeBlockToImageCommand,omitempty"`
+	// shell to run codeBlockToImageCommand: "sh", "bash", "cmd", "powershell", "pwsh" or a path to one of them
+	Shell string `yaml:"shell,omitempty" json:"shell,omitempty"`
+	// background color of text highlighted with <mark> (default: "#ffff00")
+	HighlightColor string `yaml:"highlightColor,omitempty" json:"highlightColor,omitempty"`
+	// how to render code blocks: "image" (converted with codeBlockToImageCommand, default) or "text"
+	CodeBlock string `yaml:"codeBlock,omitempty" json:"codeBlock,omitempty"`
+	// default layouts per heading level of page titles (e.g. h1: section)
+	LayoutRules map[string]string `yaml:"layoutRules,omitempty" json:"layoutRules,omitempty"`
+	// heading level of page titles that start a section (e.g. 1 for H1 pages)
+	SectionLevel int `yaml:"sectionLevel,omitempty" json:"sectionLevel,omitempty"`
+	// layout for pages without a layout (default: the first "TITLE_AND_BODY" layout of the presentation)
+	DefaultLayout string `yaml:"defaultLayout,omitempty" json:"defaultLayout,omitempty"`
+	// layout for the first page without a layout (default: the first "TITLE" layout of the presentation)
+	FirstPageLayout string `yaml:"firstPageLayout,omitempty" json:"firstPageLayout,omitempty"`
 	// folder ID to create presentations and upload temporary images to
 	FolderID string `yaml:"folderID,omitempty" json:"folderID,omitempty"`
+	// alias of folderID. The ID of a shared drive can be used to use its root folder
+	DriveFolderID string `yaml:"driveFolderID,omitempty" json:"driveFolderID,omitempty"`
 	// base presentation ID to use for new presentations
 	BasePresentationID string `yaml:"basePresentationID,omitempty" json:"basePresentationID,omitempty"`
+	// maximum number of concurrent image operations
+	Concurrency int `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	// settings for uploading images
+	ImageUpload *ImageUpload `yaml:"imageUpload,omitempty" json:"imageUpload,omitempty"`
+	// refuse to apply when more pages than this would be deleted
+	MaxDeletions *int `yaml:"maxDeletions,omitempty" json:"maxDeletions,omitempty"`
+	// commands to run before and after applying
+	Hooks *Hooks `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+}
+
+// DefaultFolderID returns the folder ID to create presentations and upload temporary images to.
+// folderID takes precedence over driveFolderID.
+func (c *Config) DefaultFolderID() string {
+	if c == nil {
+		return ""
+	}
+	if c.FolderID != "" {
+		return c.FolderID
+	}
+	return c.DriveFolderID
+}
+
+type Hooks struct {
+	BeforeApply string `yaml:"beforeApply,omitempty" json:"beforeApply,omitempty"` // command to run before applying
+	AfterApply  string `yaml:"afterApply,omitempty" json:"afterApply,omitempty"`   // command to run after applying
+}
+
+type ImageUpload struct {
+	Parallelism        int   `yaml:"parallelism,omitempty" json:"parallelism,omitempty"`               // maximum number of concurrent uploads
+	PerHostParallelism int   `yaml:"perHostParallelism,omitempty" json:"perHostParallelism,omitempty"` // maximum number of concurrent fetches per host
+	MaxBytesPerSecond  int64 `yaml:"maxBytesPerSecond,omitempty" json:"maxBytesPerSecond,omitempty"`   // upload bandwidth limit
+	Adaptive           bool  `yaml:"adaptive,omitempty" json:"adaptive,omitempty"`                     // scale parallelism based on latency and errors
+	// folder ID to upload images to (defaults to folderID)
+	FolderID string `yaml:"folderID,omitempty" json:"folderID,omitempty"`
+	// prefix of the file names of uploaded images
+	FileNamePrefix string `yaml:"fileNamePrefix,omitempty" json:"fileNamePrefix,omitempty"`
+	// keep uploaded images instead of deleting them after apply
+	Keep bool `yaml:"keep,omitempty" json:"keep,omitempty"`
+	// optimize images before upload
+	Optimize *ImageOptimize `yaml:"optimize,omitempty" json:"optimize,omitempty"`
+	// host to upload images to instead of Google Drive
+	Host *ImageHost `yaml:"host,omitempty" json:"host,omitempty"`
+}
+
+type ImageHost struct {
+	Type     string `yaml:"type" json:"type"`                             // gcs, s3 or command
+	Bucket   string `yaml:"bucket,omitempty" json:"bucket,omitempty"`     // bucket of gcs and s3
+	Prefix   string `yaml:"prefix,omitempty" json:"prefix,omitempty"`     // prefix of the names of the objects of gcs and s3
+	Region   string `yaml:"region,omitempty" json:"region,omitempty"`     // region of s3 (default: AWS_REGION)
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"` // endpoint of an S3 compatible storage
+	// JSON key of the service account to sign URLs of gcs with (default: GOOGLE_APPLICATION_CREDENTIALS)
+	CredentialsFile string `yaml:"credentialsFile,omitempty" json:"credentialsFile,omitempty"`
+	// expiration of the signed URLs of gcs and s3 (default: 15m)
+	Expires string `yaml:"expires,omitempty" json:"expires,omitempty"`
+	// URL to fetch the images uploaded by the command from, followed by the names of the images
+	BaseURL string `yaml:"baseURL,omitempty" json:"baseURL,omitempty"`
+	// command to upload the images
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
+	// command to delete the images after apply
+	DeleteCommand string `yaml:"deleteCommand,omitempty" json:"deleteCommand,omitempty"`
+}
+
+type ImageOptimize struct {
+	MaxWidth  int    `yaml:"maxWidth,omitempty" json:"maxWidth,omitempty"`   // maximum width in pixels
+	MaxHeight int    `yaml:"maxHeight,omitempty" json:"maxHeight,omitempty"` // maximum height in pixels
+	Format    string `yaml:"format,omitempty" json:"format,omitempty"`       // format to re-encode to: jpeg or png (not webp)
+	Quality   int    `yaml:"quality,omitempty" json:"quality,omitempty"`     // quality of JPEG encoding
 }
 
 type DefaultCondition struct {
@@ -46,25 +129,45 @@ func init() {
 // 2. $XDG_CONFIG_HOME/deck/config.yml
 // If no config file is found, it returns an empty Config struct.
 func Load(profile string) (*Config, error) {
+	cfg := &Config{}
+	for _, configPath := range configPaths(profile) {
+		if b, err := os.ReadFile(configPath); err == nil {
+			if err := yaml.Unmarshal(b, cfg); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+			}
+			return cfg, nil
+		}
+	}
+	// If no config file is found, return an empty config
+	return cfg, nil
+}
+
+// Path returns the path of the config file loaded by Load, or the path to create the config file
+// of the profile at if there is no config file.
+func Path(profile string) string {
+	paths := configPaths(profile)
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return paths[0]
+}
+
+// configPaths returns the paths of config files to search for in order.
+func configPaths(profile string) []string {
 	var configBasePaths []string
 	if profile != "" {
 		configBasePaths = append(configBasePaths, filepath.Join(configHomePath(), fmt.Sprintf("config-%s", profile)))
 	}
 	configBasePaths = append(configBasePaths, filepath.Join(configHomePath(), "config"))
-	cfg := &Config{}
+	var paths []string
 	for _, basePath := range configBasePaths {
 		for _, ext := range []string{".yml", ".yaml"} {
-			configPath := basePath + ext
-			if b, err := os.ReadFile(configPath); err == nil {
-				if err := yaml.Unmarshal(b, cfg); err != nil {
-					return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-				}
-				return cfg, nil
-			}
+			paths = append(paths, basePath+ext)
 		}
 	}
-	// If no config file is found, return an empty config
-	return cfg, nil
+	return paths
 }
 
 // On macOS, we use directories that conform to the XDG Base Directory instead of `os.UserConfigDir`
@@ -91,6 +194,13 @@ var stateHomePath = sync.OnceValue(func() string {
 	return filepath.Join(homeDir, ".local", "state", "deck")
 })
 
+var cacheHomePath = sync.OnceValue(func() string {
+	if v := os.Getenv("XDG_CACHE_HOME"); v != "" {
+		return filepath.Join(v, "deck")
+	}
+	return filepath.Join(homeDir, ".cache", "deck")
+})
+
 // DataHomePath returns the path to the data home directory.
 func DataHomePath() string {
 	return dataHomePath()
@@ -99,3 +209,8 @@ func DataHomePath() string {
 func StateHomePath() string {
 	return stateHomePath()
 }
+
+// CacheHomePath returns the path to the cache home directory.
+func CacheHomePath() string {
+	return cacheHomePath()
+}
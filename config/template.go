@@ -0,0 +1,48 @@
+package config
+
+// Template is the config file written by `deck init`. All fields are commented out, so that the built-in
+// defaults are used until they are uncommented.
+const Template = `# yaml-language-server: $schema=https://raw.githubusercontent.com/k1LoW/deck/main/schema.yml
+# Configuration of deck. Settings in the frontmatter of markdown files take precedence over this file.
+# See https://github.com/k1LoW/deck#configuration-file for all fields.
+
+# Base presentation whose theme and layouts are used by "deck new"
+# basePresentationID: ""
+
+# Folder to create presentations and upload temporary images to (default: My Drive)
+# folderID: ""
+
+# Whether to display line breaks in the markdown as line breaks
+# breaks: false
+
+# Command to convert code blocks to images. "builtin" renders them with the built-in renderer
+# codeBlockToImageCommand: builtin
+
+# How to render code blocks: "image" (default) or "text"
+# codeBlock: image
+
+# Background color of text highlighted with <mark>
+# highlightColor: "#ffff00"
+
+# Layout for pages without a layout (default: the first "TITLE_AND_BODY" layout of the presentation)
+# defaultLayout: title-and-body
+
+# Layout for the first page without a layout (default: the first "TITLE" layout of the presentation)
+# firstPageLayout: title
+
+# Maximum number of concurrent image operations
+# concurrency: 4
+
+# Refuse to apply when more pages than this would be deleted
+# maxDeletions: 10
+
+# Default page configs by conditions written in CEL
+# defaults:
+#   - if: speakerNote.contains("TODO")
+#     skip: true
+
+# Commands to run around "deck apply"
+# hooks:
+#   beforeApply: ""
+#   afterApply: ""
+`
@@ -26,10 +26,17 @@ func convertToSlide(p *slides.Page, layoutObjectIdMap map[string]*slides.Page) *
 	var images []*Image
 	var blockQuotes []*BlockQuote
 	var tables []*Table
+	var elements []*Element
+
+	credits := creditsOf(p)
 
 	// Extract titles, subtitles, and bodies from page elements
 	for _, element := range p.PageElements {
 		switch {
+		case element.Description == descriptionElementFromMarkdown:
+			if e := convertToElement(element.Title); e != nil {
+				elements = append(elements, e)
+			}
 		case element.Shape != nil && element.Shape.Text != nil && element.Shape.Placeholder != nil:
 			switch element.Shape.Placeholder.Type {
 			case "CENTERED_TITLE", "TITLE":
@@ -55,7 +62,7 @@ func convertToSlide(p *slides.Page, layoutObjectIdMap map[string]*slides.Page) *
 				image *Image
 				err   error
 			)
-			if element.Description == descriptionImageFromMarkdown {
+			if isImageFromMarkdown(element.Description) {
 				image, err = NewImageFromMarkdown(element.Image.ContentUrl)
 				if err != nil {
 					continue // Skip if image cannot be created
@@ -69,7 +76,16 @@ func convertToSlide(p *slides.Page, layoutObjectIdMap map[string]*slides.Page) *
 			if element.Image.ImageProperties != nil && element.Image.ImageProperties.Link != nil {
 				image.link = element.Image.ImageProperties.Link.Url
 			}
+			image.setAltText(element.Title, element.Description)
+			image.credit = credits[element.ObjectId]
 			images = append(images, image)
+		case element.Shape != nil && element.Description == descriptionKeyFromMarkdown:
+			slide.Key = element.Title
+		case element.Shape != nil && element.Description == descriptionMetadataFromMarkdown:
+			if m := pageMetadataOf(&slides.Page{PageElements: []*slides.PageElement{element}}); m != nil {
+				slide.hash = m.Hash
+				m.setSettings(slide)
+			}
 		case element.Shape != nil && element.Shape.ShapeType == "TEXT_BOX" && element.Shape.Text != nil:
 			if element.Description != descriptionTextboxFromMarkdown {
 				continue
@@ -80,7 +96,7 @@ func convertToSlide(p *slides.Page, layoutObjectIdMap map[string]*slides.Page) *
 			blockQuotes = append(blockQuotes, bq)
 		case element.Table != nil:
 			// Convert Google Slides table to deck Table
-			table := convertSlidesToTable(element.Table)
+			table := convertSlidesToTable(element.Table, isHeaderTableElement(element))
 			if table != nil {
 				tables = append(tables, table)
 			}
@@ -93,6 +109,7 @@ func convertToSlide(p *slides.Page, layoutObjectIdMap map[string]*slides.Page) *
 	slide.Images = images
 	slide.BlockQuotes = blockQuotes
 	slide.Tables = tables
+	slide.Elements = elements
 
 	// Extract speaker notes
 	if p.SlideProperties != nil && p.SlideProperties.NotesPage != nil {
@@ -125,8 +142,8 @@ func extractText(text *slides.TextContent) string {
 	return strings.TrimSpace(str)
 }
 
-// Regexp to match numberd (ordered) bullet points. (e.g., "1.", "2.", "A.", "B.", "a.", "b.", "i.", "ii.", "iii.").
-var numberedBulletReg = regexp.MustCompile(`^(?:[0-9]+|[a-zA-Z]|i+)\.$`)
+// Regexp to match numberd (ordered) bullet points. (e.g., "1.", "2.", "A.", "B.", "a.", "aa.", "i.", "iv.", "xii.").
+var numberedBulletReg = regexp.MustCompile(`^(?:[0-9]+|[a-zA-Z]+)\.$`)
 
 // convertToParagraphs converts TextContent to a slice of Paragraphs.
 func convertToParagraphs(text *slides.TextContent) []*Paragraph {
@@ -196,14 +213,14 @@ func convertToParagraphs(text *slides.TextContent) []*Paragraph {
 
 func convertTextRunToFragment(textRun *slides.TextRun) *Fragment {
 	// Get styles from TextRun
-	var bold, italic, code bool
+	var bold, italic, underline, code bool
 	var link string
 	if textRun.Style != nil {
 		bold = textRun.Style.Bold
 		italic = textRun.Style.Italic
-		if textRun.Style.Link != nil && textRun.Style.Link.Url != "" {
-			link = textRun.Style.Link.Url
-		}
+		link = fromLink(textRun.Style.Link)
+		// Links are underlined by the default link style, so the underline is only taken from text without a link.
+		underline = textRun.Style.Underline && link == ""
 
 		// Detect code style (based on font family and background color)
 		if textRun.Style.FontFamily == defaultCodeFontFamily ||
@@ -227,16 +244,18 @@ func convertTextRunToFragment(textRun *slides.TextRun) *Fragment {
 		return nil
 	}
 	return &Fragment{
-		Value:  content,
-		Bold:   bold,
-		Italic: italic,
-		Code:   code,
-		Link:   link,
+		Value:     content,
+		Bold:      bold,
+		Italic:    italic,
+		Underline: underline,
+		Code:      code,
+		Link:      link,
 	}
 }
 
 // convertSlidesToTable converts a Google Slides table to deck Table structure.
-func convertSlidesToTable(slidesTable *slides.Table) *Table {
+// If header is true, the cells in the first row are header cells.
+func convertSlidesToTable(slidesTable *slides.Table, header bool) *Table {
 	if slidesTable == nil || len(slidesTable.TableRows) == 0 {
 		return nil
 	}
@@ -262,7 +281,10 @@ func convertSlidesToTable(slidesTable *slides.Table) *Table {
 			row.Cells[j] = &TableCell{
 				Fragments: extractFragmentsFromTableCell(slidesCell),
 				Alignment: extractAlignmentFromTableCell(slidesCell),
-				IsHeader:  i == 0,
+				IsHeader:  header && i == 0,
+			}
+			if slidesCell.TableCellProperties != nil {
+				row.Cells[j].VerticalAlignment = slidesCell.TableCellProperties.ContentAlignment
 			}
 		}
 
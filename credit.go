@@ -0,0 +1,120 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/slides/v1"
+)
+
+// Credits of images are rendered as small caption text boxes anchored beneath the images.
+// The object ID of the image is stored in the title of the alt text of the caption, so that the credit can be
+// read back with the image. Captions are recreated whenever the page is applied, following the images.
+
+const (
+	descriptionCreditTextboxFromMarkdown = "Credit textbox generated from markdown" // the object ID of the image is stored in the title of the alt text
+	creditHeight                         = 228600                                   // height of the caption in EMU (18pt)
+	creditFontSize                       = 8                                        // font size of the caption in points
+	emuPerPixel                          = 9525                                     // EMU per pixel at 96 DPI
+)
+
+// SetCredit sets the credit of the image (e.g. "© NASA"), rendered as a caption beneath the image.
+func (i *Image) SetCredit(credit string) {
+	i.credit = credit
+}
+
+// Credit returns the credit of the image.
+func (i *Image) Credit() string {
+	return i.credit
+}
+
+// intrinsicSize returns the size of the image in EMU at 96 DPI, which is the size of images inserted without size.
+func (i *Image) intrinsicSize() (width, height float64, ok bool) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(i.b))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+		return 0, 0, false
+	}
+	return float64(cfg.Width * emuPerPixel), float64(cfg.Height * emuPerPixel), true
+}
+
+// elementRect returns the rectangle of the page element, or false if its size is unknown.
+func elementRect(e *slides.PageElement) (rect, bool) {
+	width, height := elementSize(e)
+	if width == 0 || height == 0 {
+		return rect{}, false
+	}
+	return rect{x: e.Transform.TranslateX, y: e.Transform.TranslateY, width: width, height: height}, true
+}
+
+// creditRequests returns requests to create the caption of the credit beneath the image identified by imageObjectID
+// placed in r on the page identified by pageObjectID. The "credit" style is applied to the caption if it is defined.
+func (b *requestBuilder) creditRequests(pageObjectID, imageObjectID, credit string, r rect) []*slides.Request {
+	textBoxObjectID := fmt.Sprintf("credit-%s", uuid.New().String())
+	style := &slides.TextStyle{
+		FontSize: &slides.Dimension{Magnitude: creditFontSize, Unit: "PT"},
+	}
+	fields := "fontSize"
+	if s, ok := b.styles["credit"]; ok {
+		req := buildCustomStyleRequest(s)
+		style, fields = req.Style, req.Fields
+	}
+	return []*slides.Request{
+		{
+			CreateShape: &slides.CreateShapeRequest{
+				ObjectId:  textBoxObjectID,
+				ShapeType: "TEXT_BOX",
+				ElementProperties: &slides.PageElementProperties{
+					PageObjectId: pageObjectID,
+					Size: &slides.Size{
+						Width:  &slides.Dimension{Magnitude: r.width, Unit: "EMU"},
+						Height: &slides.Dimension{Magnitude: creditHeight, Unit: "EMU"},
+					},
+					Transform: &slides.AffineTransform{
+						ScaleX:     1.0,
+						ScaleY:     1.0,
+						TranslateX: r.x,
+						TranslateY: r.y + r.height,
+						Unit:       "EMU",
+					},
+				},
+			},
+		},
+		{
+			InsertText: &slides.InsertTextRequest{
+				ObjectId: textBoxObjectID,
+				Text:     credit,
+			},
+		},
+		{
+			UpdateTextStyle: &slides.UpdateTextStyleRequest{
+				ObjectId: textBoxObjectID,
+				Style:    style,
+				Fields:   fields,
+				TextRange: &slides.Range{
+					Type: "ALL",
+				},
+			},
+		},
+		{
+			UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+				ObjectId:    textBoxObjectID,
+				Title:       imageObjectID,
+				Description: descriptionCreditTextboxFromMarkdown,
+			},
+		},
+	}
+}
+
+// creditsOf returns the credits of the images on the page keyed by the object IDs of the images.
+func creditsOf(page *slides.Page) map[string]string {
+	credits := map[string]string{}
+	for _, element := range page.PageElements {
+		if element.Description != descriptionCreditTextboxFromMarkdown || element.Shape == nil {
+			continue
+		}
+		credits[element.Title] = extractText(element.Shape.Text)
+	}
+	return credits
+}
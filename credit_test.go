@@ -0,0 +1,67 @@
+package deck
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/deck/fakeslides"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestApplyImageCredit(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slideWithCredit := func(credit string) Slides {
+		img := newImage(t, "testdata/test.png")
+		img.SetCredit(credit)
+		return Slides{{Layout: "title-and-body", Titles: []string{"Credit"}, Images: []*Image{img}}}
+	}
+
+	for _, credit := range []string{"© NASA", "Photo by Jane Doe", ""} {
+		if err := d.Apply(ctx, slideWithCredit(credit)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := d.DumpSlides(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || len(got[0].Images) != 1 {
+			t.Fatalf("got %d slides, want 1 slide with 1 image", len(got))
+		}
+		if got := got[0].Images[0].Credit(); got != credit {
+			t.Errorf("got credit %q, want %q", got, credit)
+		}
+
+		page := srv.Presentation(d.ID()).Slides[0]
+		var captions int
+		for _, e := range page.PageElements {
+			if e.Description != descriptionCreditTextboxFromMarkdown {
+				continue
+			}
+			captions++
+			k := slices.IndexFunc(page.PageElements, func(image *slides.PageElement) bool {
+				return image.ObjectId == e.Title
+			})
+			if k < 0 {
+				t.Fatalf("image %s of the caption not found", e.Title)
+			}
+			r, _ := elementRect(page.PageElements[k])
+			if e.Transform.TranslateY != r.y+r.height || e.Transform.TranslateX != r.x {
+				t.Errorf("caption is not beneath the image: caption at (%v, %v), image %+v", e.Transform.TranslateX, e.Transform.TranslateY, r)
+			}
+		}
+		want := 1
+		if credit == "" {
+			want = 0
+		}
+		if captions != want {
+			t.Errorf("got %d captions, want %d", captions, want)
+		}
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"os"
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/k1LoW/deck/config"
 	"github.com/k1LoW/errors"
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
 	"google.golang.org/api/slides/v1"
@@ -32,9 +35,54 @@ type Deck struct {
 	defaultLayout      string
 	styles             map[string]*slides.TextStyle
 	shapes             map[string]*slides.ShapeProperties
+	paragraphStyles    map[string]*slides.ParagraphStyle
+	highlightColor     string
 	tableStyle         *TableStyle
 	logger             *slog.Logger
 	fresh              bool
+	stale              bool                // the whole presentation has to be fetched on refresh
+	dirtyPages         map[string]struct{} // object IDs of the pages mutated since the last refresh
+
+	// image operation settings
+	concurrency       int
+	imageSem          *semaphore.Weighted
+	uploadParallelism int
+	hostParallelism   int
+	uploadBytesPerSec int64
+	adaptiveUpload    bool
+	imageFolderID     string
+	imageNamePrefix   string
+	keepUploaded      bool
+	imageOptimization *ImageOptimization
+	imageHost         ImageHost
+
+	notifyOwners bool
+	changes      []*PageChange
+	summary      ApplySummary // pages appended, updated, moved and deleted by the last apply
+	hotSwap      bool
+	maxDeletions *int // maximum number of pages deleted by an apply (nil: unlimited)
+	elementFunc  ElementFunc
+	pageSize     *PageSize // page size to create the presentation with, or to validate the presentation against
+
+	continueOnError bool          // continue applying the remaining pages when some pages fail
+	pageTimeout     time.Duration // time budget to prepare each page (0: no budget)
+
+	editRetries        int    // number of times to plan and apply again when the presentation is edited during apply
+	requiredRevisionID string // revision required by batch updates during apply, empty outside apply
+
+	journal        *Journal
+	lastRevisionID string // revision ID returned by the last batch update
+	progress       *progressReporter
+	usage          usageCounter // API calls made since the Deck was created
+	lastUsage      APIUsage     // API calls made by the last apply
+	usageFunc      APIUsageFunc
+
+	endpoint string // base URL of the API server to use instead of Google's, without authentication
+
+	// authentication settings
+	impersonateUser           string
+	impersonateServiceAccount string
+	isolateTokenCache         bool
 }
 
 type Option func(*Deck) error
@@ -46,6 +94,39 @@ func WithPresentationID(id string) Option {
 	}
 }
 
+// WithImpersonateUser sets the user to impersonate with a service account (domain-wide delegation).
+func WithImpersonateUser(email string) Option {
+	return func(d *Deck) error {
+		d.impersonateUser = email
+		return nil
+	}
+}
+
+// WithImpersonateServiceAccount sets the service account to impersonate with Application Default Credentials.
+func WithImpersonateServiceAccount(email string) Option {
+	return func(d *Deck) error {
+		d.impersonateServiceAccount = email
+		return nil
+	}
+}
+
+// WithIsolatedTokenCache caches OAuth2 tokens per presentation.
+func WithIsolatedTokenCache(enable bool) Option {
+	return func(d *Deck) error {
+		d.isolateTokenCache = enable
+		return nil
+	}
+}
+
+// WithEndpoint accesses the Google Slides and Google Drive APIs at the base URL instead of Google's, without
+// authentication. It is intended for fake servers such as the one of the fakeslides package.
+func WithEndpoint(endpoint string) Option {
+	return func(d *Deck) error {
+		d.endpoint = strings.TrimSuffix(endpoint, "/")
+		return nil
+	}
+}
+
 func WithLogger(logger *slog.Logger) Option {
 	return func(d *Deck) error {
 		d.logger = logger
@@ -70,10 +151,118 @@ func WithFolderID(folderID string) Option {
 	}
 }
 
+// WithConcurrency sets the maximum number of image operations (preloading, uploading and cleanup)
+// running concurrently across the whole apply.
+func WithConcurrency(n int) Option {
+	return func(d *Deck) error {
+		if n < 0 {
+			return fmt.Errorf("invalid concurrency: %d", n)
+		}
+		d.concurrency = n
+		return nil
+	}
+}
+
+// WithUploadParallelism sets the maximum number of images uploaded concurrently.
+func WithUploadParallelism(n int) Option {
+	return func(d *Deck) error {
+		if n < 0 {
+			return fmt.Errorf("invalid upload parallelism: %d", n)
+		}
+		d.uploadParallelism = n
+		return nil
+	}
+}
+
+// WithHostParallelism sets the maximum number of concurrent image fetches per host.
+// 0 means no per-host limit.
+func WithHostParallelism(n int) Option {
+	return func(d *Deck) error {
+		if n < 0 {
+			return fmt.Errorf("invalid per-host parallelism: %d", n)
+		}
+		d.hostParallelism = n
+		return nil
+	}
+}
+
+// WithUploadBandwidth limits the total upload throughput in bytes per second.
+// 0 means unlimited.
+func WithUploadBandwidth(bytesPerSec int64) Option {
+	return func(d *Deck) error {
+		if bytesPerSec < 0 {
+			return fmt.Errorf("invalid upload bandwidth: %d", bytesPerSec)
+		}
+		d.uploadBytesPerSec = bytesPerSec
+		return nil
+	}
+}
+
+// WithAdaptiveUpload enables adaptive scaling of upload parallelism based on observed latency and error rates.
+func WithAdaptiveUpload(enabled bool) Option {
+	return func(d *Deck) error {
+		d.adaptiveUpload = enabled
+		return nil
+	}
+}
+
+// WithImageFolderID sets the Drive folder ID to upload images to.
+// If not set, the folder ID set by WithFolderID is used.
+func WithImageFolderID(folderID string) Option {
+	return func(d *Deck) error {
+		d.imageFolderID = folderID
+		return nil
+	}
+}
+
+// WithImageFileNamePrefix sets the prefix of the file names of uploaded images.
+func WithImageFileNamePrefix(prefix string) Option {
+	return func(d *Deck) error {
+		d.imageNamePrefix = prefix
+		return nil
+	}
+}
+
+// WithKeepUploadedImages keeps uploaded images in Drive instead of deleting them after apply,
+// so that they stay referenceable.
+func WithKeepUploadedImages(keep bool) Option {
+	return func(d *Deck) error {
+		d.keepUploaded = keep
+		return nil
+	}
+}
+
+// WithNotifyOwners enables posting a Drive comment mentioning the owner when an owned page changes.
+func WithNotifyOwners(enabled bool) Option {
+	return func(d *Deck) error {
+		d.notifyOwners = enabled
+		return nil
+	}
+}
+
+// WithHotSwap builds updated pages on hidden duplicates and swaps them into place,
+// so that viewers never see a half-rendered page. The swapped pages have new object IDs.
+func WithHotSwap(enabled bool) Option {
+	return func(d *Deck) error {
+		d.hotSwap = enabled
+		return nil
+	}
+}
+
+// WithJournal records the progress of apply to the journal so that an interrupted apply can be resumed.
+func WithJournal(j *Journal) Option {
+	return func(d *Deck) error {
+		d.journal = j
+		return nil
+	}
+}
+
 type placeholder struct {
 	objectID string
 	x        float64
 	y        float64
+	width    float64 // width in EMU, 0 if unknown
+	height   float64 // height in EMU, 0 if unknown
 }
 
 type bulletRange struct {
@@ -118,6 +307,26 @@ func Create(ctx context.Context, opts ...Option) (_ *Deck, err error) {
 		return nil, err
 	}
 	title := "Untitled"
+	if d.pageSize != nil {
+		// The page size can be set only by creating the presentation with the Slides API.
+		p, err := d.srv.Presentations.Create(&slides.Presentation{
+			Title:    title,
+			PageSize: d.pageSize.toSize(),
+		}).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		d.id = p.PresentationId
+		if d.folderID != "" {
+			if err := d.MoveToFolder(ctx, d.folderID); err != nil {
+				return nil, err
+			}
+		}
+		if err := d.refresh(ctx); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
 	file := &drive.File{
 		Name:     title,
 		MimeType: "application/vnd.google-apps.presentation",
@@ -145,6 +354,17 @@ func CreateFrom(ctx context.Context, id string, opts ...Option) (_ *Deck, err er
 	if err != nil {
 		return nil, err
 	}
+	if d.pageSize != nil {
+		// The copy has the page size of the base presentation, which cannot be changed with the API.
+		base, err := d.srv.Presentations.Get(id).Fields("pageSize").Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		if !d.pageSize.Match(base.PageSize) {
+			return nil, fmt.Errorf("page size of the base presentation is %s, which does not match the page size %s",
+				formatPageSize(base.PageSize), d.pageSize)
+		}
+	}
 	// copy presentation
 	file := &drive.File{
 		Name:     "Untitled",
@@ -174,6 +394,33 @@ func CreateFrom(ctx context.Context, id string, opts ...Option) (_ *Deck, err er
 	return d, nil
 }
 
+// Copy copies the presentation of the ID with its pages and creates a new Deck of the copy.
+// If name is empty, the copy is named by Google Drive (e.g. "Copy of ...").
+func Copy(ctx context.Context, id, name string, opts ...Option) (_ *Deck, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d, err := newDeck(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	file := &drive.File{
+		Name: name,
+	}
+	if d.folderID != "" {
+		file.Parents = []string{d.folderID}
+	}
+	f, err := d.driveSrv.Files.Copy(id, file).SupportsAllDrives(true).Do()
+	if err != nil {
+		return nil, err
+	}
+	d.id = f.Id
+	if err := d.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
 func Doctor(ctx context.Context, opts ...Option) error {
 	d, err := newDeck(ctx, opts...)
 	if err != nil {
@@ -202,6 +449,28 @@ func (d *Deck) UpdateTitle(ctx context.Context, title string) (err error) {
 	return nil
 }
 
+// MoveToFolder moves the presentation to the folder. The folder can be in a shared drive,
+// and the ID of a shared drive moves the presentation to the root folder of the shared drive.
+func (d *Deck) MoveToFolder(ctx context.Context, folderID string) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	f, err := d.driveSrv.Files.Get(d.id).Fields("parents").SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get the folder of the presentation: %w", err)
+	}
+	if slices.Contains(f.Parents, folderID) {
+		return nil
+	}
+	if _, err := d.driveSrv.Files.Update(d.id, &drive.File{}).
+		AddParents(folderID).
+		RemoveParents(strings.Join(f.Parents, ",")).
+		SupportsAllDrives(true).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to move the presentation: %w", err)
+	}
+	return nil
+}
+
 // Export the presentation as PDF.
 func (d *Deck) Export(ctx context.Context, w io.Writer) (err error) {
 	defer func() {
@@ -301,15 +570,21 @@ func (d *Deck) AllowReadingByAnyone(ctx context.Context, objectID string) (err e
 
 func newDeck(ctx context.Context, opts ...Option) (*Deck, error) {
 	d := &Deck{
-		styles:     map[string]*slides.TextStyle{},
-		shapes:     map[string]*slides.ShapeProperties{},
-		tableStyle: defaultTableStyle(),
+		styles:          map[string]*slides.TextStyle{},
+		shapes:          map[string]*slides.ShapeProperties{},
+		paragraphStyles: map[string]*slides.ParagraphStyle{},
+		tableStyle:      defaultTableStyle(),
+		editRetries:     defaultEditRetries,
 	}
 	for _, opt := range opts {
 		if err := opt(d); err != nil {
 			return nil, err
 		}
 	}
+	if d.concurrency == 0 {
+		d.concurrency = defaultConcurrency
+	}
+	d.imageSem = semaphore.NewWeighted(int64(d.concurrency))
 	err := d.initialize(ctx)
 	return d, err
 }
@@ -327,19 +602,29 @@ func (d *Deck) initialize(ctx context.Context) (err error) {
 		return err
 	}
 
-	// Get client option (service account or OAuth2)
-	client, err := d.getHTTPClient(ctx)
-	if err != nil {
-		return errors.Join(err, HTTPClientError)
+	var slidesOpts, driveOpts []option.ClientOption
+	if d.endpoint != "" {
+		client := countingClient(&http.Client{}, &d.usage)
+		slidesOpts = append(slidesOpts, option.WithHTTPClient(client), option.WithEndpoint(d.endpoint+"/"))
+		driveOpts = append(driveOpts, option.WithHTTPClient(client), option.WithEndpoint(d.endpoint+"/drive/v3/"))
+	} else {
+		// Get client option (service account or OAuth2)
+		client, err := d.getHTTPClient(ctx)
+		if err != nil {
+			return errors.Join(err, HTTPClientError)
+		}
+		client = countingClient(client, &d.usage)
+		slidesOpts = append(slidesOpts, option.WithHTTPClient(client))
+		driveOpts = append(driveOpts, option.WithHTTPClient(client))
 	}
 
-	srv, err := slides.NewService(ctx, option.WithHTTPClient(client))
+	srv, err := slides.NewService(ctx, slidesOpts...)
 	if err != nil {
 		return err
 	}
 	srv.UserAgent = userAgent
 	d.srv = srv
-	driveSrv, err := drive.NewService(ctx, option.WithHTTPClient(client))
+	driveSrv, err := drive.NewService(ctx, driveOpts...)
 	if err != nil {
 		return err
 	}
@@ -360,12 +645,14 @@ func (d *Deck) createPage(ctx context.Context, index int, slide *Slide) (err err
 	}
 
 	// create new page
+	// The insertion index 0 is sent explicitly, as the page is appended to the end if it is omitted.
 	reqs := []*slides.Request{{
 		CreateSlide: &slides.CreateSlideRequest{
 			InsertionIndex: int64(index),
 			SlideLayoutReference: &slides.LayoutReference{
 				LayoutId: layout.ObjectId,
 			},
+			ForceSendFields: []string{"InsertionIndex"},
 		},
 	}}
 
@@ -408,30 +695,58 @@ func (d *Deck) movePage(ctx context.Context, from_index, to_index int) (err erro
 	defer func() {
 		err = errors.WithStack(err)
 	}()
-	if from_index == to_index || from_index < 0 || to_index < 0 || from_index >= len(d.presentation.Slides) || to_index >= len(d.presentation.Slides) {
+	req := d.movePageRequest(from_index, to_index)
+	if req == nil {
 		return nil
 	}
+	if err := d.batchUpdate(ctx, []*slides.Request{req}); err != nil {
+		return err
+	}
+	if err := d.refresh(ctx); err != nil {
+		return err
+	}
+	return nil
+}
 
+// movePageRequest returns the request to move the page at from_index to to_index, or nil if the page does not move.
+// The page is moved in the snapshot as well, so that the indices of the following pages can be resolved
+// before the request is sent. The snapshot is refreshed as a whole after the request is sent.
+func (d *Deck) movePageRequest(from_index, to_index int) *slides.Request {
+	if from_index == to_index || from_index < 0 || to_index < 0 || from_index >= len(d.presentation.Slides) || to_index >= len(d.presentation.Slides) {
+		return nil
+	}
 	currentSlide := d.presentation.Slides[from_index]
-
+	// The insertion index is based on the arrangement before the move.
+	insertionIndex := to_index
 	if from_index < to_index {
-		to_index++
+		insertionIndex++
 	}
-
-	reqs := []*slides.Request{{
+	req := &slides.Request{
 		UpdateSlidesPosition: &slides.UpdateSlidesPositionRequest{
 			SlideObjectIds:  []string{currentSlide.ObjectId},
-			InsertionIndex:  int64(to_index),
+			InsertionIndex:  int64(insertionIndex),
 			ForceSendFields: []string{"InsertionIndex"},
 		},
-	}}
-	if err := d.batchUpdate(ctx, reqs); err != nil {
-		return err
 	}
-	if err := d.refresh(ctx); err != nil {
-		return err
+	d.markDirty([]*slides.Request{req})
+	d.presentation.Slides = slices.Insert(slices.Delete(d.presentation.Slides, from_index, from_index+1), to_index, currentSlide)
+	return req
+}
+
+// deletePageRequest returns the request to delete the page at index, or nil if there is no page at index.
+// The page is deleted from the snapshot as well, like movePageRequest.
+func (d *Deck) deletePageRequest(index int) *slides.Request {
+	if index < 0 || index >= len(d.presentation.Slides) {
+		return nil
 	}
-	return nil
+	req := &slides.Request{
+		DeleteObject: &slides.DeleteObjectRequest{
+			ObjectId: d.presentation.Slides[index].ObjectId,
+		},
+	}
+	d.markDirty([]*slides.Request{req})
+	d.presentation.Slides = slices.Delete(d.presentation.Slides, index, index+1)
+	return req
 }
 
 func (d *Deck) layoutMap() map[string]*slides.Page {
@@ -484,11 +799,21 @@ func (d *Deck) refresh(ctx context.Context) (err error) {
 	if d.fresh {
 		return nil
 	}
+	refreshed, err := d.refreshDirtyPages(ctx)
+	if err != nil {
+		return err
+	}
+	if refreshed {
+		d.resetDirty()
+		d.fresh = true
+		return nil
+	}
 	presentation, err := d.srv.Presentations.Get(d.id).Context(ctx).Do()
 	if err != nil {
 		return err
 	}
 	d.presentation = presentation
+	d.resetDirty()
 
 	// set default layouts and detect style
 	for _, l := range d.presentation.Layouts {
@@ -508,7 +833,11 @@ func (d *Deck) refresh(ctx context.Context) (err error) {
 			for _, e := range l.PageElements {
 				// Extract text styles from shapes
 				if e.Shape != nil && e.Shape.Text != nil {
+					var paragraphStyle *slides.ParagraphStyle
 					for _, t := range e.Shape.Text.TextElements {
+						if t.ParagraphMarker != nil {
+							paragraphStyle = t.ParagraphMarker.Style
+						}
 						if t.TextRun == nil {
 							continue
 						}
@@ -518,6 +847,9 @@ func (d *Deck) refresh(ctx context.Context) (err error) {
 						}
 						d.styles[styleName] = t.TextRun.Style
 						d.shapes[styleName] = e.Shape.ShapeProperties
+						if paragraphStyle != nil {
+							d.paragraphStyles[styleName] = paragraphStyle
+						}
 					}
 				}
 
@@ -1,9 +1,11 @@
 package deck
 
 import (
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/k1LoW/deck/config"
 	"google.golang.org/api/slides/v1"
 )
 
@@ -175,6 +177,29 @@ func TestWithProfile(t *testing.T) {
 	}
 }
 
+func TestWithConcurrency(t *testing.T) {
+	tests := []struct {
+		n       int
+		wantErr bool
+	}{
+		{0, false},
+		{1, false},
+		{16, false},
+		{-1, true},
+	}
+	for _, tt := range tests {
+		d := &Deck{}
+		err := WithConcurrency(tt.n)(d)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("WithConcurrency(%d) error = %v, wantErr %v", tt.n, err, tt.wantErr)
+			continue
+		}
+		if err == nil && d.concurrency != tt.n {
+			t.Errorf("got concurrency %d, want %d", d.concurrency, tt.n)
+		}
+	}
+}
+
 func TestValidateLayouts(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -305,3 +330,26 @@ func TestValidateLayouts(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenPath(t *testing.T) {
+	t.Setenv(EnvIsolateTokenCache, "")
+	tests := []struct {
+		name string
+		d    *Deck
+		want string
+	}{
+		{"default", &Deck{}, "token.json"},
+		{"profile", &Deck{profile: "work"}, "token-work.json"},
+		{"not isolated", &Deck{id: "xxxxx"}, "token.json"},
+		{"isolated", &Deck{id: "xxxxx", isolateTokenCache: true}, "token-xxxxx.json"},
+		{"isolated with profile", &Deck{profile: "work", id: "xxxxx", isolateTokenCache: true}, "token-work-xxxxx.json"},
+		{"isolated without presentation", &Deck{isolateTokenCache: true}, "token.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := tt.d.tokenPath(), filepath.Join(config.StateHomePath(), tt.want); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
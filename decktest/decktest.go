@@ -0,0 +1,136 @@
+// Package decktest provides utilities to write regression tests for code extending deck without accessing
+// the Google Slides API. It builds the requests for slides or markdown with stable object IDs, records them
+// into golden JSON files and diffs them on later runs.
+package decktest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/md"
+	"github.com/k1LoW/deck/requests"
+	"google.golang.org/api/slides/v1"
+)
+
+// UpdateEnv is the environment variable to record the golden files instead of diffing them, e.g. `UPDATE_GOLDEN=1 go test ./...`.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// Requests returns the requests to fill the titles, subtitles, bodies, block quotes and tables of the slides.
+// Since there is no presentation, the object IDs are derived from the 1-based page numbers, such as
+// `page1` for the page, `page1-title0` and `page1-body0` for the placeholders, and `page1-table0` for tables.
+func Requests(ss deck.Slides, opts ...requests.Option) []*slides.Request {
+	var reqs []*slides.Request
+	for i, s := range ss {
+		pageObjectID := fmt.Sprintf("page%d", i+1)
+		for j, b := range s.TitleBodies {
+			reqs = append(reqs, requests.Paragraphs(fmt.Sprintf("%s-title%d", pageObjectID, j), b.Paragraphs, opts...)...)
+		}
+		for j, b := range s.SubtitleBodies {
+			reqs = append(reqs, requests.Paragraphs(fmt.Sprintf("%s-subtitle%d", pageObjectID, j), b.Paragraphs, opts...)...)
+		}
+		for j, b := range s.Bodies {
+			reqs = append(reqs, requests.Paragraphs(fmt.Sprintf("%s-body%d", pageObjectID, j), b.Paragraphs, opts...)...)
+		}
+		for j, bq := range s.BlockQuotes {
+			reqs = append(reqs, requests.BlockQuote(pageObjectID, fmt.Sprintf("%s-blockquote%d", pageObjectID, j), bq, j, opts...)...)
+		}
+		for j, t := range s.Tables {
+			reqs = append(reqs, requests.Table(pageObjectID, fmt.Sprintf("%s-table%d", pageObjectID, j), t, j, opts...)...)
+		}
+	}
+	return reqs
+}
+
+// RequestsFromMarkdown parses the markdown b and returns the requests for its slides, as Requests does.
+// Relative paths such as images are resolved against baseDir. Code blocks are not converted to images.
+func RequestsFromMarkdown(ctx context.Context, baseDir string, b []byte, opts ...requests.Option) ([]*slides.Request, error) {
+	m, err := md.Parse(baseDir, b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse markdown: %w", err)
+	}
+	ss, err := m.ToSlides(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert markdown to slides: %w", err)
+	}
+	return Requests(ss, opts...), nil
+}
+
+// Load reads the requests recorded in the golden file.
+func Load(path string) ([]*slides.Request, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var reqs []*slides.Request
+	if err := json.Unmarshal(b, &reqs); err != nil {
+		return nil, fmt.Errorf("failed to decode golden file %s: %w", path, err)
+	}
+	return reqs, nil
+}
+
+// Record writes the requests to the golden file, creating its directory if needed.
+func Record(path string, reqs []*slides.Request) error {
+	b, err := json.MarshalIndent(reqs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode requests: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o600)
+}
+
+// Diff returns the difference between the requests as JSON, or an empty string if they are the same.
+// Requests are compared as JSON so that fields sent by the API client, not their Go representation, matter.
+func Diff(want, got []*slides.Request) (string, error) {
+	w, err := toJSONValue(want)
+	if err != nil {
+		return "", err
+	}
+	g, err := toJSONValue(got)
+	if err != nil {
+		return "", err
+	}
+	return cmp.Diff(w, g), nil
+}
+
+// Golden compares the requests with the golden file at path, and reports the difference as a test error.
+// If the environment variable UpdateEnv is set, it records the requests into the golden file instead.
+func Golden(t testing.TB, path string, got []*slides.Request) {
+	t.Helper()
+	if os.Getenv(UpdateEnv) != "" {
+		if err := Record(path, got); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := Load(path)
+	if err != nil {
+		t.Fatalf("%v (run with %s=1 to record it)", err, UpdateEnv)
+	}
+	diff, err := Diff(want, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Errorf("requests differ from %s (-want +got):\n%s", path, diff)
+	}
+}
+
+func toJSONValue(reqs []*slides.Request) (any, error) {
+	b, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode requests: %w", err)
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
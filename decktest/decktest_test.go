@@ -0,0 +1,45 @@
+package decktest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestGolden(t *testing.T) {
+	b, err := os.ReadFile("testdata/basic.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := RequestsFromMarkdown(context.Background(), "testdata", b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Golden(t, "testdata/basic.golden.json", got)
+}
+
+func TestDiff(t *testing.T) {
+	want := []*slides.Request{{InsertText: &slides.InsertTextRequest{ObjectId: "page1-body0", Text: "Hello"}}}
+	tests := []struct {
+		name     string
+		got      []*slides.Request
+		wantDiff bool
+	}{
+		{"same", []*slides.Request{{InsertText: &slides.InsertTextRequest{ObjectId: "page1-body0", Text: "Hello"}}}, false},
+		{"different text", []*slides.Request{{InsertText: &slides.InsertTextRequest{ObjectId: "page1-body0", Text: "Hi"}}}, true},
+		{"more requests", append(want, &slides.Request{DeleteObject: &slides.DeleteObjectRequest{ObjectId: "page1-body0"}}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := Diff(want, tt.got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (diff != "") != tt.wantDiff {
+				t.Errorf("got diff %q, want diff: %v", diff, tt.wantDiff)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package deck
+
+import (
+	"fmt"
+	"slices"
+)
+
+// WithMaxDeletions refuses to apply when the apply would delete more pages than max.
+// Set 0 to refuse any deletion. By default, the number of deleted pages is not limited.
+func WithMaxDeletions(maxDeletions int) Option {
+	return func(d *Deck) error {
+		if maxDeletions < 0 {
+			return fmt.Errorf("invalid maximum number of deletions: %d", maxDeletions)
+		}
+		d.maxDeletions = &maxDeletions
+		return nil
+	}
+}
+
+// checkDeletions returns an error if the delete actions exceed the maximum number of deletions
+// or delete a frozen page, before anything is applied.
+// The index of a delete action is the index of the page in the presentation, and its slide is the page
+// itself, which is the frozen slide if the page is pinned to a frozen slide.
+func (d *Deck) checkDeletions(actions []*action) error {
+	var deleting []int
+	for _, a := range actions {
+		if a.actionType != actionTypeDelete {
+			continue
+		}
+		if a.slide != nil && (a.slide.Freeze || a.slide.pin != 0) {
+			return fmt.Errorf("refusing to delete frozen page %d", a.index+1)
+		}
+		deleting = append(deleting, a.index+1)
+	}
+	if d.maxDeletions == nil || len(deleting) <= *d.maxDeletions {
+		return nil
+	}
+	slices.Sort(deleting)
+	return fmt.Errorf("refusing to delete %d pages %v, more than the maximum number of deletions %d",
+		len(deleting), deleting, *d.maxDeletions)
+}
@@ -0,0 +1,63 @@
+package deck
+
+import (
+	"testing"
+)
+
+func TestCheckDeletions(t *testing.T) {
+	deletes := []*action{
+		{actionType: actionTypeUpdate, index: 0},
+		{actionType: actionTypeDelete, index: 4, slide: &Slide{}},
+		{actionType: actionTypeDelete, index: 3, slide: &Slide{}},
+	}
+	tests := []struct {
+		name         string
+		maxDeletions *int
+		actions      []*action
+		wantErr      bool
+	}{
+		{"unlimited", nil, deletes, false},
+		{"within the limit", new(2), deletes, false},
+		{"over the limit", new(1), deletes, true},
+		{"no deletions allowed", new(0), deletes, true},
+		{"no deletions", new(0), deletes[:1], false},
+		{"frozen page", nil, []*action{{actionType: actionTypeDelete, index: 1, slide: &Slide{Freeze: true}}}, true},
+		{"pinned page", nil, []*action{{actionType: actionTypeDelete, index: 1, slide: &Slide{pin: 2}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Deck{maxDeletions: tt.maxDeletions}
+			err := d.checkDeletions(tt.actions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkDeletions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDeletionsBeforeFrozenPage(t *testing.T) {
+	page := func(title string) *Slide {
+		return &Slide{Layout: "title-and-body", Titles: []string{title}}
+	}
+	frozen := page("Y")
+	frozen.Freeze = true
+	before := Slides{page("A"), page("X"), page("Y")}
+	after := Slides{page("A"), frozen}
+	actions, err := generateActions(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deleted []int
+	for _, a := range actions {
+		if a.actionType == actionTypeDelete {
+			deleted = append(deleted, a.index+1)
+		}
+	}
+	if len(deleted) != 1 || deleted[0] != 2 {
+		t.Fatalf("got deleted pages %v, want [2]", deleted)
+	}
+	d := &Deck{}
+	if err := d.checkDeletions(actions); err != nil {
+		t.Errorf("got error %v for deleting the page X before the frozen page", err)
+	}
+}
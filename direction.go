@@ -0,0 +1,117 @@
+package deck
+
+import (
+	"fmt"
+	"slices"
+	"unicode"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// Text directions of the paragraphs of a slide.
+const (
+	DirectionLTR = "ltr" // left to right
+	DirectionRTL = "rtl" // right to left
+)
+
+// rtlScripts are the scripts written from right to left.
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Arabic,
+	unicode.Hebrew,
+	unicode.Syriac,
+	unicode.Thaana,
+	unicode.Nko,
+	unicode.Samaritan,
+	unicode.Mandaic,
+}
+
+// ValidateDirection returns an error if direction is not a valid text direction.
+// An empty direction detects the direction of each paragraph from its text.
+func ValidateDirection(direction string) error {
+	switch direction {
+	case "", DirectionLTR, DirectionRTL:
+		return nil
+	default:
+		return fmt.Errorf("invalid direction: %q (must be %q or %q)", direction, DirectionLTR, DirectionRTL)
+	}
+}
+
+// isRTL reports whether s is written from right to left, that is, whether its first letter belongs to a right-to-left script.
+func isRTL(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		return unicode.In(r, rtlScripts...)
+	}
+	return false
+}
+
+// paragraphDirection returns the paragraph direction of the Slides API for text.
+// When direction is empty, it is detected from text.
+func paragraphDirection(direction, text string) string {
+	switch direction {
+	case DirectionRTL:
+		return "RIGHT_TO_LEFT"
+	case DirectionLTR:
+		return "LEFT_TO_RIGHT"
+	}
+	if isRTL(text) {
+		return "RIGHT_TO_LEFT"
+	}
+	return "LEFT_TO_RIGHT"
+}
+
+// directionRange is the range of paragraphs with the same text direction.
+type directionRange struct {
+	direction string
+	start     int64
+	end       int64
+}
+
+// directionRequests returns requests to set the text directions of the ranges in the shape identified by objectID.
+// When the direction is detected and no paragraph is right to left, it returns nil to keep the directions as they are.
+func (b *requestBuilder) directionRequests(objectID string, ranges []*directionRange) []*slides.Request {
+	if b.direction == "" && !slices.ContainsFunc(ranges, func(r *directionRange) bool {
+		return r.direction == "RIGHT_TO_LEFT"
+	}) {
+		return nil
+	}
+	// merge adjacent ranges with the same direction
+	var merged []*directionRange
+	for _, r := range ranges {
+		if len(merged) > 0 && merged[len(merged)-1].direction == r.direction {
+			merged[len(merged)-1].end = r.end
+			continue
+		}
+		merged = append(merged, &directionRange{direction: r.direction, start: r.start, end: r.end})
+	}
+	var requests []*slides.Request
+	for _, r := range merged {
+		if r.start >= r.end {
+			continue
+		}
+		requests = append(requests, &slides.Request{
+			UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+				ObjectId: objectID,
+				Style: &slides.ParagraphStyle{
+					Direction: r.direction,
+				},
+				Fields: "direction",
+				TextRange: &slides.Range{
+					Type:       "FIXED_RANGE",
+					StartIndex: new(r.start),
+					EndIndex:   new(r.end),
+				},
+			},
+		})
+	}
+	return requests
+}
+
+// hasRTLParagraph reports whether text has a right-to-left paragraph.
+func hasRTLParagraph(text *slides.TextContent) bool {
+	return slices.ContainsFunc(text.TextElements, func(e *slides.TextElement) bool {
+		return e.ParagraphMarker != nil && e.ParagraphMarker.Style != nil && e.ParagraphMarker.Style.Direction == "RIGHT_TO_LEFT"
+	})
+}
@@ -0,0 +1,136 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestIsRTL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"", false},
+		{"Hello", false},
+		{"مرحبا", true},
+		{"שלום", true},
+		{"123 مرحبا", true}, // digits are not letters
+		{"Go مرحبا", false},
+		{"مرحبا Go", true},
+		{"こんにちは", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := isRTL(tt.in); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParagraphsRequestsDirection(t *testing.T) {
+	paragraphs := []*Paragraph{
+		{Fragments: []*Fragment{{Value: "Hello"}}},
+		{Fragments: []*Fragment{{Value: "مرحبا"}}, Bullet: BulletDash},
+		{Fragments: []*Fragment{{Value: "שלום"}}, Bullet: BulletDash},
+	}
+	directionRequest := func(direction string, start, end int64) *slides.Request {
+		return &slides.Request{
+			UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+				ObjectId:  "shape",
+				Style:     &slides.ParagraphStyle{Direction: direction},
+				Fields:    "direction",
+				TextRange: &slides.Range{Type: "FIXED_RANGE", StartIndex: new(start), EndIndex: new(end)},
+			},
+		}
+	}
+	bulletRequest := &slides.Request{
+		CreateParagraphBullets: &slides.CreateParagraphBulletsRequest{
+			ObjectId:     "shape",
+			BulletPreset: "BULLET_DISC_CIRCLE_SQUARE",
+			TextRange:    &slides.Range{Type: "FIXED_RANGE", StartIndex: new(int64(6)), EndIndex: new(int64(16))},
+		},
+	}
+	tests := []struct {
+		name       string
+		direction  string
+		paragraphs []*Paragraph
+		want       []*slides.Request
+	}{
+		{
+			"detected",
+			"",
+			paragraphs,
+			[]*slides.Request{
+				directionRequest("LEFT_TO_RIGHT", 0, 6),
+				directionRequest("RIGHT_TO_LEFT", 6, 16),
+				bulletRequest,
+			},
+		},
+		{
+			"rtl",
+			DirectionRTL,
+			paragraphs,
+			[]*slides.Request{
+				directionRequest("RIGHT_TO_LEFT", 0, 16),
+				bulletRequest,
+			},
+		},
+		{
+			"no rtl paragraphs",
+			"",
+			[]*Paragraph{{Fragments: []*Fragment{{Value: "Hello"}}}},
+			nil,
+		},
+		{
+			"ltr",
+			DirectionLTR,
+			[]*Paragraph{{Fragments: []*Fragment{{Value: "مرحبا"}}}},
+			[]*slides.Request{
+				directionRequest("LEFT_TO_RIGHT", 0, 5),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := newRequestBuilder(withDirection(tt.direction)).ParagraphsRequests("shape", tt.paragraphs)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTableContentRequestsDirection(t *testing.T) {
+	table := &Table{Rows: []*TableRow{{Cells: []*TableCell{
+		{Fragments: []*Fragment{{Value: "Name"}}, Alignment: "START"},
+		{Fragments: []*Fragment{{Value: "الاسم"}}, Alignment: "END"},
+	}}}}
+	var got []*slides.UpdateParagraphStyleRequest
+	for _, r := range newRequestBuilder().TableContentRequests("table", table) {
+		if r.UpdateParagraphStyle != nil {
+			got = append(got, r.UpdateParagraphStyle)
+		}
+	}
+	want := []*slides.UpdateParagraphStyleRequest{
+		{
+			ObjectId:     "table",
+			CellLocation: &slides.TableCellLocation{RowIndex: 0, ColumnIndex: 0},
+			Style:        &slides.ParagraphStyle{Alignment: "START"},
+			Fields:       "alignment",
+			TextRange:    &slides.Range{Type: "ALL"},
+		},
+		{
+			ObjectId:     "table",
+			CellLocation: &slides.TableCellLocation{RowIndex: 0, ColumnIndex: 1},
+			Style:        &slides.ParagraphStyle{Alignment: "END", Direction: "RIGHT_TO_LEFT"},
+			Fields:       "alignment,direction",
+			TextRange:    &slides.Range{Type: "ALL"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
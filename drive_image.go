@@ -0,0 +1,75 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// Permission IDs of Drive files shared with anyone.
+var publicPermissionIDs = []string{"anyoneWithLink", "anyone"}
+
+// loadDriveImages loads the images on Google Drive in the slides of the pages with the Drive API.
+// The images are not uploaded again, but inserted with the links of the existing files.
+func (d *Deck) loadDriveImages(ctx context.Context, ss Slides, pages []int) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(d.concurrency)
+	for _, page := range pages {
+		for _, i := range ss[page-1].Images {
+			if !i.needsDriveLoad() {
+				continue
+			}
+			eg.Go(func() error {
+				return d.loadDriveImage(ctx, i)
+			})
+		}
+	}
+	return eg.Wait()
+}
+
+func (d *Deck) loadDriveImage(ctx context.Context, i *Image) error {
+	f, err := d.driveSrv.Files.Get(i.driveFileID).SupportsAllDrives(true).
+		Fields("id", "name", "mimeType", "webContentLink", "permissionIds").Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get image %s on Google Drive: %w", i.url, err)
+	}
+	if !strings.HasPrefix(f.MimeType, "image/") {
+		return fmt.Errorf("file %s on Google Drive is not an image: %s", i.url, f.MimeType)
+	}
+	if !slices.ContainsFunc(f.PermissionIds, func(id string) bool {
+		return slices.Contains(publicPermissionIDs, id)
+	}) {
+		return fmt.Errorf("image %s (%s) on Google Drive is not shared with \"Anyone with the link\", so Google Slides cannot insert it. "+
+			"Share the file with \"Anyone with the link\" as a viewer, or download it and reference the local file", i.url, f.Name)
+	}
+	res, err := d.driveSrv.Files.Get(i.driveFileID).SupportsAllDrives(true).Context(ctx).Download()
+	if err != nil {
+		return fmt.Errorf("failed to download image %s on Google Drive: %w", i.url, err)
+	}
+	defer res.Body.Close()
+	loaded, err := newImageFromBuffer(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to load image %s on Google Drive: %w", i.url, err)
+	}
+	i.b = loaded.b
+	i.mimeType = loaded.mimeType
+	if loaded.converted {
+		// The file on Google Drive cannot be inserted as it is, so the converted image is uploaded.
+		d.logger.Debug("loaded image on Google Drive to be converted", slog.String("url", i.url), slog.String("name", f.Name))
+		return nil
+	}
+	i.webContentLink = f.WebContentLink
+	if i.webContentLink == "" {
+		i.webContentLink = fmt.Sprintf("https://drive.google.com/uc?id=%s&export=download", f.Id)
+	}
+	d.logger.Debug("loaded image on Google Drive", slog.String("url", i.url), slog.String("name", f.Name))
+	return nil
+}
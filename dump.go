@@ -0,0 +1,171 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"unicode"
+)
+
+// DumpOption is an option for DumpSlides.
+type DumpOption func(*dumpOptions)
+
+type dumpOptions struct {
+	pages  []int
+	redact bool
+}
+
+// WithDumpPages dumps only the pages of the 1-based page numbers, in the order of the presentation.
+func WithDumpPages(pages ...int) DumpOption {
+	return func(o *dumpOptions) {
+		o.pages = append(o.pages, pages...)
+	}
+}
+
+// WithRedaction redacts the text content of the dumped slides, so that they can be shared as fixtures or
+// in bug reports without confidential content. Letters and digits are replaced while whitespace, punctuation,
+// lengths, styles and structure are kept, and images are replaced with blank images of the same size.
+func WithRedaction() DumpOption {
+	return func(o *dumpOptions) {
+		o.redact = true
+	}
+}
+
+// filterPages returns the slides of the 1-based page numbers.
+func filterPages(ss Slides, pages []int) (Slides, error) {
+	selected := make([]bool, len(ss))
+	for _, p := range pages {
+		if p < 1 || p > len(ss) {
+			return nil, fmt.Errorf("page %d is out of range (1-%d)", p, len(ss))
+		}
+		selected[p-1] = true
+	}
+	var filtered Slides
+	for i, s := range ss {
+		if selected[i] {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered, nil
+}
+
+// redact redacts the text content of the slide in place.
+func (s *Slide) redact() error {
+	s.Titles = redactTexts(s.Titles)
+	s.Subtitles = redactTexts(s.Subtitles)
+	for _, bodies := range [][]*Body{s.TitleBodies, s.SubtitleBodies, s.Bodies} {
+		for _, b := range bodies {
+			redactParagraphs(b.Paragraphs)
+		}
+	}
+	for _, bq := range s.BlockQuotes {
+		redactParagraphs(bq.Paragraphs)
+	}
+	for _, t := range s.Tables {
+		for _, row := range t.Rows {
+			for _, cell := range row.Cells {
+				if cell != nil {
+					redactFragments(cell.Fragments)
+				}
+			}
+		}
+	}
+	for i, img := range s.Images {
+		redacted, err := img.redacted()
+		if err != nil {
+			return err
+		}
+		s.Images[i] = redacted
+	}
+	for _, e := range s.Elements {
+		for k, v := range e.Data {
+			e.Data[k] = redactValue(v)
+		}
+	}
+	s.SpeakerNote = redactText(s.SpeakerNote)
+	s.Owner = redactText(s.Owner)
+	return nil
+}
+
+func redactParagraphs(paragraphs []*Paragraph) {
+	for _, p := range paragraphs {
+		redactFragments(p.Fragments)
+	}
+}
+
+func redactFragments(fragments []*Fragment) {
+	for _, f := range fragments {
+		f.Value = redactText(f.Value)
+		f.Link = redactText(f.Link)
+	}
+}
+
+func redactTexts(texts []string) []string {
+	for i, t := range texts {
+		texts[i] = redactText(t)
+	}
+	return texts
+}
+
+// redactText replaces the letters and digits of s, keeping whitespace, punctuation and the length.
+func redactText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsDigit(r):
+			return '0'
+		case unicode.IsLetter(r):
+			return 'x'
+		default:
+			return r
+		}
+	}, s)
+}
+
+// redactValue redacts the strings in the JSON-encodable value v.
+func redactValue(v any) any {
+	switch vv := v.(type) {
+	case string:
+		return redactText(vv)
+	case []any:
+		for i := range vv {
+			vv[i] = redactValue(vv[i])
+		}
+		return vv
+	case map[string]any:
+		for k := range vv {
+			vv[k] = redactValue(vv[k])
+		}
+		return vv
+	default:
+		return v
+	}
+}
+
+// redacted returns a blank image of the same size as i, with the alt text redacted.
+func (i *Image) redacted() (*Image, error) {
+	width, height := 1, 1
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(i.b)); err == nil {
+		width, height = cfg.Width, cfg.Height
+	}
+	blank := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(blank, blank.Bounds(), &image.Uniform{C: color.Gray{Y: 0xcc}}, image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, blank); err != nil {
+		return nil, fmt.Errorf("failed to encode redacted image: %w", err)
+	}
+	redacted, err := newImageFromBuffer(&buf)
+	if err != nil {
+		return nil, err
+	}
+	redacted.fromMarkdown = i.fromMarkdown
+	redacted.placeholder = i.placeholder
+	redacted.alt = redactText(i.alt)
+	redacted.title = redactText(i.title)
+	redacted.credit = redactText(i.credit)
+	redacted.link = redactText(i.link)
+	return redacted, nil
+}
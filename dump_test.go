@@ -0,0 +1,82 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFilterPages(t *testing.T) {
+	ss := Slides{NewSlide("", "one"), NewSlide("", "two"), NewSlide("", "three")}
+	tests := []struct {
+		pages   []int
+		want    []string
+		wantErr bool
+	}{
+		{[]int{1}, []string{"one"}, false},
+		{[]int{3, 1}, []string{"one", "three"}, false},
+		{[]int{2, 2}, []string{"two"}, false},
+		{[]int{4}, nil, true},
+		{[]int{0}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := filterPages(ss, tt.pages)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("want error for pages %v", tt.pages)
+			continue
+		}
+		var titles []string
+		for _, s := range got {
+			titles = append(titles, s.Titles...)
+		}
+		if diff := cmp.Diff(tt.want, titles); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestRedactSlide(t *testing.T) {
+	img, err := NewImageFromBytes(dummyPNG(t).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	img.SetAlt("Secret chart")
+	s := NewSlide("Title and body", "Q3 revenue")
+	s.Bodies = []*Body{NewBody(&Paragraph{
+		Fragments: []*Fragment{{Value: "Up 12% ", Bold: true}, {Value: "see", Link: "https://example.com/x"}},
+		Bullet:    BulletDash,
+	})}
+	s.Tables = []*Table{NewTable([][]string{{"Region", "Sales"}, {"APAC", "120"}}, true)}
+	s.Images = []*Image{img}
+	s.Elements = []*Element{{Kind: "poll", Data: map[string]any{"question": "Why?", "options": []any{"A", "B"}, "max": 2.0}}}
+	s.SpeakerNote = "Do not share"
+	if err := s.redact(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewSlide("Title and body", "x0 xxxxxxx")
+	want.Bodies = []*Body{NewBody(&Paragraph{
+		Fragments: []*Fragment{{Value: "xx 00% ", Bold: true}, {Value: "xxx", Link: "xxxxx://xxxxxxx.xxx/x"}},
+		Bullet:    BulletDash,
+	})}
+	want.Tables = []*Table{NewTable([][]string{{"xxxxxx", "xxxxx"}, {"xxxx", "000"}}, true)}
+	want.Elements = []*Element{{Kind: "poll", Data: map[string]any{"question": "xxx?", "options": []any{"x", "x"}, "max": 2.0}}}
+	want.SpeakerNote = "xx xxx xxxxx"
+	images := s.Images
+	s.Images = nil
+	if diff := cmp.Diff(want, s, cmp.AllowUnexported(Slide{})); diff != "" {
+		t.Error(diff)
+	}
+	if len(images) != 1 {
+		t.Fatalf("got %d images, want 1", len(images))
+	}
+	if got := images[0]; got.Alt() != "xxxxxx xxxxx" || got.Checksum() == img.Checksum() {
+		t.Errorf("image is not redacted: alt %q", got.Alt())
+	}
+}
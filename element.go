@@ -0,0 +1,104 @@
+package deck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/slides/v1"
+)
+
+// Elements are custom page elements such as shapes, emitted by plugins of the md package or set programmatically.
+// They are created by the ElementFunc given with WithElementFunc, and the element is stored as JSON in the
+// alt text of the created page element, so that it is kept as long as it is unchanged and replaced otherwise.
+
+const descriptionElementFromMarkdown = "Element generated from markdown" // the element is stored in the title of the alt text
+
+// Element is a custom page element of a slide.
+type Element struct {
+	Kind string         `json:"kind"`           // Kind of the element, used by ElementFunc to decide how to create it
+	Data map[string]any `json:"data,omitempty"` // Data of the element, which must be encodable as JSON
+}
+
+// ElementFunc returns the requests to create the element in the page identified by pageObjectID.
+// The requests must create exactly one page element with objectID.
+type ElementFunc func(ctx context.Context, pageObjectID, objectID string, element *Element) ([]*slides.Request, error)
+
+// WithElementFunc sets the function that creates the custom page elements of slides on apply.
+func WithElementFunc(fn ElementFunc) Option {
+	return func(d *Deck) error {
+		d.elementFunc = fn
+		return nil
+	}
+}
+
+// elementRequests returns requests to create the elements in the page identified by pageObjectID,
+// keeping the unchanged ones. currentElements is the elements of the existing page elements, as JSON
+// keyed by their object IDs.
+func (d *Deck) elementRequests(ctx context.Context, pageObjectID string, elements []*Element, currentElements map[string]string) ([]*slides.Request, error) {
+	kept := map[string]struct{}{}
+	var (
+		requests []*slides.Request
+		creates  []*slides.Request
+	)
+	for _, e := range elements {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode element %q: %w", e.Kind, err)
+		}
+		if objectID, ok := findElement(currentElements, string(b), kept); ok {
+			kept[objectID] = struct{}{}
+			continue
+		}
+		if d.elementFunc == nil {
+			return nil, fmt.Errorf("element %q cannot be created without WithElementFunc", e.Kind)
+		}
+		objectID := fmt.Sprintf("element-%s", uuid.New().String())
+		reqs, err := d.elementFunc(ctx, pageObjectID, objectID, e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create element %q: %w", e.Kind, err)
+		}
+		creates = append(creates, reqs...)
+		creates = append(creates, &slides.Request{
+			UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+				ObjectId:    objectID,
+				Title:       string(b),
+				Description: descriptionElementFromMarkdown,
+			},
+		})
+	}
+	for objectID := range currentElements {
+		if _, ok := kept[objectID]; ok {
+			continue
+		}
+		requests = append(requests, &slides.Request{
+			DeleteObject: &slides.DeleteObjectRequest{
+				ObjectId: objectID,
+			},
+		})
+	}
+	return append(requests, creates...), nil
+}
+
+// findElement returns the object ID of the current element encoded as v, which is not in kept.
+func findElement(currentElements map[string]string, v string, kept map[string]struct{}) (string, bool) {
+	for objectID, current := range currentElements {
+		if _, ok := kept[objectID]; ok {
+			continue
+		}
+		if current == v {
+			return objectID, true
+		}
+	}
+	return "", false
+}
+
+// convertToElement returns the element stored in the alt text of a page element, or nil if it is broken.
+func convertToElement(title string) *Element {
+	e := &Element{}
+	if err := json.Unmarshal([]byte(title), e); err != nil {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,64 @@
+package deck
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestElementRequests(t *testing.T) {
+	var created []string
+	d := &Deck{}
+	if err := WithElementFunc(func(_ context.Context, pageObjectID, objectID string, e *Element) ([]*slides.Request, error) {
+		created = append(created, e.Kind)
+		return []*slides.Request{{CreateShape: &slides.CreateShapeRequest{
+			ObjectId:          objectID,
+			ElementProperties: &slides.PageElementProperties{PageObjectId: pageObjectID},
+			ShapeType:         "STAR_5",
+		}}}, nil
+	})(d); err != nil {
+		t.Fatal(err)
+	}
+	elements := []*Element{{Kind: "star"}, {Kind: "poll", Data: map[string]any{"question": "Yes?"}}}
+	current := map[string]string{
+		"kept":    `{"kind":"star"}`,
+		"changed": `{"kind":"poll","data":{"question":"No?"}}`,
+	}
+	reqs, err := d.elementRequests(t.Context(), "page", elements, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 3 {
+		t.Fatalf("got %d requests, want 3", len(reqs))
+	}
+	if reqs[0].DeleteObject == nil || reqs[0].DeleteObject.ObjectId != "changed" {
+		t.Errorf("the changed element should be deleted: %v", reqs[0])
+	}
+	if reqs[1].CreateShape == nil || reqs[1].CreateShape.ElementProperties.PageObjectId != "page" {
+		t.Errorf("the element should be created: %v", reqs[1])
+	}
+	alt := reqs[2].UpdatePageElementAltText
+	if alt == nil || alt.ObjectId != reqs[1].CreateShape.ObjectId || alt.Description != descriptionElementFromMarkdown {
+		t.Fatalf("the element should be stored in the alt text: %v", reqs[2])
+	}
+	if e := convertToElement(alt.Title); e == nil || !elementsEqual([]*Element{e}, elements[1:]) {
+		t.Errorf("got %v, want %v", e, elements[1])
+	}
+	if len(created) != 1 || created[0] != "poll" {
+		t.Errorf("got %v, want only poll created", created)
+	}
+
+	// Elements cannot be created without the function.
+	if _, err := (&Deck{}).elementRequests(t.Context(), "page", elements, nil); err == nil {
+		t.Error("want error")
+	}
+	// Removing all elements deletes the current ones.
+	reqs, err = (&Deck{}).elementRequests(t.Context(), "page", nil, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 2 {
+		t.Errorf("got %d requests, want 2", len(reqs))
+	}
+}
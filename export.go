@@ -10,12 +10,15 @@ import (
 )
 
 // AppendPage appends a new slide to the end of the presentation.
-// The deck command currently does not utilize this method and is only used within tests;
-// however, it has been retained for potential future usage as a library.
+// If the slide has no layout, the default layout of the presentation is used.
 func (d *Deck) AppendPage(ctx context.Context, slide *Slide) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	if slide.Layout == "" {
+		slide.Layout = d.defaultLayout
+	}
+	slide.fillBodies()
 	d.logger.Info("appending new page")
 	index := len(d.presentation.Slides)
 	if err := d.createPage(ctx, index, slide); err != nil {
@@ -56,12 +59,15 @@ func Delete(ctx context.Context, id string, opts ...Option) (err error) {
 }
 
 // InsertPage inserts a new slide at the specified index in the presentation.
-// The deck command currently does not utilize this method and is only used within tests;
-// however, it has been retained for potential future usage as a library.
+// If the slide has no layout, the default layout of the presentation is used.
 func (d *Deck) InsertPage(ctx context.Context, index int, slide *Slide) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	if slide.Layout == "" {
+		slide.Layout = d.defaultLayout
+	}
+	slide.fillBodies()
 	d.logger.Info("inserting page", slog.Int("index", index))
 	if len(d.presentation.Slides) <= index {
 		return fmt.Errorf("index out of range: %d", index)
@@ -69,11 +75,6 @@ func (d *Deck) InsertPage(ctx context.Context, index int, slide *Slide) (err err
 	if err := d.createPage(ctx, index, slide); err != nil {
 		return fmt.Errorf("failed to create page: %w", err)
 	}
-	if index == 0 {
-		if err := d.movePage(ctx, 1, 0); err != nil {
-			return fmt.Errorf("failed to move page: %w", err)
-		}
-	}
 	if err := d.refresh(ctx); err != nil {
 		return fmt.Errorf("failed to refresh presentation: %w", err)
 	}
@@ -92,9 +93,8 @@ func (d *Deck) InsertPage(ctx context.Context, index int, slide *Slide) (err err
 }
 
 // DumpSlides retrieves all slides from the presentation and converts them into the internal Slides structure.
-// The deck command currently does not utilize this method and is only used within tests;
-// however, it has been retained for potential future usage as a library.
-func (d *Deck) DumpSlides(ctx context.Context) (_ Slides, err error) {
+// WithDumpPages selects the pages to dump, and WithRedaction redacts their text content.
+func (d *Deck) DumpSlides(ctx context.Context, opts ...DumpOption) (_ Slides, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
@@ -113,5 +113,23 @@ func (d *Deck) DumpSlides(ctx context.Context) (_ Slides, err error) {
 		slide := convertToSlide(p, layoutObjectIdMap)
 		slides = append(slides, slide)
 	}
+	resolvePageLinks(slides, d.presentation.Slides)
+	o := &dumpOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.pages) > 0 {
+		slides, err = filterPages(slides, o.pages)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if o.redact {
+		for _, slide := range slides {
+			if err := slide.redact(); err != nil {
+				return nil, err
+			}
+		}
+	}
 	return slides, nil
 }
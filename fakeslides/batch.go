@@ -0,0 +1,722 @@
+package fakeslides
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// objectIDReg is the pattern of object IDs given by clients.
+// Ref. https://developers.google.com/workspace/slides/api/reference/rest/v1/presentations/request#createsliderequest
+var objectIDReg = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_\-:]{4,49}$`)
+
+// requestError is an error of a request in a batch update.
+type requestError struct {
+	kind string
+	msg  string
+}
+
+func (e *requestError) Error() string {
+	return fmt.Sprintf("%s: %s", e.kind, e.msg)
+}
+
+// batch applies the requests of a batch update to a copy of the presentation.
+type batch struct {
+	s *Server
+	p *slides.Presentation
+}
+
+func (s *Server) batchUpdate(w http.ResponseWriter, r *http.Request) {
+	id, ok := strings.CutSuffix(r.PathValue("id"), ":batchUpdate")
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unknown method: %s", r.URL.Path)
+		return
+	}
+	var body struct {
+		Requests     []json.RawMessage    `json:"requests"`
+		WriteControl *slides.WriteControl `json:"writeControl"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON payload received. %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[id]
+	if !ok || f.presentation == nil {
+		writeError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+	if body.WriteControl != nil && body.WriteControl.RequiredRevisionId != "" &&
+		body.WriteControl.RequiredRevisionId != f.presentation.RevisionId {
+		writeError(w, http.StatusBadRequest, "The revision ID %s does not match the current revision of the presentation.", body.WriteControl.RequiredRevisionId)
+		return
+	}
+	// The requests are applied to a copy, so that the presentation is left untouched if any of them fails.
+	b := &batch{s: s, p: &slides.Presentation{}}
+	if err := clone(f.presentation, b.p); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	replies := make([]*slides.Response, 0, len(body.Requests))
+	for i, raw := range body.Requests {
+		reply, err := b.apply(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid requests[%d].%v", i, err)
+			return
+		}
+		replies = append(replies, reply)
+	}
+	if len(body.Requests) > 0 {
+		b.p.RevisionId = s.newID("rev")
+	}
+	f.presentation = b.p
+	writeJSON(w, &slides.BatchUpdatePresentationResponse{
+		PresentationId: id,
+		Replies:        replies,
+		WriteControl:   &slides.WriteControl{RequiredRevisionId: b.p.RevisionId},
+	})
+}
+
+// apply applies the request and returns its reply.
+func (b *batch) apply(raw json.RawMessage) (*slides.Response, error) {
+	var req slides.Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, &requestError{kind: "request", msg: err.Error()}
+	}
+	var (
+		kind  string
+		reply = &slides.Response{}
+		err   error
+	)
+	switch {
+	case req.CreateSlide != nil:
+		kind = "createSlide"
+		var fields map[string]json.RawMessage
+		_ = json.Unmarshal(raw, &struct {
+			CreateSlide *map[string]json.RawMessage `json:"createSlide"`
+		}{&fields})
+		_, hasIndex := fields["insertionIndex"]
+		var id string
+		id, err = b.createSlide(req.CreateSlide, hasIndex)
+		reply.CreateSlide = &slides.CreateSlideResponse{ObjectId: id}
+	case req.DeleteObject != nil:
+		kind = "deleteObject"
+		err = b.deleteObject(req.DeleteObject.ObjectId)
+	case req.UpdateSlidesPosition != nil:
+		kind = "updateSlidesPosition"
+		err = b.updateSlidesPosition(req.UpdateSlidesPosition)
+	case req.DuplicateObject != nil:
+		kind = "duplicateObject"
+		var id string
+		id, err = b.duplicateObject(req.DuplicateObject)
+		reply.DuplicateObject = &slides.DuplicateObjectResponse{ObjectId: id}
+	case req.UpdateSlideProperties != nil:
+		kind = "updateSlideProperties"
+		err = b.updateSlideProperties(req.UpdateSlideProperties)
+	case req.CreateShape != nil:
+		kind = "createShape"
+		var id string
+		id, err = b.createElement(req.CreateShape.ObjectId, req.CreateShape.ElementProperties, func(e *slides.PageElement) {
+			e.Shape = &slides.Shape{ShapeType: req.CreateShape.ShapeType, ShapeProperties: &slides.ShapeProperties{}}
+		})
+		reply.CreateShape = &slides.CreateShapeResponse{ObjectId: id}
+	case req.CreateImage != nil:
+		kind = "createImage"
+		if req.CreateImage.Url == "" {
+			err = fmt.Errorf("the image URL is required")
+			break
+		}
+		var id string
+		id, err = b.createElement(req.CreateImage.ObjectId, req.CreateImage.ElementProperties, func(e *slides.PageElement) {
+			e.Image = &slides.Image{
+				ContentUrl:      b.s.imageURL(req.CreateImage.Url),
+				SourceUrl:       req.CreateImage.Url,
+				ImageProperties: &slides.ImageProperties{},
+			}
+		})
+		reply.CreateImage = &slides.CreateImageResponse{ObjectId: id}
+	case req.ReplaceImage != nil:
+		kind = "replaceImage"
+		err = b.replaceImage(req.ReplaceImage)
+	case req.UpdateImageProperties != nil:
+		kind = "updateImageProperties"
+		err = b.updateImageProperties(req.UpdateImageProperties)
+	case req.UpdatePageElementAltText != nil:
+		kind = "updatePageElementAltText"
+		err = b.updateAltText(req.UpdatePageElementAltText)
+	case req.UpdatePageElementsZOrder != nil:
+		kind = "updatePageElementsZOrder"
+		err = b.updateZOrder(req.UpdatePageElementsZOrder)
+	case req.UpdateShapeProperties != nil:
+		kind = "updateShapeProperties"
+		err = b.updateShapeProperties(req.UpdateShapeProperties)
+	case req.InsertText != nil:
+		kind = "insertText"
+		r := req.InsertText
+		err = b.editText(r.ObjectId, r.CellLocation, func(t *text) error {
+			return t.insert(r.InsertionIndex, r.Text)
+		})
+	case req.DeleteText != nil:
+		kind = "deleteText"
+		r := req.DeleteText
+		err = b.editText(r.ObjectId, r.CellLocation, func(t *text) error {
+			return t.delete(r.TextRange)
+		})
+	case req.UpdateTextStyle != nil:
+		kind = "updateTextStyle"
+		r := req.UpdateTextStyle
+		err = b.editText(r.ObjectId, r.CellLocation, func(t *text) error {
+			return t.updateStyle(r.TextRange, r.Style, r.Fields)
+		})
+	case req.UpdateParagraphStyle != nil:
+		kind = "updateParagraphStyle"
+		r := req.UpdateParagraphStyle
+		err = b.editText(r.ObjectId, r.CellLocation, func(t *text) error {
+			return t.updateParagraphStyle(r.TextRange, r.Style, r.Fields)
+		})
+	case req.CreateParagraphBullets != nil:
+		kind = "createParagraphBullets"
+		r := req.CreateParagraphBullets
+		err = b.editText(r.ObjectId, r.CellLocation, func(t *text) error {
+			return t.createBullets(r.TextRange, r.BulletPreset)
+		})
+	case req.DeleteParagraphBullets != nil:
+		kind = "deleteParagraphBullets"
+		r := req.DeleteParagraphBullets
+		err = b.editText(r.ObjectId, r.CellLocation, func(t *text) error {
+			return t.deleteBullets(r.TextRange)
+		})
+	case req.CreateTable != nil:
+		kind = "createTable"
+		var id string
+		id, err = b.createTable(req.CreateTable)
+		reply.CreateTable = &slides.CreateTableResponse{ObjectId: id}
+	case req.InsertTableRows != nil:
+		kind = "insertTableRows"
+		err = b.insertTableRows(req.InsertTableRows)
+	case req.InsertTableColumns != nil:
+		kind = "insertTableColumns"
+		err = b.insertTableColumns(req.InsertTableColumns)
+	case req.DeleteTableRow != nil:
+		kind = "deleteTableRow"
+		err = b.deleteTableRow(req.DeleteTableRow)
+	case req.DeleteTableColumn != nil:
+		kind = "deleteTableColumn"
+		err = b.deleteTableColumn(req.DeleteTableColumn)
+	case req.UpdateTableCellProperties != nil:
+		kind = "updateTableCellProperties"
+		err = b.updateTableCellProperties(req.UpdateTableCellProperties)
+	case req.UpdateTableColumnProperties != nil:
+		kind = "updateTableColumnProperties"
+		err = b.updateTableColumnProperties(req.UpdateTableColumnProperties)
+	case req.UpdateTableBorderProperties != nil:
+		kind = "updateTableBorderProperties"
+		// Borders are not kept, since deck does not read them back from slides other than the layouts.
+		_, err = b.table(req.UpdateTableBorderProperties.ObjectId)
+	default:
+		return nil, &requestError{kind: "request", msg: "the request is not supported by the fake server"}
+	}
+	if err != nil {
+		return nil, &requestError{kind: kind, msg: err.Error()}
+	}
+	return reply, nil
+}
+
+// checkNewID returns an error if id cannot be used as the ID of a new object.
+func (b *batch) checkNewID(id string) error {
+	if !objectIDReg.MatchString(id) {
+		return fmt.Errorf("the object ID (%s) should be 5 to 50 characters of [a-zA-Z0-9_-:], not starting with - or :", id)
+	}
+	if b.exists(id) {
+		return fmt.Errorf("the object ID (%s) should be unique among all pages and page elements", id)
+	}
+	return nil
+}
+
+// exists reports whether a page or a page element has the ID.
+func (b *batch) exists(id string) bool {
+	found := false
+	b.walk(func(p *slides.Page, _ *slides.Page) {
+		if p.ObjectId == id {
+			found = true
+		}
+		for _, e := range p.PageElements {
+			if e.ObjectId == id {
+				found = true
+			}
+		}
+	})
+	return found
+}
+
+// walk calls fn with all pages of the presentation, and the slides the notes pages belong to.
+func (b *batch) walk(fn func(p *slides.Page, slide *slides.Page)) {
+	for _, pages := range [][]*slides.Page{b.p.Masters, b.p.Layouts, b.p.Slides} {
+		for _, p := range pages {
+			fn(p, nil)
+		}
+	}
+	for _, s := range b.p.Slides {
+		if s.SlideProperties != nil && s.SlideProperties.NotesPage != nil {
+			fn(s.SlideProperties.NotesPage, s)
+		}
+	}
+	if b.p.NotesMaster != nil {
+		fn(b.p.NotesMaster, nil)
+	}
+}
+
+// newID returns a new object ID, or id if it is given.
+func (b *batch) newID(id, prefix string) (string, error) {
+	if id == "" {
+		return b.s.newID(prefix), nil
+	}
+	if err := b.checkNewID(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// slideIndex returns the index of the slide, or -1 if it does not exist.
+func (b *batch) slideIndex(id string) int {
+	return slices.IndexFunc(b.p.Slides, func(s *slides.Page) bool { return s.ObjectId == id })
+}
+
+// element returns the page element and the page containing it.
+func (b *batch) element(id string) (*slides.PageElement, *slides.Page, error) {
+	var (
+		element *slides.PageElement
+		page    *slides.Page
+	)
+	b.walk(func(p *slides.Page, _ *slides.Page) {
+		for _, e := range p.PageElements {
+			if e.ObjectId == id {
+				element, page = e, p
+			}
+		}
+	})
+	if element == nil {
+		return nil, nil, fmt.Errorf("the object (%s) could not be found", id)
+	}
+	return element, page, nil
+}
+
+func (b *batch) createSlide(r *slides.CreateSlideRequest, hasIndex bool) (string, error) {
+	id, err := b.newID(r.ObjectId, "slide")
+	if err != nil {
+		return "", err
+	}
+	var layout *slides.Page
+	if ref := r.SlideLayoutReference; ref != nil {
+		for _, l := range b.p.Layouts {
+			if (ref.LayoutId != "" && l.ObjectId == ref.LayoutId) ||
+				(ref.PredefinedLayout != "" && l.LayoutProperties.Name == ref.PredefinedLayout) {
+				layout = l
+			}
+		}
+		if layout == nil {
+			return "", fmt.Errorf("the layout (%s%s) could not be found", ref.LayoutId, ref.PredefinedLayout)
+		}
+	}
+	// Without the index, the slide is added at the end, as the API does when insertionIndex is omitted.
+	index := len(b.p.Slides)
+	if hasIndex {
+		index = int(r.InsertionIndex)
+	}
+	if index < 0 || index > len(b.p.Slides) {
+		return "", fmt.Errorf("the insertion index %d should be between 0 and %d", index, len(b.p.Slides))
+	}
+	ids := map[string]string{} // key: object ID of the layout placeholder, value: object ID of the slide placeholder
+	if layout != nil {
+		for _, m := range r.PlaceholderIdMappings {
+			for _, e := range layout.PageElements {
+				ph := placeholderOf(e)
+				if ph == nil {
+					continue
+				}
+				if e.ObjectId == m.LayoutPlaceholderObjectId ||
+					(m.LayoutPlaceholder != nil && m.LayoutPlaceholder.Type == ph.Type && m.LayoutPlaceholder.Index == ph.Index) {
+					if err := b.checkNewID(m.ObjectId); err != nil {
+						return "", err
+					}
+					ids[e.ObjectId] = m.ObjectId
+				}
+			}
+		}
+	}
+	slide := b.s.newSlide(id, layout, ids)
+	b.p.Slides = slices.Insert(b.p.Slides, index, slide)
+	return id, nil
+}
+
+func (b *batch) deleteObject(id string) error {
+	if i := b.slideIndex(id); i >= 0 {
+		b.p.Slides = slices.Delete(b.p.Slides, i, i+1)
+		return nil
+	}
+	_, page, err := b.element(id)
+	if err != nil {
+		return err
+	}
+	page.PageElements = slices.DeleteFunc(page.PageElements, func(e *slides.PageElement) bool { return e.ObjectId == id })
+	return nil
+}
+
+func (b *batch) updateSlidesPosition(r *slides.UpdateSlidesPositionRequest) error {
+	index := int(r.InsertionIndex)
+	if index < 0 || index > len(b.p.Slides) {
+		return fmt.Errorf("the insertion index %d should be between 0 and %d", index, len(b.p.Slides))
+	}
+	var moved []*slides.Page
+	for _, id := range r.SlideObjectIds {
+		i := b.slideIndex(id)
+		if i < 0 {
+			return fmt.Errorf("the slide (%s) could not be found", id)
+		}
+		moved = append(moved, b.p.Slides[i])
+	}
+	// The insertion index is based on the arrangement before the move.
+	rest := make([]*slides.Page, 0, len(b.p.Slides))
+	for i, s := range b.p.Slides {
+		if slices.Contains(moved, s) {
+			if i < index {
+				index--
+			}
+			continue
+		}
+		rest = append(rest, s)
+	}
+	b.p.Slides = slices.Insert(rest, index, moved...)
+	return nil
+}
+
+func (b *batch) duplicateObject(r *slides.DuplicateObjectRequest) (string, error) {
+	// newID returns the IDs for the copies of the objects, from the mapping of the request or generated.
+	newID := func(id, prefix string) (string, error) {
+		if nid, ok := r.ObjectIds[id]; ok {
+			return b.newID(nid, prefix)
+		}
+		return b.s.newID(prefix), nil
+	}
+	renameElements := func(p *slides.Page) error {
+		for _, e := range p.PageElements {
+			id, err := newID(e.ObjectId, "element")
+			if err != nil {
+				return err
+			}
+			e.ObjectId = id
+		}
+		return nil
+	}
+	if i := b.slideIndex(r.ObjectId); i >= 0 {
+		dup := &slides.Page{}
+		if err := clone(b.p.Slides[i], dup); err != nil {
+			return "", err
+		}
+		id, err := newID(dup.ObjectId, "slide")
+		if err != nil {
+			return "", err
+		}
+		dup.ObjectId = id
+		if err := renameElements(dup); err != nil {
+			return "", err
+		}
+		if notes := dup.SlideProperties.NotesPage; notes != nil {
+			notes.ObjectId = b.s.newID("notes")
+			old := notes.NotesProperties.SpeakerNotesObjectId
+			for _, e := range notes.PageElements {
+				id := b.s.newID("element")
+				if e.ObjectId == old {
+					notes.NotesProperties.SpeakerNotesObjectId = id
+				}
+				e.ObjectId = id
+			}
+		}
+		b.p.Slides = slices.Insert(b.p.Slides, i+1, dup)
+		return id, nil
+	}
+	e, page, err := b.element(r.ObjectId)
+	if err != nil {
+		return "", err
+	}
+	dup := &slides.PageElement{}
+	if err := clone(e, dup); err != nil {
+		return "", err
+	}
+	id, err := newID(dup.ObjectId, "element")
+	if err != nil {
+		return "", err
+	}
+	dup.ObjectId = id
+	page.PageElements = append(page.PageElements, dup)
+	return id, nil
+}
+
+func (b *batch) updateSlideProperties(r *slides.UpdateSlidePropertiesRequest) error {
+	i := b.slideIndex(r.ObjectId)
+	if i < 0 {
+		return fmt.Errorf("the slide (%s) could not be found", r.ObjectId)
+	}
+	s := b.p.Slides[i]
+	// The layout and the notes page cannot be changed.
+	props := &slides.SlideProperties{}
+	if err := merge(s.SlideProperties, r.SlideProperties, r.Fields, props); err != nil {
+		return err
+	}
+	props.LayoutObjectId = s.SlideProperties.LayoutObjectId
+	props.MasterObjectId = s.SlideProperties.MasterObjectId
+	props.NotesPage = s.SlideProperties.NotesPage
+	s.SlideProperties = props
+	return nil
+}
+
+// createElement adds a page element made by init to the slide of the properties.
+func (b *batch) createElement(id string, props *slides.PageElementProperties, init func(e *slides.PageElement)) (string, error) {
+	if props == nil {
+		return "", fmt.Errorf("elementProperties is required")
+	}
+	i := b.slideIndex(props.PageObjectId)
+	if i < 0 {
+		return "", fmt.Errorf("the page (%s) could not be found", props.PageObjectId)
+	}
+	id, err := b.newID(id, "element")
+	if err != nil {
+		return "", err
+	}
+	e := &slides.PageElement{
+		ObjectId:  id,
+		Size:      props.Size,
+		Transform: props.Transform,
+	}
+	if e.Transform == nil {
+		e.Transform = &slides.AffineTransform{ScaleX: 1, ScaleY: 1, Unit: "EMU"}
+	}
+	init(e)
+	b.p.Slides[i].PageElements = append(b.p.Slides[i].PageElements, e)
+	return id, nil
+}
+
+func (b *batch) image(id string) (*slides.Image, error) {
+	e, _, err := b.element(id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Image == nil {
+		return nil, fmt.Errorf("the object (%s) is not an image", id)
+	}
+	return e.Image, nil
+}
+
+func (b *batch) replaceImage(r *slides.ReplaceImageRequest) error {
+	img, err := b.image(r.ImageObjectId)
+	if err != nil {
+		return err
+	}
+	if r.Url == "" {
+		return fmt.Errorf("the image URL is required")
+	}
+	img.ContentUrl = b.s.imageURL(r.Url)
+	img.SourceUrl = r.Url
+	return nil
+}
+
+func (b *batch) updateImageProperties(r *slides.UpdateImagePropertiesRequest) error {
+	img, err := b.image(r.ObjectId)
+	if err != nil {
+		return err
+	}
+	props := &slides.ImageProperties{}
+	if err := merge(img.ImageProperties, r.ImageProperties, r.Fields, props); err != nil {
+		return err
+	}
+	img.ImageProperties = props
+	return nil
+}
+
+func (b *batch) updateAltText(r *slides.UpdatePageElementAltTextRequest) error {
+	e, _, err := b.element(r.ObjectId)
+	if err != nil {
+		return err
+	}
+	// As the API does, the unset values are kept.
+	if r.Title != "" || slices.Contains(r.ForceSendFields, "Title") {
+		e.Title = r.Title
+	}
+	if r.Description != "" || slices.Contains(r.ForceSendFields, "Description") {
+		e.Description = r.Description
+	}
+	return nil
+}
+
+// updateZOrder moves the page elements to the back or the front of the page, keeping their relative order.
+// Moving elements forward or backward by one step is not supported.
+func (b *batch) updateZOrder(r *slides.UpdatePageElementsZOrderRequest) error {
+	if len(r.PageElementObjectIds) == 0 {
+		return fmt.Errorf("the page element object IDs are required")
+	}
+	var (
+		page  *slides.Page
+		moved []*slides.PageElement
+	)
+	for _, id := range r.PageElementObjectIds {
+		e, p, err := b.element(id)
+		if err != nil {
+			return err
+		}
+		if page != nil && p != page {
+			return fmt.Errorf("the page elements should be on the same page")
+		}
+		page = p
+		moved = append(moved, e)
+	}
+	// The elements are kept in the order of the page, not in the order of the request.
+	rest := slices.DeleteFunc(slices.Clone(page.PageElements), func(e *slides.PageElement) bool { return slices.Contains(moved, e) })
+	moved = slices.DeleteFunc(slices.Clone(page.PageElements), func(e *slides.PageElement) bool { return !slices.Contains(moved, e) })
+	switch r.Operation {
+	case "SEND_TO_BACK":
+		page.PageElements = append(moved, rest...)
+	case "BRING_TO_FRONT":
+		page.PageElements = append(rest, moved...)
+	default:
+		return fmt.Errorf("the z-order operation (%s) is not supported by the fake server", r.Operation)
+	}
+	return nil
+}
+
+func (b *batch) updateShapeProperties(r *slides.UpdateShapePropertiesRequest) error {
+	e, _, err := b.element(r.ObjectId)
+	if err != nil {
+		return err
+	}
+	if e.Shape == nil {
+		return fmt.Errorf("the object (%s) is not a shape", r.ObjectId)
+	}
+	props := &slides.ShapeProperties{}
+	if err := merge(e.Shape.ShapeProperties, r.ShapeProperties, r.Fields, props); err != nil {
+		return err
+	}
+	e.Shape.ShapeProperties = props
+	return nil
+}
+
+// editText edits the text of the shape, or the table cell if cell is given.
+func (b *batch) editText(id string, cell *slides.TableCellLocation, edit func(t *text) error) error {
+	e, _, err := b.element(id)
+	if err != nil {
+		return err
+	}
+	var tc **slides.TextContent
+	switch {
+	case cell != nil:
+		c, err := tableCell(e, cell)
+		if err != nil {
+			return err
+		}
+		tc = &c.Text
+	case e.Shape != nil:
+		tc = &e.Shape.Text
+	default:
+		return fmt.Errorf("the object (%s) does not allow text editing", id)
+	}
+	t := parseText(*tc)
+	if err := edit(t); err != nil {
+		return err
+	}
+	*tc = t.content()
+	return nil
+}
+
+func placeholderOf(e *slides.PageElement) *slides.Placeholder {
+	switch {
+	case e.Shape != nil:
+		return e.Shape.Placeholder
+	case e.Image != nil:
+		return e.Image.Placeholder
+	default:
+		return nil
+	}
+}
+
+// merge sets dst to cur updated with the fields of upd listed in fields, which is a field mask of the API
+// such as `bold,foregroundColor` or `*` to replace all fields. Fields not set in upd are cleared.
+func merge(cur, upd any, fields string, dst any) error {
+	c, err := toMap(cur)
+	if err != nil {
+		return err
+	}
+	u, err := toMap(upd)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(fields) == "*" {
+		c = u
+	} else {
+		for f := range strings.SplitSeq(fields, ",") {
+			f = strings.TrimSpace(f)
+			if f == "" {
+				continue
+			}
+			path := strings.Split(f, ".")
+			setPath(c, path, getPath(u, path))
+		}
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+func toMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = map[string]any{}
+	}
+	return m, nil
+}
+
+func getPath(m map[string]any, path []string) any {
+	v, ok := m[path[0]]
+	if !ok || len(path) == 1 {
+		return v
+	}
+	child, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return getPath(child, path[1:])
+}
+
+// setPath sets v at the path of m, or deletes it if v is nil.
+func setPath(m map[string]any, path []string, v any) {
+	if len(path) == 1 {
+		if v == nil {
+			delete(m, path[0])
+		} else {
+			m[path[0]] = v
+		}
+		return
+	}
+	child, ok := m[path[0]].(map[string]any)
+	if !ok {
+		if v == nil {
+			return
+		}
+		child = map[string]any{}
+		m[path[0]] = child
+	}
+	setPath(child, path[1:], v)
+}
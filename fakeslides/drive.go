@@ -0,0 +1,340 @@
+package fakeslides
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// minimalPDF is the content of exported presentations. The fake does not render slides.
+var minimalPDF = []byte("%PDF-1.4\n1 0 obj<</Type/Catalog/Pages 2 0 R>>endobj\n" +
+	"2 0 obj<</Type/Pages/Kids[3 0 R]/Count 1>>endobj\n" +
+	"3 0 obj<</Type/Page/Parent 2 0 R/MediaBox[0 0 720 405]>>endobj\n" +
+	"trailer<</Root 1 0 R>>\n%%EOF\n")
+
+// addFile adds the file with the metadata to the fake Drive, filling the fields set by Drive.
+func (s *Server) addFile(meta *drive.File) *file {
+	meta.Id = s.newID("file")
+	meta.Kind = "drive#file"
+	if meta.Name == "" {
+		meta.Name = "Untitled"
+	}
+	meta.CreatedTime = now()
+	meta.ModifiedTime = meta.CreatedTime
+	meta.Capabilities = &drive.FileCapabilities{CanDelete: true, CanTrash: true, CanEdit: true}
+	f := &file{meta: meta}
+	s.files[meta.Id] = f
+	return f
+}
+
+func (s *Server) createPresentation(meta *drive.File) *file {
+	f := s.addFile(meta)
+	f.presentation = s.newPresentation(f.meta.Id, f.meta.Name)
+	return f
+}
+
+func (s *Server) listFiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Only the conditions used by deck are understood, and other conditions are ignored.
+	q := r.URL.Query().Get("q")
+	list := &drive.FileList{Kind: "drive#fileList", Files: []*drive.File{}}
+	for _, f := range s.files {
+		if strings.Contains(q, "mimeType='"+presentationMimeType+"'") && f.meta.MimeType != presentationMimeType {
+			continue
+		}
+		if strings.Contains(q, "trashed=false") && f.meta.Trashed {
+			continue
+		}
+		list.Files = append(list.Files, f.meta)
+	}
+	slices.SortFunc(list.Files, func(a, b *drive.File) int {
+		return strings.Compare(a.CreatedTime+a.Id, b.CreatedTime+b.Id)
+	})
+	writeJSON(w, list)
+}
+
+func (s *Server) createFile(w http.ResponseWriter, r *http.Request) {
+	var meta drive.File
+	if err := json.NewDecoder(r.Body).Decode(&meta); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid file metadata: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if meta.MimeType == presentationMimeType {
+		writeJSON(w, s.createPresentation(&meta).meta)
+		return
+	}
+	writeJSON(w, s.addFile(&meta).meta)
+}
+
+// uploadFile handles multipart uploads, which consist of the metadata and the content of the file.
+func (s *Server) uploadFile(w http.ResponseWriter, r *http.Request) {
+	if t := r.URL.Query().Get("uploadType"); t != "multipart" {
+		writeError(w, http.StatusBadRequest, "Unsupported upload type: %q", t)
+		return
+	}
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid content type: %v", err)
+		return
+	}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	var (
+		meta    drive.File
+		content []byte
+	)
+	for i := 0; ; i++ {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid multipart body: %v", err)
+			return
+		}
+		b, err := io.ReadAll(p)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid multipart body: %v", err)
+			return
+		}
+		if i == 0 {
+			if err := json.Unmarshal(b, &meta); err != nil {
+				writeError(w, http.StatusBadRequest, "Invalid file metadata: %v", err)
+				return
+			}
+			continue
+		}
+		content = b
+		if meta.MimeType == "" {
+			meta.MimeType = p.Header.Get("Content-Type")
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.addFile(&meta)
+	f.content = content
+	f.meta.Size = int64(len(content))
+	f.meta.WebContentLink = s.contentURL(f.meta.Id)
+	writeJSON(w, f.meta)
+}
+
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.file(w, r)
+	if !ok {
+		return
+	}
+	if r.URL.Query().Get("alt") == "media" {
+		if f.presentation != nil {
+			writeError(w, http.StatusForbidden, "Only files with binary content can be downloaded. Use Export with Docs Editors files.")
+			return
+		}
+		w.Header().Set("Content-Type", f.meta.MimeType)
+		_, _ = w.Write(f.content)
+		return
+	}
+	writeJSON(w, f.meta)
+}
+
+func (s *Server) updateFile(w http.ResponseWriter, r *http.Request) {
+	var upd drive.File
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid file metadata: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.file(w, r)
+	if !ok {
+		return
+	}
+	if upd.Name != "" {
+		f.meta.Name = upd.Name
+		if f.presentation != nil {
+			f.presentation.Title = upd.Name
+		}
+	}
+	if upd.Description != "" || slices.Contains(upd.NullFields, "Description") {
+		f.meta.Description = upd.Description
+	}
+	if upd.Properties != nil {
+		if f.meta.Properties == nil {
+			f.meta.Properties = map[string]string{}
+		}
+		for k, v := range upd.Properties {
+			f.meta.Properties[k] = v
+		}
+	}
+	if upd.Trashed {
+		f.meta.Trashed = true
+	}
+	q := r.URL.Query()
+	if remove := q.Get("removeParents"); remove != "" {
+		f.meta.Parents = slices.DeleteFunc(f.meta.Parents, func(p string) bool {
+			return slices.Contains(strings.Split(remove, ","), p)
+		})
+	}
+	if add := q.Get("addParents"); add != "" {
+		f.meta.Parents = append(f.meta.Parents, strings.Split(add, ",")...)
+	}
+	f.meta.ModifiedTime = now()
+	writeJSON(w, f.meta)
+}
+
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.file(w, r)
+	if !ok {
+		return
+	}
+	delete(s.files, f.meta.Id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) copyFile(w http.ResponseWriter, r *http.Request) {
+	var meta drive.File
+	if err := json.NewDecoder(r.Body).Decode(&meta); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid file metadata: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.file(w, r)
+	if !ok {
+		return
+	}
+	if meta.Name == "" {
+		meta.Name = "Copy of " + src.meta.Name
+	}
+	meta.MimeType = src.meta.MimeType
+	f := s.addFile(&meta)
+	f.content = bytes.Clone(src.content)
+	if src.presentation != nil {
+		if err := s.copyPresentation(src.presentation, f); err != nil {
+			delete(s.files, f.meta.Id)
+			writeError(w, http.StatusInternalServerError, "Failed to copy presentation: %v", err)
+			return
+		}
+	}
+	writeJSON(w, f.meta)
+}
+
+func (s *Server) exportFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.file(w, r)
+	if !ok {
+		return
+	}
+	if mt := r.URL.Query().Get("mimeType"); f.presentation == nil || mt != "application/pdf" {
+		writeError(w, http.StatusBadRequest, "Export to %q is not supported for the file", mt)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	_, _ = w.Write(minimalPDF)
+}
+
+func (s *Server) createPermission(w http.ResponseWriter, r *http.Request) {
+	var p drive.Permission
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid permission: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.file(w, r)
+	if !ok {
+		return
+	}
+	p.Kind = "drive#permission"
+	switch p.Type {
+	case "anyone":
+		p.Id = "anyoneWithLink"
+	default:
+		p.Id = s.newID("permission")
+	}
+	f.permissions = append(f.permissions, &p)
+	if !slices.Contains(f.meta.PermissionIds, p.Id) {
+		f.meta.PermissionIds = append(f.meta.PermissionIds, p.Id)
+	}
+	writeJSON(w, &p)
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request) {
+	var c drive.Comment
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid comment: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.file(w, r)
+	if !ok {
+		return
+	}
+	c.Id = s.newID("comment")
+	c.Kind = "drive#comment"
+	c.CreatedTime = now()
+	f.comments = append(f.comments, &c)
+	writeJSON(w, &c)
+}
+
+// getContent serves the content of uploaded files as their webContentLink, and of images on slides.
+func (s *Server) getContent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	f, ok := s.files[id]
+	if !ok {
+		f, ok = s.images[id]
+	}
+	if !ok || f.presentation != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", f.meta.MimeType)
+	_, _ = w.Write(f.content)
+}
+
+// imageURL returns the URL of the image on slides inserted from url. The content of the uploaded files is copied,
+// and other URLs are kept as they are, since the fake does not fetch them.
+// The caller must hold s.mu.
+func (s *Server) imageURL(url string) string {
+	id, ok := strings.CutPrefix(url, s.URL+"/content/")
+	if !ok {
+		return url
+	}
+	f, ok := s.files[id]
+	if !ok {
+		f, ok = s.images[id]
+	}
+	if !ok || f.presentation != nil {
+		return url
+	}
+	imageID := s.newID("image")
+	s.images[imageID] = &file{meta: &drive.File{Id: imageID, MimeType: f.meta.MimeType}, content: f.content}
+	return s.contentURL(imageID)
+}
+
+// file returns the file of the ID in the path, or writes the not found error.
+// The caller must hold s.mu.
+func (s *Server) file(w http.ResponseWriter, r *http.Request) (*file, bool) {
+	id := r.PathValue("id")
+	f, ok := s.files[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "File not found: %s.", id)
+		return nil, false
+	}
+	return f, true
+}
@@ -0,0 +1,155 @@
+// Package fakeslides provides an in-memory fake of the subset of the Google Slides and Google Drive APIs
+// used by deck, so that deck can be tested and developed without credentials or quota.
+//
+// The fake keeps presentations and files in memory. It serves getting presentations and pages, batch updates
+// mutating the presentations, thumbnails, and the Drive operations to create, copy, update, delete, list,
+// upload and share files. Requests it does not support are rejected as the API rejects invalid requests,
+// so tests relying on them fail instead of passing silently.
+//
+//	srv := fakeslides.NewServer()
+//	defer srv.Close()
+//	d, err := deck.New(ctx, deck.WithEndpoint(srv.URL), deck.WithPresentationID(srv.CreatePresentation("test")))
+package fakeslides
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/slides/v1"
+)
+
+const presentationMimeType = "application/vnd.google-apps.presentation"
+
+// Server is a fake server of the Google Slides and Google Drive APIs.
+type Server struct {
+	// URL is the base URL of the server, to be passed to deck.WithEndpoint.
+	URL string
+
+	srv   *httptest.Server
+	mu    sync.Mutex
+	files map[string]*file
+	// images are the contents of the images on slides, copied from the uploaded files when the images are created
+	// as the API does, so that the uploaded files can be deleted
+	images map[string]*file
+	seq    int
+}
+
+// file is a file on the fake Drive. Presentations have their content as presentation,
+// and other files as content.
+type file struct {
+	meta         *drive.File
+	presentation *slides.Presentation
+	content      []byte
+	permissions  []*drive.Permission
+	comments     []*drive.Comment
+}
+
+// NewServer starts a fake server. It should be closed by Close.
+func NewServer() *Server {
+	s := &Server{files: map[string]*file{}, images: map[string]*file{}}
+	s.srv = httptest.NewServer(s.handler())
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// CreatePresentation creates a presentation with the default layouts and a slide of the `title` layout,
+// and returns its ID.
+func (s *Server) CreatePresentation(title string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createPresentation(&drive.File{Name: title, MimeType: presentationMimeType}).meta.Id
+}
+
+// Presentation returns a copy of the presentation, or nil if it does not exist.
+func (s *Server) Presentation(id string) *slides.Presentation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[id]
+	if !ok || f.presentation == nil {
+		return nil
+	}
+	var p slides.Presentation
+	if err := clone(f.presentation, &p); err != nil {
+		return nil
+	}
+	return &p
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	// Slides API
+	mux.HandleFunc("POST /v1/presentations", s.createSlidesPresentation)
+	mux.HandleFunc("GET /v1/presentations/{id}", s.getPresentation)
+	mux.HandleFunc("POST /v1/presentations/{id}", s.batchUpdate) // {id}:batchUpdate
+	mux.HandleFunc("GET /v1/presentations/{id}/pages/{pageID}", s.getPage)
+	mux.HandleFunc("GET /v1/presentations/{id}/pages/{pageID}/thumbnail", s.getThumbnail)
+	// Drive API
+	mux.HandleFunc("GET /drive/v3/files", s.listFiles)
+	mux.HandleFunc("POST /drive/v3/files", s.createFile)
+	mux.HandleFunc("POST /upload/drive/v3/files", s.uploadFile)
+	mux.HandleFunc("GET /drive/v3/files/{id}", s.getFile)
+	mux.HandleFunc("PATCH /drive/v3/files/{id}", s.updateFile)
+	mux.HandleFunc("DELETE /drive/v3/files/{id}", s.deleteFile)
+	mux.HandleFunc("POST /drive/v3/files/{id}/copy", s.copyFile)
+	mux.HandleFunc("GET /drive/v3/files/{id}/export", s.exportFile)
+	mux.HandleFunc("POST /drive/v3/files/{id}/permissions", s.createPermission)
+	mux.HandleFunc("POST /drive/v3/files/{id}/comments", s.createComment)
+	// Contents of uploaded files and thumbnails
+	mux.HandleFunc("GET /content/{id}", s.getContent)
+	mux.HandleFunc("GET /thumbnail", s.thumbnailContent)
+	return mux
+}
+
+// newID returns a new ID with the prefix.
+func (s *Server) newID(prefix string) string {
+	s.seq++
+	return prefix + strconv.Itoa(s.seq)
+}
+
+// contentURL returns the URL to download the content of the file.
+func (s *Server) contentURL(id string) string {
+	return fmt.Sprintf("%s/content/%s", s.URL, id)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// writeJSON writes v as the response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error response in the format of Google APIs, which googleapi.CheckResponse parses.
+func writeError(w http.ResponseWriter, code int, format string, args ...any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": fmt.Sprintf(format, args...),
+			"status":  http.StatusText(code),
+		},
+	})
+}
+
+// clone deep copies src into dst through JSON.
+func clone(src, dst any) error {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
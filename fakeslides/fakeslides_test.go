@@ -0,0 +1,253 @@
+package fakeslides
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+	"google.golang.org/api/slides/v1"
+)
+
+func newServices(t *testing.T) (*Server, *slides.Service, *drive.Service) {
+	t.Helper()
+	ctx := context.Background()
+	s := NewServer()
+	t.Cleanup(s.Close)
+	srv, err := slides.NewService(ctx, option.WithHTTPClient(&http.Client{}), option.WithEndpoint(s.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	driveSrv, err := drive.NewService(ctx, option.WithHTTPClient(&http.Client{}), option.WithEndpoint(s.URL+"/drive/v3/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, srv, driveSrv
+}
+
+func placeholderID(t *testing.T, p *slides.Page, typ string) string {
+	t.Helper()
+	for _, e := range p.PageElements {
+		if e.Shape != nil && e.Shape.Placeholder != nil && e.Shape.Placeholder.Type == typ {
+			return e.ObjectId
+		}
+	}
+	t.Fatalf("placeholder %s not found", typ)
+	return ""
+}
+
+func TestBatchUpdateText(t *testing.T) {
+	s, srv, _ := newServices(t)
+	id := s.CreatePresentation("test")
+	p, err := srv.Presentations.Get(id).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodyID := placeholderID(t, p.Slides[0], "BODY")
+	reqs := []*slides.Request{
+		{InsertText: &slides.InsertTextRequest{ObjectId: bodyID, Text: "one\n\ttwo\nthree"}},
+		{UpdateTextStyle: &slides.UpdateTextStyleRequest{
+			ObjectId:  bodyID,
+			Style:     &slides.TextStyle{Bold: true},
+			Fields:    "bold",
+			TextRange: &slides.Range{Type: "FIXED_RANGE", StartIndex: new(int64(0)), EndIndex: new(int64(3))},
+		}},
+		{CreateParagraphBullets: &slides.CreateParagraphBulletsRequest{
+			ObjectId:     bodyID,
+			BulletPreset: "NUMBERED_DIGIT_ALPHA_ROMAN",
+			TextRange:    &slides.Range{Type: "FIXED_RANGE", StartIndex: new(int64(0)), EndIndex: new(int64(8))},
+		}},
+	}
+	res, err := srv.Presentations.BatchUpdate(id, &slides.BatchUpdatePresentationRequest{Requests: reqs}).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.WriteControl.RequiredRevisionId == p.RevisionId {
+		t.Error("revision ID is not updated")
+	}
+	page, err := srv.Presentations.Pages.Get(id, p.Slides[0].ObjectId).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range page.PageElements {
+		if e.ObjectId != bodyID {
+			continue
+		}
+		for _, te := range e.Shape.Text.TextElements {
+			switch {
+			case te.ParagraphMarker != nil && te.ParagraphMarker.Bullet != nil:
+				got = append(got, "bullet:"+te.ParagraphMarker.Bullet.Glyph+":"+strings.Repeat(">", int(te.ParagraphMarker.Bullet.NestingLevel)))
+			case te.ParagraphMarker != nil:
+				got = append(got, "paragraph")
+			case te.TextRun.Style != nil && te.TextRun.Style.Bold:
+				got = append(got, "bold:"+te.TextRun.Content)
+			default:
+				got = append(got, te.TextRun.Content)
+			}
+		}
+	}
+	want := []string{
+		"bullet:1.:", "bold:one", "\n",
+		"bullet:a.:>", "two\n", // the leading tab is removed as the nesting level
+		"paragraph", "three\n",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestBatchUpdateIsAtomic(t *testing.T) {
+	s, srv, _ := newServices(t)
+	id := s.CreatePresentation("test")
+	before := s.Presentation(id)
+	reqs := []*slides.Request{
+		{CreateSlide: &slides.CreateSlideRequest{}},
+		{DeleteObject: &slides.DeleteObjectRequest{ObjectId: "not-found"}},
+	}
+	_, err := srv.Presentations.BatchUpdate(id, &slides.BatchUpdatePresentationRequest{Requests: reqs}).Do()
+	if err == nil || !strings.Contains(err.Error(), "Invalid requests[1].deleteObject") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(before, s.Presentation(id)); diff != "" {
+		t.Errorf("presentation is changed by the failed batch update: %s", diff)
+	}
+
+	_, err = srv.Presentations.BatchUpdate(id, &slides.BatchUpdatePresentationRequest{
+		Requests:     []*slides.Request{{CreateSlide: &slides.CreateSlideRequest{}}},
+		WriteControl: &slides.WriteControl{RequiredRevisionId: "stale"},
+	}).Do()
+	if err == nil {
+		t.Error("want error for the stale revision ID")
+	}
+}
+
+func TestUpdateSlidesPosition(t *testing.T) {
+	s, srv, _ := newServices(t)
+	id := s.CreatePresentation("test")
+	reqs := []*slides.Request{
+		{CreateSlide: &slides.CreateSlideRequest{ObjectId: "slide-b"}},
+		{CreateSlide: &slides.CreateSlideRequest{ObjectId: "slide-c"}},
+		{UpdateSlidesPosition: &slides.UpdateSlidesPositionRequest{
+			SlideObjectIds:  []string{"slide-c"},
+			InsertionIndex:  0,
+			ForceSendFields: []string{"InsertionIndex"},
+		}},
+	}
+	if _, err := srv.Presentations.BatchUpdate(id, &slides.BatchUpdatePresentationRequest{Requests: reqs}).Do(); err != nil {
+		t.Fatal(err)
+	}
+	p := s.Presentation(id)
+	var got []string
+	for _, slide := range p.Slides[:2] {
+		got = append(got, slide.ObjectId)
+	}
+	if diff := cmp.Diff([]string{"slide-c", p.Slides[1].ObjectId}, got); diff != "" {
+		t.Error(diff)
+	}
+	if p.Slides[2].ObjectId != "slide-b" {
+		t.Errorf("got %s as the last slide, want slide-b", p.Slides[2].ObjectId)
+	}
+}
+
+func TestUpdatePageElementsZOrder(t *testing.T) {
+	s, srv, _ := newServices(t)
+	id := s.CreatePresentation("test")
+	p := s.Presentation(id)
+	slideID := p.Slides[0].ObjectId
+	var before []string
+	for _, e := range p.Slides[0].PageElements {
+		before = append(before, e.ObjectId)
+	}
+	reqs := []*slides.Request{
+		{CreateShape: &slides.CreateShapeRequest{ObjectId: "shape-a", ShapeType: "TEXT_BOX", ElementProperties: &slides.PageElementProperties{PageObjectId: slideID}}},
+		{CreateShape: &slides.CreateShapeRequest{ObjectId: "shape-b", ShapeType: "TEXT_BOX", ElementProperties: &slides.PageElementProperties{PageObjectId: slideID}}},
+		{UpdatePageElementsZOrder: &slides.UpdatePageElementsZOrderRequest{
+			PageElementObjectIds: []string{"shape-b", "shape-a"},
+			Operation:            "SEND_TO_BACK",
+		}},
+	}
+	if _, err := srv.Presentations.BatchUpdate(id, &slides.BatchUpdatePresentationRequest{Requests: reqs}).Do(); err != nil {
+		t.Fatal(err)
+	}
+	got := func() []string {
+		var ids []string
+		for _, e := range s.Presentation(id).Slides[0].PageElements {
+			ids = append(ids, e.ObjectId)
+		}
+		return ids
+	}
+	if diff := cmp.Diff(append([]string{"shape-a", "shape-b"}, before...), got()); diff != "" {
+		t.Error(diff)
+	}
+	reqs = []*slides.Request{
+		{UpdatePageElementsZOrder: &slides.UpdatePageElementsZOrderRequest{
+			PageElementObjectIds: []string{"shape-a"},
+			Operation:            "BRING_TO_FRONT",
+		}},
+	}
+	if _, err := srv.Presentations.BatchUpdate(id, &slides.BatchUpdatePresentationRequest{Requests: reqs}).Do(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(append(append([]string{"shape-b"}, before...), "shape-a"), got()); diff != "" {
+		t.Error(diff)
+	}
+	reqs[0].UpdatePageElementsZOrder.Operation = "BRING_FORWARD"
+	if _, err := srv.Presentations.BatchUpdate(id, &slides.BatchUpdatePresentationRequest{Requests: reqs}).Do(); err == nil {
+		t.Error("want error for the unsupported operation")
+	}
+}
+
+func TestUploadAndThumbnail(t *testing.T) {
+	s, srv, driveSrv := newServices(t)
+	content := []byte("\x89PNG fake")
+	f, err := driveSrv.Files.Create(&drive.File{Name: "image.png", MimeType: "image/png"}).Media(bytes.NewReader(content)).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := driveSrv.Permissions.Create(f.Id, &drive.Permission{Type: "anyone", Role: "reader"}).Do(); err != nil {
+		t.Fatal(err)
+	}
+	f, err = driveSrv.Files.Get(f.Id).Fields("webContentLink", "permissionIds").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"anyoneWithLink"}, f.PermissionIds); diff != "" {
+		t.Error(diff)
+	}
+	if got := download(t, f.WebContentLink); !bytes.Equal(got, content) {
+		t.Errorf("got %q, want %q", got, content)
+	}
+
+	id := s.CreatePresentation("test")
+	p := s.Presentation(id)
+	thumb, err := srv.Presentations.Pages.GetThumbnail(id, p.Slides[0].ObjectId).ThumbnailPropertiesThumbnailSize("SMALL").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumb.Width != 200 || thumb.Height != 112 {
+		t.Errorf("got %dx%d thumbnail, want 200x112", thumb.Width, thumb.Height)
+	}
+	if got := download(t, thumb.ContentUrl); !bytes.HasPrefix(got, []byte("\x89PNG")) {
+		t.Errorf("thumbnail is not a PNG image: %q", got)
+	}
+}
+
+func download(t *testing.T, url string) []byte {
+	t.Helper()
+	res, err := http.Get(url) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
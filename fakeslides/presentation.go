@@ -0,0 +1,309 @@
+package fakeslides
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/slides/v1"
+)
+
+// Page size of 16:9 presentations in EMU.
+const (
+	pageWidth  = 9144000
+	pageHeight = 5143500
+)
+
+// layoutDef defines a layout of new presentations.
+type layoutDef struct {
+	name         string // predefined layout name, e.g. TITLE_AND_BODY
+	displayName  string
+	placeholders []placeholderDef
+}
+
+type placeholderDef struct {
+	typ  string
+	x, y float64
+	w, h float64
+}
+
+// defaultLayouts are the layouts of new presentations, named as the layouts of the presentation
+// used by the integration tests of deck. The first layouts named TITLE and TITLE_AND_BODY are used
+// by deck as the default layouts of the first and the other pages.
+var defaultLayouts = []layoutDef{
+	{name: "TITLE", displayName: "title", placeholders: []placeholderDef{
+		{typ: "CENTERED_TITLE", x: 311700, y: 744575, w: 8520600, h: 1552600},
+		{typ: "SUBTITLE", x: 311700, y: 2334125, w: 8520600, h: 792600},
+		{typ: "BODY", x: 311700, y: 3226725, w: 8520600, h: 1342200},
+	}},
+	{name: "SECTION_HEADER", displayName: "section", placeholders: []placeholderDef{
+		{typ: "TITLE", x: 311700, y: 2150850, w: 8520600, h: 841800},
+	}},
+	{name: "TITLE_AND_BODY", displayName: "title-and-body", placeholders: []placeholderDef{
+		{typ: "TITLE", x: 311700, y: 445025, w: 8520600, h: 572700},
+		{typ: "SUBTITLE", x: 311700, y: 1017725, w: 8520600, h: 400000},
+		{typ: "BODY", x: 311700, y: 1500000, w: 8520600, h: 3068875},
+	}},
+	{name: "CUSTOM_1", displayName: "title-and-body-half", placeholders: []placeholderDef{
+		{typ: "TITLE", x: 311700, y: 445025, w: 8520600, h: 572700},
+		{typ: "BODY", x: 311700, y: 1152475, w: 4260300, h: 3416400},
+	}},
+	{name: "CUSTOM_2", displayName: "title-and-body-3col", placeholders: []placeholderDef{
+		{typ: "TITLE", x: 311700, y: 445025, w: 8520600, h: 572700},
+		{typ: "SUBTITLE", x: 311700, y: 1017725, w: 8520600, h: 400000},
+		{typ: "SUBTITLE", x: 311700, y: 1500000, w: 2760600, h: 400000},
+		{typ: "SUBTITLE", x: 3191700, y: 1500000, w: 2760600, h: 400000},
+		{typ: "SUBTITLE", x: 6071700, y: 1500000, w: 2760600, h: 400000},
+		{typ: "BODY", x: 311700, y: 2000000, w: 2760600, h: 2600000},
+		{typ: "BODY", x: 3191700, y: 2000000, w: 2760600, h: 2600000},
+		{typ: "BODY", x: 6071700, y: 2000000, w: 2760600, h: 2600000},
+	}},
+	{name: "BLANK", displayName: "blank"},
+}
+
+// newPresentation returns a presentation with the default layouts and a slide of the first layout.
+// The caller must hold s.mu.
+func (s *Server) newPresentation(id, title string) *slides.Presentation {
+	master := &slides.Page{ObjectId: s.newID("master"), PageType: "MASTER"}
+	p := &slides.Presentation{
+		PresentationId: id,
+		Title:          title,
+		Locale:         "en",
+		PageSize: &slides.Size{
+			Width:  &slides.Dimension{Magnitude: pageWidth, Unit: "EMU"},
+			Height: &slides.Dimension{Magnitude: pageHeight, Unit: "EMU"},
+		},
+		Masters:     []*slides.Page{master},
+		NotesMaster: &slides.Page{ObjectId: s.newID("notesMaster"), PageType: "NOTES_MASTER"},
+		RevisionId:  s.newID("rev"),
+	}
+	for _, def := range defaultLayouts {
+		layout := &slides.Page{
+			ObjectId: s.newID("layout"),
+			PageType: "LAYOUT",
+			LayoutProperties: &slides.LayoutProperties{
+				Name:           def.name,
+				DisplayName:    def.displayName,
+				MasterObjectId: master.ObjectId,
+			},
+		}
+		indices := map[string]int64{}
+		for _, ph := range def.placeholders {
+			layout.PageElements = append(layout.PageElements, &slides.PageElement{
+				ObjectId: s.newID("element"),
+				Size: &slides.Size{
+					Width:  &slides.Dimension{Magnitude: ph.w, Unit: "EMU"},
+					Height: &slides.Dimension{Magnitude: ph.h, Unit: "EMU"},
+				},
+				Transform: &slides.AffineTransform{ScaleX: 1, ScaleY: 1, TranslateX: ph.x, TranslateY: ph.y, Unit: "EMU"},
+				Shape: &slides.Shape{
+					ShapeType:       "TEXT_BOX",
+					ShapeProperties: &slides.ShapeProperties{},
+					Placeholder:     &slides.Placeholder{Type: ph.typ, Index: indices[ph.typ]},
+				},
+			})
+			indices[ph.typ]++
+		}
+		p.Layouts = append(p.Layouts, layout)
+	}
+	p.Slides = []*slides.Page{s.newSlide(s.newID("slide"), p.Layouts[0], nil)}
+	return p
+}
+
+// newSlide returns a slide with the placeholders of the layout and a notes page.
+// ids maps the object IDs of the layout placeholders to those of the slide placeholders.
+// The caller must hold s.mu.
+func (s *Server) newSlide(id string, layout *slides.Page, ids map[string]string) *slides.Page {
+	slide := &slides.Page{
+		ObjectId:        id,
+		PageType:        "SLIDE",
+		SlideProperties: &slides.SlideProperties{},
+	}
+	if layout != nil {
+		slide.SlideProperties.LayoutObjectId = layout.ObjectId
+		slide.SlideProperties.MasterObjectId = layout.LayoutProperties.MasterObjectId
+		for _, e := range layout.PageElements {
+			ph := placeholderOf(e)
+			if ph == nil {
+				continue
+			}
+			elementID, ok := ids[e.ObjectId]
+			if !ok {
+				elementID = s.newID("element")
+			}
+			slide.PageElements = append(slide.PageElements, &slides.PageElement{
+				ObjectId:  elementID,
+				Size:      e.Size,
+				Transform: e.Transform,
+				Shape: &slides.Shape{
+					ShapeType:       e.Shape.ShapeType,
+					ShapeProperties: &slides.ShapeProperties{},
+					Placeholder:     &slides.Placeholder{Type: ph.Type, Index: ph.Index, ParentObjectId: e.ObjectId},
+				},
+			})
+		}
+	}
+	notesID := s.newID("element")
+	slide.SlideProperties.NotesPage = &slides.Page{
+		ObjectId: s.newID("notes"),
+		PageType: "NOTES",
+		PageElements: []*slides.PageElement{{
+			ObjectId:  notesID,
+			Transform: &slides.AffineTransform{ScaleX: 1, ScaleY: 1, Unit: "EMU"},
+			Shape: &slides.Shape{
+				ShapeType:       "TEXT_BOX",
+				ShapeProperties: &slides.ShapeProperties{},
+				Placeholder:     &slides.Placeholder{Type: "BODY", Index: 1},
+			},
+		}},
+		NotesProperties: &slides.NotesProperties{SpeakerNotesObjectId: notesID},
+	}
+	return slide
+}
+
+// copyPresentation copies the presentation src as the content of the file f.
+// The caller must hold s.mu.
+func (s *Server) copyPresentation(src *slides.Presentation, f *file) error {
+	p := &slides.Presentation{}
+	if err := clone(src, p); err != nil {
+		return err
+	}
+	p.PresentationId = f.meta.Id
+	p.Title = f.meta.Name
+	p.RevisionId = s.newID("rev")
+	f.presentation = p
+	return nil
+}
+
+// presentation returns the presentation of the ID in the path, or writes the not found error.
+// The caller must hold s.mu.
+func (s *Server) presentation(w http.ResponseWriter, r *http.Request) (*slides.Presentation, bool) {
+	f, ok := s.files[r.PathValue("id")]
+	if !ok || f.presentation == nil {
+		writeError(w, http.StatusNotFound, "Requested entity was not found.")
+		return nil, false
+	}
+	return f.presentation, true
+}
+
+// page returns the page of the presentation, or writes the not found error.
+func page(w http.ResponseWriter, p *slides.Presentation, id string) (*slides.Page, bool) {
+	for _, pages := range [][]*slides.Page{p.Slides, p.Layouts, p.Masters} {
+		for _, page := range pages {
+			if page.ObjectId == id {
+				return page, true
+			}
+		}
+	}
+	for _, slide := range p.Slides {
+		if notes := slide.SlideProperties.NotesPage; notes != nil && notes.ObjectId == id {
+			return notes, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "The page (%s) could not be found.", id)
+	return nil, false
+}
+
+// createSlidesPresentation creates a presentation with the Slides API, which can set the page size.
+func (s *Server) createSlidesPresentation(w http.ResponseWriter, r *http.Request) {
+	var req slides.Presentation
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid presentation: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.createPresentation(&drive.File{Name: req.Title, MimeType: presentationMimeType})
+	if req.PageSize != nil {
+		f.presentation.PageSize = req.PageSize
+	}
+	writeJSON(w, f.presentation)
+}
+
+func (s *Server) getPresentation(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.presentation(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, p)
+}
+
+func (s *Server) getPage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.presentation(w, r)
+	if !ok {
+		return
+	}
+	pg, ok := page(w, p, r.PathValue("pageID"))
+	if !ok {
+		return
+	}
+	res := &slides.Page{}
+	if err := clone(pg, res); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	res.RevisionId = p.RevisionId
+	writeJSON(w, res)
+}
+
+// thumbnailWidths are the widths of the thumbnail sizes.
+var thumbnailWidths = map[string]int64{
+	"SMALL":  200,
+	"MEDIUM": 800,
+	"LARGE":  1600,
+}
+
+func (s *Server) getThumbnail(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.presentation(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := page(w, p, r.PathValue("pageID")); !ok {
+		return
+	}
+	width, ok := thumbnailWidths[r.URL.Query().Get("thumbnailProperties.thumbnailSize")]
+	if !ok {
+		width = thumbnailWidths["LARGE"]
+	}
+	height := width * pageHeight / pageWidth
+	writeJSON(w, &slides.Thumbnail{
+		ContentUrl: fmt.Sprintf("%s/thumbnail?width=%d&height=%d", s.URL, width, height),
+		Width:      width,
+		Height:     height,
+	})
+}
+
+// thumbnailContent serves a blank PNG image as the thumbnail, since the fake does not render slides.
+func (s *Server) thumbnailContent(w http.ResponseWriter, r *http.Request) {
+	width, err := strconv.Atoi(r.URL.Query().Get("width"))
+	if err != nil || width < 1 || width > 1600 {
+		http.Error(w, "invalid width", http.StatusBadRequest)
+		return
+	}
+	height, err := strconv.Atoi(r.URL.Query().Get("height"))
+	if err != nil || height < 1 || height > 1600 {
+		http.Error(w, "invalid height", http.StatusBadRequest)
+		return
+	}
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,240 @@
+package fakeslides
+
+import (
+	"fmt"
+	"slices"
+
+	"google.golang.org/api/slides/v1"
+)
+
+const (
+	defaultRowHeight   = 370840  // in EMU
+	defaultColumnWidth = 1828800 // in EMU
+)
+
+// table returns the table of the page element.
+func (b *batch) table(id string) (*slides.Table, error) {
+	e, _, err := b.element(id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Table == nil {
+		return nil, fmt.Errorf("the object (%s) is not a table", id)
+	}
+	return e.Table, nil
+}
+
+// tableCell returns the cell of the table at the location.
+func tableCell(e *slides.PageElement, loc *slides.TableCellLocation) (*slides.TableCell, error) {
+	if e.Table == nil {
+		return nil, fmt.Errorf("the object (%s) is not a table", e.ObjectId)
+	}
+	if err := checkLocation(e.Table, loc); err != nil {
+		return nil, err
+	}
+	return e.Table.TableRows[loc.RowIndex].TableCells[loc.ColumnIndex], nil
+}
+
+func checkLocation(t *slides.Table, loc *slides.TableCellLocation) error {
+	if loc == nil {
+		return fmt.Errorf("cellLocation is required")
+	}
+	if loc.RowIndex < 0 || loc.RowIndex >= t.Rows || loc.ColumnIndex < 0 || loc.ColumnIndex >= t.Columns {
+		return fmt.Errorf("the cell location (%d, %d) is out of the table of %d rows and %d columns",
+			loc.RowIndex, loc.ColumnIndex, t.Rows, t.Columns)
+	}
+	return nil
+}
+
+func newTableCell() *slides.TableCell {
+	return &slides.TableCell{
+		RowSpan:             1,
+		ColumnSpan:          1,
+		Location:            &slides.TableCellLocation{},
+		TableCellProperties: &slides.TableCellProperties{},
+	}
+}
+
+func newTableRow(columns int64) *slides.TableRow {
+	row := &slides.TableRow{RowHeight: &slides.Dimension{Magnitude: defaultRowHeight, Unit: "EMU"}}
+	for range columns {
+		row.TableCells = append(row.TableCells, newTableCell())
+	}
+	return row
+}
+
+// renumber updates the sizes of the table and the locations of the cells after rows or columns are changed.
+func renumber(t *slides.Table) {
+	t.Rows = int64(len(t.TableRows))
+	t.Columns = int64(len(t.TableColumns))
+	for i, row := range t.TableRows {
+		for j, cell := range row.TableCells {
+			cell.Location = &slides.TableCellLocation{RowIndex: int64(i), ColumnIndex: int64(j)}
+		}
+	}
+}
+
+func (b *batch) createTable(r *slides.CreateTableRequest) (string, error) {
+	if r.Rows < 1 || r.Columns < 1 {
+		return "", fmt.Errorf("the table should have at least 1 row and 1 column")
+	}
+	t := &slides.Table{}
+	for range r.Columns {
+		t.TableColumns = append(t.TableColumns, &slides.TableColumnProperties{
+			ColumnWidth: &slides.Dimension{Magnitude: defaultColumnWidth, Unit: "EMU"},
+		})
+	}
+	for range r.Rows {
+		t.TableRows = append(t.TableRows, newTableRow(r.Columns))
+	}
+	renumber(t)
+	return b.createElement(r.ObjectId, r.ElementProperties, func(e *slides.PageElement) {
+		e.Table = t
+	})
+}
+
+func (b *batch) insertTableRows(r *slides.InsertTableRowsRequest) error {
+	t, err := b.table(r.TableObjectId)
+	if err != nil {
+		return err
+	}
+	if err := checkLocation(t, r.CellLocation); err != nil {
+		return err
+	}
+	at := r.CellLocation.RowIndex
+	if r.InsertBelow {
+		at++
+	}
+	n := max(r.Number, 1)
+	rows := make([]*slides.TableRow, n)
+	for i := range rows {
+		rows[i] = newTableRow(t.Columns)
+	}
+	t.TableRows = slices.Insert(t.TableRows, int(at), rows...)
+	renumber(t)
+	return nil
+}
+
+func (b *batch) insertTableColumns(r *slides.InsertTableColumnsRequest) error {
+	t, err := b.table(r.TableObjectId)
+	if err != nil {
+		return err
+	}
+	if err := checkLocation(t, r.CellLocation); err != nil {
+		return err
+	}
+	at := int(r.CellLocation.ColumnIndex)
+	if r.InsertRight {
+		at++
+	}
+	n := int(max(r.Number, 1))
+	for range n {
+		t.TableColumns = slices.Insert(t.TableColumns, at, &slides.TableColumnProperties{
+			ColumnWidth: &slides.Dimension{Magnitude: defaultColumnWidth, Unit: "EMU"},
+		})
+		for _, row := range t.TableRows {
+			row.TableCells = slices.Insert(row.TableCells, at, newTableCell())
+		}
+	}
+	renumber(t)
+	return nil
+}
+
+func (b *batch) deleteTableRow(r *slides.DeleteTableRowRequest) error {
+	e, page, err := b.element(r.TableObjectId)
+	if err != nil {
+		return err
+	}
+	if e.Table == nil {
+		return fmt.Errorf("the object (%s) is not a table", r.TableObjectId)
+	}
+	if err := checkLocation(e.Table, r.CellLocation); err != nil {
+		return err
+	}
+	i := int(r.CellLocation.RowIndex)
+	e.Table.TableRows = slices.Delete(e.Table.TableRows, i, i+1)
+	renumber(e.Table)
+	if e.Table.Rows == 0 {
+		// As the API does, the table is deleted with its last row.
+		page.PageElements = slices.DeleteFunc(page.PageElements, func(pe *slides.PageElement) bool { return pe == e })
+	}
+	return nil
+}
+
+func (b *batch) deleteTableColumn(r *slides.DeleteTableColumnRequest) error {
+	e, page, err := b.element(r.TableObjectId)
+	if err != nil {
+		return err
+	}
+	if e.Table == nil {
+		return fmt.Errorf("the object (%s) is not a table", r.TableObjectId)
+	}
+	if err := checkLocation(e.Table, r.CellLocation); err != nil {
+		return err
+	}
+	j := int(r.CellLocation.ColumnIndex)
+	e.Table.TableColumns = slices.Delete(e.Table.TableColumns, j, j+1)
+	for _, row := range e.Table.TableRows {
+		row.TableCells = slices.Delete(row.TableCells, j, j+1)
+	}
+	renumber(e.Table)
+	if e.Table.Columns == 0 {
+		// As the API does, the table is deleted with its last column.
+		page.PageElements = slices.DeleteFunc(page.PageElements, func(pe *slides.PageElement) bool { return pe == e })
+	}
+	return nil
+}
+
+func (b *batch) updateTableCellProperties(r *slides.UpdateTableCellPropertiesRequest) error {
+	t, err := b.table(r.ObjectId)
+	if err != nil {
+		return err
+	}
+	// Without the range, the whole table is updated.
+	rowStart, colStart, rowSpan, colSpan := int64(0), int64(0), t.Rows, t.Columns
+	if tr := r.TableRange; tr != nil {
+		if err := checkLocation(t, tr.Location); err != nil {
+			return err
+		}
+		rowStart, colStart, rowSpan, colSpan = tr.Location.RowIndex, tr.Location.ColumnIndex, tr.RowSpan, tr.ColumnSpan
+		if rowStart+rowSpan > t.Rows || colStart+colSpan > t.Columns {
+			return fmt.Errorf("the table range is out of the table of %d rows and %d columns", t.Rows, t.Columns)
+		}
+	}
+	for i := rowStart; i < rowStart+rowSpan; i++ {
+		for j := colStart; j < colStart+colSpan; j++ {
+			cell := t.TableRows[i].TableCells[j]
+			props := &slides.TableCellProperties{}
+			if err := merge(cell.TableCellProperties, r.TableCellProperties, r.Fields, props); err != nil {
+				return err
+			}
+			cell.TableCellProperties = props
+		}
+	}
+	return nil
+}
+
+func (b *batch) updateTableColumnProperties(r *slides.UpdateTableColumnPropertiesRequest) error {
+	t, err := b.table(r.ObjectId)
+	if err != nil {
+		return err
+	}
+	indices := r.ColumnIndices
+	if len(indices) == 0 {
+		// Without the indices, all columns are updated.
+		for j := range t.Columns {
+			indices = append(indices, j)
+		}
+	}
+	for _, j := range indices {
+		if j < 0 || j >= t.Columns {
+			return fmt.Errorf("the column index %d is out of the table of %d columns", j, t.Columns)
+		}
+		props := &slides.TableColumnProperties{}
+		if err := merge(t.TableColumns[j], r.TableColumnProperties, r.Fields, props); err != nil {
+			return err
+		}
+		t.TableColumns[j] = props
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package fakeslides
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf16"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// The text of shapes and table cells is edited as a sequence of characters with their styles, and stored as
+// the TextContent returned by the API. Every text ends with a newline, which cannot be deleted, and the
+// paragraph properties such as bullets are kept on the newline ending the paragraph.
+
+type char struct {
+	r     rune
+	style *slides.TextStyle
+	para  *paragraph // properties of the paragraph ended by the newline, nil for other characters
+}
+
+type paragraph struct {
+	style  *slides.ParagraphStyle
+	bullet *slides.Bullet
+}
+
+type text struct {
+	chars []char
+}
+
+// parseText returns the text stored in tc.
+func parseText(tc *slides.TextContent) *text {
+	t := &text{}
+	var para *paragraph
+	if tc != nil {
+		for _, e := range tc.TextElements {
+			switch {
+			case e.ParagraphMarker != nil:
+				para = &paragraph{style: e.ParagraphMarker.Style, bullet: e.ParagraphMarker.Bullet}
+			case e.TextRun != nil:
+				for _, r := range e.TextRun.Content {
+					c := char{r: r, style: e.TextRun.Style}
+					if r == '\n' {
+						c.para = para
+						if c.para == nil {
+							c.para = &paragraph{}
+						}
+						para = nil
+					}
+					t.chars = append(t.chars, c)
+				}
+			}
+		}
+	}
+	if len(t.chars) == 0 || t.chars[len(t.chars)-1].r != '\n' {
+		t.chars = append(t.chars, char{r: '\n', para: &paragraph{}})
+	}
+	return t
+}
+
+// content returns the text stored as TextContent, or nil if it is empty.
+func (t *text) content() *slides.TextContent {
+	if len(t.chars) == 1 && t.chars[0].para.bullet == nil && t.chars[0].para.style == nil {
+		return nil
+	}
+	tc := &slides.TextContent{}
+	var (
+		index int64 // in UTF-16 code units
+		start int   // first character of the paragraph
+	)
+	for i, c := range t.chars {
+		if c.r != '\n' {
+			continue
+		}
+		pstart := index
+		for _, cc := range t.chars[start : i+1] {
+			index += int64(utf16.RuneLen(cc.r))
+		}
+		tc.TextElements = append(tc.TextElements, &slides.TextElement{
+			StartIndex: pstart,
+			EndIndex:   index,
+			ParagraphMarker: &slides.ParagraphMarker{
+				Style:  c.para.style,
+				Bullet: c.para.bullet,
+			},
+		})
+		// Split the paragraph into runs of the same style.
+		rstart := pstart
+		var run strings.Builder
+		for j := start; j <= i; j++ {
+			run.WriteRune(t.chars[j].r)
+			if j == i || !reflect.DeepEqual(t.chars[j].style, t.chars[j+1].style) {
+				rend := rstart + int64(len(utf16.Encode([]rune(run.String()))))
+				tc.TextElements = append(tc.TextElements, &slides.TextElement{
+					StartIndex: rstart,
+					EndIndex:   rend,
+					TextRun: &slides.TextRun{
+						Content: run.String(),
+						Style:   cloneStyle(t.chars[j].style),
+					},
+				})
+				rstart = rend
+				run.Reset()
+			}
+		}
+		start = i + 1
+	}
+	return tc
+}
+
+// charIndex returns the index of the character at the UTF-16 index.
+func (t *text) charIndex(index int64) (int, error) {
+	var n int64
+	for i, c := range t.chars {
+		if n == index {
+			return i, nil
+		}
+		if n > index {
+			return 0, fmt.Errorf("index %d is in the middle of a character", index)
+		}
+		n += int64(utf16.RuneLen(c.r))
+	}
+	if n == index {
+		return len(t.chars), nil
+	}
+	return 0, fmt.Errorf("index %d is out of range (text length %d)", index, n)
+}
+
+// span returns the range of characters of r. The final newline is included only if includeLast is true.
+func (t *text) span(r *slides.Range, includeLast bool) (int, int, error) {
+	last := len(t.chars)
+	if !includeLast {
+		last--
+	}
+	if r == nil {
+		return 0, last, nil
+	}
+	switch r.Type {
+	case "ALL", "":
+		return 0, last, nil
+	case "FROM_START_INDEX":
+		if r.StartIndex == nil {
+			return 0, 0, fmt.Errorf("startIndex is required for FROM_START_INDEX")
+		}
+		s, err := t.charIndex(*r.StartIndex)
+		if err != nil {
+			return 0, 0, err
+		}
+		return min(s, last), last, nil
+	case "FIXED_RANGE":
+		if r.StartIndex == nil || r.EndIndex == nil {
+			return 0, 0, fmt.Errorf("startIndex and endIndex are required for FIXED_RANGE")
+		}
+		s, err := t.charIndex(*r.StartIndex)
+		if err != nil {
+			return 0, 0, err
+		}
+		e, err := t.charIndex(*r.EndIndex)
+		if err != nil {
+			return 0, 0, err
+		}
+		if s > e {
+			return 0, 0, fmt.Errorf("startIndex %d is greater than endIndex %d", *r.StartIndex, *r.EndIndex)
+		}
+		if e > last && !includeLast {
+			e = last
+		}
+		return s, e, nil
+	default:
+		return 0, 0, fmt.Errorf("unsupported range type %q", r.Type)
+	}
+}
+
+// insert inserts s at the UTF-16 index. The inserted characters take the style of the preceding character
+// in the paragraph, and the inserted newlines the properties of the paragraph.
+func (t *text) insert(index int64, s string) error {
+	at, err := t.charIndex(index)
+	if err != nil {
+		return err
+	}
+	if at == len(t.chars) {
+		return fmt.Errorf("index %d is after the last newline", index)
+	}
+	var style *slides.TextStyle
+	if at > 0 && t.chars[at-1].r != '\n' {
+		style = t.chars[at-1].style
+	} else {
+		style = t.chars[at].style
+	}
+	para := t.paragraphAt(at)
+	var inserted []char
+	for _, r := range s {
+		c := char{r: r, style: style}
+		if r == '\n' {
+			c.para = &paragraph{style: para.style, bullet: cloneBullet(para.bullet)}
+		}
+		inserted = append(inserted, c)
+	}
+	t.chars = append(t.chars[:at], append(inserted, t.chars[at:]...)...)
+	return nil
+}
+
+// delete deletes the characters in r, except the final newline.
+func (t *text) delete(r *slides.Range) error {
+	s, e, err := t.span(r, false)
+	if err != nil {
+		return err
+	}
+	t.chars = append(t.chars[:s], t.chars[e:]...)
+	return nil
+}
+
+// paragraphAt returns the properties of the paragraph containing the character at i.
+func (t *text) paragraphAt(i int) *paragraph {
+	for ; i < len(t.chars); i++ {
+		if t.chars[i].r == '\n' {
+			return t.chars[i].para
+		}
+	}
+	return t.chars[len(t.chars)-1].para
+}
+
+// paragraphs returns the indices of the newlines ending the paragraphs overlapping the characters from s to e.
+func (t *text) paragraphs(s, e int) []int {
+	var ends []int
+	for i := s; i < len(t.chars); i++ {
+		if t.chars[i].r != '\n' {
+			continue
+		}
+		ends = append(ends, i)
+		if i >= e-1 {
+			break
+		}
+	}
+	return ends
+}
+
+// updateStyle updates the fields of the style of the characters in r.
+func (t *text) updateStyle(r *slides.Range, style *slides.TextStyle, fields string) error {
+	s, e, err := t.span(r, true)
+	if err != nil {
+		return err
+	}
+	for i := s; i < e; i++ {
+		updated := &slides.TextStyle{}
+		if err := merge(t.chars[i].style, style, fields, updated); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(updated, &slides.TextStyle{}) {
+			updated = nil
+		}
+		t.chars[i].style = updated
+	}
+	return nil
+}
+
+// updateParagraphStyle updates the fields of the style of the paragraphs overlapping r.
+func (t *text) updateParagraphStyle(r *slides.Range, style *slides.ParagraphStyle, fields string) error {
+	s, e, err := t.span(r, true)
+	if err != nil {
+		return err
+	}
+	for _, i := range t.paragraphs(s, e) {
+		updated := &slides.ParagraphStyle{}
+		if err := merge(t.chars[i].para.style, style, fields, updated); err != nil {
+			return err
+		}
+		p := *t.chars[i].para
+		p.style = updated
+		t.chars[i].para = &p
+	}
+	return nil
+}
+
+// createBullets adds bullets to the paragraphs overlapping r. As the API does, the leading tabs of
+// the paragraphs are removed and set as the nesting levels of the bullets.
+func (t *text) createBullets(r *slides.Range, preset string) error {
+	s, e, err := t.span(r, true)
+	if err != nil {
+		return err
+	}
+	ends := t.paragraphs(s, e)
+	// Remove tabs from the last paragraph first, so that the indices of the preceding ones do not change.
+	for k := len(ends) - 1; k >= 0; k-- {
+		end := ends[k]
+		start := 0
+		if k > 0 {
+			start = ends[k-1] + 1
+		} else {
+			for start = end; start > 0 && t.chars[start-1].r != '\n'; start-- {
+			}
+		}
+		tabs := 0
+		for start+tabs < end && t.chars[start+tabs].r == '\t' {
+			tabs++
+		}
+		p := *t.chars[end].para
+		p.bullet = &slides.Bullet{NestingLevel: int64(tabs), Glyph: bulletGlyph(preset, tabs), ListId: preset}
+		t.chars[end].para = &p
+		t.chars = append(t.chars[:start], t.chars[start+tabs:]...)
+	}
+	return nil
+}
+
+// bulletGlyph returns the glyph of the first item at the nesting level of the list of the preset,
+// such as "1.", "a." and "i." of NUMBERED_DIGIT_ALPHA_ROMAN. The following items are not numbered.
+func bulletGlyph(preset string, nesting int) string {
+	glyphs := []string{"●", "○", "■"}
+	if strings.HasPrefix(preset, "NUMBERED") {
+		glyphs = []string{"1.", "a.", "i."}
+	}
+	return glyphs[nesting%len(glyphs)]
+}
+
+// deleteBullets removes the bullets of the paragraphs overlapping r.
+func (t *text) deleteBullets(r *slides.Range) error {
+	s, e, err := t.span(r, true)
+	if err != nil {
+		return err
+	}
+	for _, i := range t.paragraphs(s, e) {
+		p := *t.chars[i].para
+		p.bullet = nil
+		t.chars[i].para = &p
+	}
+	return nil
+}
+
+func cloneStyle(s *slides.TextStyle) *slides.TextStyle {
+	if s == nil {
+		return nil
+	}
+	c := &slides.TextStyle{}
+	if err := merge(nil, s, "*", c); err != nil {
+		return s
+	}
+	return c
+}
+
+func cloneBullet(b *slides.Bullet) *slides.Bullet {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	return &c
+}
@@ -0,0 +1,76 @@
+package deck
+
+// Frozen pages are pinned to the pages of the presentation before the slides are mapped, so that the similarity
+// matcher never pairs them with other pages. A page frozen with FreezePosition is pinned to the page at the same
+// index, and keeps its index. Any other frozen page is pinned to the page it is, identified by the key or else
+// by the contents, and is moved as a whole if the pages around it change.
+
+// minFreezeSimilarity is the minimum similarity of the contents to identify the page of a frozen slide,
+// which is more than the score of the same layout alone.
+const minFreezeSimilarity = 51
+
+// pinFrozenSlides pins the frozen slides of after to the pages of before, and replaces the pinned pages of before with
+// copies of the frozen slides so that they are never updated. before and after have the same length.
+func pinFrozenSlides(before, after Slides) {
+	pinned := make([]bool, len(before))
+	pin := func(beforeIdx, afterIdx int) {
+		pinned[beforeIdx] = true
+		before[beforeIdx] = copySlide(after[afterIdx])
+		before[beforeIdx].pin = afterIdx + 1
+		after[afterIdx].pin = afterIdx + 1
+	}
+	// Pages frozen in their positions are pinned first, since they cannot be pinned to any other page.
+	for i, s := range after {
+		if s.Freeze && s.FreezePosition && !before[i].new {
+			pin(i, i)
+		}
+	}
+	for i, s := range after {
+		if !s.Freeze || s.FreezePosition {
+			continue
+		}
+		if j := frozenPageIndex(before, s, i, pinned); j >= 0 {
+			pin(j, i)
+		}
+	}
+}
+
+// frozenPageIndex returns the index of the page of before that the frozen slide at index i of after is, or -1 if none.
+// The page with the same key is preferred, then the page with the most similar contents, the nearest one
+// to i if they are equally similar, and then the page at the same index.
+func frozenPageIndex(before Slides, frozen *Slide, i int, pinned []bool) int {
+	available := func(j int) bool {
+		return !pinned[j] && !before[j].new && (before[j].Key == "" || before[j].Key == frozen.Key)
+	}
+	if frozen.Key != "" {
+		for j, s := range before {
+			if available(j) && s.Key == frozen.Key {
+				return j
+			}
+		}
+	}
+	best, bestScore := -1, minFreezeSimilarity-1
+	for j, s := range before {
+		if !available(j) {
+			continue
+		}
+		score := getSimilarity(s, frozen)
+		if score > bestScore || score == bestScore && best >= 0 && abs(j-i) < abs(best-i) {
+			best, bestScore = j, score
+		}
+	}
+	if best >= 0 {
+		return best
+	}
+	if i < len(before) && available(i) {
+		return i
+	}
+	return -1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
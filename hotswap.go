@@ -0,0 +1,112 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// hotSwapPage applies slide to the page at index without touching the page itself.
+// The page is duplicated as a hidden page right after it, the slide is applied to the duplicate,
+// and then the duplicate is shown and the original page is deleted in a single batch update.
+// Since a batch update is atomic, viewers see either the old page or the new page.
+// The Slides API cannot move page elements between pages, so the swapped page has a new object ID:
+// deep links to the original page and Drive comments anchored to it do not follow the swap.
+func (d *Deck) hotSwapPage(ctx context.Context, index int, slide *Slide) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if len(d.presentation.Slides) <= index {
+		return fmt.Errorf("index out of range: %d", index)
+	}
+	original := d.presentation.Slides[index].ObjectId
+	duplicate := fmt.Sprintf("hotswap-%s", uuid.New().String())
+	if err := d.batchUpdate(ctx, []*slides.Request{
+		{
+			DuplicateObject: &slides.DuplicateObjectRequest{
+				ObjectId:  original,
+				ObjectIds: map[string]string{original: duplicate},
+			},
+		},
+		skipSlideRequest(duplicate, true),
+	}); err != nil {
+		return fmt.Errorf("failed to duplicate page: %w", err)
+	}
+	if err := d.refresh(ctx); err != nil {
+		return err
+	}
+
+	// The duplicate is at index+1. If the layout changes, the duplicate is replaced with a new page
+	// at the same index, so the object ID of the page is looked up again after each step.
+	reqs, err := d.prepareToApplyPage(ctx, index+1, slide, nil)
+	if err != nil {
+		return err
+	}
+	reqs = hideSlideRequests(reqs)
+	if d.presentation.Slides[index+1].ObjectId != duplicate {
+		// The page created for the new layout is not hidden yet.
+		reqs = append([]*slides.Request{skipSlideRequest(d.presentation.Slides[index+1].ObjectId, true)}, reqs...)
+	}
+	if len(reqs) > 0 {
+		if err := d.batchUpdate(ctx, reqs); err != nil {
+			return fmt.Errorf("failed to apply page: %w", err)
+		}
+	}
+	if err := d.refresh(ctx); err != nil {
+		return err
+	}
+	swapped := d.presentation.Slides[index+1].ObjectId
+	if len(slide.Tables) > 0 {
+		reqs, err := d.collectTableContentRequests(swapped, slide.Tables, slide.Direction)
+		if err != nil {
+			return err
+		}
+		if len(reqs) > 0 {
+			if err := d.batchUpdate(ctx, reqs); err != nil {
+				return fmt.Errorf("failed to update table content: %w", err)
+			}
+		}
+	}
+
+	if err := d.batchUpdate(ctx, []*slides.Request{
+		skipSlideRequest(swapped, slide.Skip),
+		{
+			DeleteObject: &slides.DeleteObjectRequest{
+				ObjectId: original,
+			},
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to swap page: %w", err)
+	}
+	d.logger.Info("swapped page", slog.Int("index", index), slog.String("original", original), slog.String("swapped", swapped))
+	return d.refresh(ctx)
+}
+
+// skipSlideRequest returns the request to set whether the page is skipped in presentation mode.
+func skipSlideRequest(objectID string, skip bool) *slides.Request {
+	return &slides.Request{
+		UpdateSlideProperties: &slides.UpdateSlidePropertiesRequest{
+			ObjectId: objectID,
+			SlideProperties: &slides.SlideProperties{
+				IsSkipped: skip,
+			},
+			Fields: "isSkipped",
+		},
+	}
+}
+
+// hideSlideRequests returns reqs with the pages kept skipped, so that a page being built stays hidden.
+func hideSlideRequests(reqs []*slides.Request) []*slides.Request {
+	hidden := make([]*slides.Request, 0, len(reqs))
+	for _, r := range reqs {
+		if r.UpdateSlideProperties != nil && r.UpdateSlideProperties.Fields == "isSkipped" {
+			r = skipSlideRequest(r.UpdateSlideProperties.ObjectId, true)
+		}
+		hidden = append(hidden, r)
+	}
+	return hidden
+}
@@ -0,0 +1,102 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestHideSlideRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		reqs []*slides.Request
+		want []*slides.Request
+	}{
+		{
+			"no requests",
+			nil,
+			[]*slides.Request{},
+		},
+		{
+			"shown page is kept hidden",
+			[]*slides.Request{
+				{DeleteObject: &slides.DeleteObjectRequest{ObjectId: "shape"}},
+				skipSlideRequest("page", false),
+			},
+			[]*slides.Request{
+				{DeleteObject: &slides.DeleteObjectRequest{ObjectId: "shape"}},
+				skipSlideRequest("page", true),
+			},
+		},
+		{
+			"other slide properties are kept",
+			[]*slides.Request{
+				{
+					UpdateSlideProperties: &slides.UpdateSlidePropertiesRequest{
+						ObjectId:        "page",
+						SlideProperties: &slides.SlideProperties{LayoutObjectId: "layout"},
+						Fields:          "layoutObjectId",
+					},
+				},
+			},
+			[]*slides.Request{
+				{
+					UpdateSlideProperties: &slides.UpdateSlidePropertiesRequest{
+						ObjectId:        "page",
+						SlideProperties: &slides.SlideProperties{LayoutObjectId: "layout"},
+						Fields:          "layoutObjectId",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hideSlideRequests(tt.reqs)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("hideSlideRequests() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHotSwapPageLinks(t *testing.T) {
+	ctx := t.Context()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	id := srv.CreatePresentation("test")
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Apply(ctx, Slides{NewSlide("title-and-body", "A"), NewSlide("title-and-body", "B")}); err != nil {
+		t.Fatal(err)
+	}
+	before := d.PageLinks()
+
+	d.hotSwap = true
+	if err := d.Apply(ctx, Slides{NewSlide("title-and-body", "A"), NewSlide("title-and-body", "C")}); err != nil {
+		t.Fatal(err)
+	}
+	after := d.PageLinks()
+	p := srv.Presentation(id)
+	if len(after) != 2 || len(p.Slides) != 2 {
+		t.Fatalf("unexpected pages: %d links, %d slides", len(after), len(p.Slides))
+	}
+	if after[0].ObjectID != before[0].ObjectID {
+		t.Errorf("unchanged page is swapped: %s -> %s", before[0].ObjectID, after[0].ObjectID)
+	}
+	if after[1].ObjectID == before[1].ObjectID {
+		t.Errorf("updated page is not swapped: %s", after[1].ObjectID)
+	}
+	for i, l := range after {
+		if want := p.Slides[i].ObjectId; l.ObjectID != want || l.URL != PageURL(id, want) {
+			t.Errorf("link to page %d is not the swapped page: got %s %s, want %s", l.Page, l.ObjectID, l.URL, want)
+		}
+	}
+	if after[1].Title != "C" {
+		t.Errorf("got title %q, want %q", after[1].Title, "C")
+	}
+}
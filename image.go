@@ -16,13 +16,18 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/corona10/goimagehash"
 	"github.com/k1LoW/errors"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
 	"golang.org/x/net/publicsuffix"
+	"google.golang.org/api/slides/v1"
 )
 
 type MIMEType string
@@ -43,6 +48,15 @@ type Image struct {
 	pHash        *goimagehash.ImageHash // Perceptual hash for JPEG images
 	modTime      time.Time              // Modification time of the image file, if applicable
 	link         string                 // External link associated with the image
+	driveFileID  string                 // ID of the file on Google Drive if the URL is a Drive link
+	alt          string                 // Alternative text of the image
+	title        string                 // Title of the image
+	optimization *ImageOptimization     // Optimization before upload, overriding the one of the deck
+	optimized    bool                   // Whether the image data has been optimized
+	placeholder  int                    // 1-based index of the picture placeholder to place the image in (0: in order)
+	credit       string                 // Credit of the image rendered as a caption beneath it
+	converted    bool                   // Whether the image data has been rotated or converted to be uploaded
+	zOrder       string                 // Z-order of the image: ZOrderBack or empty (in front)
 
 	// Upload state management
 	uploadMutex    sync.RWMutex
@@ -60,10 +74,21 @@ const (
 	uploadStateFailed
 )
 
+// NewImage returns a new image from a local file path or URL.
+// Images created by NewImage are not managed by deck, so they are not deleted when they disappear from the slide.
+// Use NewImageFromMarkdown or NewImageFromBytes for images that deck should manage.
 func NewImage(pathOrURL string) (_ *Image, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	if id, ok := driveFileIDFromURL(pathOrURL); ok {
+		// Images on Google Drive are loaded with the Drive API when applying,
+		// because they cannot be fetched without credentials.
+		return &Image{
+			url:         pathOrURL,
+			driveFileID: id,
+		}, nil
+	}
 	var b io.Reader
 	var modTime time.Time
 	if strings.HasPrefix(pathOrURL, "http://") || strings.HasPrefix(pathOrURL, "https://") {
@@ -116,7 +141,7 @@ func NewImage(pathOrURL string) (_ *Image, err error) {
 		return nil, fmt.Errorf("failed to create image from buffer: %w", err)
 	}
 	i.url = pathOrURL
-	if isPublicURL(pathOrURL) {
+	if isPublicURL(pathOrURL) && !i.converted {
 		// If the URL appears to be OK for direct access, `deck` will not upload a temporary image to Google Drive
 		// but will instead specify that URL directly in the CreateImageRequest.
 		i.webContentLink = pathOrURL
@@ -126,10 +151,20 @@ func NewImage(pathOrURL string) (_ *Image, err error) {
 	return i, nil
 }
 
+// NewImageFromMarkdown returns a new image managed by deck from a local file path, URL or data URI.
+// Images embedded as data URIs such as `data:image/png;base64,...` are uploaded like images from local files.
 func NewImageFromMarkdown(pathOrURL string) (_ *Image, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	if strings.HasPrefix(pathOrURL, "data:") {
+		i, err := newImageFromDataURI(pathOrURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create image from data URI: %w", err)
+		}
+		i.fromMarkdown = true
+		return i, nil
+	}
 	i, err := NewImage(pathOrURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create image from path or URL: %w", err)
@@ -138,6 +173,7 @@ func NewImageFromMarkdown(pathOrURL string) (_ *Image, err error) {
 	return i, nil
 }
 
+// NewImageFromCodeBlock returns a new image managed by deck from the output of a code block command.
 func NewImageFromCodeBlock(r io.Reader) (_ *Image, err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -150,6 +186,48 @@ func NewImageFromCodeBlock(r io.Reader) (_ *Image, err error) {
 	return i, nil
 }
 
+// NewImageFromBytes returns a new image managed by deck from raw image data.
+// Images other than PNG, JPEG and GIF are converted to PNG, and JPEG images are rotated by their EXIF orientation.
+func NewImageFromBytes(b []byte) (_ *Image, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	i, err := newImageFromBuffer(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image from bytes: %w", err)
+	}
+	i.fromMarkdown = true
+	return i, nil
+}
+
+// newImageFromDataURI returns a new image from a data URI such as `data:image/png;base64,...`.
+// The media type of the data URI is not trusted, the image data is decoded to detect it.
+func newImageFromDataURI(dataURI string) (_ *Image, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	mediaType, data, ok := strings.Cut(strings.TrimPrefix(dataURI, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid data URI: missing comma")
+	}
+	var b []byte
+	if strings.HasSuffix(mediaType, ";base64") {
+		// Pasted data may be wrapped or lack the padding.
+		data = strings.TrimRight(strings.Join(strings.Fields(data), ""), "=")
+		b, err = base64.RawStdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 image data: %w", err)
+		}
+	} else {
+		s, err := url.PathUnescape(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unescape image data: %w", err)
+		}
+		b = []byte(s)
+	}
+	return newImageFromBuffer(bytes.NewReader(b))
+}
+
 func newImageFromBuffer(r io.Reader) (_ *Image, err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -158,27 +236,50 @@ func newImageFromBuffer(r io.Reader) (_ *Image, err error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read image data: %w", err)
 	}
-	_, mimeType, err := image.DecodeConfig(bytes.NewReader(b))
+	b, mt, converted, err := normalizeImage(b)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode image: %w", err)
-	}
-	var mt MIMEType
-	switch mimeType {
-	case "png":
-		mt = MIMETypeImagePNG
-	case "jpeg":
-		mt = MIMETypeImageJPEG
-	case "gif":
-		mt = MIMETypeImageGIF
-	default:
-		return nil, fmt.Errorf("unsupported image MIME type: %s", mimeType)
+		return nil, err
 	}
 	return &Image{
-		b:        b,
-		mimeType: mt,
+		b:         b,
+		mimeType:  mt,
+		converted: converted,
 	}, nil
 }
 
+// SetAlt sets the alternative text of the image.
+func (i *Image) SetAlt(alt string) {
+	i.alt = alt
+}
+
+// Alt returns the alternative text of the image.
+func (i *Image) Alt() string {
+	return i.alt
+}
+
+// SetTitle sets the title of the image.
+func (i *Image) SetTitle(title string) {
+	i.title = title
+}
+
+// Title returns the title of the image.
+func (i *Image) Title() string {
+	return i.title
+}
+
+// SetPlaceholder sets the 1-based index of the picture placeholder of the layout to place the image in,
+// in the order of the placeholders from top to bottom and left to right.
+// Images without it fill the remaining placeholders in order.
+func (i *Image) SetPlaceholder(n int) {
+	i.placeholder = n
+}
+
+// Placeholder returns the 1-based index of the picture placeholder to place the image in, or 0 if not set.
+func (i *Image) Placeholder() int {
+	return i.placeholder
+}
+
+// SetLink sets the link of the image.
 func (i *Image) SetLink(link string) {
 	i.link = link
 }
@@ -286,6 +387,11 @@ type internalImage struct {
 	FromMarkdown bool
 	ModTime      time.Time
 	Link         string
+	Alt          string
+	Title        string
+	Placeholder  int    `json:",omitempty"`
+	Credit       string `json:",omitempty"`
+	ZOrder       string `json:",omitempty"`
 }
 
 // MarshalJSON and UnmarshalJSON are defined for cloning data and for similarity comparisons of `slide` structures.
@@ -367,6 +473,72 @@ func (i *Image) IsUploadNeeded() bool {
 	return i.uploadState == uploadStateNotStarted && i.webContentLink == ""
 }
 
+// resetUpload forgets the result of the upload of the image, so that it is uploaded again.
+// Images not uploaded by deck, such as images on Google Drive, are left as they are.
+func (i *Image) resetUpload() {
+	i.uploadMutex.Lock()
+	defer i.uploadMutex.Unlock()
+	if i.uploadState == uploadStateNotStarted {
+		return
+	}
+	i.uploadState = uploadStateNotStarted
+	i.webContentLink = ""
+	i.uploadError = nil
+}
+
+// altTextRequest returns the request to set the alt text of the image element.
+// The title of the image is set as the title of the alt text and the alternative text as the description.
+// The description of images managed by deck ends with descriptionImageFromMarkdown, or with
+// descriptionBackImageFromMarkdown for images sent back, so that they can be recognized.
+// It returns nil if there is nothing to set.
+func (i *Image) altTextRequest(objectID string) *slides.Request {
+	description := i.alt
+	if i.fromMarkdown {
+		description = imageDescription(i.alt, i.zOrder)
+	}
+	if description == "" && i.title == "" {
+		return nil
+	}
+	return &slides.Request{
+		UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+			ObjectId:    objectID,
+			Title:       i.title,
+			Description: description,
+		},
+	}
+}
+
+// setAltText sets the alternative text and the title of the image from the alt text of the image element.
+func (i *Image) setAltText(title, description string) {
+	i.title = title
+	i.alt = description
+	switch {
+	case isBackImageFromMarkdown(description):
+		i.alt = strings.TrimSuffix(strings.TrimSuffix(description, descriptionBackImageFromMarkdown), "\n\n")
+		i.zOrder = ZOrderBack
+	case isImageFromMarkdown(description):
+		i.alt = strings.TrimSuffix(strings.TrimSuffix(description, descriptionImageFromMarkdown), "\n\n")
+	}
+}
+
+// imageDescription returns the description of the alt text of an image managed by deck.
+func imageDescription(alt, zOrder string) string {
+	marker := descriptionImageFromMarkdown
+	if zOrder == ZOrderBack {
+		marker = descriptionBackImageFromMarkdown
+	}
+	if alt == "" {
+		return marker
+	}
+	return alt + "\n\n" + marker
+}
+
+// isImageFromMarkdown reports whether description is the description of the alt text of an image managed by deck.
+func isImageFromMarkdown(description string) bool {
+	return description == descriptionImageFromMarkdown || strings.HasSuffix(description, "\n\n"+descriptionImageFromMarkdown) ||
+		isBackImageFromMarkdown(description)
+}
+
 func (i *Image) codeBlock() bool {
 	return i.url == "" && i.fromMarkdown
 }
@@ -378,6 +550,11 @@ func (i *Image) toInternal() *internalImage {
 		FromMarkdown: i.fromMarkdown,
 		ModTime:      i.modTime,
 		Link:         i.link,
+		Alt:          i.alt,
+		Title:        i.title,
+		Placeholder:  i.placeholder,
+		Credit:       i.credit,
+		ZOrder:       i.zOrder,
 	}
 }
 
@@ -386,6 +563,11 @@ func (iimg *internalImage) toImage(i *Image) error {
 	i.fromMarkdown = iimg.FromMarkdown
 	i.modTime = iimg.ModTime
 	i.link = iimg.Link
+	i.alt = iimg.Alt
+	i.title = iimg.Title
+	i.placeholder = iimg.Placeholder
+	i.credit = iimg.Credit
+	i.zOrder = iimg.ZOrder
 
 	data := []byte(iimg.Data)
 	if !bytes.HasPrefix(data, []byte(`data:`)) {
@@ -411,6 +593,27 @@ func (iimg *internalImage) toImage(i *Image) error {
 	return nil
 }
 
+var driveFileURLRegs = []*regexp.Regexp{
+	regexp.MustCompile(`^https://drive\.google\.com/file/d/([-\w]+)`),
+	regexp.MustCompile(`^https://drive\.google\.com/(?:open|uc)\?(?:.*&)?id=([-\w]+)`),
+}
+
+// driveFileIDFromURL returns the file ID if rawURL is a link to a file on Google Drive
+// such as https://drive.google.com/file/d/FILE_ID/view.
+func driveFileIDFromURL(rawURL string) (string, bool) {
+	for _, re := range driveFileURLRegs {
+		if m := re.FindStringSubmatch(rawURL); m != nil {
+			return m[1], true
+		}
+	}
+	return "", false
+}
+
+// needsDriveLoad reports whether the image is a file on Google Drive that has not been loaded yet.
+func (i *Image) needsDriveLoad() bool {
+	return i.driveFileID != "" && i.b == nil
+}
+
 // isPublicURL checks whether a URL string is OK for direct public access.
 // Since we only need to identify what appear to be public URLs, false negatives are acceptable.
 func isPublicURL(rawURL string) bool {
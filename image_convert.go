@@ -0,0 +1,253 @@
+package deck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/errors"
+)
+
+// Google Slides accepts only PNG, JPEG and GIF images, and ignores the EXIF orientation of JPEG images.
+// Images are normalized before upload: photos taken with phones are rotated as they are displayed on the
+// phones, and images in other formats are converted to PNG.
+
+// convertedImageQuality is the quality of JPEG images re-encoded to apply the EXIF orientation.
+const convertedImageQuality = 95
+
+// heifConverters are the commands tried in order to convert HEIC/HEIF images, since there is no decoder for them in Go.
+// {in} and {out} are replaced with the paths of the HEIC image and the PNG image to write.
+var heifConverters = [][]string{
+	{"heif-convert", "{in}", "{out}"},
+	{"magick", "{in}", "{out}"},
+	{"sips", "-s", "format", "png", "{in}", "--out", "{out}"},
+}
+
+// heifBrands are the major brands of the ISO base media file format used by HEIC/HEIF images.
+var heifBrands = []string{"heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1"}
+
+// normalizeImage returns b as an image Google Slides can display as it is meant to be.
+// JPEG and TIFF images with an EXIF orientation are rotated, and images other than PNG, JPEG and GIF are
+// converted to PNG. converted reports whether b was re-encoded.
+func normalizeImage(b []byte) (_ []byte, _ MIMEType, converted bool, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if isHEIF(b) {
+		b, err = convertHEIF(b)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return b, MIMETypeImagePNG, true, nil
+	}
+	_, format, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to decode image: %w", err)
+	}
+	switch format {
+	case "png":
+		return b, MIMETypeImagePNG, false, nil
+	case "gif":
+		return b, MIMETypeImageGIF, false, nil
+	case "jpeg":
+		orientation := exifOrientation(b)
+		if orientation <= 1 {
+			return b, MIMETypeImageJPEG, false, nil
+		}
+		img, _, err := image.Decode(bytes.NewReader(b))
+		if err != nil {
+			return nil, "", false, fmt.Errorf("failed to decode image: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, orient(img, orientation), &jpeg.Options{Quality: convertedImageQuality}); err != nil {
+			return nil, "", false, fmt.Errorf("failed to encode image as JPEG: %w", err)
+		}
+		return buf.Bytes(), MIMETypeImageJPEG, true, nil
+	case "tiff", "bmp", "webp":
+		img, _, err := image.Decode(bytes.NewReader(b))
+		if err != nil {
+			return nil, "", false, fmt.Errorf("failed to decode %s image: %w", format, err)
+		}
+		if format == "tiff" {
+			img = orient(img, tiffOrientation(b))
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", false, fmt.Errorf("failed to encode image as PNG: %w", err)
+		}
+		return buf.Bytes(), MIMETypeImagePNG, true, nil
+	default:
+		return nil, "", false, fmt.Errorf("unsupported image MIME type: %s", format)
+	}
+}
+
+// isHEIF reports whether b is a HEIC/HEIF image.
+func isHEIF(b []byte) bool {
+	if len(b) < 12 || string(b[4:8]) != "ftyp" {
+		return false
+	}
+	size := min(int(binary.BigEndian.Uint32(b[0:4])), len(b))
+	// The major brand is followed by the minor version and the compatible brands.
+	for off := 8; off+4 <= size; off += 4 {
+		if off == 12 {
+			continue
+		}
+		for _, brand := range heifBrands {
+			if string(b[off:off+4]) == brand {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertHEIF converts the HEIC/HEIF image b to PNG with the first command of heifConverters found.
+func convertHEIF(b []byte) (_ []byte, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	var names []string
+	for _, c := range heifConverters {
+		names = append(names, c[0])
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		dir, err := os.MkdirTemp("", "deck-heif-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		in := filepath.Join(dir, "image.heic")
+		out := filepath.Join(dir, "image.png")
+		if err := os.WriteFile(in, b, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write HEIC image: %w", err)
+		}
+		var args []string
+		for _, a := range c[1:] {
+			args = append(args, strings.NewReplacer("{in}", in, "{out}", out).Replace(a))
+		}
+		var stderr bytes.Buffer
+		cmd := exec.Command(c[0], args...) //nolint:gosec
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("failed to convert HEIC image with %s: %w: %s", c[0], err, strings.TrimSpace(stderr.String()))
+		}
+		converted, err := os.ReadFile(out)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read converted HEIC image: %w", err)
+		}
+		if _, format, err := image.DecodeConfig(bytes.NewReader(converted)); err != nil || format != "png" {
+			return nil, fmt.Errorf("%s did not convert the HEIC image to PNG", c[0])
+		}
+		return converted, nil
+	}
+	return nil, fmt.Errorf("unsupported image MIME type: heic (install one of %s to convert HEIC images, or convert the image to JPEG or PNG)",
+		strings.Join(names, ", "))
+}
+
+// exifOrientation returns the EXIF orientation of the JPEG image b, or 0 if it has none.
+func exifOrientation(b []byte) int {
+	if len(b) < 4 || b[0] != 0xFF || b[1] != 0xD8 {
+		return 0
+	}
+	for off := 2; off+4 <= len(b); {
+		if b[off] != 0xFF {
+			return 0
+		}
+		marker := b[off+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan or end of image
+			return 0
+		}
+		size := int(binary.BigEndian.Uint16(b[off+2 : off+4]))
+		if size < 2 || off+2+size > len(b) {
+			return 0
+		}
+		segment := b[off+4 : off+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		off += 2 + size
+	}
+	return 0
+}
+
+// tiffOrientation returns the orientation tag in the first IFD of the TIFF data b, or 0 if it has none.
+// EXIF data in JPEG images has the same structure.
+func tiffOrientation(b []byte) int {
+	if len(b) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(b[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	if order.Uint16(b[2:4]) != 42 {
+		return 0
+	}
+	ifd := int(order.Uint32(b[4:8]))
+	if ifd < 8 || ifd+2 > len(b) {
+		return 0
+	}
+	n := int(order.Uint16(b[ifd : ifd+2]))
+	for i := range n {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(b) {
+			return 0
+		}
+		// The orientation is a SHORT value stored in the first bytes of the value field.
+		if order.Uint16(b[entry:entry+2]) == 0x0112 && order.Uint16(b[entry+2:entry+4]) == 3 {
+			return int(order.Uint16(b[entry+8 : entry+10]))
+		}
+	}
+	return 0
+}
+
+// orient returns img transformed to be displayed upright according to the EXIF orientation.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		// Orientations from 5 to 8 swap the width and the height.
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range dh {
+		for x := range dw {
+			var sx, sy int
+			switch orientation {
+			case 2: // flipped horizontally
+				sx, sy = w-1-x, y
+			case 3: // rotated 180 degrees
+				sx, sy = w-1-x, h-1-y
+			case 4: // flipped vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90 degrees clockwise to be upright
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90 degrees counterclockwise to be upright
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,198 @@
+package deck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// halfRedImage returns a 16x8 image whose left half is red and right half is blue.
+func halfRedImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for y := range 8 {
+		for x := range 16 {
+			if x < 8 {
+				img.Set(x, y, color.RGBA{255, 0, 0, 255})
+			} else {
+				img.Set(x, y, color.RGBA{0, 0, 255, 255})
+			}
+		}
+	}
+	return img
+}
+
+// withEXIFOrientation returns the JPEG image b with an EXIF segment holding the orientation.
+func withEXIFOrientation(t *testing.T, b []byte, orientation uint16) []byte {
+	t.Helper()
+	var tiffData bytes.Buffer
+	tiffData.WriteString("MM")
+	for _, v := range []any{uint16(42), uint32(8), uint16(1), uint16(0x0112), uint16(3), uint32(1), orientation, uint16(0), uint32(0)} {
+		if err := binary.Write(&tiffData, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	segment := append([]byte("Exif\x00\x00"), tiffData.Bytes()...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2)) //nolint:gosec
+	out := append([]byte{}, b[:2]...)
+	out = append(out, app1...)
+	out = append(out, segment...)
+	return append(out, b[2:]...)
+}
+
+func encodeImage(t *testing.T, format string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, halfRedImage())
+	case "jpeg":
+		err = jpeg.Encode(&buf, halfRedImage(), &jpeg.Options{Quality: 100})
+	case "gif":
+		err = gif.Encode(&buf, halfRedImage(), nil)
+	case "tiff":
+		err = tiff.Encode(&buf, halfRedImage(), nil)
+	case "bmp":
+		err = bmp.Encode(&buf, halfRedImage())
+	case "webp":
+		b, err := os.ReadFile("testdata/test.webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func isRed(c color.Color) bool {
+	r, _, b, _ := c.RGBA()
+	return r > 0xC000 && b < 0x4000
+}
+
+func TestNormalizeImage(t *testing.T) {
+	jpegImage := encodeImage(t, "jpeg")
+	tests := []struct {
+		name          string
+		b             []byte
+		wantMIMEType  MIMEType
+		wantConverted bool
+		wantSize      image.Point
+		wantRed       image.Point // a point expected to be red, if the image is halfRedImage
+	}{
+		{"png as is", encodeImage(t, "png"), MIMETypeImagePNG, false, image.Pt(16, 8), image.Pt(2, 4)},
+		{"gif as is", encodeImage(t, "gif"), MIMETypeImageGIF, false, image.Pt(16, 8), image.Pt(2, 4)},
+		{"jpeg without orientation", jpegImage, MIMETypeImageJPEG, false, image.Pt(16, 8), image.Pt(2, 4)},
+		{"jpeg with normal orientation", withEXIFOrientation(t, jpegImage, 1), MIMETypeImageJPEG, false, image.Pt(16, 8), image.Pt(2, 4)},
+		{"jpeg flipped horizontally", withEXIFOrientation(t, jpegImage, 2), MIMETypeImageJPEG, true, image.Pt(16, 8), image.Pt(13, 4)},
+		{"jpeg rotated 180 degrees", withEXIFOrientation(t, jpegImage, 3), MIMETypeImageJPEG, true, image.Pt(16, 8), image.Pt(13, 4)},
+		{"jpeg rotated 90 degrees clockwise", withEXIFOrientation(t, jpegImage, 6), MIMETypeImageJPEG, true, image.Pt(8, 16), image.Pt(4, 2)},
+		{"jpeg rotated 90 degrees counterclockwise", withEXIFOrientation(t, jpegImage, 8), MIMETypeImageJPEG, true, image.Pt(8, 16), image.Pt(4, 13)},
+		{"tiff to png", encodeImage(t, "tiff"), MIMETypeImagePNG, true, image.Pt(16, 8), image.Pt(2, 4)},
+		{"bmp to png", encodeImage(t, "bmp"), MIMETypeImagePNG, true, image.Pt(16, 8), image.Pt(2, 4)},
+		{"webp to png", encodeImage(t, "webp"), MIMETypeImagePNG, true, image.Pt(0, 0), image.Pt(-1, -1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, mimeType, converted, err := normalizeImage(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mimeType != tt.wantMIMEType {
+				t.Errorf("got MIME type %s, want %s", mimeType, tt.wantMIMEType)
+			}
+			if converted != tt.wantConverted {
+				t.Errorf("got converted %v, want %v", converted, tt.wantConverted)
+			}
+			if !converted && !bytes.Equal(b, tt.b) {
+				t.Error("image data changed without conversion")
+			}
+			img, format, err := image.Decode(bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if "image/"+format != string(mimeType) {
+				t.Errorf("got format %s, want %s", format, mimeType)
+			}
+			if tt.wantSize != (image.Point{}) && img.Bounds().Size() != tt.wantSize {
+				t.Errorf("got size %v, want %v", img.Bounds().Size(), tt.wantSize)
+			}
+			if tt.wantRed.X >= 0 && !isRed(img.At(tt.wantRed.X, tt.wantRed.Y)) {
+				t.Errorf("got %v at %v, want red", img.At(tt.wantRed.X, tt.wantRed.Y), tt.wantRed)
+			}
+		})
+	}
+}
+
+func TestNormalizeImageHEIF(t *testing.T) {
+	heic := append([]byte{0, 0, 0, 24}, []byte("ftypheic\x00\x00\x00\x00mif1heic")...)
+	converted := encodeImage(t, "png")
+	dir := t.TempDir()
+	pngPath := filepath.Join(dir, "converted.png")
+	if err := os.WriteFile(pngPath, converted, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(dir, "fake-heif-convert")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncp "+pngPath+" \"$2\"\n"), 0o700); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		converters [][]string
+		wantErr    string
+	}{
+		{"converted with the command", [][]string{{filepath.Join(dir, "not-found"), "{in}", "{out}"}, {script, "{in}", "{out}"}}, ""},
+		{"no command", [][]string{{filepath.Join(dir, "not-found"), "{in}", "{out}"}}, "unsupported image MIME type: heic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := heifConverters
+			heifConverters = tt.converters
+			t.Cleanup(func() { heifConverters = orig })
+			b, mimeType, ok, err := normalizeImage(heic)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mimeType != MIMETypeImagePNG || !ok || !bytes.Equal(b, converted) {
+				t.Errorf("got %s (converted %v), want the converted PNG", mimeType, ok)
+			}
+		})
+	}
+}
+
+func TestNewImageConverted(t *testing.T) {
+	i, err := NewImageFromBytes(withEXIFOrientation(t, encodeImage(t, "jpeg"), 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !i.converted {
+		t.Error("want the image to be converted")
+	}
+	img, err := i.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(8, 16) {
+		t.Errorf("got size %v, want 8x16", got)
+	}
+}
@@ -0,0 +1,161 @@
+package deck
+
+import (
+	"fmt"
+	"math"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// Images not placed in picture placeholders are tiled in a grid within the area of the body placeholders
+// when a slide has two or more of them, or when the grid is given by ImageColumns or ImageRows.
+// Each image is scaled to fit its cell keeping the aspect ratio, and centered in the cell.
+
+const (
+	imageGridGap         = 152400 // gap between cells in EMU (12pt)
+	imageGridPageMargin  = 0.05   // margin of the page used as the area without body placeholders, as a ratio of the page size
+	imageGridMaxOneRow   = 3      // images up to this number are placed side by side in a row by default
+	emuPerPoint          = 12700
+	defaultPageWidthEMU  = 9144000 // width of the default 16:9 page
+	defaultPageHeightEMU = 5143500 // height of the default 16:9 page
+)
+
+// rect is a rectangle in EMU.
+type rect struct {
+	x, y, width, height float64
+}
+
+// ValidateImageGrid returns an error if the number of columns or rows of the image grid is invalid.
+func ValidateImageGrid(columns, rows int) error {
+	if columns < 0 || rows < 0 {
+		return fmt.Errorf("invalid image grid: columns and rows must not be negative (columns: %d, rows: %d)", columns, rows)
+	}
+	return nil
+}
+
+// imageGridSize returns the number of columns and rows of the grid of n images.
+// If only one of columns and rows is given, the other is derived from n.
+func imageGridSize(n, columns, rows int) (int, int) {
+	switch {
+	case columns > 0 && rows > 0:
+		return columns, rows
+	case columns > 0:
+		return columns, (n + columns - 1) / columns
+	case rows > 0:
+		return (n + rows - 1) / rows, rows
+	case n <= imageGridMaxOneRow:
+		return n, 1
+	default:
+		columns = int(math.Ceil(math.Sqrt(float64(n))))
+		return columns, (n + columns - 1) / columns
+	}
+}
+
+// imageGridCells returns the cells of the grid of n images in area, in row-major order.
+// Images beyond the cells of the grid are placed in the last cell.
+func imageGridCells(area rect, n, columns, rows int) []rect {
+	if n == 0 {
+		return nil
+	}
+	columns, rows = imageGridSize(n, columns, rows)
+	width := (area.width - imageGridGap*float64(columns-1)) / float64(columns)
+	height := (area.height - imageGridGap*float64(rows-1)) / float64(rows)
+	cells := make([]rect, n)
+	for i := range cells {
+		k := min(i, columns*rows-1)
+		cells[i] = rect{
+			x:      area.x + float64(k%columns)*(width+imageGridGap),
+			y:      area.y + float64(k/columns)*(height+imageGridGap),
+			width:  width,
+			height: height,
+		}
+	}
+	return cells
+}
+
+// imageGridArea returns the bounding box of the body placeholders, or the page with margins
+// if there are no body placeholders with a known size.
+func (d *Deck) imageGridArea(bodies []placeholder) rect {
+	var (
+		area  rect
+		found bool
+	)
+	for _, b := range bodies {
+		if b.width <= 0 || b.height <= 0 {
+			continue
+		}
+		r := rect{x: b.x, y: b.y, width: b.width, height: b.height}
+		if !found {
+			area, found = r, true
+			continue
+		}
+		right := math.Max(area.x+area.width, r.x+r.width)
+		bottom := math.Max(area.y+area.height, r.y+r.height)
+		area.x, area.y = math.Min(area.x, r.x), math.Min(area.y, r.y)
+		area.width, area.height = right-area.x, bottom-area.y
+	}
+	if found {
+		return area
+	}
+	width, height := float64(defaultPageWidthEMU), float64(defaultPageHeightEMU)
+	if d.presentation != nil && d.presentation.PageSize != nil {
+		if w := toEMU(d.presentation.PageSize.Width); w > 0 {
+			width = w
+		}
+		if h := toEMU(d.presentation.PageSize.Height); h > 0 {
+			height = h
+		}
+	}
+	return rect{
+		x:      width * imageGridPageMargin,
+		y:      height * imageGridPageMargin,
+		width:  width * (1 - 2*imageGridPageMargin),
+		height: height * (1 - 2*imageGridPageMargin),
+	}
+}
+
+// tiledImageCells returns the cells of the images not placed in picture placeholders, keyed by the index
+// of the image, or nil if the images are not tiled.
+func (d *Deck) tiledImageCells(slide *Slide, placeholderIndices []int, bodies []placeholder) map[int]rect {
+	var free []int
+	for i, k := range placeholderIndices {
+		if k < 0 {
+			free = append(free, i)
+		}
+	}
+	if len(free) == 0 || len(free) < 2 && slide.ImageColumns == 0 && slide.ImageRows == 0 {
+		return nil
+	}
+	cells := imageGridCells(d.imageGridArea(bodies), len(free), slide.ImageColumns, slide.ImageRows)
+	m := make(map[int]rect, len(free))
+	for j, i := range free {
+		m[i] = cells[j]
+	}
+	return m
+}
+
+// elementSize returns the size of the page element in EMU, or zeros if it is unknown.
+func elementSize(e *slides.PageElement) (float64, float64) {
+	if e.Size == nil || e.Transform == nil {
+		return 0, 0
+	}
+	scaleX, scaleY := e.Transform.ScaleX, e.Transform.ScaleY
+	if scaleX == 0 {
+		scaleX = 1
+	}
+	if scaleY == 0 {
+		scaleY = 1
+	}
+	return toEMU(e.Size.Width) * scaleX, toEMU(e.Size.Height) * scaleY
+}
+
+// toEMU returns the dimension in EMU.
+func toEMU(d *slides.Dimension) float64 {
+	if d == nil {
+		return 0
+	}
+	if d.Unit == "PT" {
+		return d.Magnitude * emuPerPoint
+	}
+	return d.Magnitude
+}
@@ -0,0 +1,111 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestImageGridSize(t *testing.T) {
+	tests := []struct {
+		n, columns, rows      int
+		wantColumns, wantRows int
+	}{
+		{1, 0, 0, 1, 1},
+		{3, 0, 0, 3, 1},
+		{4, 0, 0, 2, 2},
+		{5, 0, 0, 3, 2},
+		{3, 1, 0, 1, 3},
+		{5, 0, 2, 3, 2},
+		{2, 3, 2, 3, 2},
+	}
+	for _, tt := range tests {
+		columns, rows := imageGridSize(tt.n, tt.columns, tt.rows)
+		if columns != tt.wantColumns || rows != tt.wantRows {
+			t.Errorf("imageGridSize(%d, %d, %d) = (%d, %d), want (%d, %d)", tt.n, tt.columns, tt.rows, columns, rows, tt.wantColumns, tt.wantRows)
+		}
+	}
+}
+
+func TestImageGridCells(t *testing.T) {
+	area := rect{x: 100, y: 200, width: 2*1000000 + imageGridGap, height: 500000}
+	got := imageGridCells(area, 2, 0, 0)
+	want := []rect{
+		{x: 100, y: 200, width: 1000000, height: 500000},
+		{x: 100 + 1000000 + imageGridGap, y: 200, width: 1000000, height: 500000},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(rect{})); diff != "" {
+		t.Error(diff)
+	}
+
+	// Images beyond the grid are placed in the last cell.
+	got = imageGridCells(area, 3, 2, 1)
+	if diff := cmp.Diff(got[1], got[2], cmp.AllowUnexported(rect{})); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestTiledImageCells(t *testing.T) {
+	d := &Deck{presentation: &slides.Presentation{PageSize: &slides.Size{
+		Width:  &slides.Dimension{Magnitude: 1000000, Unit: "EMU"},
+		Height: &slides.Dimension{Magnitude: 500000, Unit: "EMU"},
+	}}}
+	body := placeholder{x: 10, y: 20, width: 1000, height: 500}
+	tests := []struct {
+		name    string
+		slide   *Slide
+		indices []int
+		bodies  []placeholder
+		want    map[int]rect
+	}{
+		{"single image is not tiled", &Slide{}, []int{-1}, []placeholder{body}, nil},
+		{"images in placeholders are not tiled", &Slide{}, []int{0, 1}, []placeholder{body}, nil},
+		{
+			"single image with the grid",
+			&Slide{ImageColumns: 1},
+			[]int{-1},
+			[]placeholder{body},
+			map[int]rect{0: {x: 10, y: 20, width: 1000, height: 500}},
+		},
+		{
+			"images not in placeholders",
+			&Slide{ImageRows: 2},
+			[]int{0, -1, -1},
+			[]placeholder{{x: 0, y: 0, width: 1000, height: 2*100 + imageGridGap}},
+			map[int]rect{
+				1: {x: 0, y: 0, width: 1000, height: 100},
+				2: {x: 0, y: 100 + imageGridGap, width: 1000, height: 100},
+			},
+		},
+		{
+			"page area without body placeholders",
+			&Slide{ImageColumns: 1},
+			[]int{-1},
+			nil,
+			map[int]rect{0: {x: 50000, y: 25000, width: 900000, height: 450000}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.tiledImageCells(tt.slide, tt.indices, tt.bodies)
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(rect{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestElementSize(t *testing.T) {
+	e := &slides.PageElement{
+		Size:      &slides.Size{Width: &slides.Dimension{Magnitude: 100, Unit: "PT"}, Height: &slides.Dimension{Magnitude: 1000, Unit: "EMU"}},
+		Transform: &slides.AffineTransform{ScaleX: 2, ScaleY: 0.5},
+	}
+	w, h := elementSize(e)
+	if w != 100*emuPerPoint*2 || h != 500 {
+		t.Errorf("got (%v, %v)", w, h)
+	}
+	if w, h := elementSize(&slides.PageElement{}); w != 0 || h != 0 {
+		t.Errorf("got (%v, %v), want zeros", w, h)
+	}
+}
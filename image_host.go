@@ -0,0 +1,198 @@
+package deck
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ImageHost hosts the images to insert into the presentation at URLs that Google Slides can fetch.
+// By default, images are uploaded to Google Drive and shared with anyone with the link while they are inserted.
+type ImageHost interface {
+	// Upload uploads the image of size bytes read from r as name, and returns the URL to fetch it.
+	Upload(ctx context.Context, name string, mimeType MIMEType, size int64, r io.Reader) (url string, err error)
+	// Delete deletes the image uploaded as name.
+	Delete(ctx context.Context, name string) error
+}
+
+// WithImageHost uploads images to the image host instead of Google Drive.
+func WithImageHost(h ImageHost) Option {
+	return func(d *Deck) error {
+		d.imageHost = h
+		return nil
+	}
+}
+
+// hostedImageName returns the name of the image uploaded to the image host at t.
+// The name includes the digest of the image, since images uploaded at the same time must have different names.
+func (d *Deck) hostedImageName(image *Image, t time.Time) string {
+	prefix := d.imageNamePrefix
+	if prefix == "" {
+		prefix = defaultUploadedImageNamePrefix
+	}
+	sum := sha256.Sum256(image.Bytes())
+	ext := ".png"
+	switch image.mimeType {
+	case MIMETypeImageJPEG:
+		ext = ".jpg"
+	case MIMETypeImageGIF:
+		ext = ".gif"
+	}
+	return prefix + t.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(sum[:8]) + ext
+}
+
+// deleteUploadedImage deletes the uploaded image of the ID, which is the name of the image if the image host is set.
+func (d *Deck) deleteUploadedImage(ctx context.Context, id string) error {
+	if d.imageHost != nil {
+		return d.imageHost.Delete(ctx, id)
+	}
+	return d.deleteOrTrashFile(ctx, id)
+}
+
+const defaultSignedURLExpires = 15 * time.Minute
+
+// presignedURLHost is an image host of an object storage, which uploads, fetches and deletes images with presigned URLs.
+type presignedURLHost struct {
+	client  *http.Client
+	prefix  string // prefix of the names of the objects
+	expires time.Duration
+	// presign returns the URL of the object signed for the method and the headers.
+	presign func(method, object string, header http.Header, expires time.Duration, t time.Time) (string, error)
+}
+
+func (h *presignedURLHost) Upload(ctx context.Context, name string, mimeType MIMEType, size int64, r io.Reader) (string, error) {
+	header := http.Header{}
+	header.Set("Content-Type", string(mimeType))
+	if err := h.do(ctx, http.MethodPut, name, header, size, r); err != nil {
+		return "", fmt.Errorf("failed to upload image: %w", err)
+	}
+	return h.presign(http.MethodGet, h.prefix+name, nil, h.expires, time.Now())
+}
+
+func (h *presignedURLHost) Delete(ctx context.Context, name string) error {
+	if err := h.do(ctx, http.MethodDelete, name, nil, 0, nil); err != nil {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	return nil
+}
+
+func (h *presignedURLHost) do(ctx context.Context, method, name string, header http.Header, size int64, body io.Reader) error {
+	u, err := h.presign(method, h.prefix+name, header, h.expires, time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	client := h.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 && !(method == http.MethodDelete && res.StatusCode == http.StatusNotFound) {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, name, res.Status, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+// commandImageHost is an image host that writes images into a directory and runs a command to upload them.
+type commandImageHost struct {
+	baseURL       string
+	command       string
+	deleteCommand string
+	dir           string
+	mu            sync.Mutex // commands are run one by one, since they may upload the whole directory
+}
+
+// NewCommandImageHost returns the image host that writes images into a local directory and runs command to upload them,
+// e.g. `rsync -a "$DECK_IMAGE_DIR/" host:/var/www/images/`. The uploaded images are fetched from baseURL followed by
+// their names. deleteCommand, if not empty, is run after an image is deleted from the directory.
+// The commands are run by the shell with the environment variables DECK_IMAGE_DIR (the directory), DECK_IMAGE_PATH
+// (the path of the image) and DECK_IMAGE_NAME (the name of the image).
+func NewCommandImageHost(baseURL, command, deleteCommand string) (ImageHost, error) {
+	if baseURL == "" || command == "" {
+		return nil, fmt.Errorf("base URL and command are required for the image host")
+	}
+	dir := filepath.Join(os.TempDir(), "deck-images")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the directory of images: %w", err)
+	}
+	return &commandImageHost{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		command:       command,
+		deleteCommand: deleteCommand,
+		dir:           dir,
+	}, nil
+}
+
+func (h *commandImageHost) Upload(ctx context.Context, name string, _ MIMEType, _ int64, r io.Reader) (string, error) {
+	path := filepath.Join(h.dir, name)
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write image: %w", err)
+	}
+	if err := h.run(ctx, h.command, name); err != nil {
+		return "", fmt.Errorf("failed to upload image: %w", err)
+	}
+	return h.baseURL + "/" + url.PathEscape(name), nil
+}
+
+func (h *commandImageHost) Delete(ctx context.Context, name string) error {
+	if err := os.Remove(filepath.Join(h.dir, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	if h.deleteCommand == "" {
+		return nil
+	}
+	if err := h.run(ctx, h.deleteCommand, name); err != nil {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	return nil
+}
+
+func (h *commandImageHost) run(ctx context.Context, command, name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/c", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(),
+		"DECK_IMAGE_DIR="+h.dir,
+		"DECK_IMAGE_PATH="+filepath.Join(h.dir, name),
+		"DECK_IMAGE_NAME="+name,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package deck
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+)
+
+// GCSImageHostConfig is the configuration of the image host of Google Cloud Storage.
+type GCSImageHostConfig struct {
+	Bucket string
+	Prefix string // prefix of the names of the objects (e.g. "deck/")
+	// JSON key of the service account to sign URLs with. The service account needs to be allowed to create,
+	// read and delete objects in the bucket.
+	CredentialsJSON []byte
+	Expires         time.Duration // expiration of the signed URLs (default: 15m)
+}
+
+// NewGCSImageHost returns the image host uploading images to a bucket of Google Cloud Storage with V4 signed URLs.
+// The bucket does not need to be public, and the Drive scope is not needed to upload images.
+func NewGCSImageHost(c GCSImageHostConfig) (ImageHost, error) {
+	if c.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required for the image host of Google Cloud Storage")
+	}
+	jwt, err := google.JWTConfigFromJSON(c.CredentialsJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the key of the service account: %w", err)
+	}
+	key, err := parseRSAPrivateKey(jwt.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the key of the service account: %w", err)
+	}
+	s := &v4Signer{
+		algorithm:   "GOOG4-RSA-SHA256",
+		paramPrefix: "X-Goog-",
+		accessKey:   jwt.Email,
+		scope: func(date string) string {
+			return date + "/auto/storage/goog4_request"
+		},
+		sign: func(stringToSign, _ string) (string, error) {
+			digest := sha256.Sum256([]byte(stringToSign))
+			sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+			if err != nil {
+				return "", err
+			}
+			return hex.EncodeToString(sig), nil
+		},
+	}
+	return &presignedURLHost{
+		prefix:  c.Prefix,
+		expires: expiresOrDefault(c.Expires),
+		presign: func(method, object string, header http.Header, expires time.Duration, t time.Time) (string, error) {
+			u := &url.URL{Scheme: "https", Host: "storage.googleapis.com", Path: "/" + c.Bucket + "/" + object}
+			return s.presign(method, u, header, expires, t)
+		},
+	}, nil
+}
+
+// S3ImageHostConfig is the configuration of the image host of Amazon S3 or S3 compatible storages.
+type S3ImageHostConfig struct {
+	Bucket string
+	Prefix string // prefix of the keys of the objects (e.g. "deck/")
+	Region string
+	// Endpoint of an S3 compatible storage (e.g. "https://minio.example.com"). Objects are addressed in the path style.
+	// If empty, the virtual-hosted style endpoint of Amazon S3 in the region is used.
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string        // session token of temporary credentials
+	Expires         time.Duration // expiration of the presigned URLs (default: 15m)
+}
+
+// NewS3ImageHost returns the image host uploading images to a bucket of Amazon S3 with presigned URLs.
+func NewS3ImageHost(c S3ImageHostConfig) (ImageHost, error) {
+	if c.Bucket == "" || c.Region == "" {
+		return nil, fmt.Errorf("bucket and region are required for the image host of S3")
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return nil, fmt.Errorf("access key ID and secret access key are required for the image host of S3")
+	}
+	var endpoint *url.URL
+	if c.Endpoint != "" {
+		var err error
+		endpoint, err = url.Parse(c.Endpoint)
+		if err != nil || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint of S3: %s", c.Endpoint)
+		}
+	}
+	s := newS3Signer(c.Region, c.AccessKeyID, c.SecretAccessKey, c.SessionToken)
+	return &presignedURLHost{
+		prefix:  c.Prefix,
+		expires: expiresOrDefault(c.Expires),
+		presign: func(method, object string, header http.Header, expires time.Duration, t time.Time) (string, error) {
+			u := &url.URL{Scheme: "https", Host: c.Bucket + ".s3." + c.Region + ".amazonaws.com", Path: "/" + object}
+			if endpoint != nil {
+				u = &url.URL{Scheme: endpoint.Scheme, Host: endpoint.Host, Path: strings.TrimSuffix(endpoint.Path, "/") + "/" + c.Bucket + "/" + object}
+			}
+			return s.presign(method, u, header, expires, t)
+		},
+	}, nil
+}
+
+func newS3Signer(region, accessKeyID, secretAccessKey, sessionToken string) *v4Signer {
+	s := &v4Signer{
+		algorithm:   "AWS4-HMAC-SHA256",
+		paramPrefix: "X-Amz-",
+		accessKey:   accessKeyID,
+		scope: func(date string) string {
+			return date + "/" + region + "/s3/aws4_request"
+		},
+		sign: func(stringToSign, date string) (string, error) {
+			key := []byte("AWS4" + secretAccessKey)
+			for _, v := range []string{date, region, "s3", "aws4_request", stringToSign} {
+				mac := hmac.New(sha256.New, key)
+				mac.Write([]byte(v))
+				key = mac.Sum(nil)
+			}
+			return hex.EncodeToString(key), nil
+		},
+	}
+	if sessionToken != "" {
+		s.params = url.Values{"X-Amz-Security-Token": {sessionToken}}
+	}
+	return s
+}
+
+func expiresOrDefault(expires time.Duration) time.Duration {
+	if expires <= 0 {
+		return defaultSignedURLExpires
+	}
+	return expires
+}
+
+func parseRSAPrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	if block, _ := pem.Decode(b); block != nil {
+		b = block.Bytes
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(b); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("not an RSA private key")
+		}
+		return rsaKey, nil
+	}
+	return x509.ParsePKCS1PrivateKey(b)
+}
+
+// v4Signer signs URLs with the query string in the way of AWS Signature Version 4, which Google Cloud Storage
+// also supports as V4 signing.
+type v4Signer struct {
+	algorithm   string // e.g. "AWS4-HMAC-SHA256"
+	paramPrefix string // prefix of the query parameters, e.g. "X-Amz-"
+	accessKey   string
+	params      url.Values // additional query parameters to sign
+	scope       func(date string) string
+	sign        func(stringToSign, date string) (string, error)
+}
+
+// presign returns the URL u signed for the method and the headers, valid for expires from t.
+func (s *v4Signer) presign(method string, u *url.URL, header http.Header, expires time.Duration, t time.Time) (string, error) {
+	t = t.UTC()
+	date := t.Format("20060102")
+	datetime := t.Format("20060102T150405Z")
+	scope := s.scope(date)
+
+	headers := map[string]string{"host": u.Host}
+	for k, v := range header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	q := url.Values{}
+	for k, v := range s.params {
+		q[k] = v
+	}
+	q.Set(s.paramPrefix+"Algorithm", s.algorithm)
+	q.Set(s.paramPrefix+"Credential", s.accessKey+"/"+scope)
+	q.Set(s.paramPrefix+"Date", datetime)
+	q.Set(s.paramPrefix+"Expires", strconv.Itoa(int(expires.Seconds())))
+	q.Set(s.paramPrefix+"SignedHeaders", signedHeaders)
+	query := canonicalQuery(q)
+
+	path := uriEncode(u.Path, false)
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		query,
+		canonicalHeaders.String(),
+		signedHeaders,
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s.algorithm, datetime, scope, hex.EncodeToString(digest[:])}, "\n")
+	sig, err := s.sign(stringToSign, date)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign URL: %w", err)
+	}
+	return u.Scheme + "://" + u.Host + path + "?" + query + "&" + s.paramPrefix + "Signature=" + sig, nil
+}
+
+// canonicalQuery returns the query string of q sorted by the keys and encoded for signing.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var params []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			params = append(params, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// uriEncode encodes s for signing, leaving only the unreserved characters (and slashes if encodeSlash is false) as they are.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
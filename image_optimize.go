@@ -0,0 +1,157 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/k1LoW/errors"
+	"github.com/nfnt/resize"
+)
+
+const defaultImageQuality = 85
+
+// ImageOptimization represents the optimization of images before upload.
+// Optimized images are re-encoded, so metadata such as EXIF is stripped.
+// GIF images are not optimized to keep animations.
+// WebP is not supported as a format to re-encode to, since Google Slides does not accept WebP images.
+// WebP images are converted to PNG when they are loaded, so they are optimized as PNG images.
+type ImageOptimization struct {
+	MaxWidth  int    // maximum width in pixels, larger images are scaled down keeping the aspect ratio (0: unlimited)
+	MaxHeight int    // maximum height in pixels (0: unlimited)
+	Format    string // format to re-encode to: "jpeg" or "png" (default: keep the format)
+	Quality   int    // quality of JPEG encoding from 1 to 100 (default: 85)
+}
+
+// Validate returns an error if the optimization settings are invalid.
+func (o *ImageOptimization) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.MaxWidth < 0 || o.MaxHeight < 0 {
+		return fmt.Errorf("invalid maximum dimensions: %dx%d", o.MaxWidth, o.MaxHeight)
+	}
+	switch o.Format {
+	case "", "jpeg", "png":
+	default:
+		return fmt.Errorf("unsupported image format: %q (must be \"jpeg\" or \"png\", as Google Slides does not accept WebP)", o.Format)
+	}
+	if o.Quality < 0 || o.Quality > 100 {
+		return fmt.Errorf("invalid quality: %d (must be between 1 and 100)", o.Quality)
+	}
+	return nil
+}
+
+// WithImageOptimization optimizes images managed by deck before upload.
+// Images with their own optimization set by Image.SetOptimization use it instead.
+func WithImageOptimization(o *ImageOptimization) Option {
+	return func(d *Deck) error {
+		if err := o.Validate(); err != nil {
+			return err
+		}
+		d.imageOptimization = o
+		return nil
+	}
+}
+
+// SetOptimization sets the optimization of the image before upload, overriding WithImageOptimization.
+// Set an empty ImageOptimization to upload the image as is.
+func (i *Image) SetOptimization(o *ImageOptimization) {
+	i.optimization = o
+}
+
+// optimizeImages optimizes the images of the slides to be uploaded.
+// Since the optimization is deterministic, optimized images are compared with the images in the presentation
+// that were uploaded after the same optimization.
+func (d *Deck) optimizeImages(ss Slides) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	for _, s := range ss {
+		if s == nil {
+			continue
+		}
+		for _, i := range s.Images {
+			o := d.imageOptimization
+			if i.optimization != nil {
+				o = i.optimization
+			}
+			if err := i.optimize(o); err != nil {
+				return fmt.Errorf("failed to optimize image %s: %w", i.url, err)
+			}
+		}
+	}
+	return nil
+}
+
+// optimize re-encodes the image with o. Images not managed by deck, GIF images and images already
+// optimized are left as they are.
+func (i *Image) optimize(o *ImageOptimization) error {
+	if o == nil || *o == (ImageOptimization{}) || !i.fromMarkdown || i.optimized || len(i.b) == 0 ||
+		i.mimeType == MIMETypeImageGIF {
+		return nil
+	}
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	src, err := i.Image()
+	if err != nil {
+		return err
+	}
+	resized := src
+	if o.MaxWidth > 0 || o.MaxHeight > 0 {
+		bounds := src.Bounds()
+		maxWidth, maxHeight := uint(bounds.Dx()), uint(bounds.Dy()) //nolint:gosec
+		if o.MaxWidth > 0 {
+			maxWidth = min(maxWidth, uint(o.MaxWidth)) //nolint:gosec
+		}
+		if o.MaxHeight > 0 {
+			maxHeight = min(maxHeight, uint(o.MaxHeight)) //nolint:gosec
+		}
+		if maxWidth != uint(bounds.Dx()) || maxHeight != uint(bounds.Dy()) { //nolint:gosec
+			resized = resize.Thumbnail(maxWidth, maxHeight, src, resize.Lanczos3)
+		}
+	}
+	mimeType := i.mimeType
+	switch o.Format {
+	case "jpeg":
+		mimeType = MIMETypeImageJPEG
+	case "png":
+		mimeType = MIMETypeImagePNG
+	}
+	var buf bytes.Buffer
+	switch mimeType {
+	case MIMETypeImageJPEG:
+		quality := o.Quality
+		if quality == 0 {
+			quality = defaultImageQuality
+		}
+		if err := jpeg.Encode(&buf, flatten(resized), &jpeg.Options{Quality: quality}); err != nil {
+			return fmt.Errorf("failed to encode image as JPEG: %w", err)
+		}
+	default:
+		enc := &png.Encoder{CompressionLevel: png.BestCompression}
+		if err := enc.Encode(&buf, resized); err != nil {
+			return fmt.Errorf("failed to encode image as PNG: %w", err)
+		}
+	}
+	i.optimized = true
+	i.b = buf.Bytes()
+	i.mimeType = mimeType
+	i.i = nil
+	i.checksum = 0
+	i.pHash = nil
+	return nil
+}
+
+// flatten draws img on a white background, since JPEG has no transparency.
+func flatten(img image.Image) image.Image {
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
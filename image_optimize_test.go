@@ -0,0 +1,123 @@
+package deck
+
+import (
+	"os"
+	"testing"
+)
+
+func TestImageOptimizeWebP(t *testing.T) {
+	b, err := os.ReadFile("testdata/test.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		o            *ImageOptimization
+		wantMIMEType MIMEType
+	}{
+		{"no optimization", nil, MIMETypeImagePNG},
+		{"resize", &ImageOptimization{MaxWidth: 10}, MIMETypeImagePNG},
+		{"re-encode as JPEG", &ImageOptimization{Format: "jpeg"}, MIMETypeImageJPEG},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, err := NewImageFromBytes(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			i.fromMarkdown = true
+			if err := i.optimize(tt.o); err != nil {
+				t.Fatal(err)
+			}
+			if i.mimeType != tt.wantMIMEType {
+				t.Errorf("got MIME type %s, want %s", i.mimeType, tt.wantMIMEType)
+			}
+			img, err := i.Image()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.o != nil && tt.o.MaxWidth > 0 && img.Bounds().Dx() > tt.o.MaxWidth {
+				t.Errorf("got width %d, want <= %d", img.Bounds().Dx(), tt.o.MaxWidth)
+			}
+		})
+	}
+}
+
+func TestImageOptimize(t *testing.T) {
+	b, err := os.ReadFile("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		o            *ImageOptimization
+		fromMarkdown bool
+		wantMIMEType MIMEType
+		wantMaxW     int
+		wantMaxH     int
+		wantChanged  bool
+	}{
+		{"no optimization", nil, true, MIMETypeImagePNG, 0, 0, false},
+		{"empty optimization", &ImageOptimization{}, true, MIMETypeImagePNG, 0, 0, false},
+		{"not managed by deck", &ImageOptimization{Format: "jpeg"}, false, MIMETypeImagePNG, 0, 0, false},
+		{"resize", &ImageOptimization{MaxWidth: 100}, true, MIMETypeImagePNG, 100, 0, true},
+		{"re-encode as JPEG", &ImageOptimization{Format: "jpeg", Quality: 50}, true, MIMETypeImageJPEG, 0, 0, true},
+		{"resize and re-encode", &ImageOptimization{MaxWidth: 50, MaxHeight: 40, Format: "jpeg"}, true, MIMETypeImageJPEG, 50, 40, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, err := NewImageFromBytes(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			i.fromMarkdown = tt.fromMarkdown
+			before := i.Checksum()
+			if err := i.optimize(tt.o); err != nil {
+				t.Fatal(err)
+			}
+			if i.mimeType != tt.wantMIMEType {
+				t.Errorf("got MIME type %s, want %s", i.mimeType, tt.wantMIMEType)
+			}
+			if changed := i.Checksum() != before; changed != tt.wantChanged {
+				t.Errorf("got changed %v, want %v", changed, tt.wantChanged)
+			}
+			img, err := i.Image()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantMaxW > 0 && img.Bounds().Dx() > tt.wantMaxW {
+				t.Errorf("got width %d, want <= %d", img.Bounds().Dx(), tt.wantMaxW)
+			}
+			if tt.wantMaxH > 0 && img.Bounds().Dy() > tt.wantMaxH {
+				t.Errorf("got height %d, want <= %d", img.Bounds().Dy(), tt.wantMaxH)
+			}
+
+			// The optimization is applied only once.
+			after := i.Checksum()
+			if err := i.optimize(tt.o); err != nil {
+				t.Fatal(err)
+			}
+			if i.Checksum() != after {
+				t.Error("optimized twice")
+			}
+		})
+	}
+}
+
+func TestImageOptimizationValidate(t *testing.T) {
+	tests := []struct {
+		o       *ImageOptimization
+		wantErr bool
+	}{
+		{nil, false},
+		{&ImageOptimization{MaxWidth: 1920, Format: "jpeg", Quality: 80}, false},
+		{&ImageOptimization{MaxWidth: -1}, true},
+		{&ImageOptimization{Format: "webp"}, true},
+		{&ImageOptimization{Quality: 101}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.o.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) = %v, want error %v", tt.o, err, tt.wantErr)
+		}
+	}
+}
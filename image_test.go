@@ -2,10 +2,14 @@ package deck
 
 import (
 	"bytes"
+	"encoding/base64"
 	"image"
 	"image/color"
 	"image/png"
+	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestIsPulicURL(t *testing.T) {
@@ -39,6 +43,38 @@ func TestIsPulicURL(t *testing.T) {
 	}
 }
 
+func TestDriveFileIDFromURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		wantID string
+		wantOK bool
+	}{
+		{"https://drive.google.com/file/d/1AbC-d_E/view?usp=sharing", "1AbC-d_E", true},
+		{"https://drive.google.com/file/d/1AbC-d_E", "1AbC-d_E", true},
+		{"https://drive.google.com/open?id=1AbC-d_E", "1AbC-d_E", true},
+		{"https://drive.google.com/uc?export=download&id=1AbC-d_E", "1AbC-d_E", true},
+		{"https://docs.google.com/presentation/d/1AbC-d_E/edit", "", false},
+		{"https://example.com/file/d/1AbC-d_E/view", "", false},
+		{"testdata/test.png", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			id, ok := driveFileIDFromURL(tt.url)
+			if id != tt.wantID || ok != tt.wantOK {
+				t.Errorf("driveFileIDFromURL(%q) = (%q, %v), want (%q, %v)", tt.url, id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+
+	i, err := NewImageFromMarkdown("https://drive.google.com/file/d/1AbC-d_E/view")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !i.needsDriveLoad() {
+		t.Error("want the image to be loaded from Google Drive")
+	}
+}
+
 func dummyPNG(t *testing.T) *bytes.Buffer {
 	t.Helper()
 	// Create a 1x1 pixel stub PNG image
@@ -61,3 +97,131 @@ func TestNewImageFromCodeBlock(t *testing.T) {
 		t.Errorf("Image.codeBlock() = %v, want true", got)
 	}
 }
+
+func TestNewImageFromBytes(t *testing.T) {
+	buf := dummyPNG(t)
+	i, err := NewImageFromBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("TestNewImageFromBytes failed: %v", err)
+	}
+	if i.mimeType != MIMETypeImagePNG {
+		t.Errorf("Image.mimeType = %v, want %v", i.mimeType, MIMETypeImagePNG)
+	}
+	if !i.fromMarkdown {
+		t.Error("Image.fromMarkdown = false, want true")
+	}
+	if _, err := NewImageFromBytes([]byte("not an image")); err == nil {
+		t.Error("want error for invalid image data")
+	}
+}
+
+func TestNewImageFromMarkdownDataURI(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(dummyPNG(t).Bytes())
+	tests := []struct {
+		name    string
+		dataURI string
+		wantErr bool
+	}{
+		{"base64", "data:image/png;base64," + encoded, false},
+		{"without padding", "data:image/png;base64," + strings.TrimRight(encoded, "="), false},
+		{"wrong media type", "data:image/jpeg;base64," + encoded, false},
+		{"not base64", "data:image/png," + encoded, true},
+		{"not an image", "data:text/plain;base64,aGVsbG8=", true},
+		{"missing comma", "data:image/png;base64", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, err := NewImageFromMarkdown(tt.dataURI)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("want error")
+			}
+			if i.mimeType != MIMETypeImagePNG {
+				t.Errorf("Image.mimeType = %v, want %v", i.mimeType, MIMETypeImagePNG)
+			}
+			if !i.fromMarkdown {
+				t.Error("Image.fromMarkdown = false, want true")
+			}
+		})
+	}
+}
+
+func TestImageAltText(t *testing.T) {
+	tests := []struct {
+		name            string
+		image           *Image
+		wantTitle       string
+		wantDescription string
+		wantNil         bool
+	}{
+		{"markdown image without alt", &Image{fromMarkdown: true}, "", descriptionImageFromMarkdown, false},
+		{"markdown image with alt", &Image{fromMarkdown: true, alt: "diagram of architecture"}, "", "diagram of architecture\n\n" + descriptionImageFromMarkdown, false},
+		{"markdown image with alt and title", &Image{fromMarkdown: true, alt: "diagram", title: "System overview"}, "System overview", "diagram\n\n" + descriptionImageFromMarkdown, false},
+		{"markdown image sent back", &Image{fromMarkdown: true, alt: "pattern", zOrder: ZOrderBack}, "", "pattern\n\n" + descriptionBackImageFromMarkdown, false},
+		{"unmanaged image with alt", &Image{alt: "diagram"}, "", "diagram", false},
+		{"unmanaged image without alt", &Image{}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.image.altTextRequest("image-1")
+			if tt.wantNil {
+				if r != nil {
+					t.Errorf("got %v, want nil", r)
+				}
+				return
+			}
+			if r == nil {
+				t.Fatal("got nil")
+			}
+			req := r.UpdatePageElementAltText
+			if req.ObjectId != "image-1" || req.Title != tt.wantTitle || req.Description != tt.wantDescription {
+				t.Errorf("got %+v, want title %q and description %q", req, tt.wantTitle, tt.wantDescription)
+			}
+			if isImageFromMarkdown(req.Description) != tt.image.fromMarkdown {
+				t.Errorf("isImageFromMarkdown(%q) = %v, want %v", req.Description, !tt.image.fromMarkdown, tt.image.fromMarkdown)
+			}
+			got := &Image{}
+			got.setAltText(req.Title, req.Description)
+			if got.alt != tt.image.alt || got.title != tt.image.title || got.zOrder != tt.image.zOrder {
+				t.Errorf("round trip: got alt %q, title %q and z-order %q, want %q, %q and %q", got.alt, got.title, got.zOrder, tt.image.alt, tt.image.title, tt.image.zOrder)
+			}
+		})
+	}
+}
+
+func TestAssignImagePlaceholders(t *testing.T) {
+	images := func(placeholders ...int) []*Image {
+		var images []*Image
+		for _, p := range placeholders {
+			images = append(images, &Image{placeholder: p})
+		}
+		return images
+	}
+	tests := []struct {
+		name   string
+		images []*Image
+		n      int
+		want   []int
+	}{
+		{"in order", images(0, 0), 2, []int{0, 1}},
+		{"more images than placeholders", images(0, 0, 0), 2, []int{0, 1, -1}},
+		{"swapped", images(2, 1), 2, []int{1, 0}},
+		{"mixed", images(0, 1), 2, []int{1, 0}},
+		{"out of range", images(3, 0), 2, []int{0, 1}},
+		{"already taken", images(1, 1), 2, []int{0, 1}},
+		{"no placeholders", images(1), 0, []int{-1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := assignImagePlaceholders(tt.images, tt.n)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("assignImagePlaceholders() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
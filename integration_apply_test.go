@@ -7,15 +7,23 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/k1LoW/deck/fakeslides"
 )
 
 func TestApply(t *testing.T) {
+	ctx := context.Background()
+	acquire := AcquirePresentation
+	baseOpts := BuildTestOptions()
 	if os.Getenv("TEST_INTEGRATION") == "" {
-		t.Skip("skipping integration test, set TEST_INTEGRATION=1 to run")
+		// Without TEST_INTEGRATION, the cases are run against the fake server instead of Google Slides.
+		srv := fakeslides.NewServer()
+		t.Cleanup(srv.Close)
+		acquire = func(t *testing.T) string {
+			return srv.CreatePresentation(titleForTest)
+		}
+		baseOpts = append(baseOpts, WithEndpoint(srv.URL))
 	}
 
-	ctx := context.Background()
-
 	cmpopts := cmp.Options{
 		cmpopts.IgnoreFields(Fragment{}, "StyleName"),
 		cmpopts.IgnoreFields(Slide{}, "TitleBodies", "SubtitleBodies"),
@@ -27,9 +35,9 @@ func TestApply(t *testing.T) {
 			t.Parallel()
 
 			// Acquire a presentation from the pool
-			presentationID := AcquirePresentation(t)
+			presentationID := acquire(t)
 
-			opts := append([]Option{WithPresentationID(presentationID)}, BuildTestOptions()...)
+			opts := append([]Option{WithPresentationID(presentationID)}, baseOpts...)
 			d, err := New(ctx, opts...)
 			if err != nil {
 				t.Fatal(err)
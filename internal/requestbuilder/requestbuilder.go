@@ -0,0 +1,18 @@
+// Package requestbuilder exposes the request builder of package deck to package requests,
+// so that the builder does not have to be exported from package deck.
+package requestbuilder
+
+import "google.golang.org/api/slides/v1"
+
+// Options are the styles used when building requests.
+type Options struct {
+	TextStyles      map[string]*slides.TextStyle
+	ShapeProperties map[string]*slides.ShapeProperties
+	ParagraphStyles map[string]*slides.ParagraphStyle
+	TableStyle      any // *deck.TableStyle
+	Direction       string
+}
+
+// New returns the request builder of package deck with the options.
+// It is set by package deck when it is initialized.
+var New func(o *Options) any
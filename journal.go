@@ -0,0 +1,113 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/k1LoW/errors"
+)
+
+// Journal records the progress of an apply so that an interrupted apply can be resumed.
+// The journal is saved every time a batch of pages is applied and removed when the apply completes.
+type Journal struct {
+	PresentationID string    `json:"presentation_id"`
+	Digest         string    `json:"digest"`      // digest of the source of the slides
+	RevisionID     string    `json:"revision_id"` // revision of the presentation after the last completed batch
+	CompletedPages []int     `json:"completed_pages,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	path string
+}
+
+// NewJournal returns a new journal saved to path.
+// digest identifies the source of the slides to apply, so that a journal of another source is not resumed.
+func NewJournal(path, digest string) *Journal {
+	return &Journal{
+		Digest: digest,
+		path:   path,
+	}
+}
+
+// LoadJournal loads the journal saved to path.
+// It returns fs.ErrNotExist if there is no journal.
+func LoadJournal(path string) (*Journal, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{}
+	if err := json.Unmarshal(b, j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %w", path, err)
+	}
+	j.path = path
+	return j, nil
+}
+
+// Verify returns an error if the journal cannot be resumed for the presentation and the source.
+// The presentation must not have been modified since the last completed batch.
+func (j *Journal) Verify(presentationID, revisionID, digest string) error {
+	if j.PresentationID != presentationID {
+		return fmt.Errorf("the journal is for another presentation: %s", j.PresentationID)
+	}
+	if j.Digest != digest {
+		return errors.New("the markdown has been changed since the interrupted apply")
+	}
+	if j.RevisionID != revisionID {
+		return errors.New("the presentation has been modified since the interrupted apply")
+	}
+	return nil
+}
+
+// RemainingPages returns the pages (1-based) of total pages that have not been applied yet.
+func (j *Journal) RemainingPages(total int) []int {
+	var pages []int
+	for p := 1; p <= total; p++ {
+		if !slices.Contains(j.CompletedPages, p) {
+			pages = append(pages, p)
+		}
+	}
+	return pages
+}
+
+// Remove removes the saved journal.
+func (j *Journal) Remove() error {
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove journal: %w", err)
+	}
+	return nil
+}
+
+// start records the presentation to apply to. The actions are not recorded, since the actions of
+// the remaining pages are generated again from the presentation when the apply is resumed.
+func (j *Journal) start(presentationID, revisionID string) error {
+	j.PresentationID = presentationID
+	j.RevisionID = revisionID
+	return j.save()
+}
+
+func (j *Journal) complete(revisionID string, pages []int) error {
+	j.RevisionID = revisionID
+	for _, p := range pages {
+		if p > 0 && !slices.Contains(j.CompletedPages, p) {
+			j.CompletedPages = append(j.CompletedPages, p)
+		}
+	}
+	slices.Sort(j.CompletedPages)
+	return j.save()
+}
+
+func (j *Journal) save() error {
+	j.UpdatedAt = time.Now()
+	b, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(j.path, b, 0600); err != nil {
+		return fmt.Errorf("failed to save journal: %w", err)
+	}
+	return nil
+}
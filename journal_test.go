@@ -0,0 +1,63 @@
+package deck
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	j := NewJournal(path, "digest")
+	if err := j.start("presentation", "rev1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.complete("rev2", []int{3, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.complete("rev3", []int{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{1, 3}, got.CompletedPages); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]int{2, 4}, got.RemainingPages(4)); diff != "" {
+		t.Error(diff)
+	}
+
+	tests := []struct {
+		name           string
+		presentationID string
+		revisionID     string
+		digest         string
+		wantErr        bool
+	}{
+		{"resumable", "presentation", "rev3", "digest", false},
+		{"another presentation", "other", "rev3", "digest", true},
+		{"modified presentation", "presentation", "rev4", "digest", true},
+		{"modified markdown", "presentation", "rev3", "other", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := got.Verify(tt.presentationID, tt.revisionID, tt.digest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := got.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJournal(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got %v, want fs.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,85 @@
+package deck
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/slides/v1"
+)
+
+// The key of a slide is stored in the alt text of a hidden shape placed outside the page,
+// so that the slide can be matched by the key on the next apply.
+
+// keyShapeSize is the width and height of the hidden shapes storing the key and the metadata of the page in EMU.
+const keyShapeSize = 12700
+
+// keyRequests returns requests to store key in the page identified by pageObjectID.
+// currentKeys is the keys of the existing key shapes keyed by their object IDs.
+func keyRequests(pageObjectID, key string, currentKeys map[string]string) []*slides.Request {
+	return hiddenShapeRequests(pageObjectID, "key", key, descriptionKeyFromMarkdown, currentKeys)
+}
+
+// hiddenShapeRequests returns requests to store value in the title of the alt text of a hidden shape with description
+// in the page identified by pageObjectID, replacing the current shapes, which are the values keyed by their object IDs.
+// An empty value removes the current shapes.
+func hiddenShapeRequests(pageObjectID, prefix, value, description string, current map[string]string) []*slides.Request {
+	if len(current) == 1 {
+		for _, v := range current {
+			if v == value {
+				return nil
+			}
+		}
+	}
+	var requests []*slides.Request
+	for objectID := range current {
+		requests = append(requests, &slides.Request{
+			DeleteObject: &slides.DeleteObjectRequest{
+				ObjectId: objectID,
+			},
+		})
+	}
+	if value == "" {
+		return requests
+	}
+	objectID := fmt.Sprintf("%s-%s", prefix, uuid.New().String())
+	return append(requests,
+		&slides.Request{
+			CreateShape: &slides.CreateShapeRequest{
+				ObjectId: objectID,
+				ElementProperties: &slides.PageElementProperties{
+					PageObjectId: pageObjectID,
+					Size: &slides.Size{
+						Height: &slides.Dimension{Magnitude: keyShapeSize, Unit: "EMU"},
+						Width:  &slides.Dimension{Magnitude: keyShapeSize, Unit: "EMU"},
+					},
+					// Outside the page so that it is not rendered
+					Transform: &slides.AffineTransform{
+						ScaleX:     1.0,
+						ScaleY:     1.0,
+						TranslateX: -2 * keyShapeSize,
+						TranslateY: -2 * keyShapeSize,
+						Unit:       "EMU",
+					},
+				},
+				ShapeType: "RECTANGLE",
+			},
+		},
+		&slides.Request{
+			UpdateShapeProperties: &slides.UpdateShapePropertiesRequest{
+				ObjectId: objectID,
+				ShapeProperties: &slides.ShapeProperties{
+					ShapeBackgroundFill: &slides.ShapeBackgroundFill{PropertyState: "NOT_RENDERED"},
+					Outline:             &slides.Outline{PropertyState: "NOT_RENDERED"},
+				},
+				Fields: "shapeBackgroundFill.propertyState,outline.propertyState",
+			},
+		},
+		&slides.Request{
+			UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+				ObjectId:    objectID,
+				Title:       value,
+				Description: description,
+			},
+		},
+	)
+}
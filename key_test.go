@@ -0,0 +1,47 @@
+package deck
+
+import "testing"
+
+func TestKeyRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		currentKeys map[string]string
+		wantDeletes int
+		wantCreate  bool
+	}{
+		{"no key", "", map[string]string{}, 0, false},
+		{"new key", "intro", map[string]string{}, 0, true},
+		{"same key", "intro", map[string]string{"key-1": "intro"}, 0, false},
+		{"changed key", "intro", map[string]string{"key-1": "old"}, 1, true},
+		{"removed key", "", map[string]string{"key-1": "intro"}, 1, false},
+		{"duplicated key shapes", "intro", map[string]string{"key-1": "intro", "key-2": "intro"}, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := keyRequests("page", tt.key, tt.currentKeys)
+			var deletes int
+			var created bool
+			for _, r := range got {
+				if r.DeleteObject != nil {
+					deletes++
+				}
+				if r.UpdatePageElementAltText != nil {
+					created = true
+					if r.UpdatePageElementAltText.Title != tt.key {
+						t.Errorf("got key %q, want %q", r.UpdatePageElementAltText.Title, tt.key)
+					}
+					if r.UpdatePageElementAltText.Description != descriptionKeyFromMarkdown {
+						t.Errorf("got description %q", r.UpdatePageElementAltText.Description)
+					}
+				}
+			}
+			if deletes != tt.wantDeletes {
+				t.Errorf("got %d deletes, want %d", deletes, tt.wantDeletes)
+			}
+			if created != tt.wantCreate {
+				t.Errorf("got created %v, want %v", created, tt.wantCreate)
+			}
+		})
+	}
+}
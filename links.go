@@ -0,0 +1,48 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// PageLink represents the deep link to a page of the presentation.
+type PageLink struct {
+	Page     int    `json:"page"`
+	Title    string `json:"title,omitempty"`
+	ObjectID string `json:"objectID"`
+	URL      string `json:"url"`
+}
+
+// String returns a human-readable line of the link.
+func (l *PageLink) String() string {
+	if l.Title == "" {
+		return fmt.Sprintf("page %d: %s", l.Page, l.URL)
+	}
+	return fmt.Sprintf("page %d: %s %s", l.Page, l.Title, l.URL)
+}
+
+// PageLinks returns the deep links to the pages of the presentation in the order of pages.
+func (d *Deck) PageLinks() []*PageLink {
+	layoutObjectIdMap := map[string]*slides.Page{}
+	for _, l := range d.presentation.Layouts {
+		layoutObjectIdMap[l.ObjectId] = l
+	}
+	links := make([]*PageLink, 0, len(d.presentation.Slides))
+	for i, p := range d.presentation.Slides {
+		slide := convertToSlide(p, layoutObjectIdMap)
+		links = append(links, &PageLink{
+			Page:     i + 1,
+			Title:    strings.Join(slide.Titles, " "),
+			ObjectID: p.ObjectId,
+			URL:      PageURL(d.id, p.ObjectId),
+		})
+	}
+	return links
+}
+
+// PageURL returns the URL to edit the page of the presentation.
+func PageURL(presentationID, objectID string) string {
+	return PresentationIDtoURL(presentationID) + "edit#slide=id." + objectID
+}
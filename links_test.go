@@ -0,0 +1,42 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestPageLinks(t *testing.T) {
+	d := &Deck{
+		id: "presentation",
+		presentation: &slides.Presentation{
+			Slides: []*slides.Page{
+				{
+					ObjectId: "p1",
+					PageElements: []*slides.PageElement{
+						{
+							Shape: &slides.Shape{
+								Placeholder: &slides.Placeholder{Type: "TITLE"},
+								Text: &slides.TextContent{
+									TextElements: []*slides.TextElement{
+										{TextRun: &slides.TextRun{Content: "Hello\n"}},
+									},
+								},
+							},
+						},
+					},
+				},
+				{ObjectId: "p2"},
+			},
+		},
+	}
+	want := []*PageLink{
+		{Page: 1, Title: "Hello", ObjectID: "p1", URL: "https://docs.google.com/presentation/d/presentation/edit#slide=id.p1"},
+		{Page: 2, ObjectID: "p2", URL: "https://docs.google.com/presentation/d/presentation/edit#slide=id.p2"},
+	}
+	got := d.PageLinks()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PageLinks() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -61,6 +61,11 @@ func (d *Deck) ListSlideURLs() []string {
 	return slideURLs
 }
 
+// PageCount returns the number of pages in the Google Slides presentation.
+func (d *Deck) PageCount() int {
+	return len(d.presentation.Slides)
+}
+
 // PresentationIDtoURL converts a presentation ID to a Google Slides URL.
 func PresentationIDtoURL(presentationID string) string {
 	return fmt.Sprintf("https://docs.google.com/presentation/d/%s/", presentationID)
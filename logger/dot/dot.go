@@ -115,6 +115,11 @@ func (h *dotHandler) Handle(ctx context.Context, r slog.Record) (err error) {
 		return nil
 	}
 
+	if r.Level == slog.LevelWarn {
+		// Warnings are printed on their own lines, since they need to be read.
+		_, err = h.stdout.Write([]byte(yellow("warning:") + " " + r.Message + "\n"))
+		return err
+	}
 	if strings.Contains(r.Message, "because freeze:true") {
 		if err := h.write([]byte(cyan("*"))); err != nil {
 			return err
@@ -0,0 +1,27 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+)
+
+// resolveAutofit validates the autofit of the pages and sets the autofit in the frontmatter to pages without autofit.
+func (md *MD) resolveAutofit() error {
+	var autofit string
+	if md.Frontmatter != nil {
+		autofit = md.Frontmatter.Autofit
+	}
+	if err := deck.ValidateAutofit(autofit); err != nil {
+		return err
+	}
+	for i, content := range md.Contents {
+		if err := deck.ValidateAutofit(content.Autofit); err != nil {
+			return fmt.Errorf("page %d: %w", i+1, err)
+		}
+		if content.Autofit == "" {
+			content.Autofit = autofit
+		}
+	}
+	return nil
+}
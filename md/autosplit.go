@@ -0,0 +1,148 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/textmeasure"
+)
+
+const (
+	defaultAutoSplitMaxLines     = 12
+	defaultAutoSplitCharsPerLine = 60
+	continuationTitleSuffix      = " (cont.)"
+)
+
+// AutoSplit represents the settings for splitting overlong pages into continuation pages.
+// The rendered height of a body is estimated from the number of lines of its paragraphs.
+type AutoSplit struct {
+	AutoSplitRule `yaml:",inline"`
+	// rules per layout name, overriding the default rule
+	Layouts map[string]AutoSplitRule `yaml:"layouts,omitempty" json:"layouts,omitempty"`
+}
+
+// AutoSplitRule represents the heuristics to estimate whether a body overflows its placeholder.
+type AutoSplitRule struct {
+	MaxLines     int `yaml:"maxLines,omitempty" json:"maxLines,omitempty"`         // maximum number of lines of a body (default: 12)
+	CharsPerLine int `yaml:"charsPerLine,omitempty" json:"charsPerLine,omitempty"` // number of characters per line, wide characters count as two (default: 60)
+}
+
+// rule returns the rule for the layout.
+func (a *AutoSplit) rule(layout string) AutoSplitRule {
+	r := a.AutoSplitRule
+	if lr, ok := a.Layouts[layout]; ok {
+		if lr.MaxLines > 0 {
+			r.MaxLines = lr.MaxLines
+		}
+		if lr.CharsPerLine > 0 {
+			r.CharsPerLine = lr.CharsPerLine
+		}
+	}
+	if r.MaxLines <= 0 {
+		r.MaxLines = defaultAutoSplitMaxLines
+	}
+	if r.CharsPerLine <= 0 {
+		r.CharsPerLine = defaultAutoSplitCharsPerLine
+	}
+	return r
+}
+
+// resolveAutoSplit splits pages whose first body is estimated to overflow into "Title (cont.)" pages.
+// It is enabled by autoSplit in the frontmatter.
+func (md *MD) resolveAutoSplit() error {
+	if md.Frontmatter == nil || md.Frontmatter.AutoSplit == nil {
+		return nil
+	}
+	a := md.Frontmatter.AutoSplit
+	for name, r := range a.Layouts {
+		if r.MaxLines < 0 || r.CharsPerLine < 0 {
+			return fmt.Errorf("invalid autoSplit rule for layout %q", name)
+		}
+	}
+	if a.MaxLines < 0 || a.CharsPerLine < 0 {
+		return fmt.Errorf("invalid autoSplit rule")
+	}
+	var contents Contents
+	for _, content := range md.Contents {
+		if content.Freeze.frozen() || (content.Ignore != nil && *content.Ignore) {
+			contents = append(contents, content)
+			continue
+		}
+		contents = append(contents, content.autoSplit(a.rule(content.Layout))...)
+	}
+	md.Contents = contents
+	return nil
+}
+
+// autoSplit splits the paragraphs of the first body of c into chunks that fit in r.MaxLines.
+// It returns c with the first chunk, followed by continuation pages with the rest.
+func (c *Content) autoSplit(r AutoSplitRule) Contents {
+	contents := Contents{c}
+	if len(c.Bodies) == 0 {
+		return contents
+	}
+	var (
+		chunks [][]*deck.Paragraph
+		chunk  []*deck.Paragraph
+		lines  int
+	)
+	for _, p := range c.Bodies[0].Paragraphs {
+		n := paragraphLines(p, r.CharsPerLine)
+		if len(chunk) > 0 && lines+n > r.MaxLines {
+			chunks = append(chunks, chunk)
+			chunk, lines = nil, 0
+		}
+		chunk = append(chunk, p)
+		lines += n
+	}
+	chunks = append(chunks, chunk)
+	if len(chunks) == 1 {
+		return contents
+	}
+	c.Bodies[0] = &deck.Body{Paragraphs: chunks[0]}
+	for _, paragraphs := range chunks[1:] {
+		continued := c.continuation(len(contents) + 1)
+		continued.Titles, continued.TitleBodies = continuationTitles(c.Titles, c.TitleBodies)
+		continued.Bodies = []*deck.Body{{Paragraphs: paragraphs}}
+		contents = append(contents, continued)
+	}
+	return contents
+}
+
+// continuationTitles returns the titles with the continuation suffix such as "Title (cont.)".
+func continuationTitles(titles []string, bodies []*deck.Body) ([]string, []*deck.Body) {
+	if len(titles) == 0 {
+		return nil, nil
+	}
+	ts := append([]string{titles[0] + continuationTitleSuffix}, titles[1:]...)
+	if len(bodies) == 0 || len(bodies[0].Paragraphs) == 0 {
+		return ts, bodies
+	}
+	ps := bodies[0].Paragraphs
+	last := *ps[len(ps)-1]
+	last.Fragments = append(append([]*deck.Fragment{}, last.Fragments...), &deck.Fragment{Value: continuationTitleSuffix})
+	b := &deck.Body{Paragraphs: append(append([]*deck.Paragraph{}, ps[:len(ps)-1]...), &last)}
+	return ts, append([]*deck.Body{b}, bodies[1:]...)
+}
+
+// paragraphLines estimates the number of lines of p wrapped at charsPerLine.
+func paragraphLines(p *deck.Paragraph, charsPerLine int) int {
+	lines := 1
+	width := 0
+	for _, f := range p.Fragments {
+		for g := range textmeasure.Graphemes(f.Value) {
+			if g == "\n" {
+				lines++
+				width = 0
+				continue
+			}
+			w := textmeasure.GraphemeWidth(g)
+			if width+w > charsPerLine {
+				lines++
+				width = 0
+			}
+			width += w
+		}
+	}
+	return lines
+}
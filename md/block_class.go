@@ -0,0 +1,198 @@
+package md
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/k1LoW/deck"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Block classes select the named style of a text box created from a block element,
+// e.g. `> text {.warning}` or a fenced div `::: warning` ... `:::`.
+// Paragraph classes select the named style of a whole paragraph, e.g. `text {.lead}` or `<p class="lead">text</p>`.
+
+// kindFencedDiv is the node kind of fencedDiv.
+var kindFencedDiv = ast.NewNodeKind("FencedDiv")
+
+// fencedDiv is a block surrounded by `:::` fences. It is treated as a block quote with a class.
+type fencedDiv struct {
+	ast.BaseBlock
+	class string
+}
+
+// Kind implements ast.Node.
+func (n *fencedDiv) Kind() ast.NodeKind {
+	return kindFencedDiv
+}
+
+// Dump implements ast.Node.
+func (n *fencedDiv) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Class": n.class}, nil)
+}
+
+type fencedDivParser struct{}
+
+// Trigger implements parser.BlockParser.
+func (p *fencedDivParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+// Open implements parser.BlockParser.
+func (p *fencedDivParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	if pc.BlockOffset() < 0 {
+		return nil, parser.NoChildren
+	}
+	class, ok := parseFencedDivOpening(line[pc.BlockOffset():])
+	if !ok {
+		return nil, parser.NoChildren
+	}
+	reader.Advance(segment.Len() - 1)
+	return &fencedDiv{class: class}, parser.HasChildren
+}
+
+// Continue implements parser.BlockParser.
+func (p *fencedDivParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if isFencedDivClosing(line) {
+		reader.Advance(segment.Len() - 1)
+		return parser.Close
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+// Close implements parser.BlockParser.
+func (p *fencedDivParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+// CanInterruptParagraph implements parser.BlockParser.
+func (p *fencedDivParser) CanInterruptParagraph() bool {
+	return true
+}
+
+// CanAcceptIndentedLine implements parser.BlockParser.
+func (p *fencedDivParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// fencedDivExtension is a goldmark extension for fenced divs.
+type fencedDivExtension struct{}
+
+// Extend implements goldmark.Extender.
+func (e *fencedDivExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(&fencedDivParser{}, 750),
+	))
+}
+
+// parseFencedDivOpening parses an opening fence such as `::: warning` or `::: {.warning}` and returns the class.
+func parseFencedDivOpening(line []byte) (string, bool) {
+	rest, ok := trimFence(line)
+	if !ok {
+		return "", false
+	}
+	rest = strings.TrimSpace(strings.TrimRight(rest, ":"))
+	if strings.HasPrefix(rest, "{") && strings.HasSuffix(rest, "}") {
+		rest = strings.TrimPrefix(strings.TrimSpace(rest[1:len(rest)-1]), ".")
+	}
+	if rest == "" || strings.ContainsAny(rest, " \t{}") {
+		return "", false
+	}
+	return rest, true
+}
+
+// isFencedDivClosing reports whether line is a closing fence consisting only of colons.
+func isFencedDivClosing(line []byte) bool {
+	rest, ok := trimFence(bytes.TrimLeft(line, " "))
+	return ok && strings.TrimSpace(rest) == ""
+}
+
+// trimFence trims a fence of at least three colons from the beginning of line.
+func trimFence(line []byte) (string, bool) {
+	s := strings.TrimRight(string(line), "\r\n")
+	trimmed := strings.TrimLeft(s, ":")
+	if len(s)-len(trimmed) < 3 {
+		return "", false
+	}
+	return trimmed, true
+}
+
+// blockClassRe matches a class attribute at the end of a block such as `{.warning}`.
+var blockClassRe = regexp.MustCompile(`(?:^|\s)\{\.([-_a-zA-Z0-9]+)\}\s*$`)
+
+// extractBlockClass removes a trailing class attribute from the last paragraph of bodies and returns the class.
+func extractBlockClass(bodies []*deck.Body) string {
+	for i := len(bodies) - 1; i >= 0; i-- {
+		if len(bodies[i].Paragraphs) == 0 {
+			continue
+		}
+		body := bodies[i]
+		paragraph := body.Paragraphs[len(body.Paragraphs)-1]
+		class := extractParagraphClass(paragraph)
+		// Remove the paragraph left empty by the removal.
+		if class != "" && len(paragraph.Fragments) == 0 {
+			body.Paragraphs = body.Paragraphs[:len(body.Paragraphs)-1]
+		}
+		return class
+	}
+	return ""
+}
+
+// extractParagraphClass removes a trailing class attribute from the paragraph and returns the class.
+func extractParagraphClass(paragraph *deck.Paragraph) string {
+	if len(paragraph.Fragments) == 0 {
+		return ""
+	}
+	last := paragraph.Fragments[len(paragraph.Fragments)-1]
+	m := blockClassRe.FindStringSubmatchIndex(last.Value)
+	if m == nil {
+		return ""
+	}
+	class := last.Value[m[2]:m[3]]
+	last.Value = last.Value[:m[0]]
+	// Remove fragments left empty by the removal.
+	for len(paragraph.Fragments) > 0 {
+		f := paragraph.Fragments[len(paragraph.Fragments)-1]
+		f.Value = strings.TrimRight(f.Value, " \t\n")
+		if f.Value != "" {
+			break
+		}
+		paragraph.Fragments = paragraph.Fragments[:len(paragraph.Fragments)-1]
+	}
+	return class
+}
+
+// paragraphElementRe matches a paragraph element with attributes such as `<p class="lead">text</p>`.
+var paragraphElementRe = regexp.MustCompile(`(?s)^(<p\s[^>]*>)(.*)</p>$`)
+
+// parseParagraphElement parses an HTML block of a paragraph element with a class such as `<p class="lead">text</p>`,
+// and returns the fragments and the images of its content parsed as inline markdown, and the class.
+// The class is empty if the block is not a paragraph element with a class.
+func parseParagraphElement(baseDir string, block []byte) (_ []*fragment, _ []*deck.Image, class string, err error) {
+	m := paragraphElementRe.FindSubmatch(bytes.TrimSpace(block))
+	if m == nil {
+		return nil, nil, "", nil
+	}
+	if c := classRe.FindSubmatch(m[1]); c != nil {
+		class = strings.TrimSpace(string(c[1]) + string(c[2]))
+	}
+	if class == "" {
+		return nil, nil, "", nil
+	}
+	inner := bytes.TrimSpace(m[2])
+	doc := newParser().Parser().Parse(text.NewReader(inner))
+	p := doc.FirstChild()
+	if p == nil || p.Kind() != ast.KindParagraph {
+		return nil, nil, class, nil
+	}
+	frags, images, err := toFragments(baseDir, inner, p, deck.Fragment{})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return frags, images, class, nil
+}
@@ -0,0 +1,48 @@
+package md
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+	"time"
+)
+
+// builtinVariables returns the variables available in all templates, computed when the template is expanded.
+// Variables that cannot be computed, such as git metadata outside a git repository, are empty strings.
+func builtinVariables() map[string]any {
+	vars := map[string]any{
+		"date":      time.Now().Format(time.DateOnly),
+		"gitCommit": gitOutput("rev-parse", "HEAD"),
+		"gitBranch": gitOutput("rev-parse", "--abbrev-ref", "HEAD"),
+		"hostname":  "",
+		"username":  "",
+	}
+	if h, err := os.Hostname(); err == nil {
+		vars["hostname"] = h
+	}
+	if u, err := user.Current(); err == nil {
+		vars["username"] = u.Username
+	}
+	return vars
+}
+
+// gitOutput returns the trimmed output of the git command in the current directory, or "" if it fails.
+func gitOutput(args ...string) string {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// withBuiltinVariables adds the built-in variables to the store of a template.
+// Values in the store take precedence over the built-in variables.
+func withBuiltinVariables(store map[string]any) map[string]any {
+	for k, v := range builtinVariables() {
+		if _, ok := store[k]; !ok {
+			store[k] = v
+		}
+	}
+	return store
+}
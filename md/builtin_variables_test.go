@@ -0,0 +1,33 @@
+package md
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuiltinVariables(t *testing.T) {
+	t.Chdir(t.TempDir()) // outside a git repository
+	vars := builtinVariables()
+	for _, k := range []string{"date", "gitCommit", "gitBranch", "hostname", "username"} {
+		if _, ok := vars[k].(string); !ok {
+			t.Errorf("built-in variable %q is not a string: %v", k, vars[k])
+		}
+	}
+	if _, err := time.Parse(time.DateOnly, vars["date"].(string)); err != nil {
+		t.Errorf("invalid date: %v", err)
+	}
+	if got := vars["gitCommit"]; got != "" {
+		t.Errorf("gitCommit outside a git repository = %q, want empty", got)
+	}
+}
+
+func TestWithBuiltinVariables(t *testing.T) {
+	store := withBuiltinVariables(map[string]any{"date": "overridden"})
+	got, err := expandTemplate("{{ date }} {{ gitCommit == '' ? 'none' : 'commit' }}", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "overridden none" && got != "overridden commit" {
+		t.Errorf("got %q", got)
+	}
+}
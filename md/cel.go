@@ -12,9 +12,11 @@ func (md *MD) reflectDefaults() error {
 	if md.Frontmatter == nil {
 		return nil
 	}
-	env, err := cel.NewEnv(
+	env, err := cel.NewEnv(append(celFunctions(),
 		cel.Variable("page", cel.IntType),
 		cel.Variable("pageTotal", cel.IntType),
+		cel.Variable("visiblePage", cel.IntType),
+		cel.Variable("visiblePageTotal", cel.IntType),
 		cel.Variable("titles", cel.ListType(cel.StringType)),
 		cel.Variable("subtitles", cel.ListType(cel.StringType)),
 		cel.Variable("bodies", cel.ListType(cel.StringType)),
@@ -25,11 +27,15 @@ func (md *MD) reflectDefaults() error {
 		cel.Variable("headings", cel.MapType(cel.IntType, cel.ListType(cel.StringType))),
 		cel.Variable("speakerNote", cel.StringType),
 		cel.Variable("topHeadingLevel", cel.IntType),
-	)
+	)...)
 	if err != nil {
 		return fmt.Errorf("failed to create environment: %w", err)
 	}
+	if err := md.Frontmatter.validateLayoutRules(); err != nil {
+		return err
+	}
 	pageTotal := len(md.Contents)
+	visiblePages, visiblePageTotal := md.Contents.visiblePages()
 	for i, content := range md.Contents {
 		for _, cond := range md.Frontmatter.Defaults {
 			ast, issues := env.Compile(fmt.Sprintf("!!(%s)", cond.If))
@@ -53,26 +59,22 @@ func (md *MD) reflectDefaults() error {
 					content.Headings[j] = []string{}
 				}
 			}
-			var topHeadingLevel int
-			for j := 1; j < sentinelLevel; j++ {
-				if len(content.Headings[j]) > 0 {
-					topHeadingLevel = j
-					break
-				}
-			}
+			topHeadingLevel := content.titleLevel()
 			out, _, err := prg.Eval(map[string]any{
-				"page":            i + 1,
-				"pageTotal":       pageTotal,
-				"titles":          content.Titles,
-				"subtitles":       content.Subtitles,
-				"bodies":          bodies,
-				"blockQuotes":     blockQuotes,
-				"codeBlocks":      content.CodeBlocks,
-				"images":          content.Images,
-				"comments":        content.Comments,
-				"headings":        content.Headings,
-				"speakerNote":     strings.Join(content.Comments, "\n\n"),
-				"topHeadingLevel": topHeadingLevel,
+				"page":             i + 1,
+				"pageTotal":        pageTotal,
+				"visiblePage":      visiblePages[i],
+				"visiblePageTotal": visiblePageTotal,
+				"titles":           content.Titles,
+				"subtitles":        content.Subtitles,
+				"bodies":           bodies,
+				"blockQuotes":      blockQuotes,
+				"codeBlocks":       content.CodeBlocks,
+				"images":           content.Images,
+				"comments":         content.Comments,
+				"headings":         content.Headings,
+				"speakerNote":      strings.Join(content.Comments, "\n\n"),
+				"topHeadingLevel":  topHeadingLevel,
 			})
 			if err != nil {
 				return fmt.Errorf("failed to evaluate values: %w", err)
@@ -93,6 +95,75 @@ func (md *MD) reflectDefaults() error {
 				break // Use the first matching condition
 			}
 		}
+		// Layout rules per heading level are weaker than defaults conditions.
+		if content.Layout == "" {
+			if level := content.titleLevel(); level > 0 {
+				content.Layout = md.Frontmatter.LayoutRules[fmt.Sprintf("h%d", level)]
+			}
+		}
+	}
+	md.reflectDefaultLayouts()
+	return nil
+}
+
+// reflectDefaultLayouts sets firstPageLayout to the first page and defaultLayout to the other pages
+// if they have no layout. The first page is the first page that is not ignored.
+func (md *MD) reflectDefaultLayouts() {
+	first := true
+	for _, content := range md.Contents {
+		if content.Ignore != nil && *content.Ignore {
+			continue
+		}
+		if content.Layout == "" {
+			if first {
+				content.Layout = md.Frontmatter.FirstPageLayout
+			} else {
+				content.Layout = md.Frontmatter.DefaultLayout
+			}
+		}
+		first = false
+	}
+}
+
+// visiblePages returns the page numbers counting only pages that are neither skipped nor ignored, and the number of such pages.
+// A hidden page has the number of the next visible page.
+// Only the skip and ignore settings given before evaluating defaults (page configuration and heading directives) are taken into account.
+func (contents Contents) visiblePages() ([]int, int) {
+	pages := make([]int, len(contents))
+	total := 0
+	for i, content := range contents {
+		pages[i] = total + 1
+		if content.hidden() {
+			continue
+		}
+		total++
+	}
+	return pages, total
+}
+
+// hidden reports whether the content is skipped or ignored.
+func (c *Content) hidden() bool {
+	return (c.Skip != nil && *c.Skip) || (c.Ignore != nil && *c.Ignore)
+}
+
+// titleLevel returns the top heading level of the content, or 0 if there are no headings.
+func (c *Content) titleLevel() int {
+	for level := 1; level < sentinelLevel; level++ {
+		if len(c.Headings[level]) > 0 {
+			return level
+		}
+	}
+	return 0
+}
+
+var layoutRuleKeyReg = regexp.MustCompile(`^h[1-6]$`)
+
+// validateLayoutRules validates that all keys of layoutRules are heading levels (h1-h6).
+func (fm *Frontmatter) validateLayoutRules() error {
+	for key := range fm.LayoutRules {
+		if !layoutRuleKeyReg.MatchString(key) {
+			return fmt.Errorf("invalid layoutRules key: %q (must be one of h1-h6)", key)
+		}
 	}
 	return nil
 }
@@ -146,7 +217,7 @@ func expandTemplate(template string, store map[string]any) (string, error) {
 
 // createCELEnv creates a CEL environment with all variables from the store.
 func createCELEnv(store map[string]any) (*cel.Env, error) {
-	var options []cel.EnvOption
+	options := celFunctions()
 
 	// Add each top-level store key as a CEL variable
 	for key, value := range store {
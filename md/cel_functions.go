@@ -0,0 +1,77 @@
+package md
+
+import (
+	"os"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// nowFunc returns the current time for now() in CEL expressions. It is replaced in tests.
+var nowFunc = time.Now
+
+// celFunctions returns helper functions available in CEL expressions of defaults and templates,
+// in addition to the standard functions such as matches().
+//
+//   - containsAny(list, list) reports whether the first list contains any element of the second list.
+//   - env(name) returns the value of the environment variable.
+//   - now() returns the current time as a timestamp.
+//   - now(layout) returns the current time formatted with the Go time layout (e.g. "2006-01-02").
+func celFunctions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("containsAny",
+			cel.Overload("containsAny_list_list",
+				[]*cel.Type{cel.ListType(cel.DynType), cel.ListType(cel.DynType)}, cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					l, ok := lhs.(traits.Lister)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(lhs)
+					}
+					r, ok := rhs.(traits.Lister)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(rhs)
+					}
+					for it := r.Iterator(); it.HasNext() == types.True; {
+						if l.Contains(it.Next()) == types.True {
+							return types.True
+						}
+					}
+					return types.False
+				}),
+			),
+		),
+		cel.Function("env",
+			cel.Overload("env_string",
+				[]*cel.Type{cel.StringType}, cel.StringType,
+				cel.UnaryBinding(func(name ref.Val) ref.Val {
+					s, ok := name.(types.String)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(name)
+					}
+					return types.String(os.Getenv(string(s)))
+				}),
+			),
+		),
+		cel.Function("now",
+			cel.Overload("now_timestamp",
+				[]*cel.Type{}, cel.TimestampType,
+				cel.FunctionBinding(func(_ ...ref.Val) ref.Val {
+					return types.Timestamp{Time: nowFunc()}
+				}),
+			),
+			cel.Overload("now_string",
+				[]*cel.Type{cel.StringType}, cel.StringType,
+				cel.UnaryBinding(func(layout ref.Val) ref.Val {
+					s, ok := layout.(types.String)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(layout)
+					}
+					return types.String(nowFunc().Format(string(s)))
+				}),
+			),
+		),
+	}
+}
@@ -0,0 +1,289 @@
+package md
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/k1LoW/deck"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// A fenced code block with the language `chart` is a spec of a bar or line chart, which is rendered
+// to an image by the built-in renderer (or by codeBlockToImageCommand if it is an external command).
+// Without data in the spec, the data is taken from the table right before the code block: the header row
+// has the names of the series and each row has a label followed by the values of the series.
+//
+//	```chart
+//	type: line
+//	title: Sales
+//	labels: [Q1, Q2, Q3]
+//	series:
+//	  - name: 2025
+//	    values: [10, 20, 15]
+//	```
+
+const (
+	chartLanguage = "chart"
+	chartBar      = "bar"
+	chartLine     = "line"
+
+	chartWidth     = 1600
+	chartHeight    = 900
+	chartPadding   = 40
+	chartTicks     = 5
+	chartLineWidth = 4
+	chartDotSize   = 12
+)
+
+// chartColors are the colors of the series, in order.
+var chartColors = []color.RGBA{
+	{0x42, 0x85, 0xf4, 0xff},
+	{0xea, 0x43, 0x35, 0xff},
+	{0xfb, 0xbc, 0x04, 0xff},
+	{0x34, 0xa8, 0x53, 0xff},
+	{0xff, 0x6d, 0x01, 0xff},
+	{0x46, 0xbd, 0xc6, 0xff},
+	{0x7e, 0x57, 0xc2, 0xff},
+}
+
+// chartSpec is the spec of a chart.
+type chartSpec struct {
+	Type   string        `yaml:"type,omitempty" json:"type"`             // "bar" (default) or "line"
+	Title  string        `yaml:"title,omitempty" json:"title,omitempty"` // title of the chart
+	Labels []string      `yaml:"labels,omitempty" json:"labels"`         // labels of the x axis
+	Series []chartSeries `yaml:"series,omitempty" json:"series"`         // series of values per label
+}
+
+// chartSeries is a series of values of a chart.
+type chartSeries struct {
+	Name   string    `yaml:"name,omitempty" json:"name,omitempty"`
+	Values []float64 `yaml:"values" json:"values"`
+}
+
+// parseChart parses the spec of the chart. If the spec has no series, they are taken from table.
+func parseChart(content string, table *deck.Table) (*chartSpec, error) {
+	spec := &chartSpec{}
+	if err := yaml.UnmarshalWithOptions([]byte(content), spec, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("invalid chart: %w", err)
+	}
+	switch spec.Type {
+	case "":
+		spec.Type = chartBar
+	case chartBar, chartLine:
+	default:
+		return nil, fmt.Errorf("invalid chart type: %q (must be %q or %q)", spec.Type, chartBar, chartLine)
+	}
+	if len(spec.Series) == 0 {
+		if table == nil {
+			return nil, fmt.Errorf("invalid chart: no series, and no table right before the chart")
+		}
+		if err := spec.fromTable(table); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range spec.Series {
+		if len(s.Values) != len(spec.Labels) {
+			return nil, fmt.Errorf("invalid chart: series %q has %d values for %d labels", s.Name, len(s.Values), len(spec.Labels))
+		}
+	}
+	if len(spec.Labels) == 0 {
+		return nil, fmt.Errorf("invalid chart: no labels")
+	}
+	return spec, nil
+}
+
+// fromTable sets the labels and series of the chart from the table.
+func (spec *chartSpec) fromTable(t *deck.Table) error {
+	if len(t.Rows) < 2 || len(t.Rows[0].Cells) < 2 {
+		return fmt.Errorf("invalid chart: the table must have a header row, a column of labels and columns of values")
+	}
+	for _, cell := range t.Rows[0].Cells[1:] {
+		spec.Series = append(spec.Series, chartSeries{Name: cellText(cell)})
+	}
+	for _, row := range t.Rows[1:] {
+		if len(row.Cells) == 0 {
+			continue
+		}
+		spec.Labels = append(spec.Labels, cellText(row.Cells[0]))
+		for i := range spec.Series {
+			var v float64
+			if i+1 < len(row.Cells) {
+				text := strings.ReplaceAll(cellText(row.Cells[i+1]), ",", "")
+				if text != "" {
+					f, err := strconv.ParseFloat(text, 64)
+					if err != nil {
+						return fmt.Errorf("invalid chart: not a number in the table: %q", cellText(row.Cells[i+1]))
+					}
+					v = f
+				}
+			}
+			spec.Series[i].Values = append(spec.Series[i].Values, v)
+		}
+	}
+	return nil
+}
+
+// chartCodeBlock returns the code block of the chart whose content is the spec in JSON,
+// so that external commands also receive the data taken from the table.
+func chartCodeBlock(spec *chartSpec) (*CodeBlock, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &CodeBlock{Language: chartLanguage, Content: string(b)}, nil
+}
+
+// genChartImage renders the chart to a PNG image.
+func genChartImage(codeBlock *CodeBlock) (*deck.Image, error) {
+	spec := &chartSpec{}
+	if err := json.Unmarshal([]byte(codeBlock.Content), spec); err != nil {
+		return nil, fmt.Errorf("invalid chart: %w", err)
+	}
+	faces, err := loadBuiltinFaces()
+	if err != nil {
+		return nil, err
+	}
+	face := faces.regular
+	lineHeight := face.Metrics().Height.Ceil()
+	ascent := face.Metrics().Ascent.Ceil()
+	fg := color.RGBA{0x33, 0x33, 0x33, 0xff}
+	grid := color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	top := chartPadding
+	if spec.Title != "" {
+		drawChartText(img, face, (chartWidth-measureChartText(face, spec.Title))/2, top+ascent, spec.Title, fg)
+		top += lineHeight + chartPadding/2
+	}
+	if len(spec.Series) > 1 || len(spec.Series) == 1 && spec.Series[0].Name != "" {
+		x := chartPadding
+		for i, s := range spec.Series {
+			c := chartColors[i%len(chartColors)]
+			fillChartRect(img, x, top+(lineHeight-chartDotSize*2)/2, chartDotSize*2, chartDotSize*2, c)
+			x += chartDotSize*2 + chartPadding/4
+			drawChartText(img, face, x, top+ascent, s.Name, fg)
+			x += measureChartText(face, s.Name) + chartPadding
+		}
+		top += lineHeight + chartPadding/2
+	}
+
+	lo, hi, step := chartScale(spec.Series)
+	var ticks []string
+	tickWidth := 0
+	for v := lo; v <= hi+step/2; v += step {
+		s := strconv.FormatFloat(math.Round(v/step)*step, 'g', 10, 64)
+		ticks = append(ticks, s)
+		tickWidth = max(tickWidth, measureChartText(face, s))
+	}
+	left := chartPadding + tickWidth + chartPadding/2
+	right := chartWidth - chartPadding
+	bottom := chartHeight - chartPadding - lineHeight - chartPadding/2
+	y := func(v float64) int {
+		return bottom - int(math.Round((v-lo)/(hi-lo)*float64(bottom-top)))
+	}
+	for i, s := range ticks {
+		ty := y(lo + float64(i)*step)
+		fillChartRect(img, left, ty, right-left, 2, grid)
+		drawChartText(img, face, left-chartPadding/2-measureChartText(face, s), ty+ascent/2, s, fg)
+	}
+
+	groupWidth := float64(right-left) / float64(len(spec.Labels))
+	for i, label := range spec.Labels {
+		cx := left + int(groupWidth*(float64(i)+0.5))
+		drawChartText(img, face, cx-measureChartText(face, label)/2, bottom+chartPadding/2+ascent, label, fg)
+	}
+	switch spec.Type {
+	case chartLine:
+		for si, s := range spec.Series {
+			c := chartColors[si%len(chartColors)]
+			for i, v := range s.Values {
+				cx := float64(left) + groupWidth*(float64(i)+0.5)
+				if i > 0 {
+					px := float64(left) + groupWidth*(float64(i)-0.5)
+					drawChartLine(img, px, float64(y(s.Values[i-1])), cx, float64(y(v)), c)
+				}
+				fillChartRect(img, int(cx)-chartDotSize/2, y(v)-chartDotSize/2, chartDotSize, chartDotSize, c)
+			}
+		}
+	default:
+		barWidth := groupWidth * 0.8 / float64(len(spec.Series))
+		zero := y(0)
+		for si, s := range spec.Series {
+			c := chartColors[si%len(chartColors)]
+			for i, v := range s.Values {
+				x := left + int(groupWidth*(float64(i)+0.1)+barWidth*float64(si))
+				y0, y1 := min(zero, y(v)), max(zero, y(v))
+				fillChartRect(img, x, y0, max(int(barWidth)-2, 1), y1-y0, c)
+			}
+		}
+	}
+	fillChartRect(img, left, y(max(lo, min(0, hi))), right-left, 2, fg)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart image: %w", err)
+	}
+	return deck.NewImageFromCodeBlock(&buf)
+}
+
+// chartScale returns the range of the y axis including zero, and the step of its ticks.
+func chartScale(series []chartSeries) (lo, hi, step float64) {
+	for _, s := range series {
+		for _, v := range s.Values {
+			lo = min(lo, v)
+			hi = max(hi, v)
+		}
+	}
+	if lo == hi {
+		hi = lo + 1
+	}
+	raw := (hi - lo) / chartTicks
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step = 10 * mag
+	for _, m := range []float64{1, 2, 2.5, 5} {
+		if raw <= m*mag {
+			step = m * mag
+			break
+		}
+	}
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step
+}
+
+// measureChartText returns the width of s in pixels.
+func measureChartText(face font.Face, s string) int {
+	return font.MeasureString(face, s).Ceil()
+}
+
+// drawChartText draws s with the baseline at (x, y).
+func drawChartText(img draw.Image, face font.Face, x, y int, s string, c color.Color) {
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
+
+// fillChartRect fills the rectangle with the color.
+func fillChartRect(img draw.Image, x, y, w, h int, c color.Color) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+// drawChartLine draws a line from (x0, y0) to (x1, y1).
+func drawChartLine(img draw.Image, x0, y0, x1, y1 float64, c color.Color) {
+	steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))) + 1
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Round(x0 + (x1-x0)*t))
+		y := int(math.Round(y0 + (y1-y0)*t))
+		fillChartRect(img, x-chartLineWidth/2, y-chartLineWidth/2, chartLineWidth, chartLineWidth, c)
+	}
+}
@@ -0,0 +1,100 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+)
+
+func TestParseChart(t *testing.T) {
+	table := &deck.Table{Rows: []*deck.TableRow{
+		{Cells: []*deck.TableCell{{Fragments: []*deck.Fragment{{Value: "Quarter"}}}, {Fragments: []*deck.Fragment{{Value: "East"}}}}},
+		{Cells: []*deck.TableCell{{Fragments: []*deck.Fragment{{Value: "Q1"}}}, {Fragments: []*deck.Fragment{{Value: "1,200"}}}}},
+		{Cells: []*deck.TableCell{{Fragments: []*deck.Fragment{{Value: "Q2"}}}, {Fragments: []*deck.Fragment{{Value: ""}}}}},
+	}}
+	tests := []struct {
+		name    string
+		content string
+		table   *deck.Table
+		want    *chartSpec
+		wantErr bool
+	}{
+		{
+			"inline data",
+			"type: line\nlabels: [a, b]\nseries:\n  - values: [1, 2.5]\n",
+			nil,
+			&chartSpec{Type: "line", Labels: []string{"a", "b"}, Series: []chartSeries{{Values: []float64{1, 2.5}}}},
+			false,
+		},
+		{
+			"data from the table",
+			"title: East\n",
+			table,
+			&chartSpec{Type: "bar", Title: "East", Labels: []string{"Q1", "Q2"}, Series: []chartSeries{{Name: "East", Values: []float64{1200, 0}}}},
+			false,
+		},
+		{
+			"inline data takes precedence over the table",
+			"labels: [a]\nseries:\n  - values: [1]\n",
+			table,
+			&chartSpec{Type: "bar", Labels: []string{"a"}, Series: []chartSeries{{Values: []float64{1}}}},
+			false,
+		},
+		{"no data", "type: bar\n", nil, nil, true},
+		{"invalid type", "type: pie\nlabels: [a]\nseries:\n  - values: [1]\n", nil, nil, true},
+		{"unknown key", "kind: bar\nlabels: [a]\nseries:\n  - values: [1]\n", nil, nil, true},
+		{"values and labels mismatch", "labels: [a, b]\nseries:\n  - values: [1]\n", nil, nil, true},
+		{
+			"not a number in the table",
+			"",
+			&deck.Table{Rows: []*deck.TableRow{
+				{Cells: []*deck.TableCell{{Fragments: []*deck.Fragment{{Value: "x"}}}, {Fragments: []*deck.Fragment{{Value: "y"}}}}},
+				{Cells: []*deck.TableCell{{Fragments: []*deck.Fragment{{Value: "a"}}}, {Fragments: []*deck.Fragment{{Value: "n/a"}}}}},
+			}},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChart(tt.content, tt.table)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseChart() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGenChartImage(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *chartSpec
+	}{
+		{"bar", &chartSpec{Type: "bar", Title: "Sales", Labels: []string{"Q1", "Q2"}, Series: []chartSeries{{Name: "East", Values: []float64{1200, 1500}}, {Name: "West", Values: []float64{800, -950}}}}},
+		{"line", &chartSpec{Type: "line", Labels: []string{"a", "b", "c"}, Series: []chartSeries{{Values: []float64{0.1, 0.3, 0.2}}}}},
+		{"all zero", &chartSpec{Type: "bar", Labels: []string{"a"}, Series: []chartSeries{{Values: []float64{0}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeBlock, err := chartCodeBlock(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := genBuiltinCodeImage(codeBlockToImageBuiltin, codeBlock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			img, err := got.Image()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.Bounds().Dx() != chartWidth || img.Bounds().Dy() != chartHeight {
+				t.Errorf("got %v, want %dx%d", img.Bounds(), chartWidth, chartHeight)
+			}
+		})
+	}
+}
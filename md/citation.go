@@ -0,0 +1,72 @@
+package md
+
+import (
+	"strings"
+
+	"github.com/k1LoW/deck"
+	"github.com/yuin/goldmark/ast"
+)
+
+// The citation of a block quote is the last line of the block quote starting with a dash, e.g. `> — Author, 2020`,
+// or marked with the class `{.cite}`. It is split into a paragraph of the `cite` style, which is rendered
+// right-aligned, italic and smaller than the quote unless the style is defined in the presentation.
+
+const classCite = "cite"
+
+// citationDashes are the dashes that start a citation.
+var citationDashes = []string{"—", "―"}
+
+// isBlockQuoteEnd reports whether n is the last paragraph of a block quote.
+func isBlockQuoteEnd(n ast.Node) bool {
+	return n.NextSibling() == nil && n.Parent() != nil && n.Parent().Kind() == ast.KindBlockquote
+}
+
+// splitCitation splits the citation from the fragments of the last paragraph of a block quote, and returns
+// the fragments of the quote and the paragraph of the citation, or nil if the paragraph has no citation.
+// The whole paragraph can be the citation only if whole is true, i.e. the block quote has other contents.
+func splitCitation(frags []*fragment, breaks, whole bool) ([]*fragment, *deck.Paragraph) {
+	// The citation is the last line of the paragraph.
+	start := 0
+	for i := len(frags) - 1; i > 0; i-- {
+		if frags[i-1].SoftLineBreak || strings.HasSuffix(frags[i-1].Value, "\n") {
+			start = i
+			break
+		}
+	}
+	if start == 0 && !whole {
+		return frags, nil
+	}
+	line := frags[start:]
+	first := strings.TrimLeft(line[0].Value, " \t")
+	dashed := false
+	for _, d := range citationDashes {
+		if strings.HasPrefix(first, d) {
+			dashed = true
+			break
+		}
+	}
+	m := blockClassRe.FindStringSubmatch(line[len(line)-1].Value)
+	marked := m != nil && m[1] == classCite
+	if !dashed && !marked {
+		return frags, nil
+	}
+	citation := &deck.Paragraph{
+		Fragments: toDeckFragments(line, breaks),
+		Bullet:    deck.BulletNone,
+		StyleName: classCite,
+	}
+	if marked {
+		extractParagraphClass(citation)
+	}
+	quote := frags[:start]
+	if len(quote) > 0 {
+		last := quote[len(quote)-1]
+		last.Value = strings.TrimSuffix(last.Value, "\n")
+		last.SoftLineBreak = false
+	}
+	if len(citation.Fragments) == 0 {
+		return quote, nil
+	}
+	citation.Fragments[0].Value = strings.TrimLeft(citation.Fragments[0].Value, " \t")
+	return quote, citation
+}
@@ -0,0 +1,97 @@
+package md
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Attributes of a fenced code block are written in braces after the language identifier, e.g.
+// ```go {1,3-5 lines=true}, to highlight the lines 1 and 3 to 5 and to show line numbers in the image of
+// the code block. They are passed to codeBlockToImageCommand, and drawn by the built-in renderer.
+
+var codeInfoReg = regexp.MustCompile(`^([^\s{]*)\s*\{([^}]*)\}\s*$`)
+
+// parseCodeInfo returns the language and sets the attributes of the code block from the info string of
+// the fenced code block n.
+func parseCodeInfo(n *ast.FencedCodeBlock, b []byte, codeBlock *CodeBlock) error {
+	if n.Info == nil {
+		return nil
+	}
+	info := strings.TrimSpace(string(n.Info.Segment.Value(b)))
+	m := codeInfoReg.FindStringSubmatch(info)
+	if m == nil {
+		codeBlock.Language = string(n.Language(b))
+		return nil
+	}
+	codeBlock.Language = m[1]
+	for attr := range strings.FieldsSeq(m[2]) {
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok {
+			lines, err := parseLineRanges(attr)
+			if err != nil {
+				return fmt.Errorf("invalid attributes of code block %q: %w", info, err)
+			}
+			codeBlock.HighlightLines = append(codeBlock.HighlightLines, lines...)
+			continue
+		}
+		switch key {
+		case "lines":
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid attributes of code block %q: lines must be true or false", info)
+			}
+			codeBlock.LineNumbers = v
+		default:
+			return fmt.Errorf("invalid attributes of code block %q: unknown attribute %q", info, key)
+		}
+	}
+	slices.Sort(codeBlock.HighlightLines)
+	codeBlock.HighlightLines = slices.Compact(codeBlock.HighlightLines)
+	return nil
+}
+
+// parseLineRanges parses comma-separated line numbers and ranges of them, e.g. "1,3-5".
+func parseLineRanges(s string) ([]int, error) {
+	var lines []int
+	for r := range strings.SplitSeq(s, ",") {
+		first, last, isRange := strings.Cut(r, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil || start < 1 {
+			return nil, fmt.Errorf("invalid line number %q", r)
+		}
+		end := start
+		if isRange {
+			end, err = strconv.Atoi(last)
+			if err != nil || end < start {
+				return nil, fmt.Errorf("invalid range of lines %q", r)
+			}
+		}
+		for l := start; l <= end; l++ {
+			lines = append(lines, l)
+		}
+	}
+	return lines, nil
+}
+
+// formatLineRanges formats the sorted line numbers as comma-separated ranges, e.g. "1,3-5".
+func formatLineRanges(lines []int) string {
+	var ranges []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(lines[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ",")
+}
@@ -0,0 +1,98 @@
+package md
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCodeInfo(t *testing.T) {
+	tests := []struct {
+		info    string
+		want    *CodeBlock
+		wantErr bool
+	}{
+		{"", &CodeBlock{}, false},
+		{"go", &CodeBlock{Language: "go"}, false},
+		{"go title=main.go", &CodeBlock{Language: "go"}, false},
+		{"go {1,3-5 lines=true}", &CodeBlock{Language: "go", HighlightLines: []int{1, 3, 4, 5}, LineNumbers: true}, false},
+		{"go{2}", &CodeBlock{Language: "go", HighlightLines: []int{2}}, false},
+		{"go {lines=true}", &CodeBlock{Language: "go", LineNumbers: true}, false},
+		{"go {3-4 2,4}", &CodeBlock{Language: "go", HighlightLines: []int{2, 3, 4}}, false},
+		{"{1}", &CodeBlock{HighlightLines: []int{1}}, false},
+		{"go {5-3}", nil, true},
+		{"go {0}", nil, true},
+		{"go {a}", nil, true},
+		{"go {lines=yes}", nil, true},
+		{"go {numbers=true}", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.info, func(t *testing.T) {
+			b := []byte("```" + tt.info + "\ncode\n```\n")
+			m, err := Parse(".", b, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			tt.want.Content = "code\n"
+			if diff := cmp.Diff(tt.want, m.Contents[0].CodeBlocks[0], cmp.AllowUnexported(CodeBlock{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFormatLineRanges(t *testing.T) {
+	tests := []struct {
+		in   []int
+		want string
+	}{
+		{nil, ""},
+		{[]int{1}, "1"},
+		{[]int{1, 3, 4, 5}, "1,3-5"},
+		{[]int{1, 2, 4, 6, 7}, "1-2,4,6-7"},
+	}
+	for _, tt := range tests {
+		if got := formatLineRanges(tt.in); got != tt.want {
+			t.Errorf("formatLineRanges(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+		if tt.want == "" {
+			continue
+		}
+		got, err := parseLineRanges(tt.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tt.in, got); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestGenCodeImageLines(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stubCmd := filepath.Join(cwd, "testdata", "stub_code_img.go")
+	codeBlock := &CodeBlock{Language: "go", Content: "package main\n", HighlightLines: []int{1, 3, 4, 5}, LineNumbers: true}
+	tests := []struct {
+		name string
+		cmd  string
+	}{
+		{"template", `test "{{highlightLines}}" = "1,3-5" && test "{{lineNumbers}}" = true && go run ` + stubCmd},
+		{"env", `test "$CODEBLOCK_HIGHLIGHT_LINES" = "1,3-5" && test "$CODEBLOCK_LINE_NUMBERS" = true && go run ` + stubCmd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := genCodeImage(context.Background(), tt.cmd, "", codeBlock); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
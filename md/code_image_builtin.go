@@ -0,0 +1,199 @@
+package md
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/k1LoW/deck"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// `codeBlockToImageCommand: builtin` renders code blocks to images without an external command,
+// highlighting them with chroma and drawing them with the bundled Go Mono font.
+// `builtin:<style>` selects the chroma style (e.g. `builtin:monokai`).
+
+const (
+	codeBlockToImageBuiltin = "builtin"
+	builtinCodeImageStyle   = "github"
+	builtinCodeImageSize    = 32 // font size in pixels, large enough to stay sharp when scaled on slides
+	builtinCodeImagePadding = 40 // padding around the code in pixels
+	builtinCodeImageTabSize = 4
+)
+
+var (
+	// colors of highlighted lines and line numbers for styles without them
+	builtinCodeImageHighlightColor  = color.RGBA{R: 0xff, G: 0xf5, B: 0xb1, A: 0xff}
+	builtinCodeImageLineNumberColor = color.RGBA{R: 0x8c, G: 0x8c, B: 0x8c, A: 0xff}
+)
+
+// isBuiltinCodeImageCommand reports whether the command is the built-in renderer.
+func isBuiltinCodeImageCommand(codeBlockToImageCmd string) bool {
+	return codeBlockToImageCmd == codeBlockToImageBuiltin || strings.HasPrefix(codeBlockToImageCmd, codeBlockToImageBuiltin+":")
+}
+
+type codeImageFaces struct {
+	regular, bold, italic, boldItalic font.Face
+}
+
+var (
+	builtinFacesOnce sync.Once
+	builtinFaces     *codeImageFaces
+	builtinFacesErr  error
+)
+
+// loadBuiltinFaces parses the bundled fonts once.
+func loadBuiltinFaces() (*codeImageFaces, error) {
+	builtinFacesOnce.Do(func() {
+		newFace := func(ttf []byte) (font.Face, error) {
+			f, err := opentype.Parse(ttf)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the bundled font: %w", err)
+			}
+			return opentype.NewFace(f, &opentype.FaceOptions{Size: builtinCodeImageSize, DPI: 72, Hinting: font.HintingFull})
+		}
+		var faces codeImageFaces
+		for _, v := range []struct {
+			face *font.Face
+			ttf  []byte
+		}{
+			{&faces.regular, gomono.TTF},
+			{&faces.bold, gomonobold.TTF},
+			{&faces.italic, gomonoitalic.TTF},
+			{&faces.boldItalic, gomonobolditalic.TTF},
+		} {
+			f, err := newFace(v.ttf)
+			if err != nil {
+				builtinFacesErr = err
+				return
+			}
+			*v.face = f
+		}
+		builtinFaces = &faces
+	})
+	return builtinFaces, builtinFacesErr
+}
+
+// face returns the face for the style entry.
+func (f *codeImageFaces) face(e chroma.StyleEntry) font.Face {
+	switch {
+	case e.Bold == chroma.Yes && e.Italic == chroma.Yes:
+		return f.boldItalic
+	case e.Bold == chroma.Yes:
+		return f.bold
+	case e.Italic == chroma.Yes:
+		return f.italic
+	default:
+		return f.regular
+	}
+}
+
+// genBuiltinCodeImage renders the code block to a PNG image with syntax highlighting, highlighted lines and
+// line numbers, or the chart to a PNG image.
+func genBuiltinCodeImage(codeBlockToImageCmd string, codeBlock *CodeBlock) (*deck.Image, error) {
+	if codeBlock.Language == chartLanguage {
+		return genChartImage(codeBlock)
+	}
+	styleName := builtinCodeImageStyle
+	if _, s, ok := strings.Cut(codeBlockToImageCmd, ":"); ok && s != "" {
+		styleName = s
+	}
+	style, ok := styles.Registry[styleName]
+	if !ok {
+		return nil, fmt.Errorf("unknown style of the builtin code block renderer: %q", styleName)
+	}
+	faces, err := loadBuiltinFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	lexer := lexers.Get(codeBlock.Language)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	content := strings.TrimRight(strings.ReplaceAll(codeBlock.Content, "\t", strings.Repeat(" ", builtinCodeImageTabSize)), "\n")
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, content+"\n")
+	if err != nil {
+		return nil, fmt.Errorf("failed to tokenize code block: %w", err)
+	}
+	lines := chroma.SplitTokensIntoLines(it.Tokens())
+
+	metrics := faces.regular.Metrics()
+	lineHeight := metrics.Height.Ceil()
+	// The gutter of line numbers is as wide as the largest line number followed by two spaces.
+	gutter := 0
+	lineNumberFormat := fmt.Sprintf("%%%dd", len(strconv.Itoa(len(lines))))
+	if codeBlock.LineNumbers {
+		gutter = font.MeasureString(faces.regular, fmt.Sprintf(lineNumberFormat, len(lines))+"  ").Ceil()
+	}
+	width := 0
+	for _, line := range lines {
+		var w fixed.Int26_6
+		for _, t := range line {
+			w += font.MeasureString(faces.face(style.Get(t.Type)), strings.TrimRight(t.Value, "\n"))
+		}
+		width = max(width, w.Ceil())
+	}
+	img := image.NewRGBA(image.Rect(0, 0, gutter+width+2*builtinCodeImagePadding, lineHeight*len(lines)+2*builtinCodeImagePadding))
+	bg := style.Get(chroma.Background)
+	draw.Draw(img, img.Bounds(), image.NewUniform(toColor(bg.Background, color.White)), image.Point{}, draw.Src)
+	fg := toColor(bg.Colour, color.Black)
+	highlight := image.NewUniform(toColor(style.Get(chroma.LineHighlight).Background, builtinCodeImageHighlightColor))
+	lineNumberColor := image.NewUniform(toColor(style.Get(chroma.LineNumbers).Colour, builtinCodeImageLineNumberColor))
+
+	for i, line := range lines {
+		top := builtinCodeImagePadding + i*lineHeight
+		if slices.Contains(codeBlock.HighlightLines, i+1) {
+			draw.Draw(img, image.Rect(0, top, img.Bounds().Dx(), top+lineHeight), highlight, image.Point{}, draw.Src)
+		}
+		if codeBlock.LineNumbers {
+			d := &font.Drawer{
+				Dst:  img,
+				Src:  lineNumberColor,
+				Face: faces.regular,
+				Dot:  fixed.P(builtinCodeImagePadding, top+metrics.Ascent.Ceil()),
+			}
+			d.DrawString(fmt.Sprintf(lineNumberFormat, i+1))
+		}
+		d := &font.Drawer{
+			Dst: img,
+			Dot: fixed.P(builtinCodeImagePadding+gutter, top+metrics.Ascent.Ceil()),
+		}
+		for _, t := range line {
+			e := style.Get(t.Type)
+			d.Face = faces.face(e)
+			d.Src = image.NewUniform(toColor(e.Colour, fg))
+			d.DrawString(strings.TrimRight(t.Value, "\n"))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode code block image: %w", err)
+	}
+	return deck.NewImageFromCodeBlock(&buf)
+}
+
+// toColor converts the chroma colour to a color, or returns def if it is not set.
+func toColor(c chroma.Colour, def color.Color) color.Color {
+	if !c.IsSet() {
+		return def
+	}
+	return color.RGBA{R: c.Red(), G: c.Green(), B: c.Blue(), A: 0xff}
+}
@@ -0,0 +1,104 @@
+package md
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+func TestGenBuiltinCodeImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     string
+		block   *CodeBlock
+		wantErr bool
+	}{
+		{"go", "builtin", &CodeBlock{Language: "go", Content: "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"}, false},
+		{"unknown language", "builtin", &CodeBlock{Language: "unknown", Content: "hello\n"}, false},
+		{"no language", "builtin", &CodeBlock{Content: "hello\n"}, false},
+		{"style", "builtin:monokai", &CodeBlock{Language: "go", Content: "package main\n"}, false},
+		{"unknown style", "builtin:unknown", &CodeBlock{Language: "go", Content: "package main\n"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := genBuiltinCodeImage(tt.cmd, tt.block)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("genBuiltinCodeImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			img, err := got.Image()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.Bounds().Dx() <= 2*builtinCodeImagePadding || img.Bounds().Dy() <= 2*builtinCodeImagePadding {
+				t.Errorf("the image is empty: %v", img.Bounds())
+			}
+		})
+	}
+}
+
+func TestGenBuiltinCodeImageLines(t *testing.T) {
+	content := "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"
+	plain, err := genBuiltinCodeImage("builtin", &CodeBlock{Language: "go", Content: content})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := plain.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	numbered, err := genBuiltinCodeImage("builtin", &CodeBlock{Language: "go", Content: content, LineNumbers: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := numbered.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() <= base.Bounds().Dx() || img.Bounds().Dy() != base.Bounds().Dy() {
+		t.Errorf("got %v, want wider than %v for the gutter of line numbers", img.Bounds(), base.Bounds())
+	}
+
+	highlighted, err := genBuiltinCodeImage("builtin", &CodeBlock{Language: "go", Content: content, HighlightLines: []int{2, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err = highlighted.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != base.Bounds() {
+		t.Errorf("got %v, want the same size as %v", img.Bounds(), base.Bounds())
+	}
+	lineHeight := (img.Bounds().Dy() - 2*builtinCodeImagePadding) / 5
+	want := toColor(styles.Registry[builtinCodeImageStyle].Get(chroma.LineHighlight).Background, builtinCodeImageHighlightColor)
+	for line, highlighted := range map[int]bool{1: false, 2: true, 3: false, 4: true, 5: false} {
+		y := builtinCodeImagePadding + (line-1)*lineHeight + lineHeight/2
+		got := color.RGBAModel.Convert(img.At(1, y))
+		if (got == color.RGBAModel.Convert(want)) != highlighted {
+			t.Errorf("line %d: got color %v, want highlighted %v", line, got, highlighted)
+		}
+	}
+}
+
+func TestIsBuiltinCodeImageCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want bool
+	}{
+		{"builtin", true},
+		{"builtin:monokai", true},
+		{"builtins", false},
+		{"silicon --output {{output}}", false},
+	}
+	for _, tt := range tests {
+		if got := isBuiltinCodeImageCommand(tt.cmd); got != tt.want {
+			t.Errorf("isBuiltinCodeImageCommand(%q) = %v, want %v", tt.cmd, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package md
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+)
+
+// ToSlidesOption is an option for ToSlides.
+type ToSlidesOption func(*toSlidesOptions)
+
+type toSlidesOptions struct {
+	codeImageCacheDir string
+}
+
+// DefaultCodeImageCacheDir returns the default directory to cache the images generated from code blocks.
+func DefaultCodeImageCacheDir() string {
+	return filepath.Join(config.CacheHomePath(), "code-images")
+}
+
+// WithCodeImageCacheDir caches the images generated from code blocks in dir, and reuses them instead of
+// running the command again for code blocks with the same command, language and content.
+// An empty dir disables the cache.
+func WithCodeImageCacheDir(dir string) ToSlidesOption {
+	return func(o *toSlidesOptions) {
+		o.codeImageCacheDir = dir
+	}
+}
+
+// codeImageCacheKey returns the key of the image generated from the code block by the command.
+func codeImageCacheKey(codeBlockToImageCmd string, codeBlock *CodeBlock) string {
+	h := sha256.New()
+	for _, s := range []string{codeBlockToImageCmd, codeBlock.Language, codeBlock.Content} {
+		// Write the length first so that the boundaries of the values are not ambiguous.
+		fmt.Fprintf(h, "%d:%s", len(s), s)
+	}
+	if len(codeBlock.HighlightLines) > 0 || codeBlock.LineNumbers {
+		// Written only with the attributes so that the keys of the images cached without them do not change.
+		fmt.Fprintf(h, "highlight:%s,lines:%t", formatLineRanges(codeBlock.HighlightLines), codeBlock.LineNumbers)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// genCachedCodeImage returns the image generated from the code block, reusing the cached image in cacheDir if any.
+// Since the reused image has the same content as the image uploaded by the last apply, it is not uploaded again.
+func genCachedCodeImage(ctx context.Context, codeBlockToImageCmd, shell string, codeBlock *CodeBlock, cacheDir string) (
+	*deck.Image, error) {
+
+	if cacheDir == "" || isBuiltinCodeImageCommand(codeBlockToImageCmd) {
+		// The built-in renderer is fast enough, and its images may change with upgrades of deck.
+		return genCodeImage(ctx, codeBlockToImageCmd, shell, codeBlock)
+	}
+	p := filepath.Join(cacheDir, codeImageCacheKey(codeBlockToImageCmd, codeBlock)+".img")
+	if b, err := os.ReadFile(p); err == nil {
+		if image, err := deck.NewImageFromCodeBlock(bytes.NewReader(b)); err == nil {
+			return image, nil
+		}
+		// Regenerate the broken cache.
+	}
+	image, err := genCodeImage(ctx, codeBlockToImageCmd, shell, codeBlock)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeCodeImageCache(p, image.Bytes()); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// writeCodeImageCache writes b to p atomically, so that concurrent applies never read a partial image.
+func writeCodeImageCache(p string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return fmt.Errorf("failed to create code image cache directory: %w", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create code image cache: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write code image cache: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write code image cache: %w", err)
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return fmt.Errorf("failed to write code image cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package md
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCodeImageCache(t *testing.T) {
+	ctx := t.Context()
+	png, err := filepath.Abs("../testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter := filepath.Join(t.TempDir(), "count")
+	cmd := "cp " + png + " {{output}} && echo run >> " + counter
+	cacheDir := t.TempDir()
+	newMD := func(content string) *MD {
+		return &MD{Contents: Contents{{CodeBlocks: []*CodeBlock{{Language: "go", Content: content}}}}}
+	}
+	runs := func() int {
+		b, err := os.ReadFile(counter)
+		if err != nil {
+			return 0
+		}
+		return bytes.Count(b, []byte("run"))
+	}
+
+	for range 2 {
+		ss, err := newMD("fmt.Println(1)").ToSlides(ctx, cmd, WithCodeImageCacheDir(cacheDir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ss[0].Images) != 1 {
+			t.Fatalf("got %d images, want 1", len(ss[0].Images))
+		}
+	}
+	if got := runs(); got != 1 {
+		t.Errorf("got %d runs of the command, want 1", got)
+	}
+
+	if _, err := newMD("fmt.Println(2)").ToSlides(ctx, cmd, WithCodeImageCacheDir(cacheDir)); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs(); got != 2 {
+		t.Errorf("got %d runs of the command after changing the content, want 2", got)
+	}
+
+	if _, err := newMD("fmt.Println(2)").ToSlides(ctx, cmd); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs(); got != 3 {
+		t.Errorf("got %d runs of the command without cache, want 3", got)
+	}
+}
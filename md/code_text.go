@@ -0,0 +1,79 @@
+package md
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/k1LoW/deck"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Code block modes. With `codeBlock: text` in the frontmatter, or the comment directive `<!-- code: text -->`
+// before a code block, the code block is inserted into the body as monospace text instead of an image,
+// which can be copied and read by screen readers.
+const (
+	codeBlockImage = "image" // convert code blocks to images with codeBlockToImageCommand (default)
+	codeBlockText  = "text"  // insert code blocks into the body as monospace text
+
+	codeBlockAsAttribute = "deck-code-as"
+)
+
+var codeDirectiveReg = regexp.MustCompile(`^code:\s*(\S+)$`)
+
+// validateCodeBlockMode returns an error if mode is not a valid code block mode.
+func validateCodeBlockMode(mode string) error {
+	switch mode {
+	case "", codeBlockImage, codeBlockText:
+		return nil
+	default:
+		return fmt.Errorf("invalid codeBlock: %q (must be %q or %q)", mode, codeBlockImage, codeBlockText)
+	}
+}
+
+// parseCodeDirective returns the value of the code directive in the comment block, if any.
+func parseCodeDirective(block string) (string, bool) {
+	m := codeDirectiveReg.FindStringSubmatch(block)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// markCodeBlock marks the code block following the comment node n to be rendered in the mode.
+// It returns false if n is not followed by a code block.
+func markCodeBlock(n ast.Node, mode string) bool {
+	next := n.NextSibling()
+	switch next.(type) {
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		next.SetAttributeString(codeBlockAsAttribute, []byte(mode))
+		return true
+	default:
+		return false
+	}
+}
+
+// codeBlockMode returns the mode of the code block node, or def if it is not marked.
+func codeBlockMode(n ast.Node, def string) string {
+	v, ok := n.AttributeString(codeBlockAsAttribute)
+	if !ok {
+		return def
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		return def
+	}
+	return string(b)
+}
+
+// codeTextParagraph renders the code block as a paragraph of a code fragment.
+// Line breaks are kept as line breaks within the paragraph.
+func codeTextParagraph(codeBlock *CodeBlock) *deck.Paragraph {
+	return &deck.Paragraph{
+		Fragments: []*deck.Fragment{{
+			Value: strings.TrimRight(codeBlock.Content, "\n"),
+			Code:  true,
+		}},
+		Bullet: deck.BulletNone,
+	}
+}
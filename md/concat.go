@@ -0,0 +1,48 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/errors"
+)
+
+// Source is the markdown of a file to parse with ParseSources.
+type Source struct {
+	Name    string // name of the file in errors
+	BaseDir string // directory to resolve relative paths such as images against
+	B       []byte // markdown
+	// Whether the markdown is untrusted, such as the markdown read from stdin or a URL (see WithUntrusted)
+	Untrusted bool
+}
+
+// ParseSources parses the markdown of multiple files as one deck, concatenating their pages in order.
+// The frontmatter of each file applies to its own pages (e.g. defaults and layoutRules), and the frontmatter
+// of the first file is the frontmatter of the deck (e.g. presentationID, title and sectionLevel).
+// Sections and agendas, and keys of pages are resolved across all the files.
+func ParseSources(srcs []Source, cfg *config.Config, opts ...ParseOption) (_ *MD, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	o := &parseOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	merged := &MD{}
+	for i, src := range srcs {
+		so := *o
+		so.untrusted = o.untrusted || src.Untrusted
+		md, err := parse(src.BaseDir, src.B, cfg, &so)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", src.Name, err)
+		}
+		if i == 0 {
+			merged.Frontmatter = md.Frontmatter
+		}
+		merged.Contents = append(merged.Contents, md.Contents...)
+	}
+	if err := merged.resolveDeck(); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
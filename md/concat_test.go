@@ -0,0 +1,81 @@
+package md
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSources(t *testing.T) {
+	intro := Source{Name: "intro.md", BaseDir: "/slides", B: []byte(`---
+presentationID: xxxxx
+sectionLevel: 1
+---
+
+# Intro
+
+---
+
+<!-- {"agenda": true} -->
+
+# Agenda
+`)}
+	chapter := Source{Name: "chapters/one.md", BaseDir: "/slides/chapters", B: []byte(`---
+defaults:
+  - if: page == 1
+    layout: section
+---
+
+# Chapter 1
+
+---
+
+## Details
+`)}
+	closing := Source{Name: "closing.md", BaseDir: "/slides", B: []byte("# Closing\n")}
+
+	md, err := ParseSources([]Source{intro, chapter, closing}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := md.Frontmatter.PresentationID; got != "xxxxx" {
+		t.Errorf("got presentationID %q, want the one of the first file", got)
+	}
+	var titles, layouts []string
+	for _, c := range md.Contents {
+		titles = append(titles, strings.Join(c.Titles, ""))
+		layouts = append(layouts, c.Layout)
+	}
+	if diff := cmp.Diff([]string{"Intro", "Agenda", "Chapter 1", "Details", "Closing"}, titles); diff != "" {
+		t.Errorf("pages are not concatenated in order (-want +got):\n%s", diff)
+	}
+	// The frontmatter of each file applies to its own pages.
+	if diff := cmp.Diff([]string{"", "", "section", "", ""}, layouts); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	// Sections are resolved across the files.
+	agenda := md.Contents[1].Bodies
+	if len(agenda) == 0 {
+		t.Fatal("agenda is not rendered")
+	}
+	var sections []string
+	for _, p := range agenda[len(agenda)-1].Paragraphs {
+		sections = append(sections, p.Fragments[0].Value)
+	}
+	if diff := cmp.Diff([]string{"Intro (p.1)", "Chapter 1 (p.3)", "Closing (p.5)"}, sections); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestParseSourcesDuplicateKeys(t *testing.T) {
+	a := Source{Name: "a.md", B: []byte("<!-- {\"key\": \"k1\"} -->\n\n# A\n")}
+	b := Source{Name: "b.md", B: []byte("<!-- {\"key\": \"k1\"} -->\n\n# B\n")}
+	if _, err := ParseSources([]Source{a, b}, nil); err == nil || !strings.Contains(err.Error(), `duplicate page key "k1" at pages 1 and 2`) {
+		t.Errorf("got error %v, want duplicate page key across files", err)
+	}
+	bad := Source{Name: "bad.md", B: []byte("---\nautofit: wrong\n---\n# Bad\n")}
+	if _, err := ParseSources([]Source{a, bad}, nil); err == nil || !strings.Contains(err.Error(), "failed to parse bad.md") {
+		t.Errorf("got error %v, want the name of the file", err)
+	}
+}
@@ -0,0 +1,116 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+)
+
+// Definition list modes.
+const (
+	definitionListParagraphs = "paragraphs" // render terms as bold paragraphs followed by indented definitions (default)
+	definitionListTable      = "table"      // render terms and definitions as a two-column table
+)
+
+// definition is a term of a definition list and its definitions.
+type definition struct {
+	term         []*deck.Fragment
+	descriptions [][]*deck.Fragment
+}
+
+// validateDefinitionListMode returns an error if mode is not a valid definition list mode.
+func validateDefinitionListMode(mode string) error {
+	switch mode {
+	case "", definitionListParagraphs, definitionListTable:
+		return nil
+	default:
+		return fmt.Errorf("invalid definitionList: %q (must be %q or %q)", mode, definitionListParagraphs, definitionListTable)
+	}
+}
+
+// parseDefinitionList parses the terms and definitions of a definition list.
+// Each block of a definition is a definition of its own.
+func parseDefinitionList(n *east.DefinitionList, baseDir string, b []byte, breaks bool) ([]*definition, []*deck.Image, error) {
+	var (
+		defs   []*definition
+		images []*deck.Image
+	)
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch v := c.(type) {
+		case *east.DefinitionTerm:
+			frags, imgs, err := toFragments(baseDir, b, v, deck.Fragment{Bold: true})
+			if err != nil {
+				return nil, nil, err
+			}
+			images = append(images, imgs...)
+			term := toDeckFragments(frags, breaks)
+			for _, f := range term {
+				f.Bold = true
+			}
+			defs = append(defs, &definition{term: term})
+		case *east.DefinitionDescription:
+			if len(defs) == 0 {
+				continue
+			}
+			d := defs[len(defs)-1]
+			for block := v.FirstChild(); block != nil; block = block.NextSibling() {
+				if block.Kind() != ast.KindParagraph && block.Kind() != ast.KindTextBlock {
+					continue
+				}
+				frags, imgs, err := toFragments(baseDir, b, block, deck.Fragment{})
+				if err != nil {
+					return nil, nil, err
+				}
+				images = append(images, imgs...)
+				if len(frags) > 0 {
+					d.descriptions = append(d.descriptions, toDeckFragments(frags, breaks))
+				}
+			}
+		}
+	}
+	return defs, images, nil
+}
+
+// definitionParagraphs renders the definitions as bold terms followed by definitions indented with a tab.
+func definitionParagraphs(defs []*definition) []*deck.Paragraph {
+	var paragraphs []*deck.Paragraph
+	for _, d := range defs {
+		paragraphs = append(paragraphs, &deck.Paragraph{
+			Fragments: d.term,
+			Bullet:    deck.BulletNone,
+		})
+		for _, desc := range d.descriptions {
+			first := *desc[0]
+			first.Value = "\t" + first.Value
+			paragraphs = append(paragraphs, &deck.Paragraph{
+				Fragments: append([]*deck.Fragment{&first}, desc[1:]...),
+				Bullet:    deck.BulletNone,
+			})
+		}
+	}
+	return paragraphs
+}
+
+// definitionTable renders the definitions as a table with terms in the first column.
+// Multiple definitions of a term are separated by line breaks in the cell.
+func definitionTable(defs []*definition) *deck.Table {
+	t := &deck.Table{}
+	for _, d := range defs {
+		desc := []*deck.Fragment{}
+		for i, frags := range d.descriptions {
+			if i > 0 {
+				desc = append(desc, &deck.Fragment{Value: "\n"})
+			}
+			desc = append(desc, frags...)
+		}
+		t.Rows = append(t.Rows, &deck.TableRow{
+			Cells: []*deck.TableCell{
+				{Fragments: d.term, Alignment: "START"},
+				{Fragments: desc, Alignment: "START"},
+			},
+		})
+	}
+	return t
+}
@@ -0,0 +1,68 @@
+package md
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// `<details>` blocks are presenter-only content. They are removed from the page and their contents are
+// added to the speaker notes, so that the same markdown can be used for both an article and a deck.
+
+var (
+	detailsOpenReg  = regexp.MustCompile(`(?i)<details(?:\s[^>]*)?>`)
+	detailsCloseReg = regexp.MustCompile(`(?i)</details\s*>`)
+	summaryReg      = regexp.MustCompile(`(?is)<summary(?:\s[^>]*)?>(.*?)</summary\s*>`)
+	blankLinesReg   = regexp.MustCompile(`\n{3,}`)
+)
+
+// extractDetails removes `<details>` blocks outside code blocks from b and returns the contents of the blocks.
+// The text of `<summary>` is kept as the first line of the contents.
+func extractDetails(b []byte) ([]byte, []string) {
+	var (
+		out     [][]byte
+		block   [][]byte
+		notes   []string
+		depth   int
+		inFence bool
+	)
+	for line := range bytes.SplitSeq(b, []byte("\n")) {
+		if depth == 0 {
+			if isFenceLine(line) {
+				inFence = !inFence
+			}
+			if inFence || !bytes.HasPrefix(bytes.TrimSpace(line), []byte("<details")) || detailsOpenReg.Find(line) == nil {
+				out = append(out, line)
+				continue
+			}
+		}
+		block = append(block, line)
+		depth += len(detailsOpenReg.FindAll(line, -1)) - len(detailsCloseReg.FindAll(line, -1))
+		if depth <= 0 {
+			if note := detailsNote(bytes.Join(block, []byte("\n"))); note != "" {
+				notes = append(notes, note)
+			}
+			block, depth = nil, 0
+		}
+	}
+	if len(block) > 0 {
+		// Unclosed <details> is left as is.
+		out = append(out, block...)
+	}
+	return bytes.Join(out, []byte("\n")), notes
+}
+
+// detailsNote returns the contents of the `<details>` block b without the outermost tags.
+func detailsNote(b []byte) string {
+	s := string(b)
+	if loc := detailsOpenReg.FindStringIndex(s); loc != nil {
+		s = s[loc[1]:]
+	}
+	if locs := detailsCloseReg.FindAllStringIndex(s, -1); len(locs) > 0 {
+		s = s[:locs[len(locs)-1][0]]
+	}
+	s = summaryReg.ReplaceAllStringFunc(s, func(m string) string {
+		return strings.TrimSpace(summaryReg.FindStringSubmatch(m)[1]) + "\n\n"
+	})
+	return strings.TrimSpace(blankLinesReg.ReplaceAllString(s, "\n\n"))
+}
@@ -0,0 +1,27 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+)
+
+// resolveDirection validates the text direction of the pages and sets the direction in the frontmatter to pages without direction.
+func (md *MD) resolveDirection() error {
+	var direction string
+	if md.Frontmatter != nil {
+		direction = md.Frontmatter.Direction
+	}
+	if err := deck.ValidateDirection(direction); err != nil {
+		return err
+	}
+	for i, content := range md.Contents {
+		if err := deck.ValidateDirection(content.Direction); err != nil {
+			return fmt.Errorf("page %d: %w", i+1, err)
+		}
+		if content.Direction == "" {
+			content.Direction = direction
+		}
+	}
+	return nil
+}
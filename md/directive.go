@@ -0,0 +1,128 @@
+package md
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Heading directives are lightweight page configurations written as heading attributes,
+// e.g. `# Title {.skip}` or `# Title {layout=section}`.
+// Only braces of the directives are taken as attributes, so that titles ending in other braces such as
+// `# Go struct {}` or `# Map {a=1}` are kept as they are.
+const (
+	directiveSkip   = "skip"
+	directiveFreeze = "freeze"
+	directiveIgnore = "ignore"
+	directiveLayout = "layout"
+)
+
+var (
+	// headingDirectivesReg matches the heading directives at the end of a heading, e.g. ` {.skip layout=section}`.
+	headingDirectivesReg = regexp.MustCompile(`\s*\{\s*((?:(?:\.(?:skip|freeze|ignore)|layout\s*=\s*(?:"[^"]*"|[^\s"{}]+))\s*)+)\}$`)
+	headingDirectiveReg  = regexp.MustCompile(`\.(skip|freeze|ignore)|layout\s*=\s*(?:"([^"]*)"|([^\s"{}]+))`)
+)
+
+// headingDirectiveExtension is a goldmark extension taking the heading directives as attributes of headings.
+type headingDirectiveExtension struct{}
+
+// Extend implements goldmark.Extender.
+func (e *headingDirectiveExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&headingDirectiveTransformer{}, 100),
+	))
+}
+
+// headingDirectiveTransformer moves the heading directives at the end of headings to their attributes.
+type headingDirectiveTransformer struct{}
+
+// Transform implements parser.ASTTransformer.
+func (t *headingDirectiveTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	src := reader.Source()
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		last, ok := h.LastChild().(*ast.Text)
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+		v := last.Segment.Value(src)
+		m := headingDirectivesReg.FindSubmatchIndex(v)
+		if m == nil {
+			return ast.WalkSkipChildren, nil
+		}
+		var classes []string
+		for _, d := range headingDirectiveReg.FindAllSubmatch(v[m[2]:m[3]], -1) {
+			switch {
+			case len(d[1]) > 0:
+				classes = append(classes, string(d[1]))
+			case d[2] != nil:
+				h.SetAttributeString(directiveLayout, d[2])
+			default:
+				h.SetAttributeString(directiveLayout, d[3])
+			}
+		}
+		if len(classes) > 0 {
+			h.SetAttributeString("class", []byte(strings.Join(classes, " ")))
+		}
+		last.Segment = text.NewSegment(last.Segment.Start, last.Segment.Start+m[0])
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// addHeadingDirectives adds the page configuration given by the attributes of heading h to the content.
+func (c *Content) addHeadingDirectives(h *ast.Heading) {
+	if h.Attributes() == nil {
+		return
+	}
+	if c.directives == nil {
+		c.directives = &Config{}
+	}
+	if v, ok := h.AttributeString("class"); ok {
+		if b, ok := v.([]byte); ok {
+			for class := range strings.FieldsSeq(string(b)) {
+				switch class {
+				case directiveSkip:
+					c.directives.Skip = new(true)
+				case directiveFreeze:
+					c.directives.Freeze = new(FreezeContents)
+				case directiveIgnore:
+					c.directives.Ignore = new(true)
+				}
+			}
+		}
+	}
+	if v, ok := h.AttributeString(directiveLayout); ok {
+		if b, ok := v.([]byte); ok {
+			c.directives.Layout = string(b)
+		}
+	}
+}
+
+// applyDirectives applies heading directives to the content.
+// Page configuration written in JSON comments takes precedence over heading directives.
+func (c *Content) applyDirectives() {
+	d := c.directives
+	if d == nil {
+		return
+	}
+	if c.Layout == "" {
+		c.Layout = d.Layout
+	}
+	if c.Freeze == nil {
+		c.Freeze = d.Freeze
+	}
+	if c.Ignore == nil {
+		c.Ignore = d.Ignore
+	}
+	if c.Skip == nil {
+		c.Skip = d.Skip
+	}
+}
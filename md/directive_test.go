@@ -0,0 +1,42 @@
+package md
+
+import (
+	"testing"
+)
+
+func TestHeadingDirectives(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantTitle  string
+		wantLayout string
+		wantSkip   bool
+	}{
+		{"# Work in progress {.skip}", "Work in progress", "", true},
+		{"# Chapter 1 {layout=section}", "Chapter 1", "section", false},
+		{`# Chapter 2 {.skip layout="title and body"}`, "Chapter 2", "title and body", true},
+		{"# Go struct {}", "Go struct {}", "", false},
+		{"# Map {a=1}", "Map {a=1}", "", false},
+		{"# Heading {#id}", "Heading {#id}", "", false},
+		{"# Classes {.lead}", "Classes {.lead}", "", false},
+		{"# Mixed {.skip a=1}", "Mixed {.skip a=1}", "", false},
+		{"# **Bold** {.skip}", "Bold", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			m, err := Parse(".", []byte(tt.in+"\n"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := m.Contents[0]
+			if len(c.Titles) != 1 || c.Titles[0] != tt.wantTitle {
+				t.Errorf("got titles %q, want %q", c.Titles, tt.wantTitle)
+			}
+			if c.Layout != tt.wantLayout {
+				t.Errorf("got layout %q, want %q", c.Layout, tt.wantLayout)
+			}
+			if got := c.Skip != nil && *c.Skip; got != tt.wantSkip {
+				t.Errorf("got skip %v, want %v", got, tt.wantSkip)
+			}
+		})
+	}
+}
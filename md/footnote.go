@@ -0,0 +1,215 @@
+package md
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/deck"
+)
+
+// Footnote modes.
+const (
+	footnotesNotes = "notes" // append footnotes to the speaker notes of the page (default)
+	footnotesSlide = "slide" // collect footnotes into a slide at the end
+)
+
+const defaultFootnotesTitle = "References"
+
+var (
+	footnoteDefReg = regexp.MustCompile(`^\[\^([^\]\s]+)\]:[ \t]?(.*)$`)
+	footnoteRefReg = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+)
+
+// footnotes holds the footnote definitions of a deck.
+type footnotes struct {
+	defs    map[string][]byte // source of the definition per label
+	numbers map[string]int    // number per label in the order of the first reference
+	labels  []string          // labels in the order of numbers
+	pages   [][]int           // numbers referenced per page in the order of appearance
+}
+
+// extractFootnotes removes footnote definitions from the pages, numbers the footnotes in the order of
+// their first reference, and replaces the references with `<sup>[N]</sup>`.
+// Footnote definitions can be written on any page.
+func extractFootnotes(bpages [][]byte) ([][]byte, *footnotes) {
+	fn := &footnotes{
+		defs:    map[string][]byte{},
+		numbers: map[string]int{},
+	}
+	pages := make([][]byte, len(bpages))
+	for i, bpage := range bpages {
+		pages[i] = fn.extractDefs(bpage)
+	}
+	if len(fn.defs) == 0 {
+		return bpages, fn
+	}
+	fn.pages = make([][]int, len(pages))
+	for i, bpage := range pages {
+		pages[i], fn.pages[i] = fn.replaceRefs(bpage)
+	}
+	return pages, fn
+}
+
+// extractDefs removes footnote definitions from b and stores them.
+func (fn *footnotes) extractDefs(b []byte) []byte {
+	var (
+		out     [][]byte
+		label   string
+		inFence bool
+	)
+	for line := range bytes.SplitSeq(b, []byte("\n")) {
+		if isFenceLine(line) {
+			inFence = !inFence
+		}
+		if !inFence {
+			if m := footnoteDefReg.FindSubmatch(line); m != nil {
+				label = string(m[1])
+				fn.defs[label] = append([]byte{}, m[2]...)
+				continue
+			}
+			if label != "" {
+				// Continuation lines of a definition are indented, blank lines are kept in the definition.
+				if len(bytes.TrimSpace(line)) == 0 || line[0] == ' ' || line[0] == '\t' {
+					fn.defs[label] = append(append(fn.defs[label], '\n'), bytes.TrimSpace(line)...)
+					continue
+				}
+				label = ""
+			}
+		}
+		out = append(out, line)
+	}
+	return bytes.Join(out, []byte("\n"))
+}
+
+// replaceRefs replaces references to defined footnotes in b, except in code.
+// It returns the replaced source and the referenced numbers.
+func (fn *footnotes) replaceRefs(b []byte) ([]byte, []int) {
+	var numbers []int
+	lines := bytes.Split(b, []byte("\n"))
+	inFence := false
+	for i, line := range lines {
+		if isFenceLine(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		// Segments at odd indices are in code spans.
+		segments := strings.Split(string(line), "`")
+		for j := 0; j < len(segments); j += 2 {
+			segments[j] = footnoteRefReg.ReplaceAllStringFunc(segments[j], func(ref string) string {
+				label := footnoteRefReg.FindStringSubmatch(ref)[1]
+				if _, ok := fn.defs[label]; !ok {
+					return ref
+				}
+				n, ok := fn.numbers[label]
+				if !ok {
+					fn.labels = append(fn.labels, label)
+					n = len(fn.labels)
+					fn.numbers[label] = n
+				}
+				if !slices.Contains(numbers, n) {
+					numbers = append(numbers, n)
+				}
+				return fmt.Sprintf("<sup>[%d]</sup>", n)
+			})
+		}
+		lines[i] = []byte(strings.Join(segments, "`"))
+	}
+	return bytes.Join(lines, []byte("\n")), numbers
+}
+
+// paragraph returns the footnote numbered n as a paragraph such as "[1] text".
+func (fn *footnotes) paragraph(baseDir string, n int, breaks bool) (*deck.Paragraph, error) {
+	label := fn.labels[n-1]
+	c, err := ParseContent(baseDir, fn.defs[label], breaks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse footnote %q: %w", label, err)
+	}
+	p := &deck.Paragraph{
+		Fragments: []*deck.Fragment{{Value: fmt.Sprintf("[%d] ", n)}},
+	}
+	for _, body := range c.Bodies {
+		for i, bp := range body.Paragraphs {
+			if i > 0 {
+				p.Fragments = append(p.Fragments, &deck.Fragment{Value: " "})
+			}
+			p.Fragments = append(p.Fragments, bp.Fragments...)
+		}
+	}
+	return p, nil
+}
+
+// footnoteText returns the plain text of the footnote paragraph p for speaker notes.
+// Since speaker notes are plain text, the URLs of links are written after the link texts.
+func footnoteText(p *deck.Paragraph) string {
+	var b strings.Builder
+	for _, f := range p.Fragments {
+		b.WriteString(f.Value)
+		if f.Link != "" && f.Link != f.Value {
+			fmt.Fprintf(&b, " (%s)", f.Link)
+		}
+	}
+	return b.String()
+}
+
+func isFenceLine(line []byte) bool {
+	trimmed := bytes.TrimLeft(line, " ")
+	return bytes.HasPrefix(trimmed, []byte("```")) || bytes.HasPrefix(trimmed, []byte("~~~"))
+}
+
+// resolveFootnotes adds the footnotes to the speaker notes of the pages referencing them,
+// or appends a page listing all footnotes, depending on the footnotes mode.
+func (md *MD) resolveFootnotes(baseDir string, fn *footnotes, breaks bool) error {
+	if len(fn.labels) == 0 {
+		return nil
+	}
+	mode := footnotesNotes
+	title := defaultFootnotesTitle
+	if md.Frontmatter != nil {
+		if md.Frontmatter.Footnotes != "" {
+			mode = md.Frontmatter.Footnotes
+		}
+		if md.Frontmatter.FootnotesTitle != "" {
+			title = md.Frontmatter.FootnotesTitle
+		}
+	}
+	switch mode {
+	case footnotesNotes:
+		for i, content := range md.Contents {
+			var lines []string
+			for _, n := range fn.pages[i] {
+				p, err := fn.paragraph(baseDir, n, breaks)
+				if err != nil {
+					return err
+				}
+				lines = append(lines, footnoteText(p))
+			}
+			if len(lines) > 0 {
+				content.Comments = append(content.Comments, strings.Join(lines, "\n"))
+			}
+		}
+	case footnotesSlide:
+		body := &deck.Body{}
+		for n := 1; n <= len(fn.labels); n++ {
+			p, err := fn.paragraph(baseDir, n, breaks)
+			if err != nil {
+				return err
+			}
+			body.Paragraphs = append(body.Paragraphs, p)
+		}
+		md.Contents = append(md.Contents, &Content{
+			Titles:      []string{title},
+			TitleBodies: []*deck.Body{{Paragraphs: []*deck.Paragraph{{Fragments: []*deck.Fragment{{Value: title}}}}}},
+			Bodies:      []*deck.Body{body},
+			Headings:    map[int][]string{1: {title}},
+		})
+	default:
+		return fmt.Errorf("invalid footnotes: %q (must be %q or %q)", mode, footnotesNotes, footnotesSlide)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package md
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Freeze is the freeze setting of a page, written as true, false or "position".
+type Freeze int
+
+const (
+	FreezeNone     Freeze = iota // false: the page is applied
+	FreezeContents               // true: the contents of the page are not modified, but the page can be moved
+	FreezePosition               // "position": the page is neither modified nor moved
+)
+
+const freezePosition = "position"
+
+// frozen reports whether the page is frozen. f may be nil.
+func (f *Freeze) frozen() bool {
+	return f != nil && *f != FreezeNone
+}
+
+// freezeFromBool returns the freeze setting of b. b may be nil.
+func freezeFromBool(b *bool) *Freeze {
+	if b == nil {
+		return nil
+	}
+	if *b {
+		return new(FreezeContents)
+	}
+	return new(FreezeNone)
+}
+
+func (f Freeze) MarshalJSON() ([]byte, error) {
+	if f == FreezePosition {
+		return json.Marshal(freezePosition)
+	}
+	return json.Marshal(f != FreezeNone)
+}
+
+func (f *Freeze) UnmarshalJSON(b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return f.set(v)
+}
+
+func (f *Freeze) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return f.set(v)
+}
+
+func (f *Freeze) set(v any) error {
+	switch v {
+	case true:
+		*f = FreezeContents
+	case false:
+		*f = FreezeNone
+	case freezePosition:
+		*f = FreezePosition
+	default:
+		return fmt.Errorf("invalid freeze: %v (must be true, false or %q)", v, freezePosition)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package md
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+)
+
+func TestFreeze(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Freeze
+		wantErr bool
+	}{
+		{"true", FreezeContents, false},
+		{"false", FreezeNone, false},
+		{`"position"`, FreezePosition, false},
+		{`"contents"`, FreezeNone, true},
+		{"1", FreezeNone, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var got Freeze
+			if err := json.Unmarshal([]byte(tt.in), &got); (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v from JSON, want %v", got, tt.want)
+			}
+			got = FreezeNone
+			if err := yaml.Unmarshal([]byte(tt.in), &got); (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v from YAML, want %v", got, tt.want)
+			}
+			if tt.wantErr {
+				return
+			}
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.in {
+				t.Errorf("got %s, want %s", b, tt.in)
+			}
+		})
+	}
+}
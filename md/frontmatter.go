@@ -96,12 +96,39 @@ func (fm *Frontmatter) applyConfig(cfg *config.Config) *Frontmatter {
 	if fm.CodeBlockToImageCommand == "" {
 		fm.CodeBlockToImageCommand = cfg.CodeBlockToImageCommand
 	}
+	if fm.CodeBlock == "" {
+		fm.CodeBlock = cfg.CodeBlock
+	}
+	if fm.Shell == "" {
+		fm.Shell = cfg.Shell
+	}
+	if fm.HighlightColor == "" {
+		fm.HighlightColor = cfg.HighlightColor
+	}
+	if fm.SectionLevel == 0 {
+		fm.SectionLevel = cfg.SectionLevel
+	}
+	if fm.DefaultLayout == "" {
+		fm.DefaultLayout = cfg.DefaultLayout
+	}
+	if fm.FirstPageLayout == "" {
+		fm.FirstPageLayout = cfg.FirstPageLayout
+	}
+	for level, layout := range cfg.LayoutRules {
+		if _, ok := fm.LayoutRules[level]; ok {
+			continue
+		}
+		if fm.LayoutRules == nil {
+			fm.LayoutRules = map[string]string{}
+		}
+		fm.LayoutRules[level] = layout
+	}
 	// append default conditions from config
 	for _, cond := range cfg.Defaults {
 		fm.Defaults = append(fm.Defaults, DefaultCondition{
 			If:     cond.If,
 			Layout: cond.Layout,
-			Freeze: cond.Freeze,
+			Freeze: freezeFromBool(cond.Freeze),
 			Ignore: cond.Ignore,
 			Skip:   cond.Skip,
 		})
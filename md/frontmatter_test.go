@@ -287,7 +287,7 @@ func TestApplyConfig(t *testing.T) {
 					{
 						If:     "page == 1",
 						Layout: "title",
-						Freeze: new(true),
+						Freeze: new(FreezeContents),
 					},
 				},
 			},
@@ -328,7 +328,7 @@ func TestApplyConfig(t *testing.T) {
 					{
 						If:     "page == 1",
 						Layout: "title",
-						Freeze: new(true),
+						Freeze: new(FreezeContents),
 					},
 					{
 						If:     "page == 3",
@@ -375,6 +375,44 @@ func TestApplyConfig(t *testing.T) {
 				Defaults:                nil,
 			},
 		},
+		{
+			name: "Merge config layoutRules without overriding frontmatter rules",
+			initialFrontmatter: &Frontmatter{
+				LayoutRules: map[string]string{"h1": "section"},
+			},
+			config: &config.Config{
+				LayoutRules: map[string]string{"h1": "title", "h2": "title-and-body"},
+			},
+			want: &Frontmatter{
+				LayoutRules: map[string]string{"h1": "section", "h2": "title-and-body"},
+			},
+		},
+		{
+			name: "Frontmatter sectionLevel takes precedence over config",
+			initialFrontmatter: &Frontmatter{
+				SectionLevel: 2,
+			},
+			config: &config.Config{
+				SectionLevel: 1,
+			},
+			want: &Frontmatter{
+				SectionLevel: 2,
+			},
+		},
+		{
+			name: "Merge config default layouts without overriding frontmatter",
+			initialFrontmatter: &Frontmatter{
+				DefaultLayout: "content",
+			},
+			config: &config.Config{
+				DefaultLayout:   "title-and-body",
+				FirstPageLayout: "cover",
+			},
+			want: &Frontmatter{
+				DefaultLayout:   "content",
+				FirstPageLayout: "cover",
+			},
+		},
 	}
 
 	for _, tt := range tests {
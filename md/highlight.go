@@ -0,0 +1,19 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+)
+
+// HighlightColor returns the background color of highlighted text in the frontmatter, or "" if it is not set.
+func (md *MD) HighlightColor() (string, error) {
+	if md.Frontmatter == nil || md.Frontmatter.HighlightColor == "" {
+		return "", nil
+	}
+	c, err := deck.NormalizeColor(md.Frontmatter.HighlightColor)
+	if err != nil {
+		return "", fmt.Errorf("highlightColor: %w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,57 @@
+package md
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// An image can be followed by attributes in braces (e.g. `![x](a.png){placeholder=2 credit="© NASA"}`).
+//
+//   - `placeholder=N` places the image in the N-th picture placeholder of the layout, in the same order as titles,
+//     so that editing one image does not move the others.
+//   - `credit="..."` renders the credit as a small caption beneath the image.
+//   - `z=back` sends the image behind the placeholders, so that decorative images can sit behind the texts.
+
+var (
+	imageAttributesReg = regexp.MustCompile(`^\{\s*((?:(?:placeholder\s*=\s*[1-9][0-9]*|credit\s*=\s*"[^"]*"|z\s*=\s*back)\s*)+)\}`)
+	imageAttributeReg  = regexp.MustCompile(`(placeholder|credit|z)\s*=\s*(?:([1-9][0-9]*)|"([^"]*)"|(back))`)
+)
+
+// imageAttributes are the attributes of an image.
+type imageAttributes struct {
+	placeholder int    // 1-based index of the picture placeholder, 0 if not set
+	credit      string // credit rendered beneath the image
+	zOrder      string // z-order of the image, deck.ZOrderBack if sent behind the placeholders
+}
+
+// consumeImageAttributes removes the attributes right after the image node from the text and returns them.
+// It returns the zero value if the image is not followed by them.
+func consumeImageAttributes(n *ast.Image, b []byte) imageAttributes {
+	var attrs imageAttributes
+	t, ok := n.NextSibling().(*ast.Text)
+	if !ok {
+		return attrs
+	}
+	v := t.Segment.Value(b)
+	m := imageAttributesReg.FindSubmatchIndex(v)
+	if m == nil {
+		return attrs
+	}
+	for _, a := range imageAttributeReg.FindAllSubmatch(v[m[2]:m[3]], -1) {
+		switch string(a[1]) {
+		case "placeholder":
+			if position, err := strconv.Atoi(string(a[2])); err == nil {
+				attrs.placeholder = position
+			}
+		case "credit":
+			attrs.credit = string(a[3])
+		case "z":
+			attrs.zOrder = string(a[4])
+		}
+	}
+	t.Segment = text.NewSegment(t.Segment.Start+m[1], t.Segment.Stop)
+	return attrs
+}
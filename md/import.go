@@ -0,0 +1,299 @@
+package md
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Import converts markdown written for Marp or Slidev into markdown for deck, to ease migration.
+// Their frontmatter and per-slide directives are mapped onto the frontmatter and page configs of deck,
+// and the directives that cannot be mapped are dropped with warnings.
+
+const (
+	ImportFromMarp   = "marp"
+	ImportFromSlidev = "slidev"
+)
+
+// ImportResult is the result of Import.
+type ImportResult struct {
+	Markdown []byte
+	Warnings []string // directives that are not supported, prefixed with the page
+}
+
+// importPage is a slide of the markdown being imported.
+type importPage struct {
+	body       []byte
+	directives map[string]any // directives applied to the slide
+	warnings   []string
+}
+
+// Ref. https://marpit.marp.app/directives
+var marpDirectives = []string{
+	"marp", "theme", "style", "headingDivider", "size", "math", "title", "description", "author",
+	"keywords", "url", "image", "lang", "paginate", "header", "footer", "class", "backgroundColor",
+	"backgroundImage", "backgroundPosition", "backgroundRepeat", "backgroundSize", "color",
+}
+
+var (
+	htmlCommentReg     = regexp.MustCompile(`(?s)<!--(.*?)-->\n?`)
+	importSeparatorSeq = []byte("---")
+)
+
+// ValidateImportFrom returns an error if the format to import from is not supported.
+func ValidateImportFrom(from string) error {
+	switch from {
+	case "", ImportFromMarp, ImportFromSlidev:
+		return nil
+	default:
+		return fmt.Errorf("invalid format to import from: %q (must be %q or %q)", from, ImportFromMarp, ImportFromSlidev)
+	}
+}
+
+// Import converts the markdown b written for Marp or Slidev into markdown for deck.
+// If from is empty, the format is detected from the frontmatter (`marp: true` for Marp, Slidev otherwise).
+func Import(b []byte, from string) (*ImportResult, error) {
+	if err := ValidateImportFrom(from); err != nil {
+		return nil, err
+	}
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	chunks := splitImportChunks(b)
+	headmatter := map[string]any{}
+	if len(chunks) > 2 && len(bytes.TrimSpace(chunks[0])) == 0 {
+		if m, ok := importYAML(chunks[1]); ok {
+			headmatter = m
+			chunks = chunks[2:]
+		}
+	}
+	if from == "" {
+		from = ImportFromSlidev
+		if marp, ok := headmatter["marp"].(bool); ok && marp {
+			from = ImportFromMarp
+		}
+	}
+	var (
+		fm       = &Frontmatter{}
+		warnings []string
+		pages    []*importPage
+	)
+	if v, ok := headmatter["title"].(string); ok {
+		fm.Title = v
+	}
+	if v, ok := headmatter["description"].(string); ok {
+		fm.Description = v
+	}
+	switch from {
+	case ImportFromMarp:
+		pages, warnings = importMarpPages(chunks, headmatter)
+	case ImportFromSlidev:
+		pages, warnings = importSlidevPages(chunks, headmatter)
+	}
+
+	var out bytes.Buffer
+	if fm.Title != "" || fm.Description != "" {
+		y, err := yaml.Marshal(fm)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString("---\n")
+		out.Write(y)
+		out.WriteString("---\n\n")
+	}
+	for i, p := range pages {
+		if i > 0 {
+			out.WriteString("\n---\n\n")
+		}
+		config := importConfig(from, p)
+		for _, w := range p.warnings {
+			warnings = append(warnings, fmt.Sprintf("page %d: %s", i+1, w))
+		}
+		if config != nil {
+			c, err := json.Marshal(config)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&out, "<!-- %s -->\n\n", c)
+		}
+		body := bytes.TrimSpace(p.body)
+		if len(body) > 0 {
+			out.Write(body)
+			out.WriteString("\n")
+		}
+	}
+	return &ImportResult{
+		Markdown: out.Bytes(),
+		Warnings: warnings,
+	}, nil
+}
+
+// importMarpPages returns the slides of Marp markdown. The class directive of the frontmatter is
+// inherited by all slides, the one in an HTML comment by the following slides, and `_class` applies
+// only to the slide.
+func importMarpPages(chunks [][]byte, headmatter map[string]any) ([]*importPage, []string) {
+	var warnings []string
+	inherited := map[string]any{}
+	for _, k := range sortedKeys(headmatter) {
+		switch k {
+		case "marp", "title", "description":
+		case "class":
+			inherited[k] = headmatter[k]
+		default:
+			warnings = append(warnings, fmt.Sprintf("unsupported directive %q in the frontmatter is ignored", k))
+		}
+	}
+	var pages []*importPage
+	for _, chunk := range chunks {
+		p := &importPage{}
+		scoped := map[string]any{}
+		p.body = htmlCommentReg.ReplaceAllFunc(chunk, func(m []byte) []byte {
+			d, ok := importYAML(htmlCommentReg.FindSubmatch(m)[1])
+			if !ok || !slices.ContainsFunc(sortedKeys(d), isMarpDirective) {
+				return m // presenter notes
+			}
+			for _, k := range sortedKeys(d) {
+				switch k {
+				case "class":
+					inherited[k] = d[k]
+				case "_class":
+					scoped["class"] = d[k]
+				default:
+					p.warnings = append(p.warnings, fmt.Sprintf("unsupported directive %q is ignored", k))
+				}
+			}
+			return nil
+		})
+		p.directives = cloneMap(inherited)
+		maps.Copy(p.directives, scoped)
+		if len(bytes.TrimSpace(p.body)) == 0 && len(p.warnings) == 0 {
+			continue
+		}
+		pages = append(pages, p)
+	}
+	return pages, warnings
+}
+
+// importSlidevPages returns the slides of Slidev markdown. The headmatter is also the frontmatter of
+// the first slide, and each slide can have its own frontmatter right after the separator.
+func importSlidevPages(chunks [][]byte, headmatter map[string]any) ([]*importPage, []string) {
+	var warnings []string
+	first := map[string]any{}
+	for _, k := range sortedKeys(headmatter) {
+		switch k {
+		case "title", "description":
+		case "layout", "class", "hide", "disabled":
+			first[k] = headmatter[k]
+		default:
+			warnings = append(warnings, fmt.Sprintf("unsupported option %q in the headmatter is ignored", k))
+		}
+	}
+	var pages []*importPage
+	for i := 0; i < len(chunks); i++ {
+		p := &importPage{body: chunks[i], directives: map[string]any{}}
+		if len(pages) == 0 {
+			p.directives = first
+		}
+		if i+1 < len(chunks) && len(pages) > 0 {
+			if m, ok := importYAML(chunks[i]); ok {
+				p.body, p.directives = chunks[i+1], m
+				i++
+			}
+		}
+		if len(bytes.TrimSpace(p.body)) == 0 && len(p.directives) == 0 {
+			continue
+		}
+		pages = append(pages, p)
+	}
+	return pages, warnings
+}
+
+// importConfig maps the directives of the slide onto the page config of deck, or returns nil if there is nothing to map.
+// The directives that cannot be mapped are added to the warnings of the slide.
+func importConfig(from string, p *importPage) *Config {
+	var config Config
+	for _, k := range sortedKeys(p.directives) {
+		v := p.directives[k]
+		switch {
+		case k == "layout" && from == ImportFromSlidev:
+			config.Layout = fmt.Sprint(v)
+		case k == "class" && from == ImportFromMarp:
+			// The first class of the slide is used as the layout, e.g. `lead` for `<!-- _class: lead -->`.
+			classes := strings.Fields(fmt.Sprint(v))
+			if len(classes) > 0 {
+				config.Layout = classes[0]
+			}
+			if len(classes) > 1 {
+				p.warnings = append(p.warnings, fmt.Sprintf("only the first class %q is used as the layout", classes[0]))
+			}
+		case (k == "hide" || k == "disabled") && from == ImportFromSlidev:
+			if hide, ok := v.(bool); ok && hide {
+				skip := true
+				config.Skip = &skip
+			}
+		default:
+			p.warnings = append(p.warnings, fmt.Sprintf("unsupported directive %q is ignored", k))
+		}
+	}
+	if config == (Config{}) {
+		return nil
+	}
+	return &config
+}
+
+// splitImportChunks splits b by `---` lines outside code blocks.
+func splitImportChunks(b []byte) [][]byte {
+	var (
+		chunks  [][]byte
+		chunk   [][]byte
+		inFence bool
+	)
+	for line := range bytes.SplitSeq(b, []byte("\n")) {
+		if isFenceLine(line) {
+			inFence = !inFence
+		}
+		if !inFence && bytes.Equal(bytes.TrimRight(line, " \t"), importSeparatorSeq) {
+			chunks = append(chunks, bytes.Join(chunk, []byte("\n")))
+			chunk = nil
+			continue
+		}
+		chunk = append(chunk, line)
+	}
+	return append(chunks, bytes.Join(chunk, []byte("\n")))
+}
+
+// importYAML parses b as a YAML mapping of directives.
+func importYAML(b []byte) (map[string]any, bool) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || b[0] == '#' {
+		return nil, false
+	}
+	m := map[string]any{}
+	if err := yaml.Unmarshal(b, &m); err != nil || len(m) == 0 {
+		return nil, false
+	}
+	return m, true
+}
+
+func isMarpDirective(k string) bool {
+	return slices.Contains(marpDirectives, strings.TrimPrefix(k, "_"))
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func cloneMap(m map[string]any) map[string]any {
+	c := make(map[string]any, len(m))
+	maps.Copy(c, m)
+	return c
+}
@@ -0,0 +1,149 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           string
+		from         string
+		want         string
+		wantWarnings []string
+	}{
+		{
+			"marp",
+			`---
+marp: true
+theme: gaia
+title: Migrated
+class: invert
+---
+
+<!-- _class: lead -->
+
+# Title
+
+---
+
+<!-- paginate: true -->
+<!-- presenter note -->
+
+# Second
+
+---
+
+<!-- class: section wide -->
+
+# Third
+`,
+			"",
+			`---
+title: Migrated
+---
+
+<!-- {"layout":"lead"} -->
+
+# Title
+
+---
+
+<!-- {"layout":"invert"} -->
+
+<!-- presenter note -->
+
+# Second
+
+---
+
+<!-- {"layout":"section"} -->
+
+# Third
+`,
+			[]string{
+				`unsupported directive "theme" in the frontmatter is ignored`,
+				`page 2: unsupported directive "paginate" is ignored`,
+				`page 3: only the first class "section" is used as the layout`,
+			},
+		},
+		{
+			"slidev",
+			`---
+theme: seriph
+layout: cover
+---
+
+# Title
+
+---
+layout: two-cols
+hide: true
+transition: fade
+---
+
+# Hidden
+
+` + "```yaml\n---\nkey: value\n---\n```" + `
+
+---
+
+# Plain
+
+<!-- notes -->
+`,
+			"",
+			`<!-- {"layout":"cover"} -->
+
+# Title
+
+---
+
+<!-- {"layout":"two-cols","skip":true} -->
+
+# Hidden
+
+` + "```yaml\n---\nkey: value\n---\n```" + `
+
+---
+
+# Plain
+
+<!-- notes -->
+`,
+			[]string{
+				`unsupported option "theme" in the headmatter is ignored`,
+				`page 2: unsupported directive "transition" is ignored`,
+			},
+		},
+		{
+			"marp without the frontmatter",
+			"<!-- _class: lead -->\n# A\n",
+			ImportFromMarp,
+			"<!-- {\"layout\":\"lead\"} -->\n\n# A\n",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Import([]byte(tt.in), tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, string(got.Markdown)); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.wantWarnings, got.Warnings); diff != "" {
+				t.Error(diff)
+			}
+			if _, err := Parse(".", got.Markdown, nil, WithStrict(true)); err != nil {
+				t.Errorf("imported markdown should be parsed: %v", err)
+			}
+		})
+	}
+	if _, err := Import(nil, "reveal"); err == nil {
+		t.Error("want error")
+	}
+}
@@ -3,16 +3,20 @@ package md
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/goccy/go-yaml"
 	"github.com/k1LoW/deck"
@@ -59,20 +63,62 @@ type MD struct {
 type Frontmatter struct {
 	PresentationID string `yaml:"presentationID,omitempty" json:"presentationID,omitempty"` // ID of the Google Slides presentation
 	Title          string `yaml:"title,omitempty" json:"title,omitempty"`                   // title of the presentation
+	// IDs of the presentations of the parts split by `deck apply --split-by`, in order
+	PresentationIDs []string `yaml:"presentationIDs,omitempty" json:"presentationIDs,omitempty"`
+	// description of the presentation file on Google Drive
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// custom properties of the presentation file on Google Drive (e.g. commit: "{{ env.GITHUB_SHA }}")
+	Properties map[string]string `yaml:"properties,omitempty" json:"properties,omitempty"`
 	// Whether to display line breaks in the document as line breaks
 	Breaks *bool `yaml:"breaks,omitempty" json:"breaks,omitempty"`
 	// Conditions for default
 	Defaults []DefaultCondition `yaml:"defaults,omitempty" json:"defaults,omitempty"`
 	// command to convert code blocks to images
 	CodeBlockToImageCommand string `yaml:"codeBlockToImageCommand,omitempty" json:"codeBlockToImageCommand,omitempty"`
+	// default layouts per heading level of page titles (e.g. h1: section)
+	LayoutRules map[string]string `yaml:"layoutRules,omitempty" json:"layoutRules,omitempty"`
+	// heading level of page titles that start a section (e.g. 1 for H1 pages)
+	SectionLevel int `yaml:"sectionLevel,omitempty" json:"sectionLevel,omitempty"`
+	// layout for pages without a layout (default: the first "TITLE_AND_BODY" layout of the presentation)
+	DefaultLayout string `yaml:"defaultLayout,omitempty" json:"defaultLayout,omitempty"`
+	// layout for the first page without a layout (default: the first "TITLE" layout of the presentation)
+	FirstPageLayout string `yaml:"firstPageLayout,omitempty" json:"firstPageLayout,omitempty"`
+	// where to render footnotes: "notes" (speaker notes of the page, default) or "slide" (a slide at the end)
+	Footnotes string `yaml:"footnotes,omitempty" json:"footnotes,omitempty"`
+	// title of the slide of footnotes (default: "References")
+	FootnotesTitle string `yaml:"footnotesTitle,omitempty" json:"footnotesTitle,omitempty"`
+	// split tables with more body rows than this into continuation pages (default: no limit)
+	TableMaxRows int `yaml:"tableMaxRows,omitempty" json:"tableMaxRows,omitempty"`
+	// split pages whose body is estimated to overflow into "Title (cont.)" pages
+	AutoSplit *AutoSplit `yaml:"autoSplit,omitempty" json:"autoSplit,omitempty"`
+	// how to render definition lists: "paragraphs" (bold terms and indented definitions, default) or "table"
+	DefinitionList string `yaml:"definitionList,omitempty" json:"definitionList,omitempty"`
+	// how to render code blocks: "image" (converted with codeBlockToImageCommand, default) or "text"
+	CodeBlock string `yaml:"codeBlock,omitempty" json:"codeBlock,omitempty"`
+	// autofit of the body placeholders of pages without autofit: "none", "shrink" or "resize"
+	Autofit string `yaml:"autofit,omitempty" json:"autofit,omitempty"`
+	// text direction of the paragraphs of pages without direction: "ltr" or "rtl" (default: detected per paragraph)
+	Direction string `yaml:"direction,omitempty" json:"direction,omitempty"`
+	// spacing of the paragraphs of the body placeholders: lineSpacing (percent), spaceAbove and spaceBelow (points),
+	// overridden per layout in layouts
+	ParagraphStyle *ParagraphStyle `yaml:"paragraphStyle,omitempty" json:"paragraphStyle,omitempty"`
+	// line splitting pages instead of "---" and "<!-- pagebreak -->" (e.g. "<!-- page -->")
+	PageDelimiter string `yaml:"pageDelimiter,omitempty" json:"pageDelimiter,omitempty"`
+	// page size of the presentation: "16:9", "4:3" or "custom(w,h)" in points
+	PageSize string `yaml:"pageSize,omitempty" json:"pageSize,omitempty"`
+	// shell to run codeBlockToImageCommand: "sh", "bash", "cmd", "powershell", "pwsh" or a path to one of them
+	// (default: $SHELL, bash or sh, and cmd on Windows)
+	Shell string `yaml:"shell,omitempty" json:"shell,omitempty"`
+	// background color of text highlighted with <mark> (default: "#ffff00")
+	HighlightColor string `yaml:"highlightColor,omitempty" json:"highlightColor,omitempty"`
 }
 
 type DefaultCondition struct {
-	If     string `json:"if"`               // condition to check
-	Layout string `json:"layout,omitempty"` // layout name to apply if condition is true
-	Freeze *bool  `json:"freeze,omitempty"` // freeze the page
-	Ignore *bool  `json:"ignore,omitempty"` // whether to ignore the page if condition is true
-	Skip   *bool  `json:"skip,omitempty"`   // whether to skip the page if condition is true
+	If     string  `json:"if"`               // condition to check
+	Layout string  `json:"layout,omitempty"` // layout name to apply if condition is true
+	Freeze *Freeze `json:"freeze,omitempty"` // freeze the page: true or "position"
+	Ignore *bool   `json:"ignore,omitempty"` // whether to ignore the page if condition is true
+	Skip   *bool   `json:"skip,omitempty"`   // whether to skip the page if condition is true
 }
 
 // Contents represents a collection of slide contents.
@@ -80,25 +126,53 @@ type Contents []*Content
 
 // Config represents the configuration for a slide.
 type Config struct {
-	Layout string `json:"layout,omitempty"` // layout name
-	Freeze *bool  `json:"freeze,omitempty"` // freeze the page
-	Ignore *bool  `json:"ignore,omitempty"` // ignore the page (skip slide generation)
-	Skip   *bool  `json:"skip,omitempty"`   // skip the page (do not show in the presentation)
-	Key    string `json:"key,omitempty"`    // opaque, stable identifier for the page; unique within the deck
+	Layout  string  `json:"layout,omitempty"`  // layout name
+	Freeze  *Freeze `json:"freeze,omitempty"`  // freeze the page: true or "position"
+	Ignore  *bool   `json:"ignore,omitempty"`  // ignore the page (skip slide generation)
+	Skip    *bool   `json:"skip,omitempty"`    // skip the page (do not show in the presentation)
+	Key     string  `json:"key,omitempty"`     // opaque, stable identifier for the page; unique within the deck
+	Owner   string  `json:"owner,omitempty"`   // owner of the page (e.g. @alice)
+	Section string  `json:"section,omitempty"` // start a section with the given title from the page
+	Agenda  *bool   `json:"agenda,omitempty"`  // render the list of sections into the page
+	// split tables with more body rows than this into continuation pages repeating the header row
+	TableMaxRows int `json:"tableMaxRows,omitempty"`
+	// continue the last table of the previous pages: the first table of the page has no header row
+	ContinueTable *bool `json:"continueTable,omitempty"`
+	// autofit of the body placeholders: "none", "shrink" or "resize"
+	Autofit string `json:"autofit,omitempty"`
+	// text direction of the paragraphs: "ltr" or "rtl"
+	Direction string `json:"direction,omitempty"`
+	// number of columns and rows of the grid of images not in picture placeholders
+	ImageColumns int `json:"imageColumns,omitempty"`
+	ImageRows    int `json:"imageRows,omitempty"`
+	// render the list of the headings at the level across the deck into the page with links to their pages
+	TOC *TOC `json:"toc,omitempty"`
 }
 
 type CodeBlock struct {
-	Language string `json:"language,omitempty"`
-	Content  string `json:"content"`
+	Language       string `json:"language,omitempty"`
+	Content        string `json:"content"`
+	HighlightLines []int  `json:"highlightLines,omitempty"` // line numbers (1-based) to highlight
+	LineNumbers    bool   `json:"lineNumbers,omitempty"`    // whether to show line numbers
+	text           bool   // inserted into the body as text instead of an image
 }
 
 // Content represents a single slide content.
 type Content struct {
 	Layout         string             `json:"layout"`
-	Freeze         *bool              `json:"freeze,omitempty"`
+	Freeze         *Freeze            `json:"freeze,omitempty"`
 	Ignore         *bool              `json:"ignore,omitempty"`
 	Skip           *bool              `json:"skip,omitempty"`
 	Key            string             `json:"key,omitempty"`
+	Owner          string             `json:"owner,omitempty"`
+	Section        string             `json:"section,omitempty"`
+	Agenda         *bool              `json:"agenda,omitempty"`
+	TableMaxRows   int                `json:"table_max_rows,omitempty"`
+	ContinueTable  *bool              `json:"continue_table,omitempty"`
+	Autofit        string             `json:"autofit,omitempty"`
+	Direction      string             `json:"direction,omitempty"`
+	ImageColumns   int                `json:"image_columns,omitempty"`
+	ImageRows      int                `json:"image_rows,omitempty"`
 	Titles         []string           `json:"titles,omitempty"`
 	TitleBodies    []*deck.Body       `json:"-"`
 	Subtitles      []string           `json:"subtitles,omitempty"`
@@ -108,12 +182,41 @@ type Content struct {
 	CodeBlocks     []*CodeBlock       `json:"code_blocks,omitempty"`
 	BlockQuotes    []*deck.BlockQuote `json:"block_quotes,omitempty"`
 	Tables         []*deck.Table      `json:"tables,omitempty"`
+	Elements       []*deck.Element    `json:"elements,omitempty"`
 	Comments       []string           `json:"comments,omitempty"`
 	Headings       map[int][]string   `json:"headings,omitempty"`
+	// spacing of the paragraphs of the body placeholders resolved from the paragraph style in the frontmatter
+	ParagraphSpacing *deck.ParagraphSpacing `json:"paragraph_spacing,omitempty"`
+	// table of contents rendered into the page
+	TOC *TOC `json:"toc,omitempty"`
+
+	directives  *Config     // page configuration given by heading attributes
+	taggedNotes taggedNotes // comments tagged such as `<!-- note:col2 ... -->`
+	continued   bool        // continuation page of a split table
+	plugged     bool        // some nodes are handled by plugins
+
+	definitionList string // how to render definition lists
+	codeBlock      string // how to render code blocks
+}
+
+// ParseOption is an option for Parse and ParseFile.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	strict    bool
+	untrusted bool
+}
+
+// WithStrict makes parsing fail on mistakes that are tolerated by default: malformed frontmatter,
+// unknown keys in the frontmatter, and invalid JSON or unknown keys in page configurations.
+func WithStrict(strict bool) ParseOption {
+	return func(o *parseOptions) {
+		o.strict = strict
+	}
 }
 
 // ParseFile parses a markdown file into contents.
-func ParseFile(f string, cfg *config.Config) (_ *MD, err error) {
+func ParseFile(f string, cfg *config.Config, opts ...ParseOption) (_ *MD, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
@@ -127,16 +230,32 @@ func ParseFile(f string, cfg *config.Config) (_ *MD, err error) {
 		return nil, err
 	}
 	baseDir := filepath.Dir(abs)
-	return Parse(baseDir, b, cfg)
+	return Parse(baseDir, b, cfg, opts...)
 }
 
 // Parse parses markdown bytes into contents.
+// Relative paths such as images are resolved against baseDir, which can also be a URL of a directory.
 // It splits the input by "---" delimiters and parses each section as a separate content.
-func Parse(baseDir string, b []byte, cfg *config.Config) (_ *MD, err error) {
+func Parse(baseDir string, b []byte, cfg *config.Config, opts ...ParseOption) (_ *MD, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	o := &parseOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	md, err := parse(baseDir, b, cfg, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := md.resolveDeck(); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
 
+// parse parses markdown bytes into contents, resolving everything within the markdown except resolveDeck.
+func parse(baseDir string, b []byte, cfg *config.Config, o *parseOptions) (_ *MD, err error) {
 	// Normalize line endings: CRLF -> LF, CR -> LF
 	if bytes.Contains(b, []byte("\r")) {
 		b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
@@ -152,27 +271,68 @@ func Parse(baseDir string, b []byte, cfg *config.Config) (_ *MD, err error) {
 		stuff := bytes.SplitN(bytes.TrimPrefix(b, sep), sep, 2)
 		if len(stuff) == 2 {
 			frontmatter = &Frontmatter{}
-			if err := yaml.Unmarshal(stuff[0], frontmatter); err == nil {
+			var yamlOpts []yaml.DecodeOption
+			if o.strict {
+				yamlOpts = append(yamlOpts, yaml.Strict())
+			}
+			if err := yaml.UnmarshalWithOptions(stuff[0], frontmatter, yamlOpts...); err == nil {
 				b = stuff[1]
+			} else if o.strict {
+				return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 			} else {
 				frontmatter = nil
 			}
 		}
 	}
+	var pageDelimiter string
+	if frontmatter != nil {
+		pageDelimiter = strings.TrimSpace(frontmatter.PageDelimiter)
+	}
+	bpages := splitPages(bytes.TrimPrefix(b, sep), pageDelimiter)
+	if o.untrusted {
+		if err := checkUntrusted(baseDir, frontmatter, bpages); err != nil {
+			return nil, err
+		}
+	}
 	frontmatter = frontmatter.applyConfig(cfg)
-
-	bpages := splitPages(bytes.TrimPrefix(b, sep))
 	var breaks bool
 	if frontmatter != nil && frontmatter.Breaks != nil {
 		breaks = *frontmatter.Breaks
 	}
+	var definitionList string
+	if frontmatter != nil {
+		definitionList = frontmatter.DefinitionList
+	}
+	if err := validateDefinitionListMode(definitionList); err != nil {
+		return nil, err
+	}
+	var codeBlock string
+	if frontmatter != nil {
+		codeBlock = frontmatter.CodeBlock
+	}
+	if err := validateCodeBlockMode(codeBlock); err != nil {
+		return nil, err
+	}
+
+	details := make([][]string, len(bpages))
+	for i, bpage := range bpages {
+		bpage, notes := extractNotesSeparator(bpage)
+		bpages[i], details[i] = extractDetails(bpage)
+		if notes != "" {
+			details[i] = append(details[i], notes)
+		}
+	}
+	bpages, fn := extractFootnotes(bpages)
 
 	var contents Contents
-	for _, bpage := range bpages {
-		c, err := ParseContent(baseDir, bpage, breaks)
+	for i, bpage := range bpages {
+		c, err := parseContent(baseDir, bpage, breaks, o.strict, definitionList, codeBlock)
 		if err != nil {
 			return nil, err
 		}
+		c.Comments = append(c.Comments, details[i]...)
+		// The tagged notes follow all the untagged notes including the details.
+		c.Comments = append(c.Comments, c.taggedNotes.comments()...)
 		contents = append(contents, c)
 	}
 
@@ -180,18 +340,62 @@ func Parse(baseDir string, b []byte, cfg *config.Config) (_ *MD, err error) {
 		Frontmatter: frontmatter,
 		Contents:    contents,
 	}
+	if err := md.resolveFootnotes(baseDir, fn, breaks); err != nil {
+		return nil, err
+	}
+	if err := md.resolveTables(); err != nil {
+		return nil, err
+	}
 	if err := md.reflectDefaults(); err != nil {
 		return nil, fmt.Errorf("failed to reflect defaults while parsing: %w", err)
 	}
-	if err := md.validateKeys(); err != nil {
+	if err := md.resolveAutofit(); err != nil {
+		return nil, err
+	}
+	if err := md.resolveDirection(); err != nil {
+		return nil, err
+	}
+	if err := md.resolveParagraphSpacing(); err != nil {
+		return nil, err
+	}
+	if err := md.resolveAutoSplit(); err != nil {
 		return nil, err
 	}
 	return md, nil
 }
 
+// resolveDeck resolves what depends on all pages of the deck, such as sections and keys of pages.
+func (md *MD) resolveDeck() error {
+	if err := md.resolveSections(); err != nil {
+		return err
+	}
+	if err := md.resolveTOC(); err != nil {
+		return err
+	}
+	if err := md.validateKeys(); err != nil {
+		return err
+	}
+	if _, err := md.PageSize(); err != nil {
+		return err
+	}
+	if _, err := md.HighlightColor(); err != nil {
+		return err
+	}
+	return nil
+}
+
 // ParseContent parses a single markdown content into a Content structure.
 // It processes headings, lists, paragraphs, and HTML blocks to create a structured representation.
 func ParseContent(baseDir string, b []byte, breaks bool) (_ *Content, err error) {
+	c, err := parseContent(baseDir, b, breaks, false, "", "")
+	if err != nil {
+		return nil, err
+	}
+	c.Comments = append(c.Comments, c.taggedNotes.comments()...)
+	return c, nil
+}
+
+func parseContent(baseDir string, b []byte, breaks, strict bool, definitionList, codeBlock string) (_ *Content, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
@@ -226,11 +430,14 @@ func ParseContent(baseDir string, b []byte, breaks bool) (_ *Content, err error)
 
 	// Second walk: parse content with determined title level
 	content := &Content{
-		Headings: make(map[int][]string),
+		Headings:       make(map[int][]string),
+		definitionList: definitionList,
+		codeBlock:      codeBlock,
 	}
-	if err := walkContents(doc, baseDir, b, content, titleLevel, breaks); err != nil {
+	if err := walkContents(doc, baseDir, b, content, titleLevel, breaks, strict); err != nil {
 		return nil, fmt.Errorf("failed to walk body: %w", err)
 	}
+	content.applyDirectives()
 
 	// remove empty bodies
 	notEmpty := false
@@ -247,14 +454,22 @@ func ParseContent(baseDir string, b []byte, breaks bool) (_ *Content, err error)
 	return content, nil
 }
 
-func (md *MD) ToSlides(ctx context.Context, codeBlockToImageCmd string) (_ deck.Slides, err error) {
+func (md *MD) ToSlides(ctx context.Context, codeBlockToImageCmd string, opts ...ToSlidesOption) (_ deck.Slides, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
-	if codeBlockToImageCmd == "" && md.Frontmatter != nil {
-		codeBlockToImageCmd = md.Frontmatter.CodeBlockToImageCommand
+	o := &toSlidesOptions{}
+	for _, opt := range opts {
+		opt(o)
 	}
-	return md.Contents.toSlides(ctx, codeBlockToImageCmd)
+	var shell string
+	if md.Frontmatter != nil {
+		if codeBlockToImageCmd == "" {
+			codeBlockToImageCmd = md.Frontmatter.CodeBlockToImageCommand
+		}
+		shell = md.Frontmatter.Shell
+	}
+	return md.Contents.toSlides(ctx, codeBlockToImageCmd, shell, o.codeImageCacheDir)
 }
 
 // validateKeys ensures that page keys are unique within the deck.
@@ -274,16 +489,20 @@ func (md *MD) validateKeys() error {
 }
 
 func newParser() goldmark.Markdown {
+	extenders := []goldmark.Extender{
+		extension.Table,
+		extension.Strikethrough,
+		extension.DefinitionList,
+		&fencedDivExtension{},
+		&headingDirectiveExtension{},
+	}
 	return goldmark.New(
-		goldmark.WithExtensions(
-			extension.Table,
-			extension.Strikethrough,
-		),
+		goldmark.WithExtensions(append(extenders, pluginExtenders()...)...),
 	)
 }
 
 // toSlides converts the contents to a slice of deck.Slide structures.
-func (contents Contents) toSlides(ctx context.Context, codeBlockToImageCmd string) (_ deck.Slides, err error) {
+func (contents Contents) toSlides(ctx context.Context, codeBlockToImageCmd, shell, codeImageCacheDir string) (_ deck.Slides, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
@@ -296,13 +515,27 @@ func (contents Contents) toSlides(ctx context.Context, codeBlockToImageCmd strin
 		}
 		var images []*deck.Image
 		images = append(images, content.Images...)
-		if codeBlockToImageCmd != "" && len(content.CodeBlocks) > 0 {
+		var codeBlocks []*CodeBlock
+		for _, codeBlock := range content.CodeBlocks {
+			if !codeBlock.text {
+				codeBlocks = append(codeBlocks, codeBlock)
+			}
+		}
+		if len(codeBlocks) > 0 {
 			mu := sync.Mutex{}
 			eg := errgroup.Group{}
 			blockMap := make(map[int]*deck.Image)
-			for i, codeBlock := range content.CodeBlocks {
+			for i, codeBlock := range codeBlocks {
+				cmd := codeBlockToImageCmd
+				if cmd == "" && codeBlock.Language == chartLanguage {
+					// Charts are rendered by the built-in renderer without codeBlockToImageCommand.
+					cmd = codeBlockToImageBuiltin
+				}
+				if cmd == "" {
+					continue
+				}
 				eg.Go(func() error {
-					image, err := genCodeImage(ctx, codeBlockToImageCmd, codeBlock)
+					image, err := genCachedCodeImage(ctx, cmd, shell, codeBlock, codeImageCacheDir)
 					if err != nil {
 						return err
 					}
@@ -315,8 +548,10 @@ func (contents Contents) toSlides(ctx context.Context, codeBlockToImageCmd strin
 			if err := eg.Wait(); err != nil {
 				return nil, fmt.Errorf("failed to convert code blocks to images: %w", err)
 			}
-			for i := range content.CodeBlocks {
-				images = append(images, blockMap[i])
+			for i := range codeBlocks {
+				if image, ok := blockMap[i]; ok {
+					images = append(images, image)
+				}
 			}
 		}
 		slide := &deck.Slide{
@@ -329,10 +564,19 @@ func (contents Contents) toSlides(ctx context.Context, codeBlockToImageCmd strin
 			Images:         images,
 			BlockQuotes:    content.BlockQuotes,
 			Tables:         content.Tables,
+			Elements:       content.Elements,
 			SpeakerNote:    strings.Join(content.Comments, "\n\n"),
+			Owner:          content.Owner,
+			Key:            content.Key,
+			Autofit:        content.Autofit,
+			Direction:      content.Direction,
+			ImageColumns:   content.ImageColumns,
+			ImageRows:      content.ImageRows,
 		}
+		slide.ParagraphSpacing = content.ParagraphSpacing
 		if content.Freeze != nil {
-			slide.Freeze = *content.Freeze
+			slide.Freeze = content.Freeze.frozen()
+			slide.FreezePosition = *content.Freeze == FreezePosition
 		}
 		if content.Skip != nil {
 			slide.Skip = *content.Skip
@@ -342,14 +586,25 @@ func (contents Contents) toSlides(ctx context.Context, codeBlockToImageCmd strin
 	return slides, nil
 }
 
-func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titleLevel int, breaks bool) error {
+func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titleLevel int, breaks, strict bool) error {
 	if len(content.Bodies) == 0 {
 		content.Bodies = append(content.Bodies, &deck.Body{})
 	}
 	currentBody := content.Bodies[len(content.Bodies)-1]
-	currentListMarker := deck.BulletNone
+	ps := registeredPlugins()
 	if err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if entering {
+			if len(ps) > 0 {
+				pc := &PluginContext{Content: content, BaseDir: baseDir, Source: b, body: currentBody, breaks: breaks}
+				handled, err := handlePlugins(ps, pc, n)
+				if err != nil {
+					return ast.WalkStop, err
+				}
+				if handled {
+					content.plugged = true
+					return ast.WalkSkipChildren, nil
+				}
+			}
 			switch v := n.(type) {
 			case *ast.Heading:
 				var text strings.Builder
@@ -365,32 +620,33 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 					return ast.WalkStop, err
 				}
 				deckFrags := toDeckFragments(frags, breaks)
+				var position int
+				if v.Level == titleLevel || v.Level == titleLevel+1 {
+					deckFrags, position = extractPlaceholderPosition(deckFrags)
+				}
 				for _, frag := range deckFrags {
 					if frag.Value != "" {
 						text.WriteString(frag.Value)
 					}
 				}
 				content.Headings[v.Level] = append(content.Headings[v.Level], text.String())
+				content.addHeadingDirectives(v)
 
 				switch v.Level {
 				case titleLevel:
-					content.Titles = append(content.Titles, text.String())
-					content.TitleBodies = append(content.TitleBodies, &deck.Body{
-						Paragraphs: []*deck.Paragraph{{
-							Fragments: deckFrags,
-						}},
-					})
+					content.Titles, content.TitleBodies, err = placeText(content.Titles, content.TitleBodies, text.String(), deckFrags, position)
+					if err != nil {
+						return ast.WalkStop, fmt.Errorf("title %q: %w", text.String(), err)
+					}
 					if len(currentBody.Paragraphs) > 0 {
 						currentBody = &deck.Body{}
 						content.Bodies = append(content.Bodies, currentBody)
 					}
 				case titleLevel + 1:
-					content.Subtitles = append(content.Subtitles, text.String())
-					content.SubtitleBodies = append(content.SubtitleBodies, &deck.Body{
-						Paragraphs: []*deck.Paragraph{{
-							Fragments: deckFrags,
-						}},
-					})
+					content.Subtitles, content.SubtitleBodies, err = placeText(content.Subtitles, content.SubtitleBodies, text.String(), deckFrags, position)
+					if err != nil {
+						return ast.WalkStop, fmt.Errorf("subtitle %q: %w", text.String(), err)
+					}
 					if len(currentBody.Paragraphs) > 0 {
 						currentBody = &deck.Body{}
 						content.Bodies = append(content.Bodies, currentBody)
@@ -407,8 +663,6 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 					currentBody = &deck.Body{}
 					content.Bodies = append(content.Bodies, currentBody)
 				}
-			case *ast.List:
-				currentListMarker = toBullet(v.Marker)
 			case *ast.ListItem:
 				tb := v.FirstChild()
 				frags, images, err := toFragments(baseDir, b, tb, deck.Fragment{})
@@ -440,9 +694,14 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 				if len(frags) == 0 {
 					return ast.WalkContinue, nil
 				}
+				// The bullet is the marker of the list of the item, which is restored after nested lists.
+				bullet := deck.BulletNone
+				if l, ok := v.Parent().(*ast.List); ok {
+					bullet = toBullet(l.Marker)
+				}
 				currentBody.Paragraphs = append(currentBody.Paragraphs, &deck.Paragraph{
 					Fragments: toDeckFragments(frags, breaks),
-					Bullet:    currentListMarker,
+					Bullet:    bullet,
 					Nesting:   nesting,
 				})
 			case *ast.Paragraph:
@@ -458,26 +717,93 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 				if len(frags) == 0 {
 					return ast.WalkContinue, nil
 				}
-				currentBody.Paragraphs = append(currentBody.Paragraphs, &deck.Paragraph{
+				var citation *deck.Paragraph
+				if isBlockQuoteEnd(v) {
+					frags, citation = splitCitation(frags, breaks, v.PreviousSibling() != nil)
+				}
+				paragraph := &deck.Paragraph{
 					Fragments: toDeckFragments(frags, breaks),
 					Bullet:    deck.BulletNone,
 					Nesting:   0,
-				})
+				}
+				// Classes at the end of paragraphs in block quotes are the classes of the block quotes.
+				if v.Parent() != nil && v.Parent().Kind() == ast.KindDocument {
+					paragraph.StyleName = extractParagraphClass(paragraph)
+				}
+				if len(paragraph.Fragments) > 0 {
+					currentBody.Paragraphs = append(currentBody.Paragraphs, paragraph)
+				}
+				if citation != nil {
+					currentBody.Paragraphs = append(currentBody.Paragraphs, citation)
+				}
 			case *ast.HTMLBlock:
 				if v.HTMLBlockType == ast.HTMLBlockType2 {
-					block := strings.TrimSpace(strings.TrimSuffix(
-						strings.TrimPrefix(strings.TrimSpace(string(v.Lines().Value(b))), "<!--"), "-->"))
-					config := &Config{}
-					if err := json.Unmarshal([]byte(block), config); err == nil {
+					block := commentBlockText(v, b)
+					if value, ok := parseTableDirective(block); ok {
+						if err := markTable(v, value); err != nil && strict {
+							return ast.WalkStop, err
+						}
+						return ast.WalkContinue, nil
+					}
+					if mode, ok := parseCodeDirective(block); ok {
+						switch {
+						case mode != codeBlockImage && mode != codeBlockText:
+							if strict {
+								return ast.WalkStop, fmt.Errorf("invalid code directive: %q (must be %q or %q)", mode, codeBlockImage, codeBlockText)
+							}
+						case !markCodeBlock(v, mode):
+							if strict {
+								return ast.WalkStop, fmt.Errorf("code directive is not followed by a code block")
+							}
+						}
+						return ast.WalkContinue, nil
+					}
+					config, err := parsePageConfig(block, strict)
+					if err != nil {
+						return ast.WalkStop, err
+					}
+					if config != nil {
 						content.Layout = config.Layout
 						content.Freeze = config.Freeze
 						content.Ignore = config.Ignore
 						content.Skip = config.Skip
 						content.Key = config.Key
+						content.Owner = config.Owner
+						content.Section = config.Section
+						content.Agenda = config.Agenda
+						content.TOC = config.TOC
+						content.TableMaxRows = config.TableMaxRows
+						content.ContinueTable = config.ContinueTable
+						content.Autofit = config.Autofit
+						content.Direction = config.Direction
+						if err := deck.ValidateImageGrid(config.ImageColumns, config.ImageRows); err != nil {
+							return ast.WalkStop, err
+						}
+						content.ImageColumns = config.ImageColumns
+						content.ImageRows = config.ImageRows
+						return ast.WalkContinue, nil
+					}
+					if tag, note, ok := parseTaggedNote(block); ok {
+						content.taggedNotes.add(tag, note)
 						return ast.WalkContinue, nil
 					}
 					content.Comments = append(content.Comments, block)
 				} else {
+					frags, images, class, err := parseParagraphElement(baseDir, v.Lines().Value(b))
+					if err != nil {
+						return ast.WalkStop, err
+					}
+					if class != "" {
+						content.Images = append(content.Images, images...)
+						if len(frags) > 0 {
+							currentBody.Paragraphs = append(currentBody.Paragraphs, &deck.Paragraph{
+								Fragments: toDeckFragments(frags, breaks),
+								Bullet:    deck.BulletNone,
+								StyleName: class,
+							})
+						}
+						return ast.WalkContinue, nil
+					}
 					trimmed := string(bytes.TrimSpace(v.Lines().Value(b)))
 					// Normalize single <br> tag to newline character.
 					// In cases of multiple <br> tags, goldmark can handle them.
@@ -496,39 +822,96 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 				}
 			case *ast.CodeBlock:
 				c := v.Lines().Value(b)
-				content.CodeBlocks = append(content.CodeBlocks, &CodeBlock{
+				codeBlock := &CodeBlock{
 					Content: string(c),
-				})
+					text:    codeBlockMode(v, content.codeBlock) == codeBlockText,
+				}
+				if codeBlock.text {
+					currentBody.Paragraphs = append(currentBody.Paragraphs, codeTextParagraph(codeBlock))
+				}
+				content.CodeBlocks = append(content.CodeBlocks, codeBlock)
 			case *ast.FencedCodeBlock:
-				lang := v.Language(b)
 				c := v.Lines().Value(b)
-				content.CodeBlocks = append(content.CodeBlocks, &CodeBlock{
-					Language: string(lang),
-					Content:  string(c),
-				})
+				codeBlock := &CodeBlock{
+					Content: string(c),
+					text:    codeBlockMode(v, content.codeBlock) == codeBlockText,
+				}
+				if err := parseCodeInfo(v, b, codeBlock); err != nil {
+					return ast.WalkStop, err
+				}
+				if codeBlock.Language == chartLanguage && codeBlockMode(v, "") != codeBlockText {
+					var table *deck.Table
+					if prev, ok := v.PreviousSibling().(*east.Table); ok {
+						t, err := parseTable(prev, baseDir, b, breaks)
+						if err != nil {
+							return ast.WalkStop, err
+						}
+						table = t
+					}
+					spec, err := parseChart(string(c), table)
+					if err != nil {
+						return ast.WalkStop, err
+					}
+					codeBlock, err := chartCodeBlock(spec)
+					if err != nil {
+						return ast.WalkStop, err
+					}
+					content.CodeBlocks = append(content.CodeBlocks, codeBlock)
+					return ast.WalkSkipChildren, nil
+				}
+				if codeBlock.text {
+					currentBody.Paragraphs = append(currentBody.Paragraphs, codeTextParagraph(codeBlock))
+				}
+				content.CodeBlocks = append(content.CodeBlocks, codeBlock)
 			case *east.Table:
 				table, err := parseTable(v, baseDir, b, breaks)
 				if err != nil {
 					return ast.WalkStop, err
 				}
+				if isTableAsText(v) {
+					currentBody.Paragraphs = append(currentBody.Paragraphs, tableTextParagraphs(table)...)
+					return ast.WalkSkipChildren, nil
+				}
 				content.Tables = append(content.Tables, table)
 				return ast.WalkSkipChildren, nil
-			case *ast.Blockquote:
+			case *east.DefinitionList:
+				defs, images, err := parseDefinitionList(v, baseDir, b, breaks)
+				if err != nil {
+					return ast.WalkStop, err
+				}
+				content.Images = append(content.Images, images...)
+				if content.definitionList == definitionListTable {
+					content.Tables = append(content.Tables, definitionTable(defs))
+					return ast.WalkSkipChildren, nil
+				}
+				currentBody.Paragraphs = append(currentBody.Paragraphs, definitionParagraphs(defs)...)
+				return ast.WalkSkipChildren, nil
+			case *ast.Blockquote, *fencedDiv:
 				blockQuoteContent := &Content{
-					Headings: make(map[int][]string),
+					Headings:  make(map[int][]string),
+					codeBlock: content.codeBlock,
 				}
 				for v := n.FirstChild(); v != nil; v = v.NextSibling() {
-					if err := walkContents(v, baseDir, b, blockQuoteContent, 1, breaks); err != nil {
+					if err := walkContents(v, baseDir, b, blockQuoteContent, 1, breaks, strict); err != nil {
 						return ast.WalkStop, err
 					}
 				}
+				var styleName string
+				if fd, ok := v.(*fencedDiv); ok {
+					styleName = fd.class
+				} else {
+					styleName = extractBlockClass(blockQuoteContent.Bodies)
+				}
 				content.CodeBlocks = append(content.CodeBlocks, blockQuoteContent.CodeBlocks...)
 				content.Images = append(content.Images, blockQuoteContent.Images...)
+				content.Elements = append(content.Elements, blockQuoteContent.Elements...)
+				content.plugged = content.plugged || blockQuoteContent.plugged
 				for _, body := range blockQuoteContent.Bodies {
 					if len(body.Paragraphs) > 0 {
 						content.BlockQuotes = append(content.BlockQuotes, &deck.BlockQuote{
 							Paragraphs: body.Paragraphs,
 							Nesting:    0,
+							StyleName:  styleName,
 						})
 					}
 				}
@@ -549,19 +932,49 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 
 var standaloneCommandReg = regexp.MustCompile(`^[-_.+a-zA-Z0-9]+$`)
 
-func buildCommand(c string) (string, []string, error) {
+func buildCommand(c, shell string) (string, []string, error) {
 	// If the string looks like a standalone command, we don't need to execute it via the shell.
 	if standaloneCommandReg.MatchString(c) {
 		return c, nil, nil
 	}
-	if runtime.GOOS == "windows" {
-		return "cmd", []string{"/c", c}, nil
+	if shell == "" {
+		if runtime.GOOS == "windows" {
+			shell = "cmd"
+		} else {
+			sh, err := detectShell()
+			if err != nil {
+				return "", nil, err
+			}
+			shell = sh
+		}
 	}
-	sh, err := detectShell()
-	if err != nil {
-		return "", nil, err
+	switch shellName(shell) {
+	case "cmd":
+		return shell, []string{"/c", c}, nil
+	case "powershell", "pwsh":
+		return shell, []string{"-NoProfile", "-NonInteractive", "-Command", c}, nil
+	default:
+		return shell, []string{"-c", c}, nil
 	}
-	return sh, []string{"-c", c}, nil
+}
+
+// shellName returns the lowercase name of the shell without the directory and the ".exe" extension,
+// handling both slashes and backslashes as path separators regardless of the platform.
+func shellName(shell string) string {
+	name := strings.ToLower(shell[strings.LastIndexAny(shell, `/\`)+1:])
+	return strings.TrimSuffix(name, ".exe")
+}
+
+// isWindowsShell reports whether the shell is cmd or PowerShell, which is the default on Windows.
+func isWindowsShell(shell string) bool {
+	if shell == "" {
+		return runtime.GOOS == "windows"
+	}
+	switch shellName(shell) {
+	case "cmd", "powershell", "pwsh":
+		return true
+	}
+	return false
 }
 
 func detectShell() (string, error) {
@@ -576,8 +989,11 @@ func detectShell() (string, error) {
 	return "", fmt.Errorf("failed to detect shell")
 }
 
-func genCodeImage(ctx context.Context, codeBlockToImageCmd string, codeBlock *CodeBlock) (
+func genCodeImage(ctx context.Context, codeBlockToImageCmd, shell string, codeBlock *CodeBlock) (
 	*deck.Image, error) {
+	if isBuiltinCodeImageCommand(codeBlockToImageCmd) {
+		return genBuiltinCodeImage(codeBlockToImageCmd, codeBlock)
+	}
 
 	dir, err := os.MkdirTemp("", "deck")
 	if err != nil {
@@ -586,10 +1002,16 @@ func genCodeImage(ctx context.Context, codeBlockToImageCmd string, codeBlock *Co
 	defer os.RemoveAll(dir)
 
 	output := filepath.Join(dir, "out.png")
+	if !isWindowsShell(shell) {
+		// POSIX shells on Windows (e.g. Git Bash) treat backslashes as escapes, while Windows accepts slashes.
+		output = filepath.ToSlash(output)
+	}
 	env := environToMap()
 	env["CODEBLOCK_LANG"] = codeBlock.Language
 	env["CODEBLOCK_CONTENT"] = codeBlock.Content
 	env["CODEBLOCK_VALUE"] = codeBlock.Content // Deprecated, use CODEBLOCK_CONTENT.
+	env["CODEBLOCK_HIGHLIGHT_LINES"] = formatLineRanges(codeBlock.HighlightLines)
+	env["CODEBLOCK_LINE_NUMBERS"] = strconv.FormatBool(codeBlock.LineNumbers)
 	// I am unsure whether to set this as an environment variable, but I will set it for consistency.
 	env["CODEBLOCK_OUTPUT"] = output
 	store := map[string]any{
@@ -598,12 +1020,15 @@ func genCodeImage(ctx context.Context, codeBlockToImageCmd string, codeBlock *Co
 		"value":   codeBlock.Content, // Deprecated, use `content`.
 		"output":  output,
 		"env":     env,
+		// line numbers to highlight as comma-separated ranges, e.g. "1,3-5"
+		"highlightLines": formatLineRanges(codeBlock.HighlightLines),
+		"lineNumbers":    codeBlock.LineNumbers,
 	}
-	replacedCmd, err := expandTemplate(codeBlockToImageCmd, store)
+	replacedCmd, err := expandTemplate(codeBlockToImageCmd, withBuiltinVariables(store))
 	if err != nil {
 		return nil, err
 	}
-	c, args, err := buildCommand(replacedCmd)
+	c, args, err := buildCommand(replacedCmd, shell)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build command: %w", err)
 	}
@@ -678,7 +1103,9 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 	if n == nil {
 		return frags, images, nil
 	}
-	var styleName string
+	var styleName, color string
+	var underline bool // inside <u> or <ins>
+	var highlight bool // inside <mark>
 	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
 		switch childNode := c.(type) {
 		case *ast.Emphasis:
@@ -694,8 +1121,11 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 						Link:      child.Link,
 						Bold:      (childNode.Level == 2) || child.Bold,
 						Italic:    (childNode.Level == 1) || child.Italic,
+						Underline: child.Underline || underline,
+						Highlight: child.Highlight || highlight,
 						Code:      child.Code,
 						StyleName: styleName,
+						Color:     cmp.Or(child.Color, color),
 					}})
 			}
 			images = append(images, childImages...)
@@ -719,8 +1149,11 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 						Link:      string(childNode.Destination),
 						Bold:      child.Bold,
 						Italic:    child.Italic,
+						Underline: child.Underline || underline,
+						Highlight: child.Highlight || highlight,
 						Code:      child.Code,
 						StyleName: styleName,
+						Color:     cmp.Or(child.Color, color),
 					}})
 			}
 			images = append(images, childImages...)
@@ -731,7 +1164,10 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 				Fragment: &deck.Fragment{
 					Value:     label,
 					Link:      url,
+					Underline: underline,
+					Highlight: highlight,
 					StyleName: styleName,
+					Color:     color,
 				}})
 		case *ast.Text:
 			b := childNode.Segment.Value(b)
@@ -750,33 +1186,39 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 			}
 			frag := seedFragment
 			frag.Value = v
+			frag.Underline = frag.Underline || underline
+			frag.Highlight = frag.Highlight || highlight
 			frag.StyleName = styleName
+			frag.Color = color
 			frags = append(frags, &fragment{
 				SoftLineBreak: childNode.SoftLineBreak(),
 				Fragment:      &frag,
 			})
 		case *ast.Image:
-			imageLink := string(childNode.Destination)
-			if !strings.Contains(imageLink, "://") && !filepath.IsAbs(imageLink) {
-				imageLink = filepath.Join(baseDir, imageLink)
-			}
+			imageLink := resolvePath(baseDir, string(childNode.Destination))
 			image, err := deck.NewImageFromMarkdown(imageLink)
 			if err != nil {
 				return nil, nil, err
 			}
+			image.SetAlt(altText(childNode, b))
+			image.SetTitle(string(childNode.Title))
+			attrs := consumeImageAttributes(childNode, b)
+			image.SetPlaceholder(attrs.placeholder)
+			image.SetCredit(attrs.credit)
+			image.SetZOrder(attrs.zOrder)
 			images = append(images, image)
 		case *ast.RawHTML:
 			// Get the raw HTML content
 			htmlContent := string(childNode.Segments.Value(b))
 
 			if !strings.HasPrefix(htmlContent, "<") {
-				styleName = "" // Reset class attribute for closing tags
-				continue       // Skip if it doesn't look like HTML
+				styleName, color, underline, highlight = "", "", false, false // Reset class attribute for closing tags
+				continue                                                      // Skip if it doesn't look like HTML
 			}
 
 			// Check if it's a closing tag
 			if strings.HasPrefix(htmlContent, "</") && strings.HasSuffix(htmlContent, ">") {
-				styleName = "" // Reset class attribute for closing tags
+				styleName, color, underline, highlight = "", "", false, false // Reset class attribute for closing tags
 				continue
 			}
 
@@ -786,9 +1228,12 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 					Fragment: &deck.Fragment{
 						Value:     "\n",
 						Bold:      false,
+						Underline: underline,
+						Highlight: highlight,
 						StyleName: styleName,
+						Color:     color,
 					}})
-				styleName = "" // Reset class attribute
+				styleName, color, underline, highlight = "", "", false, false // Reset class attribute
 				continue
 			}
 
@@ -796,9 +1241,11 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 			stuffs := allowdInlineElmReg.FindStringSubmatch(htmlContent)
 			isAllowed := len(stuffs) == 2
 			if !isAllowed {
-				styleName = "" // Reset class attribute for disallowed elements
-				continue       // Skip disallowed inline HTML elements
+				styleName, color, underline, highlight = "", "", false, false // Reset class attribute for disallowed elements
+				continue                                                      // Skip disallowed inline HTML elements
 			}
+			underline = stuffs[1] == "u" || stuffs[1] == "ins"
+			highlight = stuffs[1] == "mark"
 
 			// Extract class attribute if present
 			matches := classRe.FindStringSubmatch(htmlContent)
@@ -811,6 +1258,16 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 			} else {
 				styleName = stuffs[1] // Use the matched element name as style name
 			}
+
+			// Extract text color from style attribute if present
+			color = ""
+			if m := styleColorRe.FindStringSubmatch(htmlContent); m != nil {
+				c, err := deck.NormalizeColor(m[1])
+				if err != nil {
+					return nil, nil, err
+				}
+				color = c
+			}
 		case *ast.CodeSpan:
 			children, childImages, err := toFragments(baseDir, b, childNode, seedFragment)
 			if err != nil {
@@ -823,8 +1280,11 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 					Link:      children[0].Link,
 					Bold:      children[0].Bold,
 					Italic:    children[0].Italic,
+					Underline: children[0].Underline || underline,
+					Highlight: children[0].Highlight || highlight,
 					Code:      true,
 					StyleName: styleName,
+					Color:     color,
 				}})
 			images = append(images, childImages...)
 		case *east.Strikethrough:
@@ -837,11 +1297,13 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 				// Previously, Bold, Italic, and Code were used as flags to control styles. However, to ensure
 				// consistency with raw HTML tags, we will now simply assign StyleName instead of adding new flag fields.
 				Fragment: &deck.Fragment{
-					Value:  children[0].Value,
-					Link:   children[0].Link,
-					Bold:   children[0].Bold,
-					Italic: children[0].Italic,
-					Code:   children[0].Code,
+					Value:     children[0].Value,
+					Link:      children[0].Link,
+					Bold:      children[0].Bold,
+					Italic:    children[0].Italic,
+					Underline: children[0].Underline,
+					Highlight: children[0].Highlight,
+					Code:      children[0].Code,
 					// The GFM specification states that Strikethrough corresponds to the `del` tag, not the `s` tag,
 					// and goldmark's implementation follows this. Therefore, the style name should also be `del`.
 					StyleName: deck.StyleDel,
@@ -858,9 +1320,34 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 	return frags, images, nil
 }
 
+// parsePageConfig parses the content of a comment as a page configuration.
+// It returns nil if the comment is not a page configuration.
+// In strict mode, a comment that looks like JSON must be a valid page configuration without unknown keys.
+func parsePageConfig(block string, strict bool) (*Config, error) {
+	config := &Config{}
+	if !strict {
+		if err := json.Unmarshal([]byte(block), config); err != nil {
+			return nil, nil //nolint:nilerr
+		}
+		return config, nil
+	}
+	if !strings.HasPrefix(block, "{") {
+		return nil, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(block))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid page config %s: %w", block, err)
+	}
+	return config, nil
+}
+
 // classRe is a regular expression to extract class attribute from HTML tags.
 var classRe = regexp.MustCompile(`class="\s*([^"]*)\s*"|class='\s*([^']*)\s*'`)
 
+// styleColorRe is a regular expression to extract the color property from the style attribute of HTML tags.
+var styleColorRe = regexp.MustCompile(`style=["'](?:[^"']*;)?\s*color\s*:\s*([^;"']+)`)
+
 // DiffContents compares two Contents and returns the page numbers that have changed.
 // Page numbers are 1-indexed.
 func DiffContents(oldContents, newContents Contents) []int {
@@ -888,7 +1375,7 @@ func DiffContents(oldContents, newContents Contents) []int {
 		}
 
 		// Compare the content of the pages
-		if (newContents[i].Freeze == nil || !*newContents[i].Freeze) && !contentEqual(oldContents[i], newContents[i]) {
+		if !newContents[i].Freeze.frozen() && !contentEqual(oldContents[i], newContents[i]) {
 			changedPages = append(changedPages, i+1) // 1-indexed
 		}
 	}
@@ -915,7 +1402,11 @@ func contentEqual(old, new *Content) bool {
 	}
 
 	// Compare layout and flags
-	if old.Layout != new.Layout || old.Freeze != new.Freeze || old.Skip != new.Skip || old.Ignore != new.Ignore {
+	if !jsonEqual(old.ParagraphSpacing, new.ParagraphSpacing) {
+		return false
+	}
+	if old.Layout != new.Layout || old.Freeze != new.Freeze || old.Skip != new.Skip || old.Ignore != new.Ignore ||
+		old.Autofit != new.Autofit || old.Direction != new.Direction || old.ImageColumns != new.ImageColumns || old.ImageRows != new.ImageRows {
 		return false
 	}
 
@@ -980,6 +1471,11 @@ func contentEqual(old, new *Content) bool {
 		return false
 	}
 
+	// Compare elements
+	if !jsonEqual(old.Elements, new.Elements) {
+		return false
+	}
+
 	return true
 }
 
@@ -1005,12 +1501,37 @@ func isPageDelimiter(line []byte) bool {
 	})
 }
 
+// pageBreakComment is the page break directive accepted in addition to "---" when no page delimiter is configured.
+const pageBreakComment = "<!-- pagebreak -->"
+
 // splitPages splits markdown content by delimiters
 // while respecting fenced code blocks and setext headings to avoid splitting inside them.
-func splitPages(b []byte) [][]byte {
+// If delimiter is not empty, only the lines of delimiter split the pages.
+func splitPages(b []byte, delimiter string) [][]byte {
+	var bpages [][]byte
+	for _, span := range splitPageSpans(b, delimiter) {
+		bpages = append(bpages, bytes.Clone(b[span[0]:span[1]]))
+	}
+	return bpages
+}
+
+// splitPageSpans returns the start and end offsets in b of the non-empty pages split by delimiters,
+// without the surrounding whitespace of each page.
+// If delimiter is not empty, only the lines of delimiter split the pages. Otherwise, thematic breaks of "---"
+// and the lines of pageBreakComment split the pages.
+func splitPageSpans(b []byte, delimiter string) [][2]int {
 	md := newParser()
 	reader := text.NewReader(b)
 	doc := md.Parser().Parse(reader)
+	lines := bytes.Split(b, []byte("\n"))
+	offsets := make([]int, len(lines)+1)
+	for i, line := range lines {
+		offsets[i+1] = offsets[i] + len(line) + 1
+	}
+
+	if delimiter != "" {
+		return pageSpans(b, lines, offsets, delimiterLines(doc, lines, offsets, delimiter))
+	}
 
 	// Count original thematic breaks
 	originalBreakCount := 0
@@ -1023,8 +1544,7 @@ func splitPages(b []byte) [][]byte {
 		return ast.WalkContinue, nil
 	})
 
-	lines := bytes.Split(b, []byte("\n"))
-	var separatorLines = []int{-1} // Start with -1 to handle the first page correctly
+	var separatorLines []int
 	// For each potential delimiter line, check if removing it would reduce the thematic break count
 	for lineNum, line := range lines {
 		if isPageDelimiter(line) {
@@ -1055,21 +1575,64 @@ func splitPages(b []byte) [][]byte {
 		}
 	}
 
-	// Split content based on separator line positions
-	var bpages [][]byte
+	separatorLines = append(separatorLines, delimiterLines(doc, lines, offsets, pageBreakComment)...)
+	slices.Sort(separatorLines)
+	return pageSpans(b, lines, offsets, separatorLines)
+}
+
+// delimiterLines returns the numbers of the lines of delimiter outside code blocks.
+// offsets are the start offsets of the lines in the source of doc.
+func delimiterLines(doc ast.Node, lines [][]byte, offsets []int, delimiter string) []int {
+	inCode := map[int]bool{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			segs := n.Lines()
+			for i := range segs.Len() {
+				line, found := slices.BinarySearch(offsets, segs.At(i).Start)
+				if !found {
+					line-- // the segment starts after the indentation
+				}
+				inCode[line] = true
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	var nums []int
+	for i, line := range lines {
+		if !inCode[i] && string(bytes.TrimSpace(line)) == delimiter {
+			nums = append(nums, i)
+		}
+	}
+	return nums
+}
+
+// pageSpans returns the spans of the non-empty pages of b separated by the lines of separatorLines in ascending order.
+func pageSpans(b []byte, lines [][]byte, offsets []int, separatorLines []int) [][2]int {
+	separatorLines = append([]int{-1}, separatorLines...) // Start with -1 to handle the first page correctly
+	var spans [][2]int
 	for i, sepLine := range separatorLines {
 		from := sepLine + 1
 		to := len(lines)
 		if i < len(separatorLines)-1 {
 			to = separatorLines[i+1]
 		}
-		pageLines := lines[from:to]
-		pageContent := bytes.TrimSpace(bytes.Join(pageLines, []byte("\n")))
-		if len(pageContent) > 0 {
-			bpages = append(bpages, pageContent)
+		if from >= to {
+			continue
+		}
+		start, end := offsets[from], offsets[to]-1
+		page := b[start:end]
+		start += len(page) - len(bytes.TrimLeftFunc(page, unicode.IsSpace))
+		end -= len(page) - len(bytes.TrimRightFunc(page, unicode.IsSpace))
+		if start < end {
+			spans = append(spans, [2]int{start, end})
 		}
 	}
-	return bpages
+	return spans
 }
 
 func environToMap() map[string]string {
@@ -1082,3 +1645,42 @@ func environToMap() map[string]string {
 	}
 	return envMap
 }
+
+// resolvePath resolves the relative path p against baseDir.
+// baseDir is a local directory, or a URL such as https://example.com/decks/ for markdown fetched from the URL.
+// URLs and data URIs are returned as they are.
+func resolvePath(baseDir, p string) string {
+	if strings.Contains(p, "://") || strings.HasPrefix(p, "data:") {
+		return p
+	}
+	if strings.Contains(baseDir, "://") {
+		base, err := url.Parse(baseDir)
+		if err != nil {
+			return p
+		}
+		if !strings.HasSuffix(base.Path, "/") {
+			base.Path += "/"
+		}
+		ref, err := url.Parse(p)
+		if err != nil {
+			return p
+		}
+		return base.ResolveReference(ref).String()
+	}
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(baseDir, p)
+}
+
+// altText returns the alternative text of the image node.
+func altText(n *ast.Image, b []byte) string {
+	var alt strings.Builder
+	_ = ast.Walk(n, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := node.(*ast.Text); ok && entering {
+			alt.Write(t.Segment.Value(b))
+		}
+		return ast.WalkContinue, nil
+	})
+	return alt.String()
+}
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/k1LoW/deck"
 	"github.com/tenntenn/golden"
 )
 
@@ -47,6 +48,49 @@ func TestParse(t *testing.T) {
 		{"../testdata/hr.md"},
 		{"../testdata/tables.md"},
 		{"../testdata/key.md"},
+		{"../testdata/layout_rules.md"},
+		{"../testdata/owner.md"},
+		{"../testdata/sections.md"},
+		{"../testdata/heading_directives.md"},
+		{"../testdata/block_class.md"},
+		{"../testdata/visible_page.md"},
+		{"../testdata/footnotes.md"},
+		{"../testdata/footnotes_slide.md"},
+		{"../testdata/default_layouts.md"},
+		{"../testdata/color.md"},
+		{"../testdata/cel_functions.md"},
+		{"../testdata/image_alt.md"},
+		{"../testdata/table_split.md"},
+		{"../testdata/autosplit.md"},
+		{"../testdata/details.md"},
+		{"../testdata/table_text.md"},
+		{"../testdata/definition_list.md"},
+		{"../testdata/definition_list_table.md"},
+		{"../testdata/autofit.md"},
+		{"../testdata/placeholder_position.md"},
+		{"../testdata/image_placeholder.md"},
+		{"../testdata/code_text.md"},
+		{"../testdata/code_text_directive.md"},
+		{"../testdata/chart.md"},
+		{"../testdata/image_grid.md"},
+		{"../testdata/notes_separator.md"},
+		{"../testdata/table_valign.md"},
+		{"../testdata/table_header.md"},
+		{"../testdata/image_data_uri.md"},
+		{"../testdata/direction.md"},
+		{"../testdata/image_credit.md"},
+		{"../testdata/freeze_position.md"},
+		{"../testdata/paragraph_style.md"},
+		{"../testdata/pagebreak.md"},
+		{"../testdata/page_delimiter.md"},
+		{"../testdata/toc.md"},
+		{"../testdata/paragraph_class.md"},
+		{"../testdata/nested_ordered_list.md"},
+		{"../testdata/blockquote_citation.md"},
+		{"../testdata/tagged_notes.md"},
+		{"../testdata/codeblock_lines.md"},
+		{"../testdata/table_transform.md"},
+		{"../testdata/image_z.md"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.in, func(t *testing.T) {
@@ -113,6 +157,13 @@ func TestValidateKeys(t *testing.T) {
 	}
 }
 
+func TestLayoutRulesValidation(t *testing.T) {
+	src := "---\nlayoutRules:\n  title: section\n---\n\n# A\n"
+	if _, err := Parse("../testdata", []byte(src), nil); err == nil {
+		t.Error("Parse() should fail with an invalid layoutRules key")
+	}
+}
+
 func TestGenCodeImage(t *testing.T) {
 	ctx := context.Background()
 
@@ -163,7 +214,7 @@ func TestGenCodeImage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			img, err := genCodeImage(ctx, tt.codeBlockToImageCmd, tt.codeBlock)
+			img, err := genCodeImage(ctx, tt.codeBlockToImageCmd, "", tt.codeBlock)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("genCodeImage() error = %v, wantErr %v", err, tt.wantErr)
@@ -468,3 +519,187 @@ func TestCRLFSupport(t *testing.T) {
 		t.Errorf("ParseFile with CRLF and Parse with LF produce different results.\nLF Parse result:\n%s\n\nCRLF ParseFile result:\n%s", string(lfJSON), string(crlfFromFileJSON))
 	}
 }
+
+func TestToSlidesCodeBlockAsText(t *testing.T) {
+	md, err := Parse(".", []byte("# Code\n\n<!-- code: text -->\n```go\npackage main\n```\n\n```go\npackage image\n```\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slides, err := md.ToSlides(context.Background(), "builtin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(slides[0].Images); got != 1 {
+		t.Errorf("got %d images, want 1 for the code block not rendered as text", got)
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{"valid", "---\ntitle: deck\n---\n\n<!-- {\"layout\": \"title\"} -->\n# Title\n\n<!-- note -->\n", false},
+		{"malformed frontmatter", "---\ntitle: [deck\n---\n\n# Title\n", true},
+		{"unknown frontmatter key", "---\ntitel: deck\n---\n\n# Title\n", true},
+		{"invalid page config", "<!-- {\"layout\": \"title\",} -->\n# Title\n", true},
+		{"unknown page config key", "<!-- {\"layuot\": \"title\"} -->\n# Title\n", true},
+		{"table directive", "<!-- table: text -->\n\n| a |\n|---|\n| 1 |\n", false},
+		{"unknown table directive", "<!-- table: image -->\n\n| a |\n|---|\n| 1 |\n", true},
+		{"table directive without table", "<!-- table: text -->\n\n# Title\n", true},
+		{"table valign directive", "<!-- table: text valign=Middle header-valign=bottom -->\n\n| a |\n|---|\n| 1 |\n", false},
+		{"invalid table valign directive", "<!-- table: valign=center -->\n\n| a |\n|---|\n| 1 |\n", true},
+		{"table header directive", "<!-- table: header=false -->\n\n| a |\n|---|\n| 1 |\n", false},
+		{"invalid table header directive", "<!-- table: header=no -->\n\n| a |\n|---|\n| 1 |\n", true},
+		{"table transpose directive", "<!-- table: transpose=true sort=a:desc -->\n\n| a |\n|---|\n| 1 |\n", false},
+		{"invalid table transpose directive", "<!-- table: transpose=yes -->\n\n| a |\n|---|\n| 1 |\n", true},
+		{"invalid table sort directive", "<!-- table: sort=a:up -->\n\n| a |\n|---|\n| 1 |\n", true},
+		{"code directive", "<!-- code: text -->\n```go\npackage main\n```\n", false},
+		{"unknown code directive", "<!-- code: html -->\n```go\npackage main\n```\n", true},
+		{"code directive without code block", "<!-- code: text -->\n\n# Title\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(".", []byte(tt.in), nil); err != nil {
+				t.Fatalf("non-strict parse should not fail: %v", err)
+			}
+			_, err := Parse(".", []byte(tt.in), nil, WithStrict(true))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveTablesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "no table to continue",
+			src:  "# A\n\n---\n\n<!-- {\"continueTable\": true} -->\n\n| a | b |\n|---|---|\n",
+		},
+		{
+			name: "different number of columns",
+			src:  "| a | b |\n|---|---|\n| 1 | 2 |\n\n---\n\n<!-- {\"continueTable\": true} -->\n\n| 3 |\n|---|\n",
+		},
+		{
+			name: "negative tableMaxRows",
+			src:  "<!-- {\"tableMaxRows\": -1} -->\n\n| a |\n|---|\n| 1 |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(".", []byte(tt.src), nil); err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+}
+
+func TestParagraphLines(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 1},
+		{"0123456789", 1},
+		{"01234567890", 2},
+		{"01234\n56789", 2},
+		{"あいうえお", 1},
+		{"あいうえおか", 2},
+		{"👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧", 1},
+		{"👍🏽👍🏽👍🏽👍🏽👍🏽👍🏽", 2},
+		{"cafe\u0301cafe\u0301xy", 1},
+		{"🇯🇵🇯🇵🇯🇵🇯🇵🇯🇵🇯🇵", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			p := &deck.Paragraph{Fragments: []*deck.Fragment{{Value: tt.in}}}
+			if got := paragraphLines(p, 10); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	tests := []struct {
+		baseDir string
+		p       string
+		want    string
+	}{
+		{"/path/to", "img.png", "/path/to/img.png"},
+		{"/path/to", "../img.png", "/path/img.png"},
+		{"/path/to", "/abs/img.png", "/abs/img.png"},
+		{"/path/to", "https://example.com/img.png", "https://example.com/img.png"},
+		{"https://example.com/decks", "img.png", "https://example.com/decks/img.png"},
+		{"https://example.com/decks/", "images/img.png", "https://example.com/decks/images/img.png"},
+		{"https://example.com/decks", "../img.png", "https://example.com/img.png"},
+		{"https://example.com/decks", "/img.png", "https://example.com/img.png"},
+		{"/path/to", "data:image/png;base64,iVBORw0KGgo=", "data:image/png;base64,iVBORw0KGgo="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.baseDir+" "+tt.p, func(t *testing.T) {
+			if got := resolvePath(tt.baseDir, tt.p); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCommand(t *testing.T) {
+	tests := []struct {
+		c         string
+		shell     string
+		wantShell string
+		wantArgs  []string
+	}{
+		{"silicon", "", "silicon", nil},
+		{"silicon -o {{output}}", "bash", "bash", []string{"-c", "silicon -o {{output}}"}},
+		{"silicon -o out.png", "/usr/bin/zsh", "/usr/bin/zsh", []string{"-c", "silicon -o out.png"}},
+		{"silicon -o out.png", "cmd", "cmd", []string{"/c", "silicon -o out.png"}},
+		{"silicon -o out.png", `C:\Windows\System32\CMD.EXE`, `C:\Windows\System32\CMD.EXE`, []string{"/c", "silicon -o out.png"}},
+		{"silicon -o out.png", "powershell", "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", "silicon -o out.png"}},
+		{"silicon -o out.png", `C:\Program Files\PowerShell\7\pwsh.exe`, `C:\Program Files\PowerShell\7\pwsh.exe`, []string{"-NoProfile", "-NonInteractive", "-Command", "silicon -o out.png"}},
+		{"silicon -o out.png", `C:\Program Files\Git\bin\bash.exe`, `C:\Program Files\Git\bin\bash.exe`, []string{"-c", "silicon -o out.png"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			gotShell, gotArgs, err := buildCommand(tt.c, tt.shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotShell != tt.wantShell {
+				t.Errorf("got shell %q, want %q", gotShell, tt.wantShell)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("got args %q, want %q", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestIsWindowsShell(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  bool
+	}{
+		{"sh", false},
+		{"/bin/bash", false},
+		{`C:\Program Files\Git\bin\bash.exe`, false},
+		{"cmd", true},
+		{"cmd.exe", true},
+		{`C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`, true},
+		{"pwsh", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			if got := isWindowsShell(tt.shell); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
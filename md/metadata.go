@@ -0,0 +1,37 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+)
+
+// Metadata returns the metadata of the presentation file given by the frontmatter.
+// The description and the values of the properties are expanded as templates, e.g. `{{ env.GITHUB_SHA }}`
+// or `{{ gitCommit }}`.
+func (md *MD) Metadata() (*deck.Metadata, error) {
+	m := &deck.Metadata{}
+	if md.Frontmatter == nil {
+		return m, nil
+	}
+	store := withBuiltinVariables(map[string]any{
+		"env": environToMap(),
+	})
+	m.Title = md.Frontmatter.Title
+	description, err := expandTemplate(md.Frontmatter.Description, store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand description: %w", err)
+	}
+	m.Description = description
+	for k, v := range md.Frontmatter.Properties {
+		expanded, err := expandTemplate(v, store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand property %q: %w", k, err)
+		}
+		if m.Properties == nil {
+			m.Properties = map[string]string{}
+		}
+		m.Properties[k] = expanded
+	}
+	return m, nil
+}
@@ -0,0 +1,168 @@
+package md
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/deck/textmeasure"
+	"github.com/k1LoW/errors"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// `deck notes pull` takes the speaker notes edited in the presentation back into the comments of the markdown,
+// leaving the rest of the pages as they are. Only the speaker notes written in plain comments are rewritten,
+// since the notes written with `???`, `<details>` or tags cannot be told apart once they are joined.
+
+// NotesResult is the result of PullSpeakerNotes.
+type NotesResult struct {
+	Markdown []byte // markdown with the speaker notes of the presentation
+	Updated  []int  // pages whose speaker notes were replaced with the speaker notes of the presentation
+	Skipped  []int  // pages whose speaker notes differ, but are not written only in plain comments
+}
+
+// PullSpeakerNotes replaces the speaker notes of the pages of the markdown b with the speaker notes of the
+// slides of the presentation. The pages of the markdown must correspond to the slides one by one, as
+// applied by deck apply. Ignored and frozen pages are kept as they are.
+func PullSpeakerNotes(ctx context.Context, baseDir string, b []byte, remote deck.Slides, cfg *config.Config) (_ *NotesResult, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	b = normalizeLineEndings(b)
+	pages, slides, err := pullPages(ctx, baseDir, b, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(slides) != len(remote) {
+		return nil, fmt.Errorf("the markdown has %d pages, but the presentation has %d pages: run deck apply first", len(slides), len(remote))
+	}
+	var (
+		buf    bytes.Buffer
+		last   int
+		result = &NotesResult{}
+	)
+	for i, p := range pages {
+		if p.slide < 0 || p.content.Freeze.frozen() {
+			continue
+		}
+		note := strings.TrimSpace(remote[p.slide].SpeakerNote)
+		if textmeasure.Equal(strings.TrimSpace(slides[p.slide].SpeakerNote), note) {
+			continue
+		}
+		raw := b[p.start:p.end]
+		replaced, ok := replaceSpeakerNote(raw, p.content.Comments, note)
+		if !ok {
+			result.Skipped = append(result.Skipped, i+1)
+			continue
+		}
+		buf.Write(b[last:p.start])
+		buf.Write(replaced)
+		last = p.end
+		result.Updated = append(result.Updated, i+1)
+	}
+	buf.Write(b[last:])
+	result.Markdown = buf.Bytes()
+	return result, nil
+}
+
+// replaceSpeakerNote replaces the plain comments of the speaker notes of the page raw with note.
+// notes are the speaker notes of the page parsed from the markdown.
+// The note takes the place of the first comment, or follows the page if there are none.
+// It reports false if the speaker notes of the page are not written only in plain comments.
+func replaceSpeakerNote(raw []byte, notes []string, note string) ([]byte, bool) {
+	var (
+		spans    [][2]int
+		comments []string
+	)
+	doc := newParser().Parser().Parse(text.NewReader(raw))
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		v, ok := n.(*ast.HTMLBlock)
+		if !entering || !ok || v.HTMLBlockType != ast.HTMLBlockType2 || v.Lines().Len() == 0 {
+			return ast.WalkContinue, nil
+		}
+		block := commentBlockText(v, raw)
+		if !isSpeakerNoteComment(block) {
+			return ast.WalkContinue, nil
+		}
+		end := v.Lines().At(v.Lines().Len() - 1).Stop
+		if v.HasClosure() {
+			end = v.ClosureLine.Stop
+		}
+		for end > 0 && raw[end-1] == '\n' {
+			end--
+		}
+		spans = append(spans, [2]int{v.Lines().At(0).Start, end})
+		comments = append(comments, block)
+		return ast.WalkContinue, nil
+	})
+	if !slices.Equal(comments, notes) {
+		return nil, false
+	}
+	rendered := renderSpeakerNote(note)
+	if len(spans) == 0 {
+		if rendered == "" {
+			return raw, true
+		}
+		return append(bytes.TrimRight(raw, "\n"), []byte("\n\n"+rendered)...), true
+	}
+	var (
+		buf  bytes.Buffer
+		last int
+	)
+	for i, span := range spans {
+		buf.Write(raw[last:span[0]])
+		if i == 0 && rendered != "" {
+			buf.WriteString(rendered)
+		}
+		last = span[1]
+		if i > 0 || rendered == "" {
+			// Remove the blank lines following the removed comment.
+			for last < len(raw) && raw[last] == '\n' {
+				last++
+			}
+		}
+	}
+	buf.Write(raw[last:])
+	return bytes.TrimRight(buf.Bytes(), "\n"), true
+}
+
+// isSpeakerNoteComment reports whether the comment block is a speaker note, not a directive.
+func isSpeakerNoteComment(block string) bool {
+	if _, ok := parseTableDirective(block); ok {
+		return false
+	}
+	if _, ok := parseCodeDirective(block); ok {
+		return false
+	}
+	if c, _ := parsePageConfig(block, false); c != nil {
+		return false
+	}
+	if _, _, ok := parseTaggedNote(block); ok {
+		return false
+	}
+	return true
+}
+
+// commentBlockText returns the text of the comment block without the comment markers.
+func commentBlockText(v *ast.HTMLBlock, b []byte) string {
+	return strings.TrimSpace(strings.TrimSuffix(
+		strings.TrimPrefix(strings.TrimSpace(string(v.Lines().Value(b))), "<!--"), "-->"))
+}
+
+// renderSpeakerNote renders the speaker note as a comment.
+func renderSpeakerNote(note string) string {
+	note = strings.TrimSpace(note)
+	switch {
+	case note == "":
+		return ""
+	case strings.Contains(note, "\n"):
+		return "<!--\n" + note + "\n-->"
+	default:
+		return "<!-- " + note + " -->"
+	}
+}
@@ -0,0 +1,32 @@
+package md
+
+import (
+	"bytes"
+	"strings"
+)
+
+// A `???` line separates the contents of a page from its speaker notes, as in remark and Marp.
+// The lines after the separator are removed from the page and added to the speaker notes, so that
+// decks written for them can be migrated with fewer edits.
+
+var notesSeparator = []byte("???")
+
+// extractNotesSeparator splits b at the first `???` line outside code blocks, and returns the contents
+// before it and the speaker notes after it.
+func extractNotesSeparator(b []byte) ([]byte, string) {
+	var (
+		offset  int
+		inFence bool
+	)
+	for line := range bytes.SplitAfterSeq(b, []byte("\n")) {
+		trimmed := bytes.TrimSpace(line)
+		if isFenceLine(line) {
+			inFence = !inFence
+		}
+		if !inFence && bytes.Equal(trimmed, notesSeparator) {
+			return b[:offset], strings.TrimSpace(string(b[offset+len(line):]))
+		}
+		offset += len(line)
+	}
+	return b, ""
+}
@@ -0,0 +1,103 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+)
+
+func TestPullSpeakerNotes(t *testing.T) {
+	const local = "---\npresentationID: xxx\n---\n\n# Title\n\n<!-- hello -->\n\n---\n\n<!-- {\"layout\": \"section\"} -->\n\n## Agenda\n\n<!-- one -->\n\n- item\n\n<!--\ntwo\nlines\n-->\n\n---\n\n## Last\n\nbye\n"
+	notes := func(notes ...string) deck.Slides {
+		var ss deck.Slides
+		for _, n := range notes {
+			ss = append(ss, &deck.Slide{SpeakerNote: n})
+		}
+		return ss
+	}
+	tests := []struct {
+		name        string
+		local       string
+		remote      deck.Slides
+		want        string
+		wantUpdated []int
+		wantSkipped []int
+		wantErr     bool
+	}{
+		{
+			"no edits",
+			local,
+			notes("hello", "one\n\ntwo\nlines", ""),
+			local,
+			nil,
+			nil,
+			false,
+		},
+		{
+			"edited notes",
+			local,
+			notes("hello, world", "three", "see you\nsoon"),
+			"---\npresentationID: xxx\n---\n\n# Title\n\n<!-- hello, world -->\n\n---\n\n<!-- {\"layout\": \"section\"} -->\n\n## Agenda\n\n<!-- three -->\n\n- item\n\n---\n\n## Last\n\nbye\n\n<!--\nsee you\nsoon\n-->\n",
+			[]int{1, 2, 3},
+			nil,
+			false,
+		},
+		{
+			"removed notes",
+			local,
+			notes("", "one\n\ntwo\nlines", ""),
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n<!-- {\"layout\": \"section\"} -->\n\n## Agenda\n\n<!-- one -->\n\n- item\n\n<!--\ntwo\nlines\n-->\n\n---\n\n## Last\n\nbye\n",
+			[]int{1},
+			nil,
+			false,
+		},
+		{
+			"notes after the separator",
+			"# Title\n\n???\nhello\n",
+			notes("bye"),
+			"# Title\n\n???\nhello\n",
+			nil,
+			[]int{1},
+			false,
+		},
+		{
+			"ignored and frozen pages",
+			"<!-- {\"ignore\": true} -->\n\n# A\n\n---\n\n<!-- {\"freeze\": true} -->\n\n# B\n\n<!-- b -->\n",
+			notes("edited"),
+			"<!-- {\"ignore\": true} -->\n\n# A\n\n---\n\n<!-- {\"freeze\": true} -->\n\n# B\n\n<!-- b -->\n",
+			nil,
+			nil,
+			false,
+		},
+		{
+			"pages added",
+			local,
+			notes("hello"),
+			"",
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PullSpeakerNotes(t.Context(), ".", []byte(tt.local), tt.remote, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, string(got.Markdown)); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.wantUpdated, got.Updated); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.wantSkipped, got.Skipped); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
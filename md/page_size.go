@@ -0,0 +1,51 @@
+package md
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-yaml"
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/errors"
+)
+
+// PageSize returns the page size of the presentation in the frontmatter, or nil if it is not set.
+func (md *MD) PageSize() (_ *deck.PageSize, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if md.Frontmatter == nil || md.Frontmatter.PageSize == "" {
+		return nil, nil
+	}
+	return deck.ParsePageSize(md.Frontmatter.PageSize)
+}
+
+// ReadPageSize returns the page size in the frontmatter of the markdown file without parsing the pages,
+// or nil if the file does not exist or the page size is not set.
+func ReadPageSize(mdFile string) (_ *deck.PageSize, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	b, err := os.ReadFile(mdFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	const fmSep = "---\n"
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(b, []byte(fmSep)) {
+		return nil, nil
+	}
+	stuffs := bytes.SplitN(b, []byte(fmSep), 3)
+	if len(stuffs) != 3 {
+		return nil, nil
+	}
+	var fm Frontmatter
+	if err := yaml.Unmarshal(stuffs[1], &fm); err != nil {
+		return nil, nil //nolint:nilerr // Files without valid frontmatter are treated as having no frontmatter, as in Parse.
+	}
+	return (&MD{Frontmatter: &fm}).PageSize()
+}
@@ -0,0 +1,59 @@
+package md
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+)
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    *deck.PageSize
+		wantErr bool
+	}{
+		{"not set", "---\ntitle: test\n---\n# Page\n", nil, false},
+		{"no frontmatter", "# Page\n", nil, false},
+		{"preset", "---\npageSize: \"4:3\"\n---\n# Page\n", &deck.PageSize{Name: "4:3", Width: 720, Height: 540}, false},
+		{"custom", "---\npageSize: custom(960,540)\n---\n# Page\n", &deck.PageSize{Name: "custom", Width: 960, Height: 540}, false},
+		{"invalid", "---\npageSize: A4\n---\n# Page\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, err := Parse(".", []byte(tt.in), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := md.PageSize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+
+			// The page size can be read from the file without parsing the pages.
+			f := filepath.Join(t.TempDir(), "deck.md")
+			if err := os.WriteFile(f, []byte(tt.in), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			read, err := ReadPageSize(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, read); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	if got, err := ReadPageSize(filepath.Join(t.TempDir(), "not-found.md")); err != nil || got != nil {
+		t.Errorf("got %v, %v, want nil for a file not found", got, err)
+	}
+}
@@ -0,0 +1,67 @@
+package md
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/deck"
+)
+
+// A title or subtitle heading ending with `@N` (e.g. `# Title @2`) is placed in the N-th title or subtitle
+// placeholder of the layout, counted from top to bottom and then from left to right, instead of the next one.
+
+var placeholderPositionReg = regexp.MustCompile(`\s+@([1-9][0-9]*)$`)
+
+// extractPlaceholderPosition removes the trailing `@N` from the heading fragments and returns N.
+// It returns 0 if the heading has no position.
+func extractPlaceholderPosition(frags []*deck.Fragment) ([]*deck.Fragment, int) {
+	if len(frags) == 0 {
+		return frags, 0
+	}
+	last := frags[len(frags)-1]
+	if last.Code {
+		return frags, 0
+	}
+	m := placeholderPositionReg.FindStringSubmatchIndex(last.Value)
+	if m == nil {
+		return frags, 0
+	}
+	n, err := strconv.Atoi(last.Value[m[2]:m[3]])
+	if err != nil {
+		return frags, 0
+	}
+	trimmed := *last
+	trimmed.Value = last.Value[:m[0]]
+	frags = append(frags[:len(frags)-1:len(frags)-1], &trimmed)
+	if strings.TrimSpace(trimmed.Value) == "" {
+		frags = frags[:len(frags)-1]
+	}
+	return frags, n
+}
+
+// placeText places the heading text and fragments at the 1-based position of the texts and bodies.
+// Positions skipped are filled with empty texts, which leave their placeholders empty.
+// A position of 0 places the heading after the last one.
+func placeText(texts []string, bodies []*deck.Body, text string, frags []*deck.Fragment, position int) ([]string, []*deck.Body, error) {
+	body := &deck.Body{
+		Paragraphs: []*deck.Paragraph{{
+			Fragments: frags,
+		}},
+	}
+	if position == 0 {
+		return append(texts, text), append(bodies, body), nil
+	}
+	i := position - 1
+	for len(texts) <= i {
+		texts = append(texts, "")
+		bodies = append(bodies, &deck.Body{})
+	}
+	if len(bodies[i].Paragraphs) > 0 {
+		return nil, nil, fmt.Errorf("placeholder @%d is already used", position)
+	}
+	texts[i] = text
+	bodies[i] = body
+	return texts, bodies, nil
+}
@@ -0,0 +1,43 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlaceholderPosition(t *testing.T) {
+	tests := []struct {
+		name          string
+		src           string
+		wantTitles    []string
+		wantSubtitles []string
+		wantErr       bool
+	}{
+		{"no positions", "# A\n\n# B\n", []string{"A", "B"}, nil, false},
+		{"skipped placeholders", "# B @2\n\n# A @1\n\n## C @3\n", []string{"A", "B"}, []string{"", "", "C"}, false},
+		{"next to the placed one", "# A @2\n\n# B\n", []string{"", "A", "B"}, nil, false},
+		{"not a position", "# Meet @alice\n\n# Step@2\n", []string{"Meet @alice", "Step@2"}, nil, false},
+		{"used placeholder", "# A\n\n# B @1\n", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(".", []byte(tt.src), nil)
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("want error")
+			}
+			if diff := cmp.Diff(tt.wantTitles, m.Contents[0].Titles); diff != "" {
+				t.Errorf("titles mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSubtitles, m.Contents[0].Subtitles); diff != "" {
+				t.Errorf("subtitles mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
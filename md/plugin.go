@@ -0,0 +1,113 @@
+package md
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/k1LoW/deck"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Plugins handle markdown nodes that deck does not handle by itself, such as custom directives
+// (e.g. a fenced div `::: poll`), without forking deck.
+// A plugin that also implements goldmark.Extender extends the markdown parser, e.g. to parse a new
+// syntax such as `@tweet(...)` into its own nodes.
+
+// Plugin handles markdown nodes of a page.
+type Plugin interface {
+	// Handle is called for each block node of a page before deck handles it.
+	// If it returns true, deck does not handle the node and its children.
+	Handle(pc *PluginContext, n ast.Node) (handled bool, err error)
+}
+
+// PluginFunc is a function implementing Plugin.
+type PluginFunc func(pc *PluginContext, n ast.Node) (bool, error)
+
+// Handle implements Plugin.
+func (f PluginFunc) Handle(pc *PluginContext, n ast.Node) (bool, error) {
+	return f(pc, n)
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   []Plugin
+)
+
+// RegisterPlugin registers the plugin for all subsequent parsing.
+// Plugins are called in the order of registration, and the first one that handles a node wins.
+func RegisterPlugin(p Plugin) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	plugins = append(plugins, p)
+}
+
+func registeredPlugins() []Plugin {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	return slices.Clone(plugins)
+}
+
+// pluginExtenders returns the parser extensions of the registered plugins.
+func pluginExtenders() []goldmark.Extender {
+	var extenders []goldmark.Extender
+	for _, p := range registeredPlugins() {
+		if e, ok := p.(goldmark.Extender); ok {
+			extenders = append(extenders, e)
+		}
+	}
+	return extenders
+}
+
+// PluginContext is the page being parsed, passed to plugins.
+// Plugins emit Bodies, Images, Tables and Elements by appending them to Content,
+// or paragraphs to the current body with AddParagraphs.
+type PluginContext struct {
+	Content *Content
+	BaseDir string // directory that relative paths are resolved against
+	Source  []byte // markdown source of the page that the positions of the nodes refer to
+
+	body   *deck.Body
+	breaks bool
+}
+
+// AddParagraphs appends the paragraphs to the body being built at the node.
+func (pc *PluginContext) AddParagraphs(paragraphs ...*deck.Paragraph) {
+	pc.body.Paragraphs = append(pc.body.Paragraphs, paragraphs...)
+}
+
+// Fragments converts the inline children of n into fragments, in the same way as deck converts paragraphs.
+// Images found in them are returned separately.
+func (pc *PluginContext) Fragments(n ast.Node) ([]*deck.Fragment, []*deck.Image, error) {
+	frags, images, err := toFragments(pc.BaseDir, pc.Source, n, deck.Fragment{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return toDeckFragments(frags, pc.breaks), images, nil
+}
+
+// FencedDivClass returns the class of n if it is a fenced div such as `::: poll`.
+func FencedDivClass(n ast.Node) (string, bool) {
+	fd, ok := n.(*fencedDiv)
+	if !ok {
+		return "", false
+	}
+	return fd.class, true
+}
+
+// handlePlugins passes the block node n to the registered plugins, and reports whether one of them handled it.
+func handlePlugins(ps []Plugin, pc *PluginContext, n ast.Node) (bool, error) {
+	if n.Type() != ast.TypeBlock || n.Kind() == ast.KindDocument {
+		return false, nil
+	}
+	for _, p := range ps {
+		handled, err := p.Handle(pc, n)
+		if err != nil {
+			return false, err
+		}
+		if handled {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,62 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+	"github.com/yuin/goldmark/ast"
+)
+
+func TestPlugin(t *testing.T) {
+	saved := plugins
+	t.Cleanup(func() {
+		plugins = saved
+	})
+	RegisterPlugin(PluginFunc(func(pc *PluginContext, n ast.Node) (bool, error) {
+		class, ok := FencedDivClass(n)
+		if !ok || class != "poll" {
+			return false, nil
+		}
+		var options []any
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			frags, _, err := pc.Fragments(c)
+			if err != nil {
+				return false, err
+			}
+			for _, f := range frags {
+				options = append(options, f.Value)
+			}
+		}
+		pc.AddParagraphs(&deck.Paragraph{Fragments: []*deck.Fragment{{Value: "Vote now", Bold: true}}})
+		pc.Content.Elements = append(pc.Content.Elements, &deck.Element{Kind: "poll", Data: map[string]any{"options": options}})
+		return true, nil
+	}))
+
+	md, err := Parse(".", []byte("# Poll\n\n::: poll\nYes\n:::\n\n::: note\nkept\n:::\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := md.Contents[0]
+	wantBodies := []*deck.Body{{Paragraphs: []*deck.Paragraph{{Fragments: []*deck.Fragment{{Value: "Vote now", Bold: true}}}}}}
+	if diff := cmp.Diff(wantBodies, c.Bodies); diff != "" {
+		t.Error(diff)
+	}
+	wantElements := []*deck.Element{{Kind: "poll", Data: map[string]any{"options": []any{"Yes"}}}}
+	if diff := cmp.Diff(wantElements, c.Elements); diff != "" {
+		t.Error(diff)
+	}
+	if len(c.BlockQuotes) != 1 || c.BlockQuotes[0].StyleName != "note" {
+		t.Errorf("fenced divs not handled by the plugin should be block quotes: %v", c.BlockQuotes)
+	}
+	if c.pullable() {
+		t.Error("pages handled by plugins should not be pullable")
+	}
+	slides, err := md.ToSlides(t.Context(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantElements, slides[0].Elements); diff != "" {
+		t.Error(diff)
+	}
+}
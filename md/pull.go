@@ -0,0 +1,407 @@
+package md
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/config"
+	"github.com/k1LoW/errors"
+)
+
+// `deck pull` merges edits made in the presentation into the markdown with a 3-way merge of pages:
+// the markdown last applied is the base, the local markdown is ours and the presentation is theirs.
+// Pages of the presentation are rendered to markdown with only the information that can be read back
+// from the presentation (headings, paragraphs, lists, inline styles, links, tables, block quotes and
+// speaker notes), and base pages are rendered the same way, so that a page is only taken from the
+// presentation if it was actually edited there.
+
+const (
+	conflictMarkerLocal  = "<<<<<<< local"
+	conflictMarkerSep    = "======="
+	conflictMarkerRemote = ">>>>>>> remote"
+)
+
+// MergeResult is the result of Merge.
+type MergeResult struct {
+	Markdown  []byte // merged markdown
+	Updated   []int  // pages replaced with the pages of the presentation
+	Conflicts []int  // pages edited both in the markdown and in the presentation, marked with conflict markers
+}
+
+// pullPage is a page of the markdown.
+type pullPage struct {
+	start, end int      // offsets of the page in the markdown
+	content    *Content // parsed content of the page
+	slide      int      // index of the slide of the page in the presentation, -1 if ignored
+}
+
+// Merge merges the slides of the presentation into the local markdown, using the markdown last applied
+// to the presentation as the base. Pages not edited in the presentation are kept as they are.
+// Pages edited only in the presentation are replaced with the pages rendered from the presentation,
+// and pages edited in both are marked with conflict markers.
+// Pages must not be added, removed or generated (footnotes slides, split tables and autoSplit)
+// since the base was applied.
+func Merge(ctx context.Context, baseDir string, base, local []byte, remote deck.Slides, cfg *config.Config) (_ *MergeResult, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	base = normalizeLineEndings(base)
+	local = normalizeLineEndings(local)
+	basePages, baseSlides, err := pullPages(ctx, baseDir, base, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the markdown last applied: %w", err)
+	}
+	localPages, _, err := pullPages(ctx, baseDir, local, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(baseSlides) != len(remote) {
+		return nil, fmt.Errorf("pages were added or removed in the presentation since the last apply (%d pages applied, %d pages in the presentation)", len(baseSlides), len(remote))
+	}
+
+	type replacement struct {
+		page int
+		text string
+	}
+	var (
+		replacements []replacement
+		unresolved   []int
+		used         = make([]bool, len(localPages))
+		result       = &MergeResult{}
+	)
+	for i, bp := range basePages {
+		if bp.slide < 0 || bp.content.Freeze.frozen() || bp.content.isAgenda() || bp.content.TOC != nil {
+			// Not applied by deck apply, so edits in the presentation are expected.
+			continue
+		}
+		level := bp.content.titleLevel()
+		remoteText := renderSlide(remote[bp.slide], level)
+		if remoteText == renderSlide(baseSlides[bp.slide], level) {
+			continue
+		}
+		baseRaw := base[bp.start:bp.end]
+		k := -1
+		if len(localPages) == len(basePages) {
+			k = i
+		} else {
+			for j, lp := range localPages {
+				if !used[j] && bytes.Equal(local[lp.start:lp.end], baseRaw) {
+					k = j
+					break
+				}
+			}
+		}
+		if k < 0 {
+			unresolved = append(unresolved, i+1)
+			continue
+		}
+		used[k] = true
+		lp := localPages[k]
+		localRaw := local[lp.start:lp.end]
+		if lp.slide >= 0 {
+			if s, err := lp.content.pullSlide(ctx); err == nil && renderSlide(s, level) == remoteText {
+				continue
+			}
+		}
+		if bytes.Equal(localRaw, baseRaw) && bp.content.pullable() {
+			replacements = append(replacements, replacement{page: k, text: withPageConfigs(baseRaw, remoteText)})
+			result.Updated = append(result.Updated, k+1)
+			continue
+		}
+		replacements = append(replacements, replacement{
+			page: k,
+			text: strings.Join([]string{conflictMarkerLocal, string(localRaw), conflictMarkerSep, remoteText, conflictMarkerRemote}, "\n"),
+		})
+		result.Conflicts = append(result.Conflicts, k+1)
+	}
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("pages %v of the markdown last applied were edited in the presentation, but cannot be found in the markdown because pages were added or removed", unresolved)
+	}
+
+	slices.SortFunc(replacements, func(a, b replacement) int {
+		return a.page - b.page
+	})
+	var buf bytes.Buffer
+	last := 0
+	for _, r := range replacements {
+		p := localPages[r.page]
+		buf.Write(local[last:p.start])
+		buf.WriteString(r.text)
+		last = p.end
+	}
+	buf.Write(local[last:])
+	result.Markdown = buf.Bytes()
+	slices.Sort(result.Updated)
+	slices.Sort(result.Conflicts)
+	return result, nil
+}
+
+// normalizeLineEndings converts CRLF and CR to LF.
+func normalizeLineEndings(b []byte) []byte {
+	if bytes.Contains(b, []byte("\r")) {
+		b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+		b = bytes.ReplaceAll(b, []byte("\r"), []byte("\n"))
+	}
+	return b
+}
+
+// pullPages parses the markdown into pages and returns them with the slides of the pages not ignored.
+func pullPages(ctx context.Context, baseDir string, b []byte, cfg *config.Config) ([]*pullPage, deck.Slides, error) {
+	m, err := Parse(baseDir, b, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	offset := pagesOffset(b)
+	var pageDelimiter string
+	if m.Frontmatter != nil {
+		pageDelimiter = strings.TrimSpace(m.Frontmatter.PageDelimiter)
+	}
+	spans := splitPageSpans(b[offset:], pageDelimiter)
+	if len(spans) != len(m.Contents) {
+		return nil, nil, fmt.Errorf("cannot pull markdown with pages generated from other pages (footnotes slides, split tables or autoSplit)")
+	}
+	var (
+		pages  []*pullPage
+		slides deck.Slides
+	)
+	for i, content := range m.Contents {
+		p := &pullPage{start: offset + spans[i][0], end: offset + spans[i][1], content: content, slide: -1}
+		if content.Ignore == nil || !*content.Ignore {
+			s, err := content.pullSlide(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			p.slide = len(slides)
+			slides = append(slides, s)
+		}
+		pages = append(pages, p)
+	}
+	return pages, slides, nil
+}
+
+// pullSlide converts the content to a slide without images of code blocks and charts.
+func (c *Content) pullSlide(ctx context.Context) (*deck.Slide, error) {
+	c2 := *c
+	c2.Ignore = nil
+	c2.CodeBlocks = nil
+	ss, err := Contents{&c2}.toSlides(ctx, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	return ss[0], nil
+}
+
+// pagesOffset returns the offset of the pages in b, following the frontmatter if any, as Parse does.
+func pagesOffset(b []byte) int {
+	sep := []byte("---\n")
+	offset := 0
+	if bytes.HasPrefix(b, sep) {
+		stuff := bytes.SplitN(b[len(sep):], sep, 2)
+		if len(stuff) == 2 && yaml.Unmarshal(stuff[0], &Frontmatter{}) == nil {
+			offset = len(sep) + len(stuff[0]) + len(sep)
+		}
+	}
+	if bytes.HasPrefix(b[offset:], sep) {
+		offset += len(sep)
+	}
+	return offset
+}
+
+// pullable reports whether the page can be replaced with the page rendered from the presentation
+// without losing what cannot be read back from the presentation.
+func (c *Content) pullable() bool {
+	return len(c.Images) == 0 && len(c.CodeBlocks) == 0 && !c.plugged && c.directives == nil
+}
+
+var commentLineReg = regexp.MustCompile(`^<!--(.*)-->$`)
+
+// withPageConfigs prepends the page configuration comments of the raw page to the rendered page.
+func withPageConfigs(raw []byte, rendered string) string {
+	var configs []string
+	for line := range strings.SplitSeq(string(raw), "\n") {
+		m := commentLineReg.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		if c, _ := parsePageConfig(strings.TrimSpace(m[1]), false); c != nil {
+			configs = append(configs, strings.TrimSpace(line))
+		}
+	}
+	if len(configs) == 0 {
+		return rendered
+	}
+	return strings.Join(configs, "\n") + "\n\n" + rendered
+}
+
+// renderSlide renders the slide to markdown. titleLevel is the heading level of the titles.
+func renderSlide(s *deck.Slide, titleLevel int) string {
+	titleLevel = max(titleLevel, 1)
+	var blocks []string
+	for i := range max(len(s.Titles), len(s.Subtitles), len(s.Bodies)) {
+		if i < len(s.Titles) {
+			blocks = append(blocks, renderHeading(titleLevel, s.Titles[i]))
+		}
+		if i < len(s.Subtitles) {
+			blocks = append(blocks, renderHeading(min(titleLevel+1, sentinelLevel-1), s.Subtitles[i]))
+		}
+		if i < len(s.Bodies) && s.Bodies[i] != nil {
+			if b := renderParagraphs(s.Bodies[i].Paragraphs); b != "" {
+				blocks = append(blocks, b)
+			}
+		}
+	}
+	for _, bq := range s.BlockQuotes {
+		if b := renderParagraphs(bq.Paragraphs); b != "" {
+			lines := strings.Split(b, "\n")
+			for j, line := range lines {
+				lines[j] = strings.TrimRight("> "+line, " ")
+			}
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		}
+	}
+	for _, t := range s.Tables {
+		if b := renderTable(t); b != "" {
+			blocks = append(blocks, b)
+		}
+	}
+	if note := renderSpeakerNote(s.SpeakerNote); note != "" {
+		blocks = append(blocks, note)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// renderHeading renders the heading of the level.
+func renderHeading(level int, title string) string {
+	return strings.Repeat("#", level) + " " + strings.Join(strings.Fields(title), " ")
+}
+
+// renderParagraphs renders the paragraphs as markdown paragraphs and lists.
+func renderParagraphs(paragraphs []*deck.Paragraph) string {
+	var (
+		b    strings.Builder
+		prev *deck.Paragraph
+	)
+	for _, p := range paragraphs {
+		text := renderFragments(p.Fragments, false)
+		if text == "" && p.Bullet == deck.BulletNone {
+			continue
+		}
+		if prev != nil {
+			if prev.Bullet != deck.BulletNone && p.Bullet != deck.BulletNone {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		switch p.Bullet {
+		case deck.BulletDash:
+			b.WriteString(strings.Repeat("    ", p.Nesting) + "- ")
+		case deck.BulletNumbered:
+			b.WriteString(strings.Repeat("    ", p.Nesting) + "1. ")
+		}
+		b.WriteString(text)
+		prev = p
+	}
+	return b.String()
+}
+
+// renderTable renders the table as a pipe table whose first row is the header row.
+// A table without a header row is rendered with an empty header row, which is parsed as an alignment-only table.
+func renderTable(t *deck.Table) string {
+	if len(t.Rows) == 0 {
+		return ""
+	}
+	rows := t.Rows
+	header := len(rows[0].Cells) > 0 && rows[0].Cells[0].IsHeader
+	if !header {
+		empty := &deck.TableRow{}
+		for _, cell := range rows[0].Cells {
+			empty.Cells = append(empty.Cells, &deck.TableCell{Alignment: cell.Alignment})
+		}
+		rows = append([]*deck.TableRow{empty}, rows...)
+	}
+	var lines []string
+	for i, row := range rows {
+		var cells []string
+		for _, cell := range row.Cells {
+			// Header cells are rendered in bold by deck, so bold is not part of the content.
+			text := renderFragments(cell.Fragments, header && i == 0)
+			cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			var delims []string
+			for _, cell := range row.Cells {
+				switch cell.Alignment {
+				case "CENTER":
+					delims = append(delims, ":---:")
+				case "END":
+					delims = append(delims, "---:")
+				default:
+					delims = append(delims, "---")
+				}
+			}
+			lines = append(lines, "| "+strings.Join(delims, " | ")+" |")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderFragments renders the fragments as inline markdown.
+// Adjacent fragments of the same style are rendered together. If noBold is true, bold is ignored.
+func renderFragments(frags []*deck.Fragment, noBold bool) string {
+	var merged []deck.Fragment
+	for _, f := range frags {
+		if f == nil || f.Value == "" {
+			continue
+		}
+		v := deck.Fragment{Value: f.Value, Bold: f.Bold && !noBold, Italic: f.Italic, Underline: f.Underline, Code: f.Code, Link: f.Link}
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if last.Bold == v.Bold && last.Italic == v.Italic && last.Underline == v.Underline && last.Code == v.Code && last.Link == v.Link {
+				last.Value += v.Value
+				continue
+			}
+		}
+		merged = append(merged, v)
+	}
+	var b strings.Builder
+	for _, f := range merged {
+		b.WriteString(renderFragment(f))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// renderFragment renders the fragment as inline markdown, keeping the surrounding spaces outside the markers.
+func renderFragment(f deck.Fragment) string {
+	core := strings.TrimSpace(f.Value)
+	if core == "" {
+		return strings.ReplaceAll(f.Value, "\n", "<br>")
+	}
+	start := strings.Index(f.Value, core)
+	leading, trailing := f.Value[:start], f.Value[start+len(core):]
+	switch {
+	case f.Code:
+		core = "`" + core + "`"
+	case f.Bold && f.Italic:
+		core = "***" + core + "***"
+	case f.Bold:
+		core = "**" + core + "**"
+	case f.Italic:
+		core = "*" + core + "*"
+	}
+	if f.Underline {
+		core = "<u>" + core + "</u>"
+	}
+	if f.Link != "" {
+		core = "[" + core + "](" + f.Link + ")"
+	}
+	s := leading + core + trailing
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
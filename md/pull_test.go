@@ -0,0 +1,158 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	const base = "---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n<!-- {\"layout\": \"section\"} -->\n\n## Agenda\n\n- one\n- **two**\n\n---\n\n## Last\n\nbye\n"
+	tests := []struct {
+		name          string
+		local         string
+		remote        string
+		want          string
+		wantUpdated   []int
+		wantConflicts []int
+		wantErr       bool
+	}{
+		{
+			"no edits in the presentation",
+			base,
+			base,
+			base,
+			nil,
+			nil,
+			false,
+		},
+		{
+			"edits only in the presentation",
+			base,
+			"# Title\n\n---\n\n## Agenda\n\n- one\n- **two**\n    - three\n\n---\n\n## Last\n\nbye\n",
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n<!-- {\"layout\": \"section\"} -->\n\n## Agenda\n\n- one\n- **two**\n    - three\n\n---\n\n## Last\n\nbye\n",
+			[]int{2},
+			nil,
+			false,
+		},
+		{
+			"edits only in the markdown",
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n## Agenda\n\n- one\n\n---\n\n## Last\n\nbye\n",
+			base,
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n## Agenda\n\n- one\n\n---\n\n## Last\n\nbye\n",
+			nil,
+			nil,
+			false,
+		},
+		{
+			"same edits in both",
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n## Agenda\n\n- one\n- *two*\n\n---\n\n## Last\n\nbye\n",
+			"# Title\n\n---\n\n## Agenda\n\n- one\n- *two*\n\n---\n\n## Last\n\nbye\n",
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n## Agenda\n\n- one\n- *two*\n\n---\n\n## Last\n\nbye\n",
+			nil,
+			nil,
+			false,
+		},
+		{
+			"conflicting edits",
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n<!-- {\"layout\": \"section\"} -->\n\n## Agenda\n\n- one\n- **two**\n\n---\n\n## Last\n\nsee you\n",
+			"# Title\n\n---\n\n## Agenda\n\n- one\n- **two**\n\n---\n\n## Last\n\ngood bye\n",
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n<!-- {\"layout\": \"section\"} -->\n\n## Agenda\n\n- one\n- **two**\n\n---\n\n<<<<<<< local\n## Last\n\nsee you\n=======\n## Last\n\ngood bye\n>>>>>>> remote\n",
+			nil,
+			[]int{3},
+			false,
+		},
+		{
+			"edited page removed from the markdown",
+			"---\npresentationID: xxx\n---\n\n# Title\n\n---\n\n## Agenda\n\n- one\n- **two**\n",
+			"# Title\n\n---\n\n## Agenda\n\n- one\n- **two**\n\n---\n\n## Last\n\ngood bye\n",
+			"",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"pages added in the presentation",
+			base,
+			"# Title\n\n---\n\n## Agenda\n\n- one\n- **two**\n\n---\n\n## Last\n\nbye\n\n---\n\n## New\n",
+			"",
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, err := Parse(".", []byte(tt.remote), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			remote, err := rm.Contents.toSlides(t.Context(), "", "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Merge(t.Context(), ".", []byte(base), []byte(tt.local), remote, nil)
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("want error")
+			}
+			if diff := cmp.Diff(tt.want, string(got.Markdown)); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.wantUpdated, got.Updated); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.wantConflicts, got.Conflicts); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRenderSlide(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			"## Title\n\n### Subtitle\n\nHello **bold** and *italic* with `code` and [link](https://example.com).\n\n1. first\n2. second\n\n<!-- note -->\n",
+			"## Title\n\n### Subtitle\n\nHello **bold** and *italic* with `code` and [link](https://example.com).\n\n1. first\n1. second\n\n<!-- note -->",
+		},
+		{
+			"# Table\n\n| a | b |\n| :-: | --: |\n| 1 | 2 |\n\n> quote\n",
+			"# Table\n\n> quote\n\n| a | b |\n| :---: | ---: |\n| 1 | 2 |",
+		},
+		{
+			"# Table\n\n<!-- table: header=false -->\n| **a** | b |\n| --- | --: |\n| 1 | 2 |\n",
+			"# Table\n\n|  |  |\n| --- | ---: |\n| **a** | b |\n| 1 | 2 |",
+		},
+		{
+			"# Table\n\n|  |  |\n| --- | ---: |\n| a | b |\n",
+			"# Table\n\n|  |  |\n| --- | ---: |\n| a | b |",
+		},
+		{
+			"# Underline\n\nSome <u>underlined</u> and <ins>inserted</ins> text.\n",
+			"# Underline\n\nSome <u>underlined</u> and <u>inserted</u> text.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			m, err := Parse(".", []byte(tt.in), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := m.Contents[0].pullSlide(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, renderSlide(s, m.Contents[0].titleLevel())); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package md
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Sample returns markdown of a sample deck for getting started, which demonstrates page separators,
+// layouts, inline styles, lists, code blocks, tables, charts and speaker notes. Code blocks are rendered
+// by the built-in renderer. A page with two columns is included if one of the layouts has a title and
+// two or more bodies without subtitles.
+func Sample(layouts []ScaffoldLayout) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`---
+codeBlockToImageCommand: builtin
+---
+
+# Getting started with deck
+
+## Write slides in markdown, apply them to Google Slides
+
+<!-- Comments are speaker notes. Run "deck apply" again after editing this file to update the presentation. -->
+
+---
+
+# Writing slides
+
+- Pages are separated by ` + "`---`" + `
+- The first heading is the title, and the rest is the body
+- **Bold**, *italic*, ` + "`code`" + ` and [links](https://github.com/k1LoW/deck) are kept
+    - Lists can be nested
+- Images are written as ` + "`![alt text](path/to/image.png)`" + `
+
+`)
+	for _, l := range layouts {
+		if l.Placeholders.Titles > 0 && l.Placeholders.Subtitles == 0 && l.Placeholders.Bodies >= 2 {
+			fmt.Fprintf(&buf, `---
+
+<!-- {"layout": %q} -->
+
+# Layouts
+
+The layout of a page is chosen with the page config in the comment above.
+
+***
+
+Horizontal rules other than `+"`---`"+` separate the bodies of the layout.
+
+`, l.Name)
+			break
+		}
+	}
+	buf.WriteString("---\n\n# Code blocks\n\n```go\n" + `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello, deck!")
+}
+` + "```\n\n" + `---
+
+# Tables and charts
+
+| Quarter | East  | West |
+| ------- | ----: | ---: |
+| Q1      | 1,200 | 800  |
+| Q2      | 1,500 | 950  |
+| Q3      | 1,400 | 1,100 |
+
+` + "```chart\ntitle: Sales by region\n```\n")
+	return buf.Bytes()
+}
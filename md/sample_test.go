@@ -0,0 +1,72 @@
+package md
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+)
+
+func TestSample(t *testing.T) {
+	title := deck.Placeholders{Titles: 1, Subtitles: 1}
+	body := deck.Placeholders{Titles: 1, Bodies: 1}
+	tests := []struct {
+		name    string
+		layouts []ScaffoldLayout
+		want    []string
+	}{
+		{
+			"without layouts of two columns",
+			[]ScaffoldLayout{{"title", title}, {"title-and-body", body}},
+			[]string{"Getting started with deck", "Writing slides", "Code blocks", "Tables and charts"},
+		},
+		{
+			"with a layout of two columns",
+			[]ScaffoldLayout{
+				{"title", title},
+				{"title-and-body", body},
+				{"title-and-body-3col", deck.Placeholders{Titles: 1, Subtitles: 3, Bodies: 3}},
+				{"title-and-two-columns", deck.Placeholders{Titles: 1, Bodies: 2}},
+			},
+			[]string{"Getting started with deck", "Writing slides", "Layouts", "Code blocks", "Tables and charts"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Sample(tt.layouts)
+			m, err := Parse(".", b, nil, WithStrict(true))
+			if err != nil {
+				t.Fatalf("failed to parse the sample: %v\n%s", err, b)
+			}
+			if m.Frontmatter == nil || m.Frontmatter.CodeBlockToImageCommand != "builtin" {
+				t.Errorf("the sample should render code blocks with the built-in renderer:\n%s", b)
+			}
+			var got []string
+			for _, c := range m.Contents {
+				got = append(got, c.Titles...)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("%s\n%s", diff, b)
+			}
+			ss, err := m.ToSlides(context.Background(), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			images := 0
+			for _, s := range ss {
+				images += len(s.Images)
+			}
+			if images != 2 {
+				t.Errorf("got %d images, want the images of the code block and the chart", images)
+			}
+			placeholders := map[string]deck.Placeholders{}
+			for _, l := range tt.layouts {
+				placeholders[l.Name] = l.Placeholders
+			}
+			if issues := ss.Validate(placeholders); len(issues) > 0 {
+				t.Errorf("the sample does not fit the layouts: %v\n%s", issues, b)
+			}
+		})
+	}
+}
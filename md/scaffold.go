@@ -0,0 +1,53 @@
+package md
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/deck"
+)
+
+// ScaffoldLayout is a layout to generate an example page of by Scaffold.
+type ScaffoldLayout struct {
+	Name         string
+	Placeholders deck.Placeholders
+}
+
+// Scaffold returns markdown with one example page per layout, whose headings and bodies are pre-filled with
+// placeholder text for the title, subtitle and body placeholders of the layout, so that it shows how the
+// structure of markdown maps to each layout. Picture placeholders are not filled, since they need images.
+func Scaffold(layouts []ScaffoldLayout) []byte {
+	var buf bytes.Buffer
+	for i, l := range layouts {
+		if i > 0 {
+			buf.WriteString("---\n\n")
+		}
+		fmt.Fprintf(&buf, "<!-- {\"layout\": %q} -->\n\n", l.Name)
+		p := l.Placeholders
+		for j := range p.Titles {
+			fmt.Fprintf(&buf, "# %s\n\n", numbered("Title", j, p.Titles))
+		}
+		if p.Titles > 0 {
+			// Without title placeholders, the shallowest heading is taken as the title, so subtitles cannot be written.
+			for j := range p.Subtitles {
+				fmt.Fprintf(&buf, "## %s\n\n", numbered("Subtitle", j, p.Subtitles))
+			}
+		}
+		bodies := make([]string, p.Bodies)
+		for j := range bodies {
+			bodies[j] = numbered("Body", j, p.Bodies) + fmt.Sprintf(" of the layout %q.\n\n", l.Name)
+		}
+		// Bodies are separated by horizontal rules other than `---`, which separates pages.
+		buf.WriteString(strings.Join(bodies, "***\n\n"))
+	}
+	return buf.Bytes()
+}
+
+// numbered returns s numbered with the 0-based index i if there are two or more of them.
+func numbered(s string, i, n int) string {
+	if n < 2 {
+		return s
+	}
+	return fmt.Sprintf("%s %d", s, i+1)
+}
@@ -0,0 +1,53 @@
+package md
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+)
+
+func TestScaffold(t *testing.T) {
+	layouts := []ScaffoldLayout{
+		{Name: "title", Placeholders: deck.Placeholders{Titles: 1, Subtitles: 1}},
+		{Name: "section", Placeholders: deck.Placeholders{Titles: 1}},
+		{Name: "title-and-body-3col", Placeholders: deck.Placeholders{Titles: 1, Subtitles: 3, Bodies: 3}},
+		{Name: "body-only", Placeholders: deck.Placeholders{Bodies: 1}},
+		{Name: "blank"},
+	}
+	b := Scaffold(layouts)
+	m, err := Parse(".", b, nil, WithStrict(true))
+	if err != nil {
+		t.Fatalf("failed to parse the scaffold: %v\n%s", err, b)
+	}
+	ss, err := m.ToSlides(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	placeholders := map[string]deck.Placeholders{}
+	for _, l := range layouts {
+		placeholders[l.Name] = l.Placeholders
+	}
+	if issues := ss.Validate(placeholders); len(issues) > 0 {
+		t.Errorf("the scaffold does not fit the layouts: %v\n%s", issues, b)
+	}
+	type counts struct {
+		Layout                    string
+		Titles, Subtitles, Bodies int
+	}
+	var got []counts
+	for _, s := range ss {
+		got = append(got, counts{s.Layout, len(s.Titles), len(s.Subtitles), len(s.Bodies)})
+	}
+	want := []counts{
+		{"title", 1, 1, 0},
+		{"section", 1, 0, 0},
+		{"title-and-body-3col", 1, 3, 3},
+		{"body-only", 0, 0, 1},
+		{"blank", 0, 0, 0},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("%s\n%s", diff, b)
+	}
+}
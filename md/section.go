@@ -0,0 +1,70 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+)
+
+type section struct {
+	title string
+	page  int
+}
+
+// resolveSections sets the sections of the pages and renders the list of sections into agenda pages.
+// With sectionLevel, pages whose titles are at that heading level start a section named after their first title.
+func (md *MD) resolveSections() error {
+	var sectionLevel int
+	if md.Frontmatter != nil {
+		sectionLevel = md.Frontmatter.SectionLevel
+	}
+	if sectionLevel < 0 || sectionLevel >= sentinelLevel {
+		return fmt.Errorf("invalid sectionLevel: %d (must be between 1 and 6)", sectionLevel)
+	}
+
+	var (
+		sections []section
+		page     int
+	)
+	for _, content := range md.Contents {
+		if content.Ignore != nil && *content.Ignore {
+			// Ignored pages are not in the presentation
+			continue
+		}
+		page++
+		if content.Section == "" && !content.isAgenda() && content.TOC == nil && !content.continued && sectionLevel > 0 && len(content.Titles) > 0 && content.titleLevel() == sectionLevel {
+			content.Section = content.Titles[0]
+		}
+		if content.Section != "" {
+			sections = append(sections, section{title: content.Section, page: page})
+		}
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+
+	for _, content := range md.Contents {
+		if !content.isAgenda() {
+			continue
+		}
+		content.Bodies = append(content.Bodies, agendaBody(sections))
+	}
+	return nil
+}
+
+func (c *Content) isAgenda() bool {
+	return c.Agenda != nil && *c.Agenda
+}
+
+func agendaBody(sections []section) *deck.Body {
+	body := &deck.Body{}
+	for _, s := range sections {
+		body.Paragraphs = append(body.Paragraphs, &deck.Paragraph{
+			Fragments: []*deck.Fragment{{
+				Value: fmt.Sprintf("%s (p.%d)", s.title, s.page),
+			}},
+			Bullet: deck.BulletDash,
+		})
+	}
+	return body
+}
@@ -0,0 +1,46 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+)
+
+// ParagraphStyle is the spacing of the paragraphs of the body placeholders, overridden per layout.
+type ParagraphStyle struct {
+	deck.ParagraphSpacing `yaml:",inline"`
+	// spacing per layout name, overriding the fields set in it
+	Layouts map[string]*deck.ParagraphSpacing `yaml:"layouts,omitempty" json:"layouts,omitempty"`
+}
+
+// resolveParagraphSpacing validates the paragraph style in the frontmatter and sets the spacing of the layout of each page.
+// Pages without a layout get the spacing of the layout they get by default in the frontmatter, if any.
+func (md *MD) resolveParagraphSpacing() error {
+	if md.Frontmatter == nil || md.Frontmatter.ParagraphStyle == nil {
+		return nil
+	}
+	ps := md.Frontmatter.ParagraphStyle
+	if err := deck.ValidateParagraphSpacing(&ps.ParagraphSpacing); err != nil {
+		return fmt.Errorf("paragraphStyle: %w", err)
+	}
+	for name, s := range ps.Layouts {
+		if err := deck.ValidateParagraphSpacing(s); err != nil {
+			return fmt.Errorf("paragraphStyle of layout %q: %w", name, err)
+		}
+	}
+	for i, content := range md.Contents {
+		layout := content.Layout
+		if layout == "" {
+			if i == 0 {
+				layout = md.Frontmatter.FirstPageLayout
+			} else {
+				layout = md.Frontmatter.DefaultLayout
+			}
+		}
+		content.ParagraphSpacing = ps.ParagraphSpacing.Merge(ps.Layouts[layout])
+		if *content.ParagraphSpacing == (deck.ParagraphSpacing{}) {
+			content.ParagraphSpacing = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package md
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Part is a part of the markdown applied to its own presentation, split by `deck apply --split-by`.
+type Part struct {
+	PresentationID string
+	Title          string // title of the first page of the part
+	MD             *MD
+}
+
+var splitByReg = regexp.MustCompile(`^h([1-6])$`)
+
+// ParseSplitBy parses the heading level to split the markdown by, e.g. "h1".
+func ParseSplitBy(s string) (int, error) {
+	m := splitByReg.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid split-by: %q (must be h1 to h6)", s)
+	}
+	return strconv.Atoi(m[1])
+}
+
+// Split splits the markdown into parts at each page whose title is a heading of the level,
+// and assigns the presentation IDs listed in presentationIDs of the frontmatter to the parts in order.
+// Pages before the first such page belong to the first part.
+func (md *MD) Split(level int) ([]*Part, error) {
+	var parts []*Part
+	for _, content := range md.Contents {
+		start := len(content.Titles) > 0 && content.titleLevel() == level && !content.continued && !content.isAgenda() && content.TOC == nil
+		if len(parts) == 0 || (start && parts[len(parts)-1].Title != "") {
+			parts = append(parts, &Part{MD: &MD{}})
+		}
+		p := parts[len(parts)-1]
+		if start && p.Title == "" {
+			p.Title = content.Titles[0]
+		}
+		p.MD.Contents = append(p.MD.Contents, content)
+	}
+	var ids []string
+	if md.Frontmatter != nil {
+		ids = md.Frontmatter.PresentationIDs
+	}
+	if len(ids) < len(parts) {
+		return nil, fmt.Errorf("the markdown has %d parts split by h%d, but presentationIDs has %d IDs", len(parts), level, len(ids))
+	}
+	for i, p := range parts {
+		p.PresentationID = ids[i]
+		if md.Frontmatter != nil {
+			fm := *md.Frontmatter
+			p.MD.Frontmatter = &fm
+		} else {
+			p.MD.Frontmatter = &Frontmatter{}
+		}
+		p.MD.Frontmatter.PresentationID = p.PresentationID
+		p.MD.Frontmatter.PresentationIDs = nil
+		if p.Title != "" {
+			p.MD.Frontmatter.Title = p.Title
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,112 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		level     int
+		wantIDs   []string
+		wantTitle []string
+		wantPages []int
+		wantErr   bool
+	}{
+		{
+			"split by h1",
+			"---\npresentationIDs: [a, b]\n---\n\n# Lecture 1\n\n---\n\n## Topic\n\n---\n\n# Lecture 2\n\n---\n\n## Topic\n",
+			1,
+			[]string{"a", "b"},
+			[]string{"Lecture 1", "Lecture 2"},
+			[]int{2, 2},
+			false,
+		},
+		{
+			"pages before the first h1 belong to the first part",
+			"---\npresentationIDs: [a, b]\n---\n\n## Preface\n\n---\n\n# Lecture 1\n\n---\n\n# Lecture 2\n",
+			1,
+			[]string{"a", "b"},
+			[]string{"Lecture 1", "Lecture 2"},
+			[]int{2, 1},
+			false,
+		},
+		{
+			"split by h2",
+			"---\npresentationIDs: [a, b, c]\n---\n\n## A\n\n---\n\n## B\n",
+			2,
+			[]string{"a", "b"},
+			[]string{"A", "B"},
+			[]int{1, 1},
+			false,
+		},
+		{
+			"not enough presentation IDs",
+			"---\npresentationIDs: [a]\n---\n\n# Lecture 1\n\n---\n\n# Lecture 2\n",
+			1,
+			nil,
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(".", []byte(tt.in), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parts, err := m.Split(tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Split() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var (
+				ids    []string
+				titles []string
+				pages  []int
+			)
+			for _, p := range parts {
+				ids = append(ids, p.MD.Frontmatter.PresentationID)
+				titles = append(titles, p.MD.Frontmatter.Title)
+				pages = append(pages, len(p.MD.Contents))
+			}
+			if diff := cmp.Diff(tt.wantIDs, ids); diff != "" {
+				t.Errorf("presentation IDs mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantTitle, titles); diff != "" {
+				t.Errorf("titles mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPages, pages); diff != "" {
+				t.Errorf("pages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSplitBy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"h1", 1, false},
+		{"h6", 6, false},
+		{"h7", 0, true},
+		{"section", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSplitBy(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSplitBy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseSplitBy(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
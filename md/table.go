@@ -1,6 +1,12 @@
 package md
 
 import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
 	"github.com/k1LoW/deck"
 	"github.com/yuin/goldmark/ast"
 	east "github.com/yuin/goldmark/extension/ast"
@@ -32,6 +38,31 @@ func parseTable(tableNode *east.Table, baseDir string, b []byte, breaks bool) (*
 		}
 	}
 
+	switch {
+	case len(table.Rows) > 0 && !hasTableHeader(tableNode):
+		for _, cell := range table.Rows[0].Cells {
+			cell.IsHeader = false
+		}
+	case len(table.Rows) > 1 && isEmptyRow(table.Rows[0]):
+		// An alignment-only table such as `| | |` followed by `|:--|--:|` has no header row,
+		// and the alignments of its delimiter row apply to the data rows.
+		table.Rows = table.Rows[1:]
+	}
+	if err := transformTable(tableNode, table); err != nil {
+		return nil, err
+	}
+
+	header, data := tableVerticalAlignments(tableNode)
+	for _, row := range table.Rows {
+		for _, cell := range row.Cells {
+			if cell.IsHeader {
+				cell.VerticalAlignment = header
+			} else {
+				cell.VerticalAlignment = data
+			}
+		}
+	}
+
 	return table, nil
 }
 
@@ -82,3 +113,150 @@ func parseTableCell(cellNode *east.TableCell, baseDir string, b []byte, breaks,
 
 	return cell, nil
 }
+
+// resolveTables continues tables across pages and splits long tables into continuation pages.
+// The first table of a page with continueTable takes over the header row of the last table of the previous pages.
+// Tables with more body rows than tableMaxRows are split into continuation pages repeating the header row.
+func (md *MD) resolveTables() error {
+	var (
+		last     *deck.Table
+		maxRows  int
+		contents Contents
+	)
+	if md.Frontmatter != nil {
+		maxRows = md.Frontmatter.TableMaxRows
+	}
+	for i, content := range md.Contents {
+		if content.ContinueTable != nil && *content.ContinueTable && len(content.Tables) > 0 {
+			if last == nil {
+				return fmt.Errorf("page %d: continueTable is set, but there is no table on the previous pages", i+1)
+			}
+			if err := continueTable(last, content.Tables[0]); err != nil {
+				return fmt.Errorf("page %d: %w", i+1, err)
+			}
+		}
+		if len(content.Tables) > 0 {
+			last = content.Tables[len(content.Tables)-1]
+		}
+		n := cmp.Or(content.TableMaxRows, maxRows)
+		if n < 0 {
+			return fmt.Errorf("page %d: invalid tableMaxRows: %d", i+1, n)
+		}
+		contents = append(contents, content.splitTables(n)...)
+	}
+	md.Contents = contents
+	return nil
+}
+
+// continueTable makes t a continuation of prev.
+// The header row of t is turned into a body row, and the header rows of prev are prepended to t.
+// Columns of t without center or right alignment inherit the alignment of the header of prev.
+func continueTable(prev, t *deck.Table) error {
+	header := headerRows(prev)
+	if len(header) == 0 {
+		return fmt.Errorf("the table to continue has no header row")
+	}
+	columns := len(header[0].Cells)
+	for _, row := range t.Rows {
+		if len(row.Cells) != columns {
+			return fmt.Errorf("the table has %d columns, but the table to continue has %d columns", len(row.Cells), columns)
+		}
+		for j, cell := range row.Cells {
+			cell.IsHeader = false
+			if cell.Alignment == "START" {
+				cell.Alignment = header[0].Cells[j].Alignment
+			}
+		}
+	}
+	t.Rows = append(cloneRows(header), t.Rows...)
+	return nil
+}
+
+// splitTables splits the tables of c with more than maxRows body rows.
+// It returns c with the first maxRows body rows of each table, followed by continuation pages.
+// A continuation page has the titles of c and the next maxRows body rows of a table with its header rows repeated.
+func (c *Content) splitTables(maxRows int) Contents {
+	contents := Contents{c}
+	if maxRows == 0 {
+		return contents
+	}
+	for _, t := range c.Tables {
+		header := headerRows(t)
+		body := t.Rows[len(header):]
+		if len(body) <= maxRows {
+			continue
+		}
+		t.Rows = append(header, body[:maxRows]...)
+		for rows := range slices.Chunk(body[maxRows:], maxRows) {
+			continued := c.continuation(len(contents) + 1)
+			continued.Tables = []*deck.Table{{Rows: append(cloneRows(header), rows...)}}
+			contents = append(contents, continued)
+		}
+	}
+	return contents
+}
+
+// continuation returns the n-th page continuing c.
+// It has the layout, titles and page configuration of c, except the ones that must be unique such as the section.
+func (c *Content) continuation(n int) *Content {
+	continued := &Content{
+		Layout:         c.Layout,
+		Freeze:         c.Freeze,
+		Ignore:         c.Ignore,
+		Skip:           c.Skip,
+		Owner:          c.Owner,
+		Autofit:        c.Autofit,
+		Direction:      c.Direction,
+		Titles:         c.Titles,
+		TitleBodies:    c.TitleBodies,
+		Subtitles:      c.Subtitles,
+		SubtitleBodies: c.SubtitleBodies,
+		Headings:       maps.Clone(c.Headings),
+		continued:      true,
+	}
+	continued.ParagraphSpacing = c.ParagraphSpacing
+	if c.Key != "" {
+		continued.Key = fmt.Sprintf("%s-%d", c.Key, n)
+	}
+	return continued
+}
+
+// isEmptyRow reports whether all cells of the row are empty.
+func isEmptyRow(row *deck.TableRow) bool {
+	for _, cell := range row.Cells {
+		for _, f := range cell.Fragments {
+			if strings.TrimSpace(f.Value) != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// headerRows returns the leading header rows of t.
+func headerRows(t *deck.Table) []*deck.TableRow {
+	i := 0
+	for i < len(t.Rows) && len(t.Rows[i].Cells) > 0 && t.Rows[i].Cells[0].IsHeader {
+		i++
+	}
+	return t.Rows[:i:i]
+}
+
+// cloneRows returns deep copies of rows so that repeated rows do not share cells.
+func cloneRows(rows []*deck.TableRow) []*deck.TableRow {
+	cloned := make([]*deck.TableRow, 0, len(rows))
+	for _, row := range rows {
+		r := &deck.TableRow{}
+		for _, cell := range row.Cells {
+			cc := *cell
+			cc.Fragments = make([]*deck.Fragment, 0, len(cell.Fragments))
+			for _, f := range cell.Fragments {
+				ff := *f
+				cc.Fragments = append(cc.Fragments, &ff)
+			}
+			r.Cells = append(r.Cells, &cc)
+		}
+		cloned = append(cloned, r)
+	}
+	return cloned
+}
@@ -0,0 +1,218 @@
+package md
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/textmeasure"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+)
+
+// A table preceded by the comment directive `<!-- table: text -->` is rendered as aligned monospace text
+// in the body placeholder instead of a table object, for layouts without room for a floating table.
+// The directive also sets the vertical alignment of the cells, e.g. `<!-- table: valign=middle header-valign=bottom -->`,
+// and whether the first row is the header row, e.g. `<!-- table: header=false -->`.
+// The rows can be sorted by a column and the table transposed, e.g. `<!-- table: transpose=true sort=col1:desc -->`,
+// so that the same data is written only once in the form that is easy to maintain.
+
+const (
+	tableAsAttribute           = "deck-table-as"
+	tableAsText                = "text"
+	tableVAlignAttribute       = "deck-table-valign"
+	tableHeaderVAlignAttribute = "deck-table-header-valign"
+	tableVAlignOption          = "valign"
+	tableHeaderVAlignOption    = "header-valign"
+	tableHeaderAttribute       = "deck-table-header"
+	tableHeaderOption          = "header"
+	tableTransposeAttribute    = "deck-table-transpose"
+	tableTransposeOption       = "transpose"
+	tableSortAttribute         = "deck-table-sort"
+	tableSortOption            = "sort"
+)
+
+var tableDirectiveReg = regexp.MustCompile(`^table:\s*(\S.*)$`)
+
+// verticalAlignments maps the values of the valign options to the content alignments of table cells.
+var verticalAlignments = map[string]string{
+	"top":    "TOP",
+	"middle": "MIDDLE",
+	"bottom": "BOTTOM",
+}
+
+// parseTableDirective returns the value of the table directive in the comment block, if any.
+func parseTableDirective(block string) (string, bool) {
+	m := tableDirectiveReg.FindStringSubmatch(block)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// isTableAsText reports whether the table node is marked to be rendered as text.
+func isTableAsText(n *east.Table) bool {
+	v, ok := n.AttributeString(tableAsAttribute)
+	if !ok {
+		return false
+	}
+	b, ok := v.([]byte)
+	return ok && string(b) == tableAsText
+}
+
+// markTable marks the table following the comment node n with the options of the table directive value.
+// Invalid options are skipped and reported in the returned error.
+func markTable(n ast.Node, value string) error {
+	t, ok := n.NextSibling().(*east.Table)
+	if !ok {
+		return fmt.Errorf("table directive is not followed by a table")
+	}
+	var errs []error
+	for option := range strings.FieldsSeq(value) {
+		k, v, _ := strings.Cut(option, "=")
+		switch k {
+		case tableAsText:
+			t.SetAttributeString(tableAsAttribute, []byte(tableAsText))
+		case tableVAlignOption, tableHeaderVAlignOption:
+			alignment, ok := verticalAlignments[strings.ToLower(v)]
+			if !ok {
+				errs = append(errs, fmt.Errorf("invalid table directive: %q (must be top, middle or bottom)", option))
+				continue
+			}
+			if k == tableVAlignOption {
+				t.SetAttributeString(tableVAlignAttribute, []byte(alignment))
+			} else {
+				t.SetAttributeString(tableHeaderVAlignAttribute, []byte(alignment))
+			}
+		case tableHeaderOption:
+			header, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid table directive: %q (must be true or false)", option))
+				continue
+			}
+			t.SetAttributeString(tableHeaderAttribute, header)
+		case tableTransposeOption:
+			transpose, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid table directive: %q (must be true or false)", option))
+				continue
+			}
+			t.SetAttributeString(tableTransposeAttribute, transpose)
+		case tableSortOption:
+			if _, _, err := parseTableSort(v); err != nil {
+				errs = append(errs, fmt.Errorf("invalid table directive: %q (%w)", option, err))
+				continue
+			}
+			t.SetAttributeString(tableSortAttribute, []byte(v))
+		default:
+			errs = append(errs, fmt.Errorf("invalid table directive: %q (must be %q, %s=..., %s=..., %s=..., %s=... or %s=...)", option, tableAsText, tableVAlignOption, tableHeaderVAlignOption, tableHeaderOption, tableTransposeOption, tableSortOption))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// hasTableHeader reports whether the first row of the table node is the header row.
+// It is unless the table directive says `header=false`.
+func hasTableHeader(n *east.Table) bool {
+	v, ok := n.AttributeString(tableHeaderAttribute)
+	if !ok {
+		return true
+	}
+	header, ok := v.(bool)
+	return !ok || header
+}
+
+// tableVerticalAlignments returns the vertical alignments of the header and data cells of the table node
+// given by the table directive. The header cells follow the data cells unless header-valign is given.
+func tableVerticalAlignments(n *east.Table) (header, data string) {
+	if v, ok := n.AttributeString(tableVAlignAttribute); ok {
+		if b, ok := v.([]byte); ok {
+			header, data = string(b), string(b)
+		}
+	}
+	if v, ok := n.AttributeString(tableHeaderVAlignAttribute); ok {
+		if b, ok := v.([]byte); ok {
+			header = string(b)
+		}
+	}
+	return header, data
+}
+
+// tableTextParagraphs renders the table as paragraphs of code fragments whose columns are aligned with spaces.
+// Header rows are bold and followed by a separator line.
+func tableTextParagraphs(t *deck.Table) []*deck.Paragraph {
+	var (
+		texts  [][]string
+		widths []int
+	)
+	for _, row := range t.Rows {
+		var cells []string
+		for j, cell := range row.Cells {
+			s := cellText(cell)
+			cells = append(cells, s)
+			if j >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], textmeasure.Width(s))
+		}
+		texts = append(texts, cells)
+	}
+	var (
+		paragraphs []*deck.Paragraph
+		headers    = len(headerRows(t))
+	)
+	for i, row := range t.Rows {
+		header := i < headers
+		var cols []string
+		for j, cell := range row.Cells {
+			cols = append(cols, alignText(texts[i][j], widths[j], cell.Alignment))
+		}
+		paragraphs = append(paragraphs, &deck.Paragraph{
+			Fragments: []*deck.Fragment{{
+				Value: strings.TrimRight(strings.Join(cols, "  "), " "),
+				Bold:  header,
+				Code:  true,
+			}},
+			Bullet: deck.BulletNone,
+		})
+		if i == headers-1 {
+			var seps []string
+			for _, w := range widths {
+				seps = append(seps, strings.Repeat("-", w))
+			}
+			paragraphs = append(paragraphs, &deck.Paragraph{
+				Fragments: []*deck.Fragment{{
+					Value: strings.Join(seps, "  "),
+					Code:  true,
+				}},
+				Bullet: deck.BulletNone,
+			})
+		}
+	}
+	return paragraphs
+}
+
+// cellText returns the plain text of the cell on a single line.
+func cellText(cell *deck.TableCell) string {
+	var b strings.Builder
+	for _, f := range cell.Fragments {
+		b.WriteString(f.Value)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// alignText pads s with spaces to width according to the alignment of the cell.
+func alignText(s string, width int, alignment string) string {
+	pad := max(width-textmeasure.Width(s), 0)
+	switch alignment {
+	case "END":
+		return strings.Repeat(" ", pad) + s
+	case "CENTER":
+		return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+	default:
+		return s + strings.Repeat(" ", pad)
+	}
+}
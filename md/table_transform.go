@@ -0,0 +1,136 @@
+package md
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/deck"
+	east "github.com/yuin/goldmark/extension/ast"
+)
+
+const (
+	tableSortAsc  = "asc"
+	tableSortDesc = "desc"
+)
+
+// tableColumnReg matches the column of the sort option given by its position, e.g. col1 for the first column.
+var tableColumnReg = regexp.MustCompile(`^col([1-9][0-9]*)$`)
+
+// numberReplacer removes the thousands separators and the percent sign from the numbers of cells to compare.
+var numberReplacer = strings.NewReplacer(",", "", "%", "")
+
+// parseTableSort parses the value of the sort option of the table directive, such as Name or col2:desc.
+// The order is asc unless given.
+func parseTableSort(v string) (column, order string, err error) {
+	column, order, ok := strings.Cut(v, ":")
+	if !ok {
+		order = tableSortAsc
+	}
+	if column == "" {
+		return "", "", fmt.Errorf("column to sort by is empty")
+	}
+	order = strings.ToLower(order)
+	if order != tableSortAsc && order != tableSortDesc {
+		return "", "", fmt.Errorf("order must be %s or %s", tableSortAsc, tableSortDesc)
+	}
+	return column, order, nil
+}
+
+// transformTable sorts the rows and transposes the table t as the table directive of the table node says.
+// The rows are sorted before the table is transposed, so the column to sort by is a column of the written table.
+func transformTable(n *east.Table, t *deck.Table) error {
+	if v, ok := n.AttributeString(tableSortAttribute); ok {
+		if b, ok := v.([]byte); ok {
+			column, order, err := parseTableSort(string(b))
+			if err != nil {
+				return err
+			}
+			if err := sortTable(t, column, order); err != nil {
+				return err
+			}
+		}
+	}
+	if v, ok := n.AttributeString(tableTransposeAttribute); ok {
+		if transpose, ok := v.(bool); ok && transpose {
+			transposeTable(t)
+		}
+	}
+	return nil
+}
+
+// sortTable sorts the body rows of t by the column, which is the text of a header cell or the position of
+// the column such as col1. Cells of numbers are compared as numbers and come before the other cells.
+// The order of rows with equal cells is kept.
+func sortTable(t *deck.Table, column, order string) error {
+	header := headerRows(t)
+	idx := -1
+	if len(header) > 0 {
+		idx = slices.IndexFunc(header[0].Cells, func(cell *deck.TableCell) bool {
+			return cellText(cell) == column
+		})
+	}
+	if idx < 0 {
+		if m := tableColumnReg.FindStringSubmatch(column); m != nil {
+			idx, _ = strconv.Atoi(m[1])
+			idx--
+		}
+	}
+	body := t.Rows[len(header):]
+	if idx < 0 || slices.ContainsFunc(body, func(row *deck.TableRow) bool { return idx >= len(row.Cells) }) {
+		return fmt.Errorf("column to sort the table by is not found: %q", column)
+	}
+	slices.SortStableFunc(body, func(a, b *deck.TableRow) int {
+		c := compareCells(a.Cells[idx], b.Cells[idx])
+		if order == tableSortDesc {
+			return -c
+		}
+		return c
+	})
+	return nil
+}
+
+// compareCells compares the texts of the cells, as numbers if both of them are numbers.
+func compareCells(a, b *deck.TableCell) int {
+	sa, sb := cellText(a), cellText(b)
+	na, errA := strconv.ParseFloat(numberReplacer.Replace(sa), 64)
+	nb, errB := strconv.ParseFloat(numberReplacer.Replace(sb), 64)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(sa, sb)
+}
+
+// transposeTable swaps the rows and the columns of t. The first column becomes the header row if t has a header row.
+// Missing cells of short rows are filled with empty cells.
+func transposeTable(t *deck.Table) {
+	if len(t.Rows) == 0 {
+		return
+	}
+	header := len(headerRows(t)) > 0
+	columns := 0
+	for _, row := range t.Rows {
+		columns = max(columns, len(row.Cells))
+	}
+	rows := make([]*deck.TableRow, columns)
+	for j := range columns {
+		rows[j] = &deck.TableRow{Cells: make([]*deck.TableCell, len(t.Rows))}
+		for i, row := range t.Rows {
+			cell := &deck.TableCell{Fragments: []*deck.Fragment{}, Alignment: "START"}
+			if j < len(row.Cells) {
+				cell = row.Cells[j]
+			}
+			cell.IsHeader = header && j == 0
+			rows[j].Cells[i] = cell
+		}
+	}
+	t.Rows = rows
+}
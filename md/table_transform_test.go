@@ -0,0 +1,87 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+)
+
+func TestSortTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"by header", "<!-- table: sort=Name -->\n\n| Name |\n|---|\n| b |\n| a |\n| c |\n", []string{"Name", "a", "b", "c"}, false},
+		{"by position", "<!-- table: sort=col1:desc -->\n\n| Name |\n|---|\n| b |\n| a |\n| c |\n", []string{"Name", "c", "b", "a"}, false},
+		{"numbers", "<!-- table: sort=col1 -->\n\n| n |\n|---|\n| 10 |\n| 9 |\n| 1,000 |\n| 50% |\n| - |\n", []string{"n", "9", "10", "50%", "1,000", "-"}, false},
+		{"without header", "<!-- table: header=false sort=col1 -->\n\n| b |\n|---|\n| a |\n", []string{"a", "b"}, false},
+		{"stable", "<!-- table: sort=col1:desc -->\n\n| k | v |\n|---|---|\n| 1 | x |\n| 2 | y |\n| 1 | z |\n", []string{"k", "2", "1", "1"}, false},
+		{"unknown column", "<!-- table: sort=Score -->\n\n| Name |\n|---|\n| a |\n", nil, true},
+		{"out of range", "<!-- table: sort=col2 -->\n\n| Name |\n|---|\n| a |\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(".", []byte(tt.in), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, row := range m.Contents[0].Tables[0].Rows {
+				got = append(got, cellText(row.Cells[0]))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTransposeTable(t *testing.T) {
+	cell := func(v string, header bool) *deck.TableCell {
+		return &deck.TableCell{Fragments: []*deck.Fragment{{Value: v}}, Alignment: "START", IsHeader: header}
+	}
+	empty := &deck.TableCell{Fragments: []*deck.Fragment{}, Alignment: "START"}
+	tests := []struct {
+		name string
+		in   *deck.Table
+		want *deck.Table
+	}{
+		{
+			"with header",
+			&deck.Table{Rows: []*deck.TableRow{
+				{Cells: []*deck.TableCell{cell("Name", true), cell("Score", true)}},
+				{Cells: []*deck.TableCell{cell("Alice", false), cell("92", false)}},
+			}},
+			&deck.Table{Rows: []*deck.TableRow{
+				{Cells: []*deck.TableCell{cell("Name", true), cell("Alice", true)}},
+				{Cells: []*deck.TableCell{cell("Score", false), cell("92", false)}},
+			}},
+		},
+		{
+			"without header and short row",
+			&deck.Table{Rows: []*deck.TableRow{
+				{Cells: []*deck.TableCell{cell("a", false), cell("b", false)}},
+				{Cells: []*deck.TableCell{cell("c", false)}},
+			}},
+			&deck.Table{Rows: []*deck.TableRow{
+				{Cells: []*deck.TableCell{cell("a", false), cell("c", false)}},
+				{Cells: []*deck.TableCell{cell("b", false), empty}},
+			}},
+		},
+		{"empty", &deck.Table{}, &deck.Table{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transposeTable(tt.in)
+			if diff := cmp.Diff(tt.want, tt.in); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
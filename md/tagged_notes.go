@@ -0,0 +1,47 @@
+package md
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Comments tagged as `<!-- note:col2 ... -->` are grouped by the tag in the speaker notes, e.g. to keep the notes
+// of each column of multi-column layouts together. The groups follow the untagged comments in the order of
+// the first comment of each tag, each headed by a line of the tag such as `[col2]`.
+
+// taggedNoteReg matches a tagged comment such as `note:col2 text`.
+var taggedNoteReg = regexp.MustCompile(`(?s)^note:([-_.a-zA-Z0-9]+)\s+(.*\S)`)
+
+// taggedNotes are the notes of comments grouped by their tags.
+type taggedNotes struct {
+	tags  []string // tags in the order of appearance
+	notes map[string][]string
+}
+
+// parseTaggedNote returns the tag and the note of a tagged comment.
+func parseTaggedNote(comment string) (tag, note string, ok bool) {
+	m := taggedNoteReg.FindStringSubmatch(comment)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], strings.TrimSpace(m[2]), true
+}
+
+func (t *taggedNotes) add(tag, note string) {
+	if t.notes == nil {
+		t.notes = map[string][]string{}
+	}
+	if _, ok := t.notes[tag]; !ok {
+		t.tags = append(t.tags, tag)
+	}
+	t.notes[tag] = append(t.notes[tag], note)
+}
+
+// comments returns the notes of each tag joined under the header of the tag.
+func (t *taggedNotes) comments() []string {
+	var comments []string
+	for _, tag := range t.tags {
+		comments = append(comments, "["+tag+"]\n"+strings.Join(t.notes[tag], "\n\n"))
+	}
+	return comments
+}
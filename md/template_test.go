@@ -2,6 +2,7 @@ package md
 
 import (
 	"testing"
+	"time"
 )
 
 func TestExpandTemplate(t *testing.T) {
@@ -236,3 +237,38 @@ func TestCreateCELEnv(t *testing.T) {
 		t.Errorf("Failed to compile expression: %v", issues.Err())
 	}
 }
+
+func TestCELFunctions(t *testing.T) {
+	nowFunc = func() time.Time {
+		return time.Date(2025, 4, 1, 9, 30, 0, 0, time.UTC)
+	}
+	t.Cleanup(func() {
+		nowFunc = time.Now
+	})
+	t.Setenv("DECK_TEST_AUTHOR", "alice")
+	tests := []struct {
+		template string
+		store    map[string]any
+		want     string
+	}{
+		{`{{matches(title, "^Chapter")}}`, map[string]any{"title": "Chapter 1"}, "true"},
+		{`{{title.matches("^Chapter")}}`, map[string]any{"title": "Appendix"}, "false"},
+		{`{{containsAny(tags, ["draft", "wip"])}}`, map[string]any{"tags": []string{"intro", "wip"}}, "true"},
+		{`{{containsAny(tags, ["draft"])}}`, map[string]any{"tags": []string{"intro"}}, "false"},
+		{`{{env("DECK_TEST_AUTHOR")}}`, map[string]any{}, "alice"},
+		{`{{env("DECK_TEST_AUTHOR")}} {{env.HOME}}`, map[string]any{"env": map[string]string{"HOME": "/home/user"}}, "alice /home/user"},
+		{`{{now("2006-01-02")}}`, map[string]any{}, "2025-04-01"},
+		{`{{now().getFullYear()}}`, map[string]any{}, "2025"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := expandTemplate(tt.template, tt.store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
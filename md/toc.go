@@ -0,0 +1,75 @@
+package md
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/deck"
+)
+
+// TOC represents the configuration of a table of contents page.
+type TOC struct {
+	Level int `json:"level"` // heading level of the entries
+}
+
+type tocEntry struct {
+	heading string
+	page    int
+}
+
+// resolveTOC renders the list of the headings at the level of each table of contents page into the page,
+// with links to the pages of the headings.
+func (md *MD) resolveTOC() error {
+	levels := map[int]bool{}
+	for _, content := range md.Contents {
+		if content.TOC == nil {
+			continue
+		}
+		if content.TOC.Level < 1 || content.TOC.Level >= sentinelLevel {
+			return fmt.Errorf("invalid toc level: %d (must be between 1 and 6)", content.TOC.Level)
+		}
+		levels[content.TOC.Level] = true
+	}
+	if len(levels) == 0 {
+		return nil
+	}
+
+	entries := map[int][]tocEntry{}
+	var page int
+	for _, content := range md.Contents {
+		if content.Ignore != nil && *content.Ignore {
+			// Ignored pages are not in the presentation
+			continue
+		}
+		page++
+		if content.TOC != nil || content.isAgenda() || content.continued {
+			continue
+		}
+		for level := range levels {
+			for _, h := range content.Headings[level] {
+				entries[level] = append(entries[level], tocEntry{heading: h, page: page})
+			}
+		}
+	}
+
+	for _, content := range md.Contents {
+		if content.TOC == nil || len(entries[content.TOC.Level]) == 0 {
+			continue
+		}
+		content.Bodies = append(content.Bodies, tocBody(entries[content.TOC.Level]))
+	}
+	return nil
+}
+
+func tocBody(entries []tocEntry) *deck.Body {
+	body := &deck.Body{}
+	for _, e := range entries {
+		body.Paragraphs = append(body.Paragraphs, &deck.Paragraph{
+			Fragments: []*deck.Fragment{{
+				Value: e.heading,
+				Link:  deck.LinkToPage(e.page),
+			}},
+			Bullet: deck.BulletDash,
+		})
+	}
+	return body
+}
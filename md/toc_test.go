@@ -0,0 +1,23 @@
+package md
+
+import "testing"
+
+func TestTOCLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{"valid", "# Contents\n\n<!-- {\"toc\": {\"level\": 2}} -->\n\n---\n\n## A\n", false},
+		{"no level", "# Contents\n\n<!-- {\"toc\": {}} -->\n", true},
+		{"too deep", "# Contents\n\n<!-- {\"toc\": {\"level\": 7}} -->\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(".", []byte(tt.in), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package md
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Markdown read from stdin or a URL is not trusted as much as the local files of the user, since anyone
+// can write it. Parsed as untrusted, the markdown cannot run commands on the machine of the user with
+// codeBlockToImageCommand of the frontmatter, expand templates reading the environment variables into the
+// metadata of the presentation file, or upload local files out of its base directory as images.
+
+// ErrUntrustedSource is the error for the markdown parsed as untrusted using what only trusted markdown can use.
+var ErrUntrustedSource = errors.New("not allowed in markdown from stdin or a URL")
+
+// WithUntrusted parses the markdown as untrusted, such as the markdown read from stdin or a URL.
+func WithUntrusted(untrusted bool) ParseOption {
+	return func(o *parseOptions) {
+		o.untrusted = untrusted
+	}
+}
+
+// checkUntrusted returns an error if the untrusted markdown uses what only trusted markdown can use.
+// frontmatter is the frontmatter of the markdown itself, not merged with the config.
+func checkUntrusted(baseDir string, frontmatter *Frontmatter, bpages [][]byte) error {
+	if frontmatter != nil {
+		if frontmatter.CodeBlockToImageCommand != "" {
+			return fmt.Errorf("codeBlockToImageCommand in the frontmatter: %w", ErrUntrustedSource)
+		}
+		if celExprReg.MatchString(frontmatter.Description) {
+			return fmt.Errorf("templates in the description of the frontmatter: %w", ErrUntrustedSource)
+		}
+		for k, v := range frontmatter.Properties {
+			if celExprReg.MatchString(v) {
+				return fmt.Errorf("templates in the property %q of the frontmatter: %w", k, ErrUntrustedSource)
+			}
+		}
+	}
+	for _, bpage := range bpages {
+		doc := newParser().Parser().Parse(text.NewReader(bpage))
+		err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			image, ok := n.(*ast.Image)
+			if !entering || !ok {
+				return ast.WalkContinue, nil
+			}
+			if p := resolvePath(baseDir, string(image.Destination)); !isTrustedPath(baseDir, p) {
+				return ast.WalkStop, fmt.Errorf("image %s out of %s: %w", image.Destination, baseDir, ErrUntrustedSource)
+			}
+			return ast.WalkContinue, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isTrustedPath reports whether the resolved path p of an image of untrusted markdown can be read:
+// an HTTP(S) URL, a data URI, or a local file in the local directory baseDir.
+func isTrustedPath(baseDir, p string) bool {
+	if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") || strings.HasPrefix(p, "data:") {
+		return true
+	}
+	if strings.Contains(p, "://") || strings.Contains(baseDir, "://") {
+		return false
+	}
+	rel, err := filepath.Rel(baseDir, p)
+	return err == nil && filepath.IsLocal(rel)
+}
@@ -0,0 +1,45 @@
+package md
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseUntrusted(t *testing.T) {
+	baseDir, err := filepath.Abs("../testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		baseDir string
+		in      string
+		wantErr bool
+	}{
+		{"plain", baseDir, "# Title\n", false},
+		{"image in the base directory", baseDir, "![](test.png)\n", false},
+		{"data URI", baseDir, "![](data:image/gif;base64,R0lGODlhAQABAAAAACw=)\n", false},
+		{"image out of the base directory", baseDir, "![](../md/testdata/test.png)\n", true},
+		{"absolute image path", baseDir, "![](/etc/hosts)\n", true},
+		{"image in a table", baseDir, "| a |\n|---|\n| ![](/etc/hosts) |\n", true},
+		{"file URL", "https://example.com/decks/", "![](file:///etc/hosts)\n", true},
+		{"command", baseDir, "---\ncodeBlockToImageCommand: touch pwned\n---\n# Title\n", true},
+		{"template in description", baseDir, "---\ndescription: \"{{ env.HOME }}\"\n---\n# Title\n", true},
+		{"template in properties", baseDir, "---\nproperties:\n  home: \"{{ env.HOME }}\"\n---\n# Title\n", true},
+		{"description", baseDir, "---\ndescription: Slides\n---\n# Title\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.baseDir, []byte(tt.in), nil, WithUntrusted(true))
+			if got := errors.Is(err, ErrUntrustedSource); got != tt.wantErr {
+				t.Errorf("got error %v, want ErrUntrustedSource %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if _, err := Parse(tt.baseDir, []byte(tt.in), nil); errors.Is(err, ErrUntrustedSource) {
+					t.Errorf("got error %v for the trusted markdown", err)
+				}
+			}
+		})
+	}
+}
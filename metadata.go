@@ -0,0 +1,69 @@
+package deck
+
+import (
+	"context"
+	"maps"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/drive/v3"
+)
+
+// Metadata represents the metadata of the presentation file on Google Drive.
+// Empty fields are left as they are.
+type Metadata struct {
+	Title       string            // name of the file
+	Description string            // description of the file
+	Properties  map[string]string // custom properties of the file visible to all apps (e.g. commit: <SHA>)
+}
+
+// UpdateMetadata updates the metadata of the presentation file.
+// The file is not updated if the metadata has not been changed, so that its modified time is kept.
+func (d *Deck) UpdateMetadata(ctx context.Context, m *Metadata) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if m == nil {
+		return nil
+	}
+	current, err := d.driveSrv.Files.Get(d.id).Fields("name", "description", "properties").
+		SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	file := metadataUpdate(current, m)
+	if file == nil {
+		return nil
+	}
+	if _, err := d.driveSrv.Files.Update(d.id, file).SupportsAllDrives(true).Context(ctx).Do(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// metadataUpdate returns the file to update current with m, or nil if there is nothing to update.
+func metadataUpdate(current *drive.File, m *Metadata) *drive.File {
+	file := &drive.File{}
+	changed := false
+	if m.Title != "" && m.Title != current.Name {
+		file.Name = m.Title
+		changed = true
+	}
+	if m.Description != "" && m.Description != current.Description {
+		file.Description = m.Description
+		changed = true
+	}
+	props := maps.Clone(current.Properties)
+	if props == nil {
+		props = map[string]string{}
+	}
+	maps.Copy(props, m.Properties)
+	if !maps.Equal(props, current.Properties) {
+		// Properties are merged with the existing ones.
+		file.Properties = m.Properties
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return file
+}
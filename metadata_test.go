@@ -0,0 +1,39 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/drive/v3"
+)
+
+func TestMetadataUpdate(t *testing.T) {
+	current := &drive.File{
+		Name:        "Talk",
+		Description: "about deck",
+		Properties:  map[string]string{"commit": "abc", "source": "deck.md"},
+	}
+	tests := []struct {
+		name string
+		m    *Metadata
+		want *drive.File
+	}{
+		{"empty", &Metadata{}, nil},
+		{"unchanged", &Metadata{Title: "Talk", Description: "about deck", Properties: map[string]string{"commit": "abc"}}, nil},
+		{"title", &Metadata{Title: "New talk"}, &drive.File{Name: "New talk"}},
+		{"description", &Metadata{Description: "about slides"}, &drive.File{Description: "about slides"}},
+		{
+			"properties",
+			&Metadata{Properties: map[string]string{"commit": "def", "branch": "main"}},
+			&drive.File{Properties: map[string]string{"commit": "def", "branch": "main"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := metadataUpdate(current, tt.m)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("metadataUpdate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
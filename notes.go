@@ -0,0 +1,70 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/k1LoW/deck/textmeasure"
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// ApplySpeakerNotes rewrites only the speaker notes of the pages of the presentation with the speaker notes
+// of the slides, leaving the layouts, texts and images untouched. The slides must correspond to the pages of
+// the presentation one by one, as applied by Apply. Pages whose speaker notes are unchanged and frozen pages
+// are skipped.
+func (d *Deck) ApplySpeakerNotes(ctx context.Context, ss Slides) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	defer d.startUsage()()
+	return d.applyWithEditRetries(ctx, ss, func() error {
+		return d.applySpeakerNotes(ctx, ss)
+	})
+}
+
+func (d *Deck) applySpeakerNotes(ctx context.Context, ss Slides) error {
+	if err := d.refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh presentation: %w", err)
+	}
+	if len(ss) != len(d.presentation.Slides) {
+		return fmt.Errorf("the markdown has %d pages, but the presentation has %d pages: apply the markdown first", len(ss), len(d.presentation.Slides))
+	}
+	d.requiredRevisionID = d.presentation.RevisionId
+	defer func() {
+		d.requiredRevisionID = ""
+	}()
+
+	layoutObjectIdMap := map[string]*slides.Page{}
+	for _, l := range d.presentation.Layouts {
+		layoutObjectIdMap[l.ObjectId] = l
+	}
+	var (
+		requests []*slides.Request
+		changes  []*PageChange
+	)
+	for i, slide := range ss {
+		current := convertToSlide(d.presentation.Slides[i], layoutObjectIdMap)
+		if slide.Freeze || textmeasure.Equal(current.SpeakerNote, slide.SpeakerNote) {
+			continue
+		}
+		reqs, err := d.prepareToApplySpeakerNote(i, slide)
+		if err != nil {
+			return fmt.Errorf("failed to apply speaker note of page %d: %w", i+1, err)
+		}
+		requests = append(requests, reqs...)
+		changes = append(changes, &PageChange{Page: i + 1, Action: actionTypeUpdate.String(), Owner: slide.Owner, Titles: slide.Titles})
+	}
+	d.changes = changes
+	d.summary = ApplySummary{Updated: len(changes)}
+	if len(requests) == 0 {
+		d.logger.Info("no speaker notes to apply")
+		return nil
+	}
+	if err := d.batchUpdate(ctx, requests); err != nil {
+		return err
+	}
+	d.logger.Info("applied speaker notes", slog.Int("count", len(changes)))
+	return nil
+}
@@ -0,0 +1,56 @@
+package deck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+)
+
+func TestApplySpeakerNotes(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := func(title, note string) *Slide {
+		return &Slide{Layout: "title-and-body", Titles: []string{title}, Bodies: toBodies([]string{title + " body"}), SpeakerNote: note}
+	}
+	if err := d.Apply(ctx, Slides{page("A", "a"), page("B", "b"), page("C", "c")}); err != nil {
+		t.Fatal(err)
+	}
+	frozen := page("C", "edited c")
+	frozen.Freeze = true
+	// The texts other than the speaker notes are not applied.
+	if err := d.ApplySpeakerNotes(ctx, Slides{page("A", "a"), page("edited B", "edited b"), frozen}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(ApplySummary{Updated: 1}, d.Summary()); diff != "" {
+		t.Error(diff)
+	}
+	if u := d.APIUsage(); u.SlidesWrites != 1 {
+		t.Errorf("got %d batch updates, want 1: %+v", u.SlidesWrites, u)
+	}
+	got, err := d.DumpSlides(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles, notes []string
+	for _, s := range got {
+		titles = append(titles, s.Titles...)
+		notes = append(notes, s.SpeakerNote)
+	}
+	if diff := cmp.Diff([]string{"A", "B", "C"}, titles); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]string{"a", "edited b", "c"}, notes); diff != "" {
+		t.Error(diff)
+	}
+
+	if err := d.ApplySpeakerNotes(ctx, Slides{page("A", "a")}); err == nil {
+		t.Error("want error for the different number of pages")
+	}
+}
@@ -0,0 +1,125 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// WithContinueOnError continues applying the remaining pages when some pages fail to apply
+// (e.g. an image cannot be fetched), instead of aborting the apply. The apply returns PageErrors
+// of the failed pages at the end.
+func WithContinueOnError(enabled bool) Option {
+	return func(d *Deck) error {
+		d.continueOnError = enabled
+		return nil
+	}
+}
+
+// WithPageTimeout sets the time budget to prepare each page, including waiting for its images to be uploaded.
+// Pages exceeding it fail to apply. By default, there is no time budget.
+func WithPageTimeout(timeout time.Duration) Option {
+	return func(d *Deck) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid page timeout: %s", timeout)
+		}
+		d.pageTimeout = timeout
+		return nil
+	}
+}
+
+// PageError is the error of a page that failed to apply.
+type PageError struct {
+	Page int // page number in the markdown (1-based)
+	Err  error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page %d: %v", e.Page, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// PageErrors is the errors of the pages that failed to apply with WithContinueOnError.
+type PageErrors []*PageError
+
+func (e PageErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to apply %d pages:", len(e))
+	for _, pe := range e {
+		b.WriteString("\n  ")
+		b.WriteString(pe.Error())
+	}
+	return b.String()
+}
+
+// pageRequests is the start of the requests of a page in the requests of a batch update.
+// page is 0 for requests not of a page, such as moves and deletes.
+type pageRequests struct {
+	page  int
+	start int
+}
+
+// batchUpdateError is the error of batchUpdate with the number of requests applied by the batches before the failed one.
+type batchUpdateError struct {
+	sent int
+	err  error
+}
+
+func (e *batchUpdateError) Error() string {
+	return e.err.Error()
+}
+
+func (e *batchUpdateError) Unwrap() error {
+	return e.err
+}
+
+// retryPages sends the requests of each page in a batch of its own after sending all the requests failed with err,
+// skipping the requests already applied, and returns the errors of the pages that failed again.
+// It returns an error if requests not of a page fail, since the following pages depend on them.
+func (d *Deck) retryPages(ctx context.Context, requests []*slides.Request, pages []pageRequests, err error) (PageErrors, error) {
+	var sent int
+	if be, ok := errors.AsType[*batchUpdateError](err); ok {
+		sent = be.sent
+	}
+	d.logger.Warn("retrying the pages one by one", slog.Any("error", err))
+	var pageErrs PageErrors
+	for i, p := range pages {
+		end := len(requests)
+		if i+1 < len(pages) {
+			end = pages[i+1].start
+		}
+		if end <= sent {
+			continue
+		}
+		if err := d.batchUpdate(ctx, requests[max(p.start, sent):end]); err != nil {
+			if p.page == 0 || ctx.Err() != nil {
+				return nil, err
+			}
+			d.logger.Error("failed to apply page", slog.Int("page", p.page), slog.Any("error", err))
+			pageErrs = append(pageErrs, &PageError{Page: p.page, Err: err})
+		}
+	}
+	return pageErrs, nil
+}
+
+// withPageTimeout calls prepare with the context limited by the time budget of a page set by WithPageTimeout.
+func (d *Deck) withPageTimeout(ctx context.Context, prepare func(ctx context.Context) ([]*slides.Request, error)) ([]*slides.Request, error) {
+	if d.pageTimeout <= 0 {
+		return prepare(ctx)
+	}
+	pctx, cancel := context.WithTimeout(ctx, d.pageTimeout)
+	defer cancel()
+	reqs, err := prepare(pctx)
+	if err != nil && ctx.Err() == nil && errors.Is(pctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("exceeded the time budget of %s per page: %w", d.pageTimeout, err)
+	}
+	return reqs, err
+}
@@ -0,0 +1,171 @@
+package deck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestApplyContinueOnError(t *testing.T) {
+	ctx := t.Context()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	// Batch updates including the text "BROKEN" fail as if the request of the page were invalid.
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(u)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if strings.HasSuffix(r.URL.Path, ":batchUpdate") && bytes.Contains(b, []byte("BROKEN")) {
+			http.Error(w, `{"error":{"code":400,"message":"Invalid requests","status":"INVALID_ARGUMENT"}}`, http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	ss := Slides{
+		{Layout: "title", Titles: []string{"A"}},
+		{Layout: "title", Titles: []string{"BROKEN"}},
+		{Layout: "title", Titles: []string{"C"}},
+	}
+	for i, s := range ss {
+		s.page = i + 1
+	}
+
+	t.Run("abort", func(t *testing.T) {
+		d, err := New(ctx, WithEndpoint(ts.URL), WithPresentationID(srv.CreatePresentation("test")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = d.Apply(ctx, ss)
+		if err == nil {
+			t.Fatal("want error")
+		}
+		var pageErrs PageErrors
+		if errors.As(err, &pageErrs) {
+			t.Errorf("got page errors without WithContinueOnError: %v", err)
+		}
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		journalPath := filepath.Join(t.TempDir(), "journal.json")
+		d, err := New(ctx, WithEndpoint(ts.URL), WithPresentationID(srv.CreatePresentation("test")), WithContinueOnError(true),
+			WithJournal(NewJournal(journalPath, "digest")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = d.Apply(ctx, ss)
+		var pageErrs PageErrors
+		if !errors.As(err, &pageErrs) {
+			t.Fatalf("got error %v, want page errors", err)
+		}
+		if len(pageErrs) != 1 || pageErrs[0].Page != 2 {
+			t.Errorf("got page errors %v, want page 2", pageErrs)
+		}
+		got, err := d.DumpSlides(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []string
+		for _, s := range got {
+			titles = append(titles, strings.Join(s.Titles, ""))
+		}
+		// The failed page is left without its content.
+		if diff := cmp.Diff([]string{"A", "", "C"}, titles); diff != "" {
+			t.Error(diff)
+		}
+		// The failed page is not reported as changed.
+		var changed []int
+		for _, c := range d.Changes() {
+			changed = append(changed, c.Page)
+		}
+		if diff := cmp.Diff([]int{1, 3}, changed); diff != "" {
+			t.Error(diff)
+		}
+		if s := d.Summary(); s.Appended+s.Updated != 2 {
+			t.Errorf("got summary %+v, want 2 pages changed", s)
+		}
+		// The journal is kept to apply the failed page again.
+		j, err := LoadJournal(journalPath)
+		if err != nil {
+			t.Fatalf("journal is not kept: %v", err)
+		}
+		if diff := cmp.Diff([]int{2}, j.RemainingPages(len(ss))); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("hot swap aborts", func(t *testing.T) {
+		id := srv.CreatePresentation("test")
+		d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Apply(ctx, Slides{
+			{Layout: "title", Titles: []string{"A"}},
+			{Layout: "title", Titles: []string{"B"}},
+			{Layout: "title", Titles: []string{"C"}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		d, err = New(ctx, WithEndpoint(ts.URL), WithPresentationID(id), WithContinueOnError(true), WithHotSwap(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = d.Apply(ctx, ss)
+		if err == nil {
+			t.Fatal("want error")
+		}
+		var pageErrs PageErrors
+		if errors.As(err, &pageErrs) {
+			t.Errorf("got page errors for a failed hot swap: %v", err)
+		}
+	})
+}
+
+func TestPageErrors(t *testing.T) {
+	err := PageErrors{
+		{Page: 2, Err: errors.New("image not found")},
+		{Page: 5, Err: errors.New("exceeded the time budget")},
+	}
+	want := "failed to apply 2 pages:\n  page 2: image not found\n  page 5: exceeded the time budget"
+	if got := err.Error(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWithPageTimeout(t *testing.T) {
+	d := &Deck{}
+	if err := WithPageTimeout(-time.Second)(d); err == nil {
+		t.Error("want error for a negative timeout")
+	}
+	if err := WithPageTimeout(10 * time.Millisecond)(d); err != nil {
+		t.Fatal(err)
+	}
+	_, err := d.withPageTimeout(t.Context(), func(ctx context.Context) ([]*slides.Request, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err == nil || !strings.Contains(err.Error(), "exceeded the time budget of 10ms per page") {
+		t.Errorf("got error %v, want the time budget exceeded", err)
+	}
+}
@@ -0,0 +1,127 @@
+package deck
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// Links to pages in the presentation are represented as links of the form "#slide=N" to the N-th page (1-based),
+// and inserted as links to slide indices. Google Slides keeps the links to the pages rather than to the indices,
+// so the links read from the presentation are resolved to the current positions of the pages.
+
+const pageLinkPrefix = "#slide="
+
+// pageObjectLinkPrefix is the prefix of links to pages by object ID before they are resolved by resolvePageLinks.
+// It is the same as the fragment of the URL of a page in Google Slides.
+const pageObjectLinkPrefix = pageLinkPrefix + "id."
+
+// LinkToPage returns the link to the page (1-based) in the presentation to set to Fragment.Link.
+func LinkToPage(page int) string {
+	return pageLinkPrefix + strconv.Itoa(page)
+}
+
+// pageOfLink returns the page (1-based) of the link to a page in the presentation.
+func pageOfLink(link string) (int, bool) {
+	n, ok := strings.CutPrefix(link, pageLinkPrefix)
+	if !ok {
+		return 0, false
+	}
+	page, err := strconv.Atoi(n)
+	if err != nil || page < 1 {
+		return 0, false
+	}
+	return page, true
+}
+
+// toLink returns the link of the Slides API for the link of a fragment.
+func toLink(link string) *slides.Link {
+	if page, ok := pageOfLink(link); ok {
+		return &slides.Link{
+			SlideIndex:      int64(page - 1),
+			ForceSendFields: []string{"SlideIndex"}, // the first page is the index 0
+		}
+	}
+	return &slides.Link{Url: link}
+}
+
+// fromLink returns the link of a fragment for the link of the Slides API.
+// Links to pages by object ID are returned with pageObjectLinkPrefix to be resolved by resolvePageLinks.
+func fromLink(link *slides.Link) string {
+	switch {
+	case link == nil:
+		return ""
+	case link.Url != "":
+		return link.Url
+	case link.PageObjectId != "":
+		return pageObjectLinkPrefix + link.PageObjectId
+	case link.RelativeLink != "":
+		// Links relative to the page such as "NEXT_SLIDE" are not supported.
+		return ""
+	default:
+		return LinkToPage(int(link.SlideIndex) + 1)
+	}
+}
+
+// resolvePageLinks resolves the links to pages by object ID in ss to the positions of the pages in the presentation.
+// Links to pages not in the presentation are removed.
+func resolvePageLinks(ss Slides, pages []*slides.Page) {
+	positions := make(map[string]int, len(pages))
+	for i, p := range pages {
+		positions[p.ObjectId] = i + 1
+	}
+	for _, s := range ss {
+		for _, f := range s.fragments() {
+			id, ok := strings.CutPrefix(f.Link, pageObjectLinkPrefix)
+			if !ok {
+				continue
+			}
+			if page, ok := positions[id]; ok {
+				f.Link = LinkToPage(page)
+			} else {
+				f.Link = ""
+			}
+		}
+	}
+}
+
+// hasPageLinks reports whether the slide has links to pages in the presentation.
+func (s *Slide) hasPageLinks() bool {
+	if s == nil {
+		return false
+	}
+	for _, f := range s.fragments() {
+		if _, ok := pageOfLink(f.Link); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fragments returns the fragments of the texts of the slide.
+func (s *Slide) fragments() []*Fragment {
+	var fragments []*Fragment
+	for _, bodies := range [][]*Body{s.TitleBodies, s.SubtitleBodies, s.Bodies} {
+		for _, b := range bodies {
+			for _, p := range b.Paragraphs {
+				fragments = append(fragments, p.Fragments...)
+			}
+		}
+	}
+	for _, bq := range s.BlockQuotes {
+		for _, p := range bq.Paragraphs {
+			fragments = append(fragments, p.Fragments...)
+		}
+	}
+	for _, t := range s.Tables {
+		for _, row := range t.Rows {
+			for _, cell := range row.Cells {
+				if cell != nil {
+					fragments = append(fragments, cell.Fragments...)
+				}
+			}
+		}
+	}
+	return fragments
+}
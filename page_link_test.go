@@ -0,0 +1,114 @@
+package deck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestLinkRoundTrip(t *testing.T) {
+	tests := []struct {
+		link string
+		want *slides.Link
+	}{
+		{"https://example.com", &slides.Link{Url: "https://example.com"}},
+		{LinkToPage(1), &slides.Link{SlideIndex: 0, ForceSendFields: []string{"SlideIndex"}}},
+		{LinkToPage(3), &slides.Link{SlideIndex: 2, ForceSendFields: []string{"SlideIndex"}}},
+		{"#slide=0", &slides.Link{Url: "#slide=0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			got := toLink(tt.link)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+			if back := fromLink(got); back != tt.link {
+				t.Errorf("got %q, want %q", back, tt.link)
+			}
+		})
+	}
+}
+
+func TestResolvePageLinks(t *testing.T) {
+	ss := Slides{{
+		Bodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{
+			{Value: "B", Link: fromLink(&slides.Link{PageObjectId: "b"})},
+			{Value: "gone", Link: fromLink(&slides.Link{PageObjectId: "x"})},
+			{Value: "next", Link: fromLink(&slides.Link{RelativeLink: "NEXT_SLIDE"})},
+		}}}}},
+	}}
+	resolvePageLinks(ss, []*slides.Page{{ObjectId: "a"}, {ObjectId: "b"}})
+	var got []string
+	for _, f := range ss[0].fragments() {
+		got = append(got, f.Link)
+	}
+	if diff := cmp.Diff([]string{"#slide=2", "", ""}, got); diff != "" {
+		t.Error(diff)
+	}
+	if !ss[0].hasPageLinks() {
+		t.Error("want page links")
+	}
+}
+
+func TestApplyPageLinks(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := func(title string) *Slide {
+		return &Slide{Layout: "title-and-body", Titles: []string{title}, Bodies: toBodies([]string{title + " body"})}
+	}
+	toc := func(pages map[string]int, titles ...string) *Slide {
+		body := &Body{}
+		for _, title := range titles {
+			body.Paragraphs = append(body.Paragraphs, &Paragraph{
+				Fragments: []*Fragment{{Value: title, Link: LinkToPage(pages[title])}},
+				Bullet:    BulletDash,
+			})
+		}
+		return &Slide{Layout: "title-and-body", Titles: []string{"Contents"}, Bodies: []*Body{body}}
+	}
+	links := func(t *testing.T) []string {
+		t.Helper()
+		got, err := d.DumpSlides(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var links []string
+		for _, f := range got[0].fragments() {
+			if f.Link != "" {
+				links = append(links, f.Value+" "+f.Link)
+			}
+		}
+		return links
+	}
+
+	if err := d.Apply(ctx, Slides{toc(map[string]int{"A": 2, "B": 3}, "A", "B"), page("A"), page("B")}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"A #slide=2", "B #slide=3"}, links(t)); diff != "" {
+		t.Error(diff)
+	}
+
+	// Inserting a page before the linked pages updates the links to their new positions.
+	if err := d.Apply(ctx, Slides{toc(map[string]int{"A": 3, "B": 4}, "A", "B"), page("X"), page("A"), page("B")}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"A #slide=3", "B #slide=4"}, links(t)); diff != "" {
+		t.Error(diff)
+	}
+
+	// Applying the same slides again changes nothing.
+	if err := d.Apply(ctx, Slides{toc(map[string]int{"A": 3, "B": 4}, "A", "B"), page("X"), page("A"), page("B")}); err != nil {
+		t.Fatal(err)
+	}
+	if u := d.APIUsage(); u.SlidesWrites != 0 {
+		t.Errorf("got %d batch updates, want 0: %+v", u.SlidesWrites, u)
+	}
+}
@@ -0,0 +1,108 @@
+package deck
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/k1LoW/deck/version"
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// The metadata of a page is stored as JSON in the alt text of a hidden shape placed outside the page, like the key.
+// The hash of the slide applied to the page is used to match the page with the same slide exactly on the next apply,
+// so that the mapping by the similarity of the contents is only a fallback.
+// The settings of the slide that cannot be read back from the page, such as autofit and paragraph spacing,
+// are stored as applied, so that changing only them is detected as a change of the slide.
+
+const descriptionMetadataFromMarkdown = "Page metadata from markdown" // the metadata is stored in the title of the alt text as JSON
+
+// PageMetadata is the metadata stored in each page applied by deck.
+type PageMetadata struct {
+	Page    int    `json:"page,omitempty"`    // 1-based page number of the slide in the source
+	Hash    string `json:"hash"`              // hash of the contents of the slide
+	Version string `json:"version,omitempty"` // version of deck that applied the page
+
+	Autofit          string            `json:"autofit,omitempty"`           // autofit of the slide applied to the page
+	Direction        string            `json:"direction,omitempty"`         // text direction of the slide applied to the page
+	ParagraphSpacing *ParagraphSpacing `json:"paragraph_spacing,omitempty"` // paragraph spacing of the slide applied to the page
+	ImageColumns     int               `json:"image_columns,omitempty"`     // columns of the image grid of the slide applied to the page
+	ImageRows        int               `json:"image_rows,omitempty"`        // rows of the image grid of the slide applied to the page
+}
+
+// setSettings sets the settings of the slide stored in the metadata to the slide.
+func (m *PageMetadata) setSettings(slide *Slide) {
+	slide.Autofit = m.Autofit
+	slide.Direction = m.Direction
+	slide.ParagraphSpacing = m.ParagraphSpacing
+	slide.ImageColumns = m.ImageColumns
+	slide.ImageRows = m.ImageRows
+}
+
+// PageMetadata returns the metadata of each page of the presentation. It is nil for pages not applied by deck.
+func (d *Deck) PageMetadata(ctx context.Context) (_ []*PageMetadata, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if err := d.refresh(ctx); err != nil {
+		return nil, err
+	}
+	metadata := make([]*PageMetadata, len(d.presentation.Slides))
+	for i, p := range d.presentation.Slides {
+		metadata[i] = pageMetadataOf(p)
+	}
+	return metadata, nil
+}
+
+// contentHash returns the hash of the contents of the slide.
+// The speaker note is excluded, since the metadata is not rewritten when only the speaker note is applied.
+func (s *Slide) contentHash() string {
+	c := *s
+	c.SpeakerNote = ""
+	b, err := json.Marshal(&c)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+// metadataRequests returns requests to store the metadata of slide in the page identified by pageObjectID.
+// currentMetadata is the metadata of the existing metadata shapes keyed by their object IDs.
+func metadataRequests(pageObjectID string, slide *Slide, currentMetadata map[string]string) []*slides.Request {
+	hash := slide.hash
+	if hash == "" {
+		hash = slide.contentHash()
+	}
+	b, err := json.Marshal(&PageMetadata{
+		Page:             slide.page,
+		Hash:             hash,
+		Version:          version.Version,
+		Autofit:          slide.Autofit,
+		Direction:        slide.Direction,
+		ParagraphSpacing: slide.ParagraphSpacing,
+		ImageColumns:     slide.ImageColumns,
+		ImageRows:        slide.ImageRows,
+	})
+	if err != nil {
+		return nil
+	}
+	return hiddenShapeRequests(pageObjectID, "metadata", string(b), descriptionMetadataFromMarkdown, currentMetadata)
+}
+
+// pageMetadataOf returns the metadata stored in the page, or nil if none.
+func pageMetadataOf(page *slides.Page) *PageMetadata {
+	for _, element := range page.PageElements {
+		if element.Shape == nil || element.Description != descriptionMetadataFromMarkdown {
+			continue
+		}
+		m := &PageMetadata{}
+		if err := json.Unmarshal([]byte(element.Title), m); err != nil {
+			continue
+		}
+		return m
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package deck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k1LoW/deck/fakeslides"
+	"github.com/k1LoW/deck/version"
+)
+
+func TestApplyPageMetadata(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := Slides{
+		{Layout: "title", Titles: []string{"Metadata"}},
+		{Layout: "title-and-body", Titles: []string{"Page 2"}, Bodies: toBodies([]string{"Body"})},
+	}
+	if err := d.Apply(ctx, ss); err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.PageMetadata(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(ss) {
+		t.Fatalf("got metadata of %d pages, want %d", len(got), len(ss))
+	}
+	for i, m := range got {
+		if m == nil {
+			t.Fatalf("page %d has no metadata", i+1)
+		}
+		if m.Page != i+1 || m.Hash != ss[i].contentHash() || m.Version != version.Version {
+			t.Errorf("unexpected metadata of page %d: %+v", i+1, m)
+		}
+	}
+	dumped, err := d.DumpSlides(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range dumped {
+		if s.hash != got[i].Hash {
+			t.Errorf("got hash %q of page %d, want %q", s.hash, i+1, got[i].Hash)
+		}
+		if len(s.BlockQuotes) > 0 || len(s.Elements) > 0 {
+			t.Errorf("the metadata shape of page %d is dumped: %+v", i+1, s)
+		}
+	}
+}
+
+func TestGenerateActionsByHash(t *testing.T) {
+	page := func(hash string) *Slide {
+		return &Slide{Layout: "title", Titles: []string{"Same"}, hash: hash}
+	}
+	// The pages have the same contents, so only the hashes tell which slide each page was applied from.
+	actions, err := generateActions(Slides{page("a"), page("b"), page("c")}, Slides{page("c"), page("a"), page("b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var moves int
+	for _, a := range actions {
+		switch a.actionType {
+		case actionTypeMove:
+			moves++
+		default:
+			t.Errorf("unexpected action: %v", a.actionType)
+		}
+	}
+	if moves == 0 {
+		t.Error("the pages are not moved to the positions of the slides with the same hashes")
+	}
+}
+
+func TestApplyOnlySettingsChanged(t *testing.T) {
+	ctx := context.Background()
+	spacing := 90.0
+	tests := []struct {
+		name   string
+		change func(s *Slide)
+	}{
+		{"autofit", func(s *Slide) { s.Autofit = "shrink" }},
+		{"direction", func(s *Slide) { s.Direction = "rtl" }},
+		{"paragraph spacing", func(s *Slide) { s.ParagraphSpacing = &ParagraphSpacing{LineSpacing: &spacing} }},
+		{"image columns", func(s *Slide) { s.ImageColumns = 2 }},
+		{"image rows", func(s *Slide) { s.ImageRows = 2 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fakeslides.NewServer()
+			t.Cleanup(srv.Close)
+			d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			slide := func() Slides {
+				return Slides{{Layout: "title-and-body", Titles: []string{"Settings"}, Bodies: toBodies([]string{"Body"}), SpeakerNote: "note"}}
+			}
+			if err := d.Apply(ctx, slide()); err != nil {
+				t.Fatal(err)
+			}
+			changed := slide()
+			tt.change(changed[0])
+			if err := d.Apply(ctx, changed); err != nil {
+				t.Fatal(err)
+			}
+			if s := d.Summary(); s.Updated != 1 {
+				t.Errorf("got %+v, want the page updated", s)
+			}
+			// Applying the same slides again changes nothing.
+			changed = slide()
+			tt.change(changed[0])
+			if err := d.Apply(ctx, changed); err != nil {
+				t.Fatal(err)
+			}
+			if s := d.Summary(); s.Updated != 0 {
+				t.Errorf("got %+v, want no updates", s)
+			}
+		})
+	}
+}
+
+func TestSpeakerNoteOnlyChangedWithSettings(t *testing.T) {
+	before := &Slide{Layout: "title", Titles: []string{"Same"}, SpeakerNote: "before"}
+	after := &Slide{Layout: "title", Titles: []string{"Same"}, SpeakerNote: "after", Autofit: "shrink"}
+	if speakerNoteOnlyChanged(before, after) {
+		t.Error("the change of the autofit is taken as a change of only the speaker note")
+	}
+}
+
+func TestSpeakerNoteOnlyChangedKeepsHash(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(ctx context.Context, d *Deck, ss Slides) error
+	}{
+		{"apply", func(ctx context.Context, d *Deck, ss Slides) error { return d.Apply(ctx, ss) }},
+		{"apply speaker notes", func(ctx context.Context, d *Deck, ss Slides) error { return d.ApplySpeakerNotes(ctx, ss) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			srv := fakeslides.NewServer()
+			t.Cleanup(srv.Close)
+			d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			slidesWithNote := func(note string) Slides {
+				return Slides{
+					{Layout: "title", Titles: []string{"Title"}},
+					{Layout: "title-and-body", Titles: []string{"Page 2"}, Bodies: toBodies([]string{"Body"}), SpeakerNote: note},
+				}
+			}
+			if err := d.Apply(ctx, slidesWithNote("before")); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.apply(ctx, d, slidesWithNote("after")); err != nil {
+				t.Fatal(err)
+			}
+			got, err := d.PageMetadata(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, s := range slidesWithNote("after") {
+				s.page = i + 1
+				s.fillBodies()
+				if got[i] == nil || got[i].Hash != s.contentHash() {
+					t.Errorf("the hash of page %d does not match the slide: %+v", i+1, got[i])
+				}
+			}
+		})
+	}
+}
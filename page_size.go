@@ -0,0 +1,125 @@
+package deck
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// Google Slides has a single page size for all pages of a presentation, and the page size cannot be changed
+// with the API after the presentation is created. So the page size is set when deck creates a presentation,
+// and is only validated against existing presentations.
+
+// Page sizes of the presets of Google Slides in points.
+var pageSizePresets = map[string][2]float64{
+	"16:9": {720, 405},
+	"4:3":  {720, 540},
+}
+
+var customPageSizeRe = regexp.MustCompile(`^custom\(\s*([0-9.]+)\s*,\s*([0-9.]+)\s*\)$`)
+
+// PageSize represents the page size of a presentation.
+type PageSize struct {
+	Name   string  // "16:9", "4:3" or "custom"
+	Width  float64 // width in points
+	Height float64 // height in points
+}
+
+// ParsePageSize parses the page size in the form of `16:9`, `4:3` or `custom(w,h)` with the width and height in points.
+func ParsePageSize(s string) (_ *PageSize, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	s = strings.TrimSpace(s)
+	if size, ok := pageSizePresets[s]; ok {
+		return &PageSize{Name: s, Width: size[0], Height: size[1]}, nil
+	}
+	m := customPageSizeRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid page size: %q (must be \"16:9\", \"4:3\" or \"custom(w,h)\" in points)", s)
+	}
+	w, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page width: %q", m[1])
+	}
+	h, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page height: %q", m[2])
+	}
+	if w <= 0 || h <= 0 {
+		return nil, fmt.Errorf("invalid page size: %q (width and height must be positive)", s)
+	}
+	return &PageSize{Name: "custom", Width: w, Height: h}, nil
+}
+
+// String returns the page size in the form parsed by ParsePageSize.
+func (s *PageSize) String() string {
+	if s.Name != "custom" {
+		return s.Name
+	}
+	return fmt.Sprintf("custom(%s,%s)", strconv.FormatFloat(s.Width, 'f', -1, 64), strconv.FormatFloat(s.Height, 'f', -1, 64))
+}
+
+// Match reports whether size, the page size of a presentation, matches s.
+// Presets match presentations of the same aspect ratio (e.g. both 10 x 5.625 and 13.33 x 7.5 inches are 16:9),
+// and custom sizes match presentations of the same size.
+func (s *PageSize) Match(size *slides.Size) bool {
+	if size == nil {
+		return false
+	}
+	w, h := toEMU(size.Width)/emuPerPoint, toEMU(size.Height)/emuPerPoint
+	if w <= 0 || h <= 0 {
+		return false
+	}
+	if s.Name != "custom" {
+		return math.Abs(w/h-s.Width/s.Height) < 0.01
+	}
+	// EMUs are rounded by Google Slides.
+	return math.Abs(w-s.Width) < 0.5 && math.Abs(h-s.Height) < 0.5
+}
+
+// toSize returns the page size for the Slides API.
+func (s *PageSize) toSize() *slides.Size {
+	return &slides.Size{
+		Width:  &slides.Dimension{Magnitude: math.Round(s.Width * emuPerPoint), Unit: "EMU"},
+		Height: &slides.Dimension{Magnitude: math.Round(s.Height * emuPerPoint), Unit: "EMU"},
+	}
+}
+
+// formatPageSize returns the page size of a presentation in points for messages.
+func formatPageSize(size *slides.Size) string {
+	if size == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%sx%s pt",
+		strconv.FormatFloat(math.Round(toEMU(size.Width)/emuPerPoint*100)/100, 'f', -1, 64),
+		strconv.FormatFloat(math.Round(toEMU(size.Height)/emuPerPoint*100)/100, 'f', -1, 64))
+}
+
+// WithPageSize sets the page size of the presentation.
+// Presentations created by Create are created with the page size, and the page size of other presentations
+// is validated before applying, since it cannot be changed with the API.
+func WithPageSize(s *PageSize) Option {
+	return func(d *Deck) error {
+		d.pageSize = s
+		return nil
+	}
+}
+
+// validatePageSize validates that the page size of the presentation matches the one set by WithPageSize.
+func (d *Deck) validatePageSize() error {
+	if d.pageSize == nil || d.presentation == nil {
+		return nil
+	}
+	if !d.pageSize.Match(d.presentation.PageSize) {
+		return fmt.Errorf("page size of the presentation is %s, which does not match the page size %s. "+
+			"Change it from File > Page setup in Google Slides, since it cannot be changed with the API",
+			formatPageSize(d.presentation.PageSize), d.pageSize)
+	}
+	return nil
+}
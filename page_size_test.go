@@ -0,0 +1,131 @@
+package deck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestParsePageSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *PageSize
+		wantErr bool
+	}{
+		{"16:9", &PageSize{Name: "16:9", Width: 720, Height: 405}, false},
+		{"4:3", &PageSize{Name: "4:3", Width: 720, Height: 540}, false},
+		{"custom(960,540)", &PageSize{Name: "custom", Width: 960, Height: 540}, false},
+		{" custom( 612 , 792.5 ) ", &PageSize{Name: "custom", Width: 612, Height: 792.5}, false},
+		{"16:10", nil, true},
+		{"custom(0,540)", nil, true},
+		{"custom(960)", nil, true},
+		{"", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePageSize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+			if got != nil {
+				again, err := ParsePageSize(got.String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(got, again); diff != "" {
+					t.Errorf("String() does not round trip: %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestPageSizeMatch(t *testing.T) {
+	emu := func(w, h float64) *slides.Size {
+		return &slides.Size{
+			Width:  &slides.Dimension{Magnitude: w, Unit: "EMU"},
+			Height: &slides.Dimension{Magnitude: h, Unit: "EMU"},
+		}
+	}
+	tests := []struct {
+		name string
+		size string
+		in   *slides.Size
+		want bool
+	}{
+		{"16:9", "16:9", emu(9144000, 5143500), true},
+		{"16:9 widescreen", "16:9", emu(12192000, 6858000), true},
+		{"16:9 for 4:3", "16:9", emu(9144000, 6858000), false},
+		{"4:3", "4:3", emu(9144000, 6858000), true},
+		{"custom", "custom(720,405)", emu(9144000, 5143500), true},
+		{"custom in points", "custom(720,405)", &slides.Size{
+			Width:  &slides.Dimension{Magnitude: 720, Unit: "PT"},
+			Height: &slides.Dimension{Magnitude: 405, Unit: "PT"},
+		}, true},
+		{"custom of the same ratio", "custom(960,540)", emu(9144000, 5143500), false},
+		{"unknown", "16:9", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParsePageSize(tt.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Match(tt.in); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateWithPageSize(t *testing.T) {
+	ctx := t.Context()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	size, err := ParsePageSize("4:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := Create(ctx, WithEndpoint(srv.URL), WithPageSize(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := srv.Presentation(d.ID())
+	if p == nil {
+		t.Fatal("presentation not created")
+	}
+	if got := formatPageSize(p.PageSize); got != "720x540 pt" {
+		t.Errorf("got page size %s, want 720x540 pt", got)
+	}
+	if err := d.Apply(ctx, Slides{{Layout: "title", Titles: []string{"A"}}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyPageSizeMismatch(t *testing.T) {
+	ctx := t.Context()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	id := srv.CreatePresentation("test")
+	size, err := ParsePageSize("4:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(id), WithPageSize(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.Apply(ctx, Slides{{Layout: "title", Titles: []string{"A"}}})
+	if err == nil || !strings.Contains(err.Error(), "page size of the presentation is 720x405 pt, which does not match the page size 4:3") {
+		t.Errorf("got error %v, want page size mismatch", err)
+	}
+	if _, err := CreateFrom(ctx, id, WithEndpoint(srv.URL), WithPageSize(size)); err == nil {
+		t.Error("want error for the base presentation of another page size")
+	}
+}
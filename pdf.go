@@ -0,0 +1,105 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/slides/v1"
+)
+
+// ExportPages exports the pages (1-based) of the presentation as PDF.
+// Since the Drive API always exports a whole presentation, a temporary copy containing only the pages is exported
+// and deleted afterwards.
+func (d *Deck) ExportPages(ctx context.Context, w io.Writer, pages []int) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if len(pages) == 0 {
+		return d.Export(ctx, w)
+	}
+	if err := d.refresh(ctx); err != nil {
+		return err
+	}
+	var reqs []*slides.Request
+	for i, s := range d.presentation.Slides {
+		if slices.Contains(pages, i+1) {
+			continue
+		}
+		reqs = append(reqs, &slides.Request{
+			DeleteObject: &slides.DeleteObjectRequest{
+				ObjectId: s.ObjectId,
+			},
+		})
+	}
+	if len(reqs) == 0 {
+		return d.Export(ctx, w)
+	}
+	if len(reqs) == len(d.presentation.Slides) {
+		return fmt.Errorf("no pages to export: %v", pages)
+	}
+
+	file := &drive.File{
+		Name:     fmt.Sprintf("%s (deck export)", d.presentation.Title),
+		MimeType: "application/vnd.google-apps.presentation",
+	}
+	if d.folderID != "" {
+		file.Parents = []string{d.folderID}
+	}
+	f, err := d.driveSrv.Files.Copy(d.id, file).SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to copy presentation: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so that the copy is deleted even if ctx is canceled.
+		if derr := d.deleteOrTrashFile(context.WithoutCancel(ctx), f.Id); derr != nil {
+			d.logger.Error("failed to delete temporary presentation", slog.String("id", f.Id), slog.String("error", derr.Error()))
+		}
+	}()
+	req := &slides.BatchUpdatePresentationRequest{
+		Requests: reqs,
+	}
+	if _, err := d.srv.Presentations.BatchUpdate(f.Id, req).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to delete pages from temporary presentation: %w", err)
+	}
+	res, err := d.driveSrv.Files.Export(f.Id, "application/pdf").Context(ctx).Download()
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return fmt.Errorf("unable to create PDF file: %w", err)
+	}
+	return nil
+}
+
+// WaitForStableRevision waits until the revision ID of the presentation stays the same for interval,
+// so that changes still being applied (e.g. by another `deck apply`) are included in the export.
+// It returns the stable revision ID.
+func (d *Deck) WaitForStableRevision(ctx context.Context, interval time.Duration) (_ string, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	var prev string
+	for {
+		p, err := d.srv.Presentations.Get(d.id).Fields("revisionId").Context(ctx).Do()
+		if err != nil {
+			return "", fmt.Errorf("failed to get revision: %w", err)
+		}
+		if p.RevisionId == prev {
+			return prev, nil
+		}
+		d.logger.Info("waiting for the revision to be stable", slog.String("revision_id", p.RevisionId))
+		prev = p.RevisionId
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package deck
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	defaultUploadParallelism = 4
+	// throttleChunkSize is the maximum number of bytes read at once from a throttled reader.
+	throttleChunkSize = 32 * 1024
+	// latencyDegradationFactor is the ratio to the average latency at which the limiter backs off.
+	latencyDegradationFactor = 2.0
+)
+
+// adaptiveLimiter is a context-aware concurrency limiter whose limit can change at runtime.
+// When adaptive is enabled, the limit is increased additively while operations succeed
+// with stable latency, and decreased multiplicatively on errors or latency degradation (AIMD).
+type adaptiveLimiter struct {
+	mu         sync.Mutex
+	limit      int
+	max        int
+	inFlight   int
+	adaptive   bool
+	successes  int
+	avgLatency time.Duration
+	notify     chan struct{}
+}
+
+func newAdaptiveLimiter(parallelism int, adaptive bool) *adaptiveLimiter {
+	if parallelism < 1 {
+		parallelism = defaultUploadParallelism
+	}
+	limit := parallelism
+	if adaptive {
+		// Start conservatively and grow up to parallelism.
+		limit = min(defaultUploadParallelism, parallelism)
+	}
+	return &adaptiveLimiter{
+		limit:    limit,
+		max:      parallelism,
+		adaptive: adaptive,
+		notify:   make(chan struct{}),
+	}
+}
+
+// acquire blocks until a slot is available or ctx is done.
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		ch := l.notify
+		l.mu.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release frees a slot and feeds the observed latency and error back to the limiter.
+func (l *adaptiveLimiter) release(latency time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.adaptive {
+		l.adjust(latency, err)
+	}
+	l.free()
+}
+
+// cancel frees a slot without feeding back to the limiter, for operations canceled before they
+// completed, which tell nothing about the link.
+func (l *adaptiveLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.free()
+}
+
+// free frees a slot and wakes up the waiters. The caller must hold l.mu.
+func (l *adaptiveLimiter) free() {
+	l.inFlight--
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// currentLimit returns the current concurrency limit.
+func (l *adaptiveLimiter) currentLimit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+func (l *adaptiveLimiter) adjust(latency time.Duration, err error) {
+	if err != nil {
+		l.limit = max(1, l.limit/2)
+		l.successes = 0
+		return
+	}
+	if l.avgLatency > 0 && float64(latency) > float64(l.avgLatency)*latencyDegradationFactor {
+		l.limit = max(1, l.limit-1)
+		l.successes = 0
+	} else {
+		l.successes++
+		if l.successes >= l.limit {
+			l.limit = min(l.max, l.limit+1)
+			l.successes = 0
+		}
+	}
+	// exponentially weighted moving average
+	if l.avgLatency == 0 {
+		l.avgLatency = latency
+	} else {
+		l.avgLatency = (l.avgLatency*7 + latency) / 8
+	}
+}
+
+// hostLimiter limits the number of concurrent operations per host.
+type hostLimiter struct {
+	mu    sync.Mutex
+	limit int64
+	sems  map[string]*semaphore.Weighted
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{
+		limit: int64(limit),
+		sems:  map[string]*semaphore.Weighted{},
+	}
+}
+
+// acquire blocks until a slot for the host of rawURL is available.
+// It returns a function to release the slot.
+func (h *hostLimiter) acquire(ctx context.Context, rawURL string) (func(), error) {
+	if h == nil || h.limit < 1 {
+		return func() {}, nil
+	}
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	h.mu.Lock()
+	sem, ok := h.sems[host]
+	if !ok {
+		sem = semaphore.NewWeighted(h.limit)
+		h.sems[host] = sem
+	}
+	h.mu.Unlock()
+	if err := sem.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+	return func() { sem.Release(1) }, nil
+}
+
+// bandwidthLimiter throttles the total throughput shared by all readers using it.
+type bandwidthLimiter struct {
+	mu          sync.Mutex
+	bytesPerSec int64
+	next        time.Time
+}
+
+func newBandwidthLimiter(bytesPerSec int64) *bandwidthLimiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return &bandwidthLimiter{bytesPerSec: bytesPerSec}
+}
+
+// wait blocks until n bytes may be transferred.
+func (b *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	b.mu.Lock()
+	now := time.Now()
+	start := b.next
+	if start.Before(now) {
+		start = now
+	}
+	b.next = start.Add(time.Duration(float64(n) / float64(b.bytesPerSec) * float64(time.Second)))
+	b.mu.Unlock()
+	d := time.Until(start)
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reader wraps r so that reads from it are throttled.
+func (b *bandwidthLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if b == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, r: r, limiter: b}
+}
+
+type throttledReader struct {
+	ctx     context.Context //nostyle:contexts
+	r       io.Reader
+	limiter *bandwidthLimiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleChunkSize {
+		p = p[:throttleChunkSize]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.limiter.wait(t.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,171 @@
+package deck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	t.Run("fixed limit", func(t *testing.T) {
+		l := newAdaptiveLimiter(2, false)
+		ctx := context.Background()
+		if err := l.acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+		ctx2, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if err := l.acquire(ctx2); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+		l.release(time.Millisecond, errors.New("failed"))
+		if got := l.currentLimit(); got != 2 {
+			t.Errorf("limit should not change when adaptive is disabled, got %d", got)
+		}
+		if err := l.acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("additive increase", func(t *testing.T) {
+		l := newAdaptiveLimiter(8, true)
+		if got := l.currentLimit(); got != defaultUploadParallelism {
+			t.Fatalf("got initial limit %d, want %d", got, defaultUploadParallelism)
+		}
+		for range 4 {
+			if err := l.acquire(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			l.release(10*time.Millisecond, nil)
+		}
+		if got := l.currentLimit(); got != 5 {
+			t.Errorf("got limit %d, want 5", got)
+		}
+	})
+
+	t.Run("multiplicative decrease on error", func(t *testing.T) {
+		l := newAdaptiveLimiter(8, true)
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		l.release(10*time.Millisecond, errors.New("failed"))
+		if got := l.currentLimit(); got != 2 {
+			t.Errorf("got limit %d, want 2", got)
+		}
+	})
+
+	t.Run("decrease on latency degradation", func(t *testing.T) {
+		l := newAdaptiveLimiter(8, true)
+		for _, latency := range []time.Duration{10 * time.Millisecond, 100 * time.Millisecond} {
+			if err := l.acquire(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			l.release(latency, nil)
+		}
+		if got := l.currentLimit(); got != 3 {
+			t.Errorf("got limit %d, want 3", got)
+		}
+	})
+
+	t.Run("cancel does not adjust", func(t *testing.T) {
+		l := newAdaptiveLimiter(8, true)
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		l.release(100*time.Millisecond, nil)
+		for range 8 {
+			if err := l.acquire(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			l.cancel()
+		}
+		if got := l.currentLimit(); got != defaultUploadParallelism {
+			t.Errorf("got limit %d, want %d", got, defaultUploadParallelism)
+		}
+		// The latency of the canceled operations is not averaged in, so the same latency is not degraded.
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		l.release(100*time.Millisecond, nil)
+		if got := l.currentLimit(); got != defaultUploadParallelism {
+			t.Errorf("got limit %d, want %d", got, defaultUploadParallelism)
+		}
+		// All the slots are freed.
+		for range defaultUploadParallelism {
+			if err := l.acquire(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.Run("never exceeds parallelism", func(t *testing.T) {
+		l := newAdaptiveLimiter(2, true)
+		for range 10 {
+			if err := l.acquire(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			l.release(time.Millisecond, nil)
+		}
+		if got := l.currentLimit(); got != 2 {
+			t.Errorf("got limit %d, want 2", got)
+		}
+	})
+}
+
+func TestHostLimiter(t *testing.T) {
+	h := newHostLimiter(1)
+	ctx := context.Background()
+	release, err := h.acquire(ctx, "https://example.com/a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// another host is not blocked
+	releaseOther, err := h.acquire(ctx, "https://example.org/a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseOther()
+
+	ctx2, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := h.acquire(ctx2, "https://example.com/b.png"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	release()
+	release, err = h.acquire(ctx, "https://example.com/b.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func TestBandwidthLimiter(t *testing.T) {
+	if b := newBandwidthLimiter(0); b != nil {
+		t.Fatal("bandwidth limiter should be nil when unlimited")
+	}
+	var b *bandwidthLimiter
+	src := bytes.Repeat([]byte("a"), 1024)
+	if r := b.reader(context.Background(), bytes.NewReader(src)); r == nil {
+		t.Fatal("nil limiter should return the original reader")
+	}
+
+	b = newBandwidthLimiter(10 * 1024)
+	started := time.Now()
+	got, err := io.ReadAll(b.reader(context.Background(), bytes.NewReader(bytes.Repeat(src, 2))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2048 {
+		t.Errorf("got %d bytes, want 2048", len(got))
+	}
+	// The first chunk is sent immediately, the rest is throttled to 10KiB/s.
+	if elapsed := time.Since(started); elapsed < 50*time.Millisecond {
+		t.Errorf("read was not throttled: %s", elapsed)
+	}
+}
@@ -3,6 +3,8 @@ package deck
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -11,12 +13,16 @@ import (
 
 	"github.com/k1LoW/errors"
 	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/slides/v1"
 )
 
-const maxPreloadWorkersNum = 4
+const (
+	// defaultConcurrency is the default maximum number of concurrent image operations.
+	defaultConcurrency = 4
+	// defaultUploadedImageNamePrefix is the default prefix of the file names of uploaded images.
+	defaultUploadedImageNamePrefix = "________tmp-for-deck-"
+)
 
 // currentImageData holds the result of parallel image fetching.
 type currentImageData struct {
@@ -32,6 +38,8 @@ type imageToPreload struct {
 	objectID       string // objectID of existing image
 	isFromMarkdown bool   // whether this image is from markdown
 	externalLink   string // external link associated with the image, if any
+	altTitle       string // title of the alt text of existing image
+	altDescription string // description of the alt text of existing image
 }
 
 // imageResult holds the result of image processing.
@@ -52,18 +60,25 @@ func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map
 	for _, action := range actions {
 		switch action.actionType {
 		case actionTypeUpdate:
+			if action.notesOnly {
+				// Images are not touched when only the speaker note is changed
+				continue
+			}
 			// Extract existing images from the current slide
 			if action.index < len(d.presentation.Slides) {
 				currentSlide := d.presentation.Slides[action.index]
 				imageIndexInSlide := 0
 				for _, element := range currentSlide.PageElements {
-					if element.Image != nil && element.Image.Placeholder == nil && element.Image.ContentUrl != "" {
+					if element.Image != nil && element.Image.Placeholder == nil && element.Image.ContentUrl != "" &&
+						element.Description != descriptionElementFromMarkdown {
 						imagesToPreload = append(imagesToPreload, imageToPreload{
 							slideIndex:     action.index,
 							imageIndex:     imageIndexInSlide,
 							existingURL:    element.Image.ContentUrl,
 							objectID:       element.ObjectId,
-							isFromMarkdown: element.Description == descriptionImageFromMarkdown,
+							isFromMarkdown: isImageFromMarkdown(element.Description),
+							altTitle:       element.Title,
+							altDescription: element.Description,
 							externalLink: func(img *slides.Image) string {
 								if img.ImageProperties != nil && img.ImageProperties.Link != nil {
 									return img.ImageProperties.Link.Url
@@ -84,20 +99,24 @@ func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map
 	d.logger.Info("preloading current images", slog.Int("count", len(imagesToPreload)))
 
 	// Process images in parallel
-	sem := semaphore.NewWeighted(maxPreloadWorkersNum)
+	hosts := newHostLimiter(d.hostParallelism)
 	eg, ctx := errgroup.WithContext(ctx)
 	resultCh := make(chan imageResult, len(imagesToPreload))
 
 	for _, imgToPreload := range imagesToPreload {
 		eg.Go(func() error {
 			// Try to acquire semaphore
-			if err := sem.Acquire(ctx, 1); err != nil {
+			if err := d.imageSem.Acquire(ctx, 1); err != nil {
+				return err
+			}
+			defer d.imageSem.Release(1)
+			releaseHost, err := hosts.acquire(ctx, imgToPreload.existingURL)
+			if err != nil {
 				return err
 			}
-			defer sem.Release(1)
+			defer releaseHost()
 
 			var image *Image
-			var err error
 
 			// Create Image from existing URL
 			if imgToPreload.isFromMarkdown {
@@ -109,6 +128,7 @@ func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map
 				return fmt.Errorf("failed to preload image from URL %s: %w", imgToPreload.existingURL, err)
 			}
 			image.link = imgToPreload.externalLink
+			image.setAltText(imgToPreload.altTitle, imgToPreload.altDescription)
 
 			resultCh <- imageResult{
 				slideIndex: imgToPreload.slideIndex,
@@ -169,7 +189,7 @@ func (d *Deck) startUploadingImages(
 	for _, action := range actions {
 		switch action.actionType {
 		case actionTypeUpdate, actionTypeAppend:
-			if action.slide == nil {
+			if action.slide == nil || action.notesOnly {
 				continue
 			}
 			for _, image := range action.slide.Images {
@@ -187,13 +207,25 @@ func (d *Deck) startUploadingImages(
 		}
 	}
 
+	// Identical images across slides share a single upload
+	groups := groupImagesForUpload(imagesToUpload)
+
 	// Create channel for uploaded image IDs
-	uploadedCh := make(chan uploadedImageInfo, len(imagesToUpload))
-	if len(imagesToUpload) == 0 {
+	uploadedCh := make(chan uploadedImageInfo, len(groups))
+	if len(groups) == 0 {
 		close(uploadedCh)
 		return uploadedCh
 	}
-	d.logger.Info("starting image upload", slog.Int("count", len(imagesToUpload)))
+	parallelism := d.uploadParallelism
+	if parallelism == 0 {
+		parallelism = d.concurrency
+	}
+	limiter := newAdaptiveLimiter(parallelism, d.adaptiveUpload)
+	bandwidth := newBandwidthLimiter(d.uploadBytesPerSec)
+	d.logger.Info("starting image upload", slog.Int("count", len(groups)), slog.Int("images", len(imagesToUpload)),
+		slog.Int("parallelism", limiter.currentLimit()))
+
+	d.progress.startUploads(len(groups))
 
 	// Mark all images as upload in progress
 	for _, image := range imagesToUpload {
@@ -203,29 +235,63 @@ func (d *Deck) startUploadingImages(
 	// Start uploading images asynchronously
 	go func() {
 		// Process images in parallel
-		sem := semaphore.NewWeighted(maxPreloadWorkersNum)
 		eg, ctx := errgroup.WithContext(ctx)
 
-		for _, image := range imagesToUpload {
-			eg.Go(func() error {
-				if err := sem.Acquire(ctx, 1); err != nil {
+		for _, group := range groups {
+			image := group[0]
+			setUploadResult := func(webContentLink string, err error) {
+				for _, i := range group {
+					i.SetUploadResult(webContentLink, err)
+				}
+			}
+			eg.Go(func() (err error) {
+				if err := limiter.acquire(ctx); err != nil {
 					// Context canceled, set upload error on remaining images
-					image.SetUploadResult("", err)
+					setUploadResult("", err)
+					return err
+				}
+				if err := d.imageSem.Acquire(ctx, 1); err != nil {
+					limiter.cancel()
+					setUploadResult("", err)
 					return err
 				}
-				defer sem.Release(1)
+				started := time.Now()
+				defer func() {
+					d.imageSem.Release(1)
+					if ctx.Err() != nil {
+						// Uploads aborted by the cancellation do not tell the state of the link.
+						limiter.cancel()
+						return
+					}
+					limiter.release(time.Since(started), err)
+				}()
+
+				if d.imageHost != nil {
+					name := d.hostedImageName(image, time.Now())
+					media := bandwidth.reader(ctx, bytes.NewReader(image.Bytes()))
+					u, err := d.imageHost.Upload(ctx, name, image.mimeType, int64(len(image.Bytes())), media)
+					if err != nil {
+						setUploadResult("", err)
+						return err
+					}
+					setUploadResult(u, nil)
+					d.progress.upload()
+					uploadedCh <- uploadedImageInfo{uploadedID: name, image: image}
+					return nil
+				}
 
 				// Upload image to Google Drive
 				df := &drive.File{
-					Name:     fmt.Sprintf("________tmp-for-deck-%s", time.Now().Format(time.RFC3339)),
+					Name:     d.uploadedImageName(time.Now()),
 					MimeType: string(image.mimeType),
 				}
-				if d.folderID != "" {
-					df.Parents = []string{d.folderID}
+				if folderID := d.uploadFolderID(); folderID != "" {
+					df.Parents = []string{folderID}
 				}
-				uploaded, err := d.driveSrv.Files.Create(df).Media(bytes.NewBuffer(image.Bytes())).SupportsAllDrives(true).Do()
+				media := bandwidth.reader(ctx, bytes.NewReader(image.Bytes()))
+				uploaded, err := d.driveSrv.Files.Create(df).Media(media).SupportsAllDrives(true).Context(ctx).Do()
 				if err != nil {
-					image.SetUploadResult("", fmt.Errorf("failed to upload image: %w", err))
+					setUploadResult("", fmt.Errorf("failed to upload image: %w", err))
 					return err
 				}
 				defer func() {
@@ -240,24 +306,26 @@ func (d *Deck) startUploadingImages(
 				// To specify a URL for CreateImageRequest, we must make the webContentURL readable to anyone
 				// and configure the necessary permissions for this purpose.
 				if err := d.AllowReadingByAnyone(ctx, uploaded.Id); err != nil {
-					image.SetUploadResult("", fmt.Errorf("failed to set permission for image: %w", err))
+					setUploadResult("", fmt.Errorf("failed to set permission for image: %w", err))
 					return err
 				}
 
 				// Get webContentLink
-				f, err := d.driveSrv.Files.Get(uploaded.Id).Fields("webContentLink").SupportsAllDrives(true).Do()
+				f, err := d.driveSrv.Files.Get(uploaded.Id).Fields("webContentLink").SupportsAllDrives(true).Context(ctx).Do()
 				if err != nil {
-					image.SetUploadResult("", fmt.Errorf("failed to get webContentLink for image: %w", err))
+					setUploadResult("", fmt.Errorf("failed to get webContentLink for image: %w", err))
 					return err
 				}
 
 				if f.WebContentLink == "" {
-					image.SetUploadResult("", fmt.Errorf("webContentLink is empty for image: %s", uploaded.Id))
+					err := fmt.Errorf("webContentLink is empty for image: %s", uploaded.Id)
+					setUploadResult("", err)
 					return err
 				}
 
 				// Set successful upload result
-				image.SetUploadResult(f.WebContentLink, nil)
+				setUploadResult(f.WebContentLink, nil)
+				d.progress.upload()
 
 				uploadedCh <- uploadedImageInfo{uploadedID: uploaded.Id, image: image}
 				return nil
@@ -275,9 +343,50 @@ func (d *Deck) startUploadingImages(
 	return uploadedCh
 }
 
+// groupImagesForUpload groups images by their content and MIME type, keeping the order of first appearance.
+// Images in the same group can share a single upload.
+func groupImagesForUpload(images []*Image) [][]*Image {
+	var groups [][]*Image
+	indexes := map[string]int{}
+	for _, image := range images {
+		sum := sha256.Sum256(image.Bytes())
+		key := string(image.mimeType) + ":" + hex.EncodeToString(sum[:])
+		if i, ok := indexes[key]; ok {
+			groups[i] = append(groups[i], image)
+			continue
+		}
+		indexes[key] = len(groups)
+		groups = append(groups, []*Image{image})
+	}
+	return groups
+}
+
+// uploadFolderID returns the Drive folder ID to upload images to.
+func (d *Deck) uploadFolderID() string {
+	if d.imageFolderID != "" {
+		return d.imageFolderID
+	}
+	return d.folderID
+}
+
+// uploadedImageName returns the file name of an image uploaded at t.
+func (d *Deck) uploadedImageName(t time.Time) string {
+	prefix := d.imageNamePrefix
+	if prefix == "" {
+		prefix = defaultUploadedImageNamePrefix
+	}
+	return prefix + t.Format(time.RFC3339)
+}
+
 // cleanupUploadedImages deletes uploaded images in parallel.
+// If keeping uploaded images is enabled, it only waits for the uploads to finish.
 func (d *Deck) cleanupUploadedImages(ctx context.Context, uploadedCh <-chan uploadedImageInfo) error {
-	sem := semaphore.NewWeighted(maxPreloadWorkersNum)
+	if d.keepUploaded {
+		for info := range uploadedCh {
+			d.logger.Info("kept uploaded image", slog.String("id", info.uploadedID))
+		}
+		return nil
+	}
 	var wg sync.WaitGroup
 
 	for {
@@ -289,22 +398,22 @@ func (d *Deck) cleanupUploadedImages(ctx context.Context, uploadedCh <-chan uplo
 				return nil
 			}
 			// Try to acquire semaphore
-			if err := sem.Acquire(ctx, 1); err != nil {
+			if err := d.imageSem.Acquire(ctx, 1); err != nil {
 				return fmt.Errorf("failed to acquire semaphore: %w", err)
 			}
 
 			wg.Add(1)
 			go func(info uploadedImageInfo) {
 				defer func() {
-					sem.Release(1)
+					d.imageSem.Release(1)
 					wg.Done()
 				}()
 
-				// Delete uploaded image from Google Drive
+				// Delete uploaded image from Google Drive or the image host
 				// Note: We only log errors here instead of returning them to ensure
 				// all images are attempted to be deleted. A single deletion failure
 				// should not prevent cleanup of other successfully uploaded images.
-				if err := d.deleteOrTrashFile(ctx, info.uploadedID); err != nil {
+				if err := d.deleteUploadedImage(ctx, info.uploadedID); err != nil {
 					d.logger.Error("failed to delete uploaded image",
 						slog.String("id", info.uploadedID),
 						slog.Any("error", err))
@@ -0,0 +1,98 @@
+package deck
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestUploadedImageName(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"", "________tmp-for-deck-2025-01-02T03:04:05Z"},
+		{"deck-images-", "deck-images-2025-01-02T03:04:05Z"},
+	}
+	for _, tt := range tests {
+		d := &Deck{imageNamePrefix: tt.prefix}
+		if got := d.uploadedImageName(now); got != tt.want {
+			t.Errorf("uploadedImageName() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestUploadFolderID(t *testing.T) {
+	tests := []struct {
+		folderID      string
+		imageFolderID string
+		want          string
+	}{
+		{"", "", ""},
+		{"folder", "", "folder"},
+		{"folder", "images", "images"},
+		{"", "images", "images"},
+	}
+	for _, tt := range tests {
+		d := &Deck{folderID: tt.folderID, imageFolderID: tt.imageFolderID}
+		if got := d.uploadFolderID(); got != tt.want {
+			t.Errorf("uploadFolderID() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCleanupUploadedImagesKeep(t *testing.T) {
+	d := &Deck{
+		keepUploaded: true,
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	ch := make(chan uploadedImageInfo, 2)
+	ch <- uploadedImageInfo{uploadedID: "a"}
+	ch <- uploadedImageInfo{uploadedID: "b"}
+	close(ch)
+	// driveSrv is nil, so this fails if it tries to delete uploaded images.
+	if err := d.cleanupUploadedImages(context.Background(), ch); err != nil {
+		t.Fatal(err)
+	}
+	if len(ch) != 0 {
+		t.Errorf("channel should be drained, got %d remaining", len(ch))
+	}
+}
+
+func TestGroupImagesForUpload(t *testing.T) {
+	newImage := func(t *testing.T, c color.Color) *Image {
+		t.Helper()
+		img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		img.Set(0, 0, c)
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		i, err := newImageFromBuffer(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return i
+	}
+	red1 := newImage(t, color.RGBA{255, 0, 0, 255})
+	blue := newImage(t, color.RGBA{0, 0, 255, 255})
+	red2 := newImage(t, color.RGBA{255, 0, 0, 255})
+
+	groups := groupImagesForUpload([]*Image{red1, blue, red2})
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	if len(groups[0]) != 2 || groups[0][0] != red1 || groups[0][1] != red2 {
+		t.Errorf("identical images should share the first group: %v", groups[0])
+	}
+	if len(groups[1]) != 1 || groups[1][0] != blue {
+		t.Errorf("unexpected second group: %v", groups[1])
+	}
+}
@@ -0,0 +1,117 @@
+package deck
+
+import "sync"
+
+// ProgressAction is the kind of step reported by the progress function.
+type ProgressAction string
+
+const (
+	ProgressAppend ProgressAction = "append" // a page was appended
+	ProgressUpdate ProgressAction = "update" // a page was updated
+	ProgressMove   ProgressAction = "move"   // a page was moved
+	ProgressDelete ProgressAction = "delete" // a page was deleted
+	ProgressUpload ProgressAction = "upload" // an image was uploaded
+)
+
+// Progress is a step of apply reported by the progress function.
+type Progress struct {
+	Action ProgressAction `json:"action"`
+	Page   int            `json:"page,omitempty"` // page of the slides, 0 for delete and upload
+	Index  int            `json:"index"`          // index of the page in the presentation before the step, -1 for upload
+	Done   int            `json:"done"`           // number of steps done so far, counted separately for pages and uploads
+	Total  int            `json:"total"`          // number of steps of the apply, counted separately for pages and uploads
+}
+
+// ProgressFunc is called after each step of apply.
+type ProgressFunc func(Progress)
+
+// WithProgressFunc calls fn after each page is appended, updated, moved or deleted and after each image
+// is uploaded. Pages are reported once the batch update containing them is sent.
+// fn is never called concurrently, but uploads are reported from the goroutines uploading images,
+// so fn must not block.
+func WithProgressFunc(fn ProgressFunc) Option {
+	return func(d *Deck) error {
+		d.progress = &progressReporter{fn: fn}
+		return nil
+	}
+}
+
+// progressReporter counts the steps of an apply and reports them to the progress function.
+type progressReporter struct {
+	fn ProgressFunc
+
+	mu                       sync.Mutex
+	pagesDone, pagesTotal    int
+	uploadsDone, uploadTotal int
+	pending                  []Progress // steps of pages in the batch not sent yet
+}
+
+// startPages resets the counts of the steps of pages to the actions to apply.
+func (p *progressReporter) startPages(actions []*action) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pagesDone, p.pagesTotal, p.pending = 0, 0, nil
+	for _, a := range actions {
+		if a.actionType != actionTypeSentinel {
+			p.pagesTotal++
+		}
+	}
+}
+
+// startUploads resets the counts of the steps of uploads.
+func (p *progressReporter) startUploads(total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uploadsDone, p.uploadTotal = 0, total
+}
+
+// page reports the step of the page, or defers it until flush if batched is true.
+func (p *progressReporter) page(a ProgressAction, page, index int, batched bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	step := Progress{Action: a, Page: page, Index: index}
+	if batched {
+		p.pending = append(p.pending, step)
+		return
+	}
+	p.reportPage(step)
+}
+
+// flush reports the steps of the pages in the batch just sent.
+func (p *progressReporter) flush() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, step := range p.pending {
+		p.reportPage(step)
+	}
+	p.pending = nil
+}
+
+func (p *progressReporter) reportPage(step Progress) {
+	p.pagesDone++
+	step.Done, step.Total = p.pagesDone, p.pagesTotal
+	p.fn(step)
+}
+
+// upload reports that an image was uploaded.
+func (p *progressReporter) upload() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uploadsDone++
+	p.fn(Progress{Action: ProgressUpload, Index: -1, Done: p.uploadsDone, Total: p.uploadTotal})
+}
@@ -0,0 +1,54 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProgressReporter(t *testing.T) {
+	var got []Progress
+	d := &Deck{}
+	if err := WithProgressFunc(func(p Progress) {
+		got = append(got, p)
+	})(d); err != nil {
+		t.Fatal(err)
+	}
+	d.progress.startPages([]*action{
+		{actionType: actionTypeUpdate, index: 0},
+		{actionType: actionTypeAppend},
+		{actionType: actionTypeDelete, index: 3},
+		{actionType: actionTypeSentinel},
+	})
+	d.progress.startUploads(1)
+
+	d.progress.page(ProgressUpdate, 1, 0, true)
+	d.progress.page(ProgressAppend, 2, 1, true)
+	if len(got) != 0 {
+		t.Fatalf("batched pages are reported before flush: %v", got)
+	}
+	d.progress.upload()
+	d.progress.flush()
+	d.progress.page(ProgressDelete, 0, 3, false)
+	d.progress.flush()
+
+	want := []Progress{
+		{Action: ProgressUpload, Index: -1, Done: 1, Total: 1},
+		{Action: ProgressUpdate, Page: 1, Index: 0, Done: 1, Total: 3},
+		{Action: ProgressAppend, Page: 2, Index: 1, Done: 2, Total: 3},
+		{Action: ProgressDelete, Index: 3, Done: 3, Total: 3},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestProgressReporterNil(t *testing.T) {
+	d := &Deck{}
+	// Without WithProgressFunc, reporting does nothing.
+	d.progress.startPages([]*action{{actionType: actionTypeUpdate}})
+	d.progress.page(ProgressUpdate, 1, 0, true)
+	d.progress.flush()
+	d.progress.startUploads(1)
+	d.progress.upload()
+}
@@ -0,0 +1,192 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/k1LoW/errors"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/slides/v1"
+)
+
+// The presentation fetched by refresh is kept as a local snapshot.
+// When a batch update only mutates the contents of existing pages, refresh fetches only the mutated pages
+// instead of the whole presentation. Creating, deleting or moving pages requires fetching the whole presentation.
+
+// markDirty records the pages mutated by requests.
+func (d *Deck) markDirty(requests []*slides.Request) {
+	d.fresh = false
+	if d.stale || d.presentation == nil {
+		d.stale = true
+		return
+	}
+	if d.dirtyPages == nil {
+		d.dirtyPages = map[string]struct{}{}
+	}
+	owners := d.elementPages()
+	for _, r := range requests {
+		pageID, ok := requestPageID(r, owners)
+		if !ok {
+			d.stale = true
+			return
+		}
+		d.dirtyPages[pageID] = struct{}{}
+	}
+}
+
+// refreshDirtyPages fetches the pages mutated since the last refresh and replaces them in the snapshot.
+// It reports false if the whole presentation has to be fetched instead.
+func (d *Deck) refreshDirtyPages(ctx context.Context) (_ bool, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if d.stale || d.presentation == nil || d.presentation.PresentationId != d.id || d.lastRevisionID == "" {
+		return false, nil
+	}
+	pageIDs := make([]string, 0, len(d.dirtyPages))
+	for id := range d.dirtyPages {
+		pageIDs = append(pageIDs, id)
+	}
+	pages := make([]*slides.Page, len(pageIDs))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(d.concurrency)
+	for i, id := range pageIDs {
+		eg.Go(func() error {
+			page, err := d.srv.Presentations.Pages.Get(d.id, id).Context(ctx).Do()
+			if err != nil {
+				return fmt.Errorf("failed to get page %s: %w", id, err)
+			}
+			pages[i] = page
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return false, err
+	}
+	for _, page := range pages {
+		idx := slices.IndexFunc(d.presentation.Slides, func(s *slides.Page) bool {
+			return s.ObjectId == page.ObjectId
+		})
+		if idx < 0 {
+			return false, nil
+		}
+		d.presentation.Slides[idx] = page
+	}
+	d.presentation.RevisionId = d.lastRevisionID
+	d.logger.Debug("refreshed mutated pages", slog.Int("count", len(pages)))
+	return true, nil
+}
+
+// resetDirty clears the pages recorded by markDirty after the snapshot is refreshed.
+func (d *Deck) resetDirty() {
+	d.dirtyPages = nil
+	d.stale = false
+}
+
+// elementPages returns the object IDs of the page elements and the pages (including notes pages)
+// mapped to the object IDs of the pages that contain them.
+func (d *Deck) elementPages() map[string]string {
+	owners := map[string]string{}
+	var walk func(pageID string, elements []*slides.PageElement)
+	walk = func(pageID string, elements []*slides.PageElement) {
+		for _, e := range elements {
+			owners[e.ObjectId] = pageID
+			if e.ElementGroup != nil {
+				walk(pageID, e.ElementGroup.Children)
+			}
+		}
+	}
+	for _, p := range d.presentation.Slides {
+		owners[p.ObjectId] = p.ObjectId
+		walk(p.ObjectId, p.PageElements)
+		if p.SlideProperties != nil && p.SlideProperties.NotesPage != nil {
+			owners[p.SlideProperties.NotesPage.ObjectId] = p.ObjectId
+			walk(p.ObjectId, p.SlideProperties.NotesPage.PageElements)
+		}
+	}
+	return owners
+}
+
+// requestPageID returns the object ID of the page mutated by r.
+// It reports false if r changes the structure of the presentation or the page is unknown.
+// Page elements created by r are added to owners.
+func requestPageID(r *slides.Request, owners map[string]string) (string, bool) {
+	var objectID string
+	switch {
+	case r.CreateShape != nil:
+		return createdElementPageID(r.CreateShape.ObjectId, r.CreateShape.ElementProperties, owners)
+	case r.CreateImage != nil:
+		return createdElementPageID(r.CreateImage.ObjectId, r.CreateImage.ElementProperties, owners)
+	case r.CreateTable != nil:
+		return createdElementPageID(r.CreateTable.ObjectId, r.CreateTable.ElementProperties, owners)
+	case r.DeleteObject != nil:
+		objectID = r.DeleteObject.ObjectId
+		if owners[objectID] == objectID {
+			// Deleting a page
+			return "", false
+		}
+	case r.InsertText != nil:
+		objectID = r.InsertText.ObjectId
+	case r.DeleteText != nil:
+		objectID = r.DeleteText.ObjectId
+	case r.UpdateTextStyle != nil:
+		objectID = r.UpdateTextStyle.ObjectId
+	case r.UpdateParagraphStyle != nil:
+		objectID = r.UpdateParagraphStyle.ObjectId
+	case r.CreateParagraphBullets != nil:
+		objectID = r.CreateParagraphBullets.ObjectId
+	case r.DeleteParagraphBullets != nil:
+		objectID = r.DeleteParagraphBullets.ObjectId
+	case r.UpdateShapeProperties != nil:
+		objectID = r.UpdateShapeProperties.ObjectId
+	case r.UpdatePageElementAltText != nil:
+		objectID = r.UpdatePageElementAltText.ObjectId
+	case r.UpdateImageProperties != nil:
+		objectID = r.UpdateImageProperties.ObjectId
+	case r.ReplaceImage != nil:
+		objectID = r.ReplaceImage.ImageObjectId
+	case r.InsertTableRows != nil:
+		objectID = r.InsertTableRows.TableObjectId
+	case r.InsertTableColumns != nil:
+		objectID = r.InsertTableColumns.TableObjectId
+	case r.DeleteTableRow != nil:
+		objectID = r.DeleteTableRow.TableObjectId
+	case r.DeleteTableColumn != nil:
+		objectID = r.DeleteTableColumn.TableObjectId
+	case r.UpdateTableCellProperties != nil:
+		objectID = r.UpdateTableCellProperties.ObjectId
+	case r.UpdateTableBorderProperties != nil:
+		objectID = r.UpdateTableBorderProperties.ObjectId
+	case r.UpdateTableColumnProperties != nil:
+		objectID = r.UpdateTableColumnProperties.ObjectId
+	case r.UpdateTableRowProperties != nil:
+		objectID = r.UpdateTableRowProperties.ObjectId
+	case r.UpdatePageElementTransform != nil:
+		objectID = r.UpdatePageElementTransform.ObjectId
+	case r.UpdateSlideProperties != nil:
+		objectID = r.UpdateSlideProperties.ObjectId
+	case r.UpdatePageProperties != nil:
+		objectID = r.UpdatePageProperties.ObjectId
+	default:
+		// CreateSlide, UpdateSlidesPosition, DuplicateObject and other requests
+		return "", false
+	}
+	pageID, ok := owners[objectID]
+	return pageID, ok
+}
+
+func createdElementPageID(objectID string, props *slides.PageElementProperties, owners map[string]string) (string, bool) {
+	if props == nil {
+		return "", false
+	}
+	pageID, ok := owners[props.PageObjectId]
+	if !ok {
+		return "", false
+	}
+	if objectID != "" {
+		owners[objectID] = pageID
+	}
+	return pageID, true
+}
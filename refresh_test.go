@@ -0,0 +1,116 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestMarkDirty(t *testing.T) {
+	presentation := &slides.Presentation{
+		Slides: []*slides.Page{
+			{
+				ObjectId:     "p1",
+				PageElements: []*slides.PageElement{{ObjectId: "title1"}},
+				SlideProperties: &slides.SlideProperties{
+					NotesPage: &slides.Page{
+						ObjectId:     "notes1",
+						PageElements: []*slides.PageElement{{ObjectId: "speaker1"}},
+					},
+				},
+			},
+			{
+				ObjectId: "p2",
+				PageElements: []*slides.PageElement{{
+					ObjectId:     "group2",
+					ElementGroup: &slides.Group{Children: []*slides.PageElement{{ObjectId: "child2"}}},
+				}},
+			},
+			{ObjectId: "p3"},
+		},
+	}
+	tests := []struct {
+		name      string
+		requests  []*slides.Request
+		wantPages []string
+		wantStale bool
+	}{
+		{
+			name: "text of elements",
+			requests: []*slides.Request{
+				{InsertText: &slides.InsertTextRequest{ObjectId: "title1"}},
+				{DeleteText: &slides.DeleteTextRequest{ObjectId: "child2"}},
+			},
+			wantPages: []string{"p1", "p2"},
+		},
+		{
+			name: "speaker notes",
+			requests: []*slides.Request{
+				{InsertText: &slides.InsertTextRequest{ObjectId: "speaker1"}},
+			},
+			wantPages: []string{"p1"},
+		},
+		{
+			name: "created element",
+			requests: []*slides.Request{
+				{CreateShape: &slides.CreateShapeRequest{ObjectId: "new", ElementProperties: &slides.PageElementProperties{PageObjectId: "p3"}}},
+				{InsertText: &slides.InsertTextRequest{ObjectId: "new"}},
+			},
+			wantPages: []string{"p3"},
+		},
+		{
+			name: "delete element",
+			requests: []*slides.Request{
+				{DeleteObject: &slides.DeleteObjectRequest{ObjectId: "title1"}},
+			},
+			wantPages: []string{"p1"},
+		},
+		{
+			name: "delete page",
+			requests: []*slides.Request{
+				{DeleteObject: &slides.DeleteObjectRequest{ObjectId: "p1"}},
+			},
+			wantStale: true,
+		},
+		{
+			name: "create page",
+			requests: []*slides.Request{
+				{InsertText: &slides.InsertTextRequest{ObjectId: "title1"}},
+				{CreateSlide: &slides.CreateSlideRequest{}},
+			},
+			wantStale: true,
+		},
+		{
+			name: "unknown element",
+			requests: []*slides.Request{
+				{InsertText: &slides.InsertTextRequest{ObjectId: "unknown"}},
+			},
+			wantStale: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Deck{presentation: presentation, fresh: true}
+			d.markDirty(tt.requests)
+			if d.fresh {
+				t.Error("want not fresh")
+			}
+			if d.stale != tt.wantStale {
+				t.Errorf("got stale %v, want %v", d.stale, tt.wantStale)
+			}
+			if tt.wantStale {
+				return
+			}
+			var got []string
+			for _, p := range presentation.Slides {
+				if _, ok := d.dirtyPages[p.ObjectId]; ok {
+					got = append(got, p.ObjectId)
+				}
+			}
+			if diff := cmp.Diff(tt.wantPages, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
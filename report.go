@@ -0,0 +1,124 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/drive/v3"
+)
+
+// PageChange represents a page of the source that was appended or updated by the last apply.
+type PageChange struct {
+	Page   int      `json:"page"`
+	Action string   `json:"action"`
+	Owner  string   `json:"owner,omitempty"`
+	Titles []string `json:"titles,omitempty"`
+}
+
+// String returns a human-readable description of the change.
+func (c *PageChange) String() string {
+	if c.Owner != "" {
+		return fmt.Sprintf("page %d owned by %s changed", c.Page, c.Owner)
+	}
+	return fmt.Sprintf("page %d changed", c.Page)
+}
+
+// Changes returns the pages appended or updated by the last apply.
+func (d *Deck) Changes() []*PageChange {
+	return d.changes
+}
+
+// ApplySummary is the number of pages appended, updated, moved and deleted by the last apply.
+type ApplySummary struct {
+	Appended int `json:"appended"`
+	Updated  int `json:"updated"`
+	Moved    int `json:"moved"`
+	Deleted  int `json:"deleted"`
+}
+
+// Summary returns the number of pages appended, updated, moved and deleted by the last apply.
+// The pages that failed with WithContinueOnError are not counted as appended or updated.
+func (d *Deck) Summary() ApplySummary {
+	return d.summary
+}
+
+// summarizeActions counts the actions by the type.
+func summarizeActions(actions []*action) ApplySummary {
+	var s ApplySummary
+	for _, a := range actions {
+		switch a.actionType {
+		case actionTypeAppend:
+			s.Appended++
+		case actionTypeUpdate:
+			s.Updated++
+		case actionTypeMove:
+			s.Moved++
+		case actionTypeDelete:
+			s.Deleted++
+		}
+	}
+	return s
+}
+
+// pageChanges collects the pages to be appended or updated from the actions.
+func pageChanges(actions []*action) []*PageChange {
+	var changes []*PageChange
+	for _, a := range actions {
+		if a.actionType != actionTypeAppend && a.actionType != actionTypeUpdate {
+			continue
+		}
+		if a.slide == nil || a.slide.page == 0 {
+			continue
+		}
+		changes = append(changes, &PageChange{
+			Page:   a.slide.page,
+			Action: a.actionType.String(),
+			Owner:  a.slide.Owner,
+			Titles: a.slide.Titles,
+		})
+	}
+	return changes
+}
+
+// excludeFailedPages removes the pages that failed with WithContinueOnError from the changes and the summary.
+func (d *Deck) excludeFailedPages(pageErrs PageErrors) {
+	failed := func(c *PageChange) bool {
+		return slices.ContainsFunc(pageErrs, func(pe *PageError) bool { return pe.Page == c.Page })
+	}
+	for _, c := range d.changes {
+		if !failed(c) {
+			continue
+		}
+		switch c.Action {
+		case actionTypeAppend.String():
+			d.summary.Appended--
+		case actionTypeUpdate.String():
+			d.summary.Updated--
+		}
+	}
+	d.changes = slices.DeleteFunc(d.changes, failed)
+}
+
+// notifyOwnersOfChanges posts a Drive comment mentioning the owner of each changed page.
+// Google Drive notifies the mentioned user when the owner is written as an email address (e.g. @alice@example.com).
+func (d *Deck) notifyOwnersOfChanges(ctx context.Context) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	for _, c := range d.changes {
+		if c.Owner == "" {
+			continue
+		}
+		comment := &drive.Comment{
+			Content: fmt.Sprintf("%s page %d was changed by deck", c.Owner, c.Page),
+		}
+		if _, err := d.driveSrv.Comments.Create(d.id, comment).Fields("id").Context(ctx).Do(); err != nil {
+			return fmt.Errorf("failed to create comment for page %d: %w", c.Page, err)
+		}
+		d.logger.Info("notified owner", slog.Int("page", c.Page), slog.String("owner", c.Owner))
+	}
+	return nil
+}
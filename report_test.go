@@ -0,0 +1,31 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPageChanges(t *testing.T) {
+	actions := []*action{
+		{actionType: actionTypeUpdate, index: 0, slide: &Slide{Titles: []string{"A"}, Owner: "@alice", page: 1}},
+		{actionType: actionTypeAppend, index: 2, slide: &Slide{Titles: []string{"C"}, page: 3}},
+		{actionType: actionTypeMove, index: 1, moveToIndex: 0, slide: &Slide{Titles: []string{"B"}, page: 2}},
+		{actionType: actionTypeDelete, index: 4, slide: &Slide{Titles: []string{"D"}}},
+		{actionType: actionTypeUpdate, index: 3, slide: &Slide{Titles: []string{"not in source"}}},
+	}
+	got := pageChanges(actions)
+	want := []*PageChange{
+		{Page: 1, Action: "update", Owner: "@alice", Titles: []string{"A"}},
+		{Page: 3, Action: "append", Titles: []string{"C"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("pageChanges() mismatch (-want +got):\n%s", diff)
+	}
+	if got := want[0].String(); got != "page 1 owned by @alice changed" {
+		t.Errorf("String() = %q", got)
+	}
+	if diff := cmp.Diff(ApplySummary{Appended: 1, Updated: 2, Moved: 1, Deleted: 1}, summarizeActions(actions)); diff != "" {
+		t.Errorf("summarizeActions() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,116 @@
+// Package requests provides functions to build Google Slides API requests from the deck model.
+// The functions are pure: they require neither a Deck instance nor network access.
+package requests
+
+import (
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/internal/requestbuilder"
+	"google.golang.org/api/slides/v1"
+)
+
+// Option is an option to customize the styles used when building requests.
+type Option func(*requestbuilder.Options)
+
+// WithTextStyle sets the text style used for the style named name (e.g. "bold", "link", "blockquote").
+func WithTextStyle(name string, style *slides.TextStyle) Option {
+	return func(o *requestbuilder.Options) {
+		if o.TextStyles == nil {
+			o.TextStyles = map[string]*slides.TextStyle{}
+		}
+		o.TextStyles[name] = style
+	}
+}
+
+// WithShapeProperties sets the shape properties used for the style named name (e.g. "blockquote").
+func WithShapeProperties(name string, props *slides.ShapeProperties) Option {
+	return func(o *requestbuilder.Options) {
+		if o.ShapeProperties == nil {
+			o.ShapeProperties = map[string]*slides.ShapeProperties{}
+		}
+		o.ShapeProperties[name] = props
+	}
+}
+
+// WithParagraphStyle sets the paragraph style (alignment and spacing) used for paragraphs of the style named name,
+// such as the paragraphs with {.class} or <p class="...">.
+func WithParagraphStyle(name string, style *slides.ParagraphStyle) Option {
+	return func(o *requestbuilder.Options) {
+		if o.ParagraphStyles == nil {
+			o.ParagraphStyles = map[string]*slides.ParagraphStyle{}
+		}
+		o.ParagraphStyles[name] = style
+	}
+}
+
+// WithTableStyle sets the table style applied to tables.
+func WithTableStyle(ts *deck.TableStyle) Option {
+	return func(o *requestbuilder.Options) {
+		o.TableStyle = ts
+	}
+}
+
+// WithDirection sets the text direction of paragraphs, deck.DirectionLTR or deck.DirectionRTL.
+// When it is empty (default), the direction of each paragraph is detected from its text.
+func WithDirection(direction string) Option {
+	return func(o *requestbuilder.Options) {
+		o.Direction = direction
+	}
+}
+
+// builder is the request builder of package deck.
+type builder interface {
+	ParagraphsRequests(objectID string, paragraphs []*deck.Paragraph) (reqs []*slides.Request, styleReqs []*slides.Request)
+	InlineStyleRequest(fragment *deck.Fragment) *slides.UpdateTextStyleRequest
+	StyleRequest(styleName string) *slides.UpdateTextStyleRequest
+	TableStructureRequests(pageObjectID, tableObjectID string, table *deck.Table, index int) []*slides.Request
+	TableContentRequests(tableObjectID string, table *deck.Table) []*slides.Request
+	BlockQuoteRequests(pageObjectID, textBoxObjectID string, bq *deck.BlockQuote, index int) []*slides.Request
+}
+
+// newBuilder returns the request builder of package deck with opts.
+func newBuilder(opts []Option) builder {
+	o := &requestbuilder.Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return requestbuilder.New(o).(builder)
+}
+
+// Paragraphs returns requests to insert paragraphs into the empty shape identified by objectID,
+// followed by requests to apply inline styles and bullets.
+func Paragraphs(objectID string, paragraphs []*deck.Paragraph, opts ...Option) []*slides.Request {
+	reqs, styleReqs := newBuilder(opts).ParagraphsRequests(objectID, paragraphs)
+	return append(reqs, styleReqs...)
+}
+
+// InlineStyle returns a request to apply the inline styles of fragment.
+// ObjectId and TextRange of the returned request are not set.
+// It returns nil if fragment has no style.
+func InlineStyle(fragment *deck.Fragment, opts ...Option) *slides.UpdateTextStyleRequest {
+	return newBuilder(opts).InlineStyleRequest(fragment)
+}
+
+// Style returns a request to apply the style named styleName.
+// It returns nil if the style is not found.
+func Style(styleName string, opts ...Option) *slides.UpdateTextStyleRequest {
+	return newBuilder(opts).StyleRequest(styleName)
+}
+
+// Table returns requests to create table identified by tableObjectID with its content
+// on the page identified by pageObjectID.
+// index is the position of the table in the page and is used to offset the table.
+func Table(pageObjectID, tableObjectID string, table *deck.Table, index int, opts ...Option) []*slides.Request {
+	b := newBuilder(opts)
+	reqs := b.TableStructureRequests(pageObjectID, tableObjectID, table, index)
+	if len(reqs) == 0 {
+		return nil
+	}
+	return append(reqs, b.TableContentRequests(tableObjectID, table)...)
+}
+
+// BlockQuote returns requests to create a text box identified by textBoxObjectID for bq
+// on the page identified by pageObjectID.
+// index is the position of the block quote in the page and is used to offset the text box.
+func BlockQuote(pageObjectID, textBoxObjectID string, bq *deck.BlockQuote, index int, opts ...Option) []*slides.Request {
+	return newBuilder(opts).BlockQuoteRequests(pageObjectID, textBoxObjectID, bq, index)
+}
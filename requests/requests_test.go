@@ -0,0 +1,255 @@
+package requests
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestParagraphs(t *testing.T) {
+	paragraphs := []*deck.Paragraph{
+		{Fragments: []*deck.Fragment{{Value: "Hello "}, {Value: "world", Bold: true}}},
+		{Fragments: []*deck.Fragment{{Value: "item"}}, Bullet: deck.BulletDash},
+	}
+	got := Paragraphs("shape", paragraphs)
+	want := []*slides.Request{
+		{InsertText: &slides.InsertTextRequest{ObjectId: "shape", Text: "Hello world\nitem"}},
+		{UpdateTextStyle: &slides.UpdateTextStyleRequest{
+			ObjectId:  "shape",
+			Style:     &slides.TextStyle{Bold: true},
+			Fields:    ",bold",
+			TextRange: &slides.Range{Type: "FIXED_RANGE", StartIndex: new(int64(6)), EndIndex: new(int64(11))},
+		}},
+		{CreateParagraphBullets: &slides.CreateParagraphBulletsRequest{
+			ObjectId:     "shape",
+			BulletPreset: "BULLET_DISC_CIRCLE_SQUARE",
+			TextRange:    &slides.Range{Type: "FIXED_RANGE", StartIndex: new(int64(12)), EndIndex: new(int64(16))},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestParagraphsStyleName(t *testing.T) {
+	paragraphs := []*deck.Paragraph{
+		{Fragments: []*deck.Fragment{{Value: "Hello"}}},
+		{Fragments: []*deck.Fragment{{Value: "Lead"}}, StyleName: "lead"},
+	}
+	got := Paragraphs("shape", paragraphs, WithParagraphStyle("lead", &slides.ParagraphStyle{
+		Alignment:  "CENTER",
+		SpaceAbove: &slides.Dimension{Magnitude: 6, Unit: "PT"},
+	}))
+	want := []*slides.Request{
+		{InsertText: &slides.InsertTextRequest{ObjectId: "shape", Text: "Hello\nLead"}},
+		{UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+			ObjectId: "shape",
+			Style: &slides.ParagraphStyle{
+				Alignment:  "CENTER",
+				SpaceAbove: &slides.Dimension{Magnitude: 6, Unit: "PT"},
+			},
+			Fields:    "alignment,spaceAbove",
+			TextRange: &slides.Range{Type: "FIXED_RANGE", StartIndex: new(int64(6)), EndIndex: new(int64(10))},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestInlineStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment *deck.Fragment
+		opts     []Option
+		want     *slides.UpdateTextStyleRequest
+	}{
+		{
+			name:     "no style",
+			fragment: &deck.Fragment{Value: "plain"},
+			want:     nil,
+		},
+		{
+			name:     "default bold",
+			fragment: &deck.Fragment{Value: "bold", Bold: true},
+			want:     &slides.UpdateTextStyleRequest{Style: &slides.TextStyle{Bold: true}, Fields: ",bold"},
+		},
+		{
+			name:     "default link",
+			fragment: &deck.Fragment{Value: "link", Link: "https://example.com"},
+			want: &slides.UpdateTextStyleRequest{
+				Style:  &slides.TextStyle{Link: &slides.Link{Url: "https://example.com"}},
+				Fields: ",link",
+			},
+		},
+		{
+			name:     "unknown style name",
+			fragment: &deck.Fragment{Value: "x", StyleName: "unknown"},
+			want:     nil,
+		},
+		{
+			name:     "custom style",
+			fragment: &deck.Fragment{Value: "x", StyleName: "custom"},
+			opts:     []Option{WithTextStyle("custom", &slides.TextStyle{Italic: true, FontFamily: "Arial"})},
+			want: &slides.UpdateTextStyleRequest{
+				Style:  &slides.TextStyle{Italic: true, FontFamily: "Arial"},
+				Fields: ",backgroundColor,baselineOffset,bold,fontFamily,foregroundColor,italic,strikethrough,underline",
+			},
+		},
+		{
+			name:     "color",
+			fragment: &deck.Fragment{Value: "x", Color: "#ff8000"},
+			want: &slides.UpdateTextStyleRequest{
+				Style: &slides.TextStyle{ForegroundColor: &slides.OptionalColor{OpaqueColor: &slides.OpaqueColor{
+					RgbColor: &slides.RgbColor{Red: 1, Green: float64(0x80) / 255, Blue: 0},
+				}}},
+				Fields: ",foregroundColor",
+			},
+		},
+		{
+			name:     "color overrides custom style",
+			fragment: &deck.Fragment{Value: "x", StyleName: "custom", Color: "#0000ff"},
+			opts: []Option{WithTextStyle("custom", &slides.TextStyle{Bold: true, ForegroundColor: &slides.OptionalColor{OpaqueColor: &slides.OpaqueColor{
+				RgbColor: &slides.RgbColor{Red: 1},
+			}}})},
+			want: &slides.UpdateTextStyleRequest{
+				Style: &slides.TextStyle{Bold: true, ForegroundColor: &slides.OptionalColor{OpaqueColor: &slides.OpaqueColor{
+					RgbColor: &slides.RgbColor{Blue: 1},
+				}}},
+				Fields: ",backgroundColor,baselineOffset,bold,fontFamily,foregroundColor,italic,strikethrough,underline",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InlineStyle(tt.fragment, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	if got := Table("page", "table", &deck.Table{}, 0); got != nil {
+		t.Errorf("empty table should have no requests, got %v", got)
+	}
+
+	table := &deck.Table{
+		Rows: []*deck.TableRow{
+			{Cells: []*deck.TableCell{{Fragments: []*deck.Fragment{{Value: "A"}}}, {Fragments: []*deck.Fragment{{Value: "B"}}}}},
+			{Cells: []*deck.TableCell{{Fragments: []*deck.Fragment{{Value: "1"}}, Alignment: "END"}}},
+		},
+	}
+	got := Table("page", "table", table, 1)
+	if len(got) != 6 {
+		t.Fatalf("got %d requests, want 6", len(got))
+	}
+	ct := got[0].CreateTable
+	if ct == nil {
+		t.Fatal("first request should create the table")
+	}
+	if ct.ObjectId != "table" || ct.ElementProperties.PageObjectId != "page" || ct.Rows != 2 || ct.Columns != 2 {
+		t.Errorf("unexpected create table request: %+v", ct)
+	}
+	if got[1].UpdatePageElementAltText == nil {
+		t.Error("second request should mark the table as generated from markdown")
+	}
+	var texts []string
+	for _, r := range got[2:] {
+		if r.InsertText != nil {
+			texts = append(texts, r.InsertText.Text)
+		}
+	}
+	if diff := cmp.Diff([]string{"A", "B", "1"}, texts); diff != "" {
+		t.Error(diff)
+	}
+	if got[5].UpdateParagraphStyle == nil || got[5].UpdateParagraphStyle.Style.Alignment != "END" {
+		t.Errorf("last request should align the cell, got %+v", got[5])
+	}
+}
+
+func TestBlockQuote(t *testing.T) {
+	bq := &deck.BlockQuote{
+		Paragraphs: []*deck.Paragraph{{Fragments: []*deck.Fragment{{Value: "quote"}}}},
+	}
+	sp := &slides.ShapeProperties{Outline: &slides.Outline{PropertyState: "NOT_RENDERED"}}
+	got := BlockQuote("page", "textbox", bq, 0,
+		WithShapeProperties("blockquote", sp),
+		WithTextStyle("blockquote", &slides.TextStyle{Italic: true}),
+	)
+	var kinds []string
+	for _, r := range got {
+		switch {
+		case r.CreateShape != nil:
+			kinds = append(kinds, "CreateShape")
+		case r.UpdateShapeProperties != nil:
+			kinds = append(kinds, "UpdateShapeProperties")
+		case r.InsertText != nil:
+			kinds = append(kinds, "InsertText")
+		case r.UpdateTextStyle != nil:
+			kinds = append(kinds, "UpdateTextStyle")
+		case r.UpdatePageElementAltText != nil:
+			kinds = append(kinds, "UpdatePageElementAltText")
+		}
+	}
+	want := []string{"CreateShape", "UpdateShapeProperties", "InsertText", "UpdateTextStyle", "UpdatePageElementAltText"}
+	if diff := cmp.Diff(want, kinds); diff != "" {
+		t.Error(diff)
+	}
+	if got[0].CreateShape.ElementProperties.PageObjectId != "page" {
+		t.Errorf("got page object ID %q, want %q", got[0].CreateShape.ElementProperties.PageObjectId, "page")
+	}
+}
+
+func TestBlockQuoteStyleName(t *testing.T) {
+	opts := []Option{
+		WithShapeProperties("blockquote", &slides.ShapeProperties{Outline: &slides.Outline{PropertyState: "NOT_RENDERED"}}),
+		WithShapeProperties("blockquote-2", &slides.ShapeProperties{Outline: &slides.Outline{PropertyState: "INHERIT"}}),
+		WithShapeProperties("warning", &slides.ShapeProperties{Outline: &slides.Outline{PropertyState: "RENDERED"}}),
+	}
+	tests := []struct {
+		name      string
+		styleName string
+		nesting   int
+		want      string
+	}{
+		{"default", "", 0, "NOT_RENDERED"},
+		{"class", "warning", 0, "RENDERED"},
+		{"undefined class", "undefined", 0, "NOT_RENDERED"},
+		{"nested", "", 1, "INHERIT"},
+		{"nested without level style", "", 2, "NOT_RENDERED"},
+		{"nested with class", "warning", 1, "RENDERED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bq := &deck.BlockQuote{
+				Paragraphs: []*deck.Paragraph{{Fragments: []*deck.Fragment{{Value: "quote"}}}},
+				Nesting:    tt.nesting,
+				StyleName:  tt.styleName,
+			}
+			got := BlockQuote("page", "textbox", bq, 0, opts...)
+			if got[1].UpdateShapeProperties == nil {
+				t.Fatal("want UpdateShapeProperties request")
+			}
+			if got := got[1].UpdateShapeProperties.ShapeProperties.Outline.PropertyState; got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockQuoteIndent(t *testing.T) {
+	for nesting, want := range []float64{100000, 500000, 900000} {
+		bq := &deck.BlockQuote{
+			Paragraphs: []*deck.Paragraph{{Fragments: []*deck.Fragment{{Value: "quote"}}}},
+			Nesting:    nesting,
+		}
+		got := BlockQuote("page", "textbox", bq, 0)
+		if got := got[0].CreateShape.ElementProperties.Transform.TranslateX; got != want {
+			t.Errorf("nesting %d: got %v, want %v", nesting, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/k1LoW/errors"
+)
+
+// While applying, every batch update requires the revision of the presentation that the actions were planned
+// against (or the revision returned by the previous batch update). If someone edits the presentation in the
+// meantime, the batch update is rejected instead of moving or deleting the pages at shifted indices, and
+// the apply is planned again from the edited presentation.
+
+// defaultEditRetries is the default number of times to plan and apply again when the presentation is edited during apply.
+const defaultEditRetries = 2
+
+// WithEditRetries sets the number of times to plan and apply again when the presentation is edited by others
+// during apply (default: 2). With 0, the apply aborts with ConcurrentEditError at the first edit.
+func WithEditRetries(n int) Option {
+	return func(d *Deck) error {
+		if n < 0 {
+			return fmt.Errorf("invalid edit retries: %d", n)
+		}
+		d.editRetries = n
+		return nil
+	}
+}
+
+// ConcurrentEditError is the error of an apply rejected because the presentation was edited by others during the apply.
+type ConcurrentEditError struct {
+	RevisionID        string // revision the apply was planned against
+	CurrentRevisionID string // revision of the edited presentation
+}
+
+func (e *ConcurrentEditError) Error() string {
+	return fmt.Sprintf("the presentation was edited by others during apply (revision %s, expected %s): stop editing the presentation and apply again",
+		e.CurrentRevisionID, e.RevisionID)
+}
+
+// applyWithEditRetries runs apply, and plans and runs it again from the refetched presentation
+// when it fails with ConcurrentEditError, up to the number of edit retries.
+func (d *Deck) applyWithEditRetries(ctx context.Context, ss Slides, apply func() error) error {
+	for retries := 0; ; retries++ {
+		err := apply()
+		ce, ok := errors.AsType[*ConcurrentEditError](err)
+		if !ok {
+			return err
+		}
+		if retries >= d.editRetries {
+			if retries == 0 {
+				return err
+			}
+			return fmt.Errorf("gave up after %d retries: %w", retries, err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		d.logger.Warn("the presentation was edited during apply, planning again",
+			slog.String("revision_id", ce.RevisionID), slog.String("current_revision_id", ce.CurrentRevisionID),
+			slog.Int("retry", retries+1))
+		// Fetch the whole edited presentation, and upload the images deleted after the failed apply again.
+		d.fresh = false
+		d.stale = true
+		if !d.keepUploaded {
+			for _, s := range ss {
+				for _, i := range s.Images {
+					i.resetUpload()
+				}
+			}
+		}
+	}
+}
+
+// checkRevision returns ConcurrentEditError if the presentation has been edited since the required revision.
+// It is called when a batch update requiring the revision fails.
+func (d *Deck) checkRevision(ctx context.Context) error {
+	if d.requiredRevisionID == "" {
+		return nil
+	}
+	p, err := d.srv.Presentations.Get(d.id).Fields("revisionId").Context(ctx).Do()
+	if err != nil {
+		return nil
+	}
+	if p.RevisionId == d.requiredRevisionID {
+		return nil
+	}
+	return &ConcurrentEditError{RevisionID: d.requiredRevisionID, CurrentRevisionID: p.RevisionId}
+}
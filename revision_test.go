@@ -0,0 +1,107 @@
+package deck
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+	"github.com/k1LoW/errors"
+)
+
+func TestApplyConcurrentEdit(t *testing.T) {
+	ctx := t.Context()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(u)
+
+	tests := []struct {
+		name    string
+		edits   int64 // number of batch updates of deck preceded by an edit of others
+		retries int
+		wantErr bool
+	}{
+		{"no edit", 0, 0, false},
+		{"edited once", 1, 2, false},
+		{"edited on every retry", 3, 2, true},
+		{"no retry", 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := srv.CreatePresentation("test")
+			setup, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(id))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := setup.Apply(ctx, Slides{
+				{Layout: "title", Titles: []string{"A"}},
+				{Layout: "title", Titles: []string{"B"}},
+				{Layout: "title", Titles: []string{"C"}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			// Others insert a page at the top right before the batch updates of deck.
+			edits := tt.edits
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, ":batchUpdate") && edits > 0 {
+					edits--
+					res, err := http.Post(srv.URL+"/v1/presentations/"+id+":batchUpdate", "application/json",
+						strings.NewReader(`{"requests":[{"createSlide":{"insertionIndex":0}}]}`))
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					res.Body.Close()
+				}
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Error(err)
+				}
+				r.Body = io.NopCloser(bytes.NewReader(b))
+				proxy.ServeHTTP(w, r)
+			}))
+			t.Cleanup(ts.Close)
+
+			d, err := New(ctx, WithEndpoint(ts.URL), WithPresentationID(id), WithEditRetries(tt.retries))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = d.Apply(ctx, Slides{
+				{Layout: "title", Titles: []string{"C"}},
+				{Layout: "title", Titles: []string{"A"}},
+			})
+			if tt.wantErr {
+				if _, ok := errors.AsType[*ConcurrentEditError](err); !ok {
+					t.Fatalf("got error %v, want ConcurrentEditError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := d.DumpSlides(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var titles []string
+			for _, s := range got {
+				titles = append(titles, strings.Join(s.Titles, ""))
+			}
+			// The page inserted by others is deleted instead of the pages of the markdown.
+			if diff := cmp.Diff([]string{"C", "A"}, titles); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
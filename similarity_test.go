@@ -163,6 +163,44 @@ func TestSimilarityCalculation(t *testing.T) {
 			},
 			expected: 130, // layout (50) + title (80), different image arrays don't match
 		},
+		{
+			name: "title with combining accent",
+			slide1: &Slide{
+				Layout:    "title",
+				Titles:    []string{"Café"},
+				Subtitles: []string{"Menu"},
+			},
+			slide2: &Slide{
+				Layout:    "title",
+				Titles:    []string{"Cafe\u0301"},
+				Subtitles: []string{"Drinks"},
+			},
+			expected: 130, // layout (50) + title (80), precomposed and combining accents are the same
+		},
+		{
+			name: "body with ZWJ sequence",
+			slide1: &Slide{
+				Layout: "content",
+				Bodies: []*Body{NewBody(NewParagraph("Team 👨‍👩‍👧 and café"))},
+			},
+			slide2: &Slide{
+				Layout: "content",
+				Bodies: []*Body{NewBody(NewParagraph("Team 👨‍👩‍👧 and cafe\u0301"))},
+			},
+			expected: 500, // perfect match
+		},
+		{
+			name: "body with emoji not joined",
+			slide1: &Slide{
+				Layout: "content",
+				Bodies: []*Body{NewBody(NewParagraph("Team 👨‍👩"))},
+			},
+			slide2: &Slide{
+				Layout: "content",
+				Bodies: []*Body{NewBody(NewParagraph("Team 👨👩"))},
+			},
+			expected: 50, // layout (50) only
+		},
 	}
 
 	for _, tt := range tests {
@@ -2,11 +2,17 @@ package deck
 
 import "strings"
 
+// Slides represents the slides of a presentation.
+// Slides can be built by parsing markdown with the md package, or programmatically with
+// NewSlide, NewBody, NewParagraph, NewTable and NewImageFromBytes.
 type Slides []*Slide
 
+// Slide represents a slide.
+// If TitleBodies (or SubtitleBodies) is empty, it is generated from Titles (or Subtitles) on apply.
 type Slide struct {
 	Layout         string        `json:"layout"`
 	Freeze         bool          `json:"freeze,omitempty"`
+	FreezePosition bool          `json:"freeze_position,omitempty"` // Keep the frozen page at its index instead of moving it with the slides around it
 	Skip           bool          `json:"skip,omitempty"`
 	Titles         []string      `json:"titles,omitempty"`
 	TitleBodies    []*Body       `json:"title_bodies,omitempty"`
@@ -16,10 +22,22 @@ type Slide struct {
 	Images         []*Image      `json:"images,omitempty"`
 	BlockQuotes    []*BlockQuote `json:"block_quotes,omitempty"`
 	Tables         []*Table      `json:"tables,omitempty"`
+	Elements       []*Element    `json:"elements,omitempty"` // Custom page elements created by the ElementFunc
 	SpeakerNote    string        `json:"speaker_note,omitempty"`
+	Owner          string        `json:"owner,omitempty"`
+	Key            string        `json:"key,omitempty"`           // Stable identifier used to match the slide with the page of the presentation
+	Autofit        string        `json:"autofit,omitempty"`       // Autofit of the body placeholders: "none", "shrink" or "resize" (default: keep the placeholder's)
+	ImageColumns   int           `json:"image_columns,omitempty"` // Number of columns of the grid of images not in picture placeholders (default: derived from the number of images)
+	ImageRows      int           `json:"image_rows,omitempty"`    // Number of rows of the grid of images not in picture placeholders (default: derived from the number of images)
+	Direction      string        `json:"direction,omitempty"`     // Text direction of the paragraphs: "ltr" or "rtl" (default: detected per paragraph)
+	// Spacing of the paragraphs of the body placeholders (default: keep the placeholder's)
+	ParagraphSpacing *ParagraphSpacing `json:"paragraph_spacing,omitempty"`
 
 	new    bool
 	delete bool
+	page   int    // 1-based page number in the source, 0 if unknown
+	pin    int    // nonzero for a frozen slide and the page pinned to it, which are matched only with each other
+	hash   string // hash of the contents of the slide to apply, or of the slide applied to the page read from its metadata
 }
 
 // Body represents the content body of a slide.
@@ -32,6 +50,7 @@ type Paragraph struct {
 	Fragments []*Fragment `json:"fragments,omitempty"`
 	Bullet    Bullet      `json:"bullet,omitempty"`
 	Nesting   int         `json:"nesting,omitempty"`
+	StyleName string      `json:"style_name,omitempty"` // Name of the style applied to the whole paragraph
 }
 
 // Fragment represents a text fragment within a paragraph.
@@ -39,28 +58,38 @@ type Fragment struct {
 	Value     string `json:"value"`
 	Bold      bool   `json:"bold,omitempty"`
 	Italic    bool   `json:"italic,omitempty"`
+	Underline bool   `json:"underline,omitempty"`
+	Highlight bool   `json:"highlight,omitempty"` // Highlighted with the background color of the `mark` style
 	Link      string `json:"link,omitempty"`
 	Code      bool   `json:"code,omitempty"`
 	StyleName string `json:"style_name,omitempty"`
+	Color     string `json:"color,omitempty"` // Text color in the form of "#rrggbb"
 }
 
+// BlockQuote represents a block quote, which is rendered as a text box.
 type BlockQuote struct {
 	Paragraphs []*Paragraph `json:"paragraphs,omitempty"`
 	Nesting    int          `json:"nesting,omitempty"`
+	StyleName  string       `json:"style_name,omitempty"` // Name of the style to use instead of "blockquote"
 }
 
+// Table represents a table.
 type Table struct {
 	Rows []*TableRow `json:"rows,omitempty"`
 }
 
+// TableRow represents a row of a table.
 type TableRow struct {
 	Cells []*TableCell `json:"cells,omitempty"`
 }
 
+// TableCell represents a cell of a table.
 type TableCell struct {
 	Fragments []*Fragment `json:"content,omitempty"`
 	Alignment string      `json:"alignment,omitempty"`
 	IsHeader  bool        `json:"is_header,omitempty"`
+	// Vertical alignment of the content: TOP, MIDDLE or BOTTOM (default: the content alignment of the table style)
+	VerticalAlignment string `json:"vertical_alignment,omitempty"`
 }
 
 // Bullet represents the type of bullet point for a paragraph.
@@ -73,6 +102,72 @@ const (
 	BulletNumbered Bullet = "1"
 )
 
+// NewSlide returns a new slide with the layout and titles.
+// If layout is empty, the default layout of the presentation is used.
+func NewSlide(layout string, titles ...string) *Slide {
+	s := &Slide{
+		Layout: layout,
+		Titles: titles,
+	}
+	s.fillBodies()
+	return s
+}
+
+// NewBody returns a new body consisting of paragraphs.
+func NewBody(paragraphs ...*Paragraph) *Body {
+	return &Body{Paragraphs: paragraphs}
+}
+
+// NewParagraph returns a new paragraph of plain text.
+func NewParagraph(text string) *Paragraph {
+	return &Paragraph{Fragments: []*Fragment{{Value: text}}}
+}
+
+// NewBulletParagraph returns a new list item of plain text with the bullet at the nesting level.
+func NewBulletParagraph(bullet Bullet, nesting int, text string) *Paragraph {
+	p := NewParagraph(text)
+	p.Bullet = bullet
+	p.Nesting = nesting
+	return p
+}
+
+// NewTable returns a new table of plain text.
+// If header is true, the cells in the first row are header cells.
+func NewTable(rows [][]string, header bool) *Table {
+	t := &Table{}
+	for i, row := range rows {
+		r := &TableRow{}
+		for _, v := range row {
+			r.Cells = append(r.Cells, &TableCell{
+				Fragments: []*Fragment{{Value: v}},
+				IsHeader:  header && i == 0,
+			})
+		}
+		t.Rows = append(t.Rows, r)
+	}
+	return t
+}
+
+// fillBodies generates TitleBodies and SubtitleBodies from Titles and Subtitles if they are empty, and vice versa.
+func (s *Slide) fillBodies() {
+	s.TitleBodies, s.Titles = fillTextBodies(s.TitleBodies, s.Titles)
+	s.SubtitleBodies, s.Subtitles = fillTextBodies(s.SubtitleBodies, s.Subtitles)
+}
+
+func fillTextBodies(bodies []*Body, texts []string) ([]*Body, []string) {
+	switch {
+	case len(bodies) == 0 && len(texts) > 0:
+		for _, t := range texts {
+			bodies = append(bodies, NewBody(NewParagraph(t)))
+		}
+	case len(texts) == 0 && len(bodies) > 0:
+		for _, b := range bodies {
+			texts = append(texts, strings.TrimSuffix(b.String(), "\n"))
+		}
+	}
+	return bodies, texts
+}
+
 func (b *Body) String() string {
 	var result strings.Builder
 	for i, paragraph := range b.Paragraphs {
@@ -146,7 +241,10 @@ func (f *Fragment) StylesEqual(other *Fragment) bool {
 	}
 	return f.Bold == other.Bold &&
 		f.Italic == other.Italic &&
+		f.Underline == other.Underline &&
+		f.Highlight == other.Highlight &&
 		f.Link == other.Link &&
 		f.Code == other.Code &&
-		f.StyleName == other.StyleName
+		f.StyleName == other.StyleName &&
+		f.Color == other.Color
 }
@@ -6,6 +6,8 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestNewImage(t *testing.T) {
@@ -287,3 +289,54 @@ func TestEquivalent(t *testing.T) {
 		})
 	}
 }
+
+func TestSlideFillBodies(t *testing.T) {
+	tests := []struct {
+		name  string
+		slide *Slide
+		want  *Slide
+	}{
+		{
+			name:  "bodies from titles",
+			slide: &Slide{Titles: []string{"Title"}, Subtitles: []string{"Sub"}},
+			want: &Slide{
+				Titles:         []string{"Title"},
+				TitleBodies:    []*Body{NewBody(NewParagraph("Title"))},
+				Subtitles:      []string{"Sub"},
+				SubtitleBodies: []*Body{NewBody(NewParagraph("Sub"))},
+			},
+		},
+		{
+			name:  "titles from bodies",
+			slide: &Slide{TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "Hello "}, {Value: "world", Bold: true}}}}}}},
+			want: &Slide{
+				Titles:      []string{"Hello world"},
+				TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "Hello "}, {Value: "world", Bold: true}}}}}},
+			},
+		},
+		{
+			name:  "keep both",
+			slide: &Slide{Titles: []string{"Title"}, TitleBodies: []*Body{NewBody(NewParagraph("Other"))}},
+			want:  &Slide{Titles: []string{"Title"}, TitleBodies: []*Body{NewBody(NewParagraph("Other"))}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.slide.fillBodies()
+			if diff := cmp.Diff(tt.want, tt.slide, cmp.AllowUnexported(Slide{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	got := NewTable([][]string{{"Name", "Value"}, {"a", "1"}}, true)
+	want := &Table{Rows: []*TableRow{
+		{Cells: []*TableCell{{Fragments: []*Fragment{{Value: "Name"}}, IsHeader: true}, {Fragments: []*Fragment{{Value: "Value"}}, IsHeader: true}}},
+		{Cells: []*TableCell{{Fragments: []*Fragment{{Value: "a"}}}, {Fragments: []*Fragment{{Value: "1"}}}}},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,90 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// ParagraphSpacing is the spacing of the paragraphs of the body placeholders. Nil fields are left as they are.
+type ParagraphSpacing struct {
+	LineSpacing *float64 `json:"lineSpacing,omitempty"` // spacing between lines in percent of normal (e.g. 90)
+	SpaceAbove  *float64 `json:"spaceAbove,omitempty"`  // extra space above paragraphs in points
+	SpaceBelow  *float64 `json:"spaceBelow,omitempty"`  // extra space below paragraphs in points
+}
+
+// ValidateParagraphSpacing returns an error if s is not a valid paragraph spacing. s may be nil.
+func ValidateParagraphSpacing(s *ParagraphSpacing) error {
+	if s == nil {
+		return nil
+	}
+	if s.LineSpacing != nil && *s.LineSpacing <= 0 {
+		return fmt.Errorf("invalid lineSpacing: %v (must be positive)", *s.LineSpacing)
+	}
+	if s.SpaceAbove != nil && *s.SpaceAbove < 0 {
+		return fmt.Errorf("invalid spaceAbove: %v (must not be negative)", *s.SpaceAbove)
+	}
+	if s.SpaceBelow != nil && *s.SpaceBelow < 0 {
+		return fmt.Errorf("invalid spaceBelow: %v (must not be negative)", *s.SpaceBelow)
+	}
+	return nil
+}
+
+// Merge returns the spacing of s overridden by the fields set in o. s and o may be nil.
+func (s *ParagraphSpacing) Merge(o *ParagraphSpacing) *ParagraphSpacing {
+	if s == nil && o == nil {
+		return nil
+	}
+	merged := &ParagraphSpacing{}
+	for _, src := range []*ParagraphSpacing{s, o} {
+		if src == nil {
+			continue
+		}
+		if src.LineSpacing != nil {
+			merged.LineSpacing = src.LineSpacing
+		}
+		if src.SpaceAbove != nil {
+			merged.SpaceAbove = src.SpaceAbove
+		}
+		if src.SpaceBelow != nil {
+			merged.SpaceBelow = src.SpaceBelow
+		}
+	}
+	return merged
+}
+
+// paragraphSpacingRequest returns the request to set the spacing of all paragraphs of the shape,
+// or nil if no spacing is set. The request must be sent after the text is inserted.
+func paragraphSpacingRequest(objectID string, s *ParagraphSpacing) *slides.Request {
+	if s == nil {
+		return nil
+	}
+	style := &slides.ParagraphStyle{}
+	var fields []string
+	if s.LineSpacing != nil {
+		style.LineSpacing = *s.LineSpacing
+		fields = append(fields, "lineSpacing")
+	}
+	if s.SpaceAbove != nil {
+		style.SpaceAbove = &slides.Dimension{Magnitude: *s.SpaceAbove, Unit: "PT"}
+		fields = append(fields, "spaceAbove")
+	}
+	if s.SpaceBelow != nil {
+		style.SpaceBelow = &slides.Dimension{Magnitude: *s.SpaceBelow, Unit: "PT"}
+		fields = append(fields, "spaceBelow")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &slides.Request{
+		UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+			ObjectId: objectID,
+			Style:    style,
+			Fields:   strings.Join(fields, ","),
+			TextRange: &slides.Range{
+				Type: "ALL",
+			},
+		},
+	}
+}
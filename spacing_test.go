@@ -0,0 +1,118 @@
+package deck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestValidateParagraphSpacing(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       *ParagraphSpacing
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"empty", &ParagraphSpacing{}, false},
+		{"valid", &ParagraphSpacing{LineSpacing: new(90.0), SpaceAbove: new(0.0), SpaceBelow: new(4.0)}, false},
+		{"zero line spacing", &ParagraphSpacing{LineSpacing: new(0.0)}, true},
+		{"negative space above", &ParagraphSpacing{SpaceAbove: new(-1.0)}, true},
+		{"negative space below", &ParagraphSpacing{SpaceBelow: new(-1.0)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateParagraphSpacing(tt.s); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParagraphSpacingMerge(t *testing.T) {
+	base := &ParagraphSpacing{LineSpacing: new(90.0), SpaceBelow: new(4.0)}
+	tests := []struct {
+		name string
+		s    *ParagraphSpacing
+		o    *ParagraphSpacing
+		want *ParagraphSpacing
+	}{
+		{"both nil", nil, nil, nil},
+		{"no override", base, nil, base},
+		{"override", base, &ParagraphSpacing{SpaceAbove: new(0.0), SpaceBelow: new(2.0)},
+			&ParagraphSpacing{LineSpacing: new(90.0), SpaceAbove: new(0.0), SpaceBelow: new(2.0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.s.Merge(tt.o)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParagraphSpacingRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *ParagraphSpacing
+		want *slides.Request
+	}{
+		{"nil", nil, nil},
+		{"empty", &ParagraphSpacing{}, nil},
+		{"all", &ParagraphSpacing{LineSpacing: new(90.0), SpaceAbove: new(0.0), SpaceBelow: new(4.0)}, &slides.Request{
+			UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+				ObjectId: "body",
+				Style: &slides.ParagraphStyle{
+					LineSpacing: 90,
+					SpaceAbove:  &slides.Dimension{Magnitude: 0, Unit: "PT"},
+					SpaceBelow:  &slides.Dimension{Magnitude: 4, Unit: "PT"},
+				},
+				Fields:    "lineSpacing,spaceAbove,spaceBelow",
+				TextRange: &slides.Range{Type: "ALL"},
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, paragraphSpacingRequest("body", tt.s)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestApplyParagraphSpacing(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	id := srv.CreatePresentation("test")
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := Slides{{
+		Layout:           "title-and-body",
+		Titles:           []string{"Spacing"},
+		Bodies:           toBodies([]string{"Dense"}),
+		ParagraphSpacing: &ParagraphSpacing{LineSpacing: new(80.0)},
+	}}
+	if err := d.Apply(ctx, ss); err != nil {
+		t.Fatal(err)
+	}
+	var got []float64
+	for _, e := range srv.Presentation(id).Slides[0].PageElements {
+		if e.Shape == nil || e.Shape.Placeholder == nil || e.Shape.Placeholder.Type != "BODY" || e.Shape.Text == nil {
+			continue
+		}
+		for _, te := range e.Shape.Text.TextElements {
+			if te.ParagraphMarker != nil && te.ParagraphMarker.Style != nil {
+				got = append(got, te.ParagraphMarker.Style.LineSpacing)
+			}
+		}
+	}
+	if diff := cmp.Diff([]float64{80}, got); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,144 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/deck/textmeasure"
+)
+
+// Stats represents metrics of slides to check the health of a deck.
+type Stats struct {
+	Pages  int           `json:"pages"`
+	Words  int           `json:"words"`
+	Slides []*SlideStats `json:"slides"`
+	// page with the longest speaker note, 0 if there are no speaker notes
+	LongestSpeakerNotePage int `json:"longest_speaker_note_page,omitempty"`
+}
+
+// SlideStats represents metrics of a slide.
+type SlideStats struct {
+	Page              int         `json:"page"` // 1-based page number
+	Title             string      `json:"title,omitempty"`
+	Words             int         `json:"words"`
+	Bullets           int         `json:"bullets"`
+	Images            int         `json:"images"`
+	ImagesWithoutAlt  int         `json:"images_without_alt"`
+	SpeakerNoteLength int         `json:"speaker_note_length"` // number of characters as the reader sees them, e.g. an emoji sequence is one
+	Tables            []TableSize `json:"tables,omitempty"`
+}
+
+// TableSize represents the size of a table.
+type TableSize struct {
+	Rows    int `json:"rows"`
+	Columns int `json:"columns"`
+}
+
+// NewStats returns metrics of the slides.
+func NewStats(ss Slides) *Stats {
+	s := &Stats{
+		Pages: len(ss),
+	}
+	longest := 0
+	for i, slide := range ss {
+		st := newSlideStats(i+1, slide)
+		s.Words += st.Words
+		if st.SpeakerNoteLength > longest {
+			longest = st.SpeakerNoteLength
+			s.LongestSpeakerNotePage = st.Page
+		}
+		s.Slides = append(s.Slides, st)
+	}
+	return s
+}
+
+func newSlideStats(page int, slide *Slide) *SlideStats {
+	st := &SlideStats{
+		Page:              page,
+		Title:             strings.Join(slide.Titles, " "),
+		Images:            len(slide.Images),
+		SpeakerNoteLength: textmeasure.GraphemeCount(slide.SpeakerNote),
+	}
+	for _, t := range slide.Titles {
+		st.Words += countWords(t)
+	}
+	for _, t := range slide.Subtitles {
+		st.Words += countWords(t)
+	}
+	for _, b := range slide.Bodies {
+		for _, p := range b.Paragraphs {
+			st.Words += countFragmentWords(p.Fragments)
+			if p.Bullet != BulletNone {
+				st.Bullets++
+			}
+		}
+	}
+	for _, bq := range slide.BlockQuotes {
+		for _, p := range bq.Paragraphs {
+			st.Words += countFragmentWords(p.Fragments)
+		}
+	}
+	for _, t := range slide.Tables {
+		size := TableSize{Rows: len(t.Rows)}
+		for _, r := range t.Rows {
+			size.Columns = max(size.Columns, len(r.Cells))
+			for _, c := range r.Cells {
+				st.Words += countFragmentWords(c.Fragments)
+			}
+		}
+		st.Tables = append(st.Tables, size)
+	}
+	for _, i := range slide.Images {
+		if i.alt == "" && i.title == "" {
+			st.ImagesWithoutAlt++
+		}
+	}
+	return st
+}
+
+func countFragmentWords(fragments []*Fragment) int {
+	var b strings.Builder
+	for _, f := range fragments {
+		if f == nil {
+			continue
+		}
+		b.WriteString(f.Value)
+	}
+	return countWords(b.String())
+}
+
+func countWords(s string) int {
+	n := 0
+	for range strings.FieldsSeq(s) {
+		n++
+	}
+	return n
+}
+
+// StatsLimits represents thresholds of the metrics. Zero values mean no limits.
+type StatsLimits struct {
+	MaxBullets           int
+	MaxWords             int
+	MaxSpeakerNoteLength int
+	RequireAlt           bool // images must have alternative text
+}
+
+// Check returns the violations of the limits in the order of pages.
+func (s *Stats) Check(l StatsLimits) []string {
+	var violations []string
+	for _, st := range s.Slides {
+		if l.MaxBullets > 0 && st.Bullets > l.MaxBullets {
+			violations = append(violations, fmt.Sprintf("page %d: %d bullets exceed the limit of %d", st.Page, st.Bullets, l.MaxBullets))
+		}
+		if l.MaxWords > 0 && st.Words > l.MaxWords {
+			violations = append(violations, fmt.Sprintf("page %d: %d words exceed the limit of %d", st.Page, st.Words, l.MaxWords))
+		}
+		if l.MaxSpeakerNoteLength > 0 && st.SpeakerNoteLength > l.MaxSpeakerNoteLength {
+			violations = append(violations, fmt.Sprintf("page %d: speaker note of %d characters exceeds the limit of %d", st.Page, st.SpeakerNoteLength, l.MaxSpeakerNoteLength))
+		}
+		if l.RequireAlt && st.ImagesWithoutAlt > 0 {
+			violations = append(violations, fmt.Sprintf("page %d: %d images without alt text", st.Page, st.ImagesWithoutAlt))
+		}
+	}
+	return violations
+}
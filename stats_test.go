@@ -0,0 +1,81 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewStats(t *testing.T) {
+	img := &Image{}
+	imgWithAlt := &Image{alt: "logo"}
+	ss := Slides{
+		NewSlide("title", "Hello world"),
+		{
+			Titles: []string{"Agenda"},
+			Bodies: []*Body{NewBody(
+				NewBulletParagraph(BulletDash, 0, "first item"),
+				NewBulletParagraph(BulletDash, 1, "second"),
+				NewParagraph("closing words here"),
+			)},
+			Images:      []*Image{img, imgWithAlt},
+			SpeakerNote: "note",
+		},
+		{
+			Titles: []string{"Table"},
+			Tables: []*Table{NewTable([][]string{{"a", "b", "c"}, {"d e", "f"}}, true)},
+			// An emoji sequence is counted as one character.
+			SpeakerNote: "👨‍👩‍👧 ok",
+		},
+	}
+	got := NewStats(ss)
+	want := &Stats{
+		Pages: 3,
+		Words: 16,
+		Slides: []*SlideStats{
+			{Page: 1, Title: "Hello world", Words: 2},
+			{Page: 2, Title: "Agenda", Words: 7, Bullets: 2, Images: 2, ImagesWithoutAlt: 1, SpeakerNoteLength: 4},
+			{Page: 3, Title: "Table", Words: 7, SpeakerNoteLength: 4, Tables: []TableSize{{Rows: 2, Columns: 3}}},
+		},
+		LongestSpeakerNotePage: 2,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestStatsCheck(t *testing.T) {
+	stats := &Stats{
+		Pages: 2,
+		Slides: []*SlideStats{
+			{Page: 1, Words: 10, Bullets: 9},
+			{Page: 2, Words: 100, Bullets: 3, ImagesWithoutAlt: 1, SpeakerNoteLength: 20},
+		},
+	}
+	tests := []struct {
+		name   string
+		limits StatsLimits
+		want   []string
+	}{
+		{"no limits", StatsLimits{}, nil},
+		{"max bullets", StatsLimits{MaxBullets: 8}, []string{"page 1: 9 bullets exceed the limit of 8"}},
+		{
+			"all",
+			StatsLimits{MaxBullets: 8, MaxWords: 50, MaxSpeakerNoteLength: 10, RequireAlt: true},
+			[]string{
+				"page 1: 9 bullets exceed the limit of 8",
+				"page 2: 100 words exceed the limit of 50",
+				"page 2: speaker note of 20 characters exceeds the limit of 10",
+				"page 2: 1 images without alt text",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stats.Check(tt.limits)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
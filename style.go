@@ -1,6 +1,8 @@
 package deck
 
 import (
+	"cmp"
+	"fmt"
 	"slices"
 	"sort"
 	"strings"
@@ -25,7 +27,11 @@ const (
 	styleVar              = "var"    // <var> variable tag
 	styleKbd              = "kbd"    // <kbd> keyboard input tag
 	styleSamp             = "samp"   // <samp> sample output tag
+	styleMark             = "mark"   // <mark> highlight tag
+	styleCite             = "cite"   // citation of block quotes, e.g. `> — Author`
 	defaultCodeFontFamily = "Noto Sans Mono"
+	// DefaultHighlightColor is the background color of highlighted text without the `mark` style.
+	DefaultHighlightColor = "#ffff00"
 )
 
 var (
@@ -53,6 +59,14 @@ var (
 			Fields: "fontFamily",
 		}
 	}
+	underlineStyleFunc = func() *slides.UpdateTextStyleRequest {
+		return &slides.UpdateTextStyleRequest{
+			Style: &slides.TextStyle{
+				Underline: true,
+			},
+			Fields: "underline",
+		}
+	}
 	strikethroughStyleFunc = func() *slides.UpdateTextStyleRequest {
 		return &slides.UpdateTextStyleRequest{
 			Style: &slides.TextStyle{
@@ -63,6 +77,33 @@ var (
 	}
 )
 
+// WithHighlightColor sets the background color of highlighted text (e.g. "#ffff00" or "yellow").
+// The `mark` style defined in the presentation takes precedence over it.
+func WithHighlightColor(color string) Option {
+	return func(d *Deck) error {
+		c, err := NormalizeColor(color)
+		if err != nil {
+			return fmt.Errorf("highlight color: %w", err)
+		}
+		d.highlightColor = c
+		return nil
+	}
+}
+
+// highlightStyleRequest returns a request to highlight text with the background color.
+func highlightStyleRequest(color string) *slides.UpdateTextStyleRequest {
+	return &slides.UpdateTextStyleRequest{
+		Style: &slides.TextStyle{
+			BackgroundColor: &slides.OptionalColor{
+				OpaqueColor: &slides.OpaqueColor{
+					RgbColor: rgbColor(cmp.Or(color, DefaultHighlightColor)),
+				},
+			},
+		},
+		Fields: "backgroundColor",
+	}
+}
+
 var defaultStyles = map[string]func() *slides.UpdateTextStyleRequest{
 	styleCode: func() *slides.UpdateTextStyleRequest {
 		return &slides.UpdateTextStyleRequest{
@@ -96,14 +137,7 @@ var defaultStyles = map[string]func() *slides.UpdateTextStyleRequest{
 	styleStrong: boldStyleFunc,
 	styleEm:     italicStyleFunc,
 	styleS:      strikethroughStyleFunc,
-	styleU: func() *slides.UpdateTextStyleRequest {
-		return &slides.UpdateTextStyleRequest{
-			Style: &slides.TextStyle{
-				Underline: true,
-			},
-			Fields: "underline",
-		}
-	},
+	styleU:      underlineStyleFunc,
 	styleSup: func() *slides.UpdateTextStyleRequest {
 		return &slides.UpdateTextStyleRequest{
 			Style: &slides.TextStyle{
@@ -125,77 +159,27 @@ var defaultStyles = map[string]func() *slides.UpdateTextStyleRequest{
 	styleSamp: monospaceStyleFunc,
 }
 
-func (d *Deck) getInlineStyleRequest(fragment *Fragment) *slides.UpdateTextStyleRequest {
-	var reqs []*slides.UpdateTextStyleRequest
-
-	if fragment.Code {
-		reqs = append(reqs, d.getRequestForStyle(styleCode))
-	}
-
-	if fragment.Bold {
-		reqs = append(reqs, d.getRequestForStyle(styleBold))
-	}
-
-	if fragment.Italic {
-		reqs = append(reqs, d.getRequestForStyle(styleItalic))
-	}
-
-	if fragment.Link != "" {
-		s, ok := d.styles[styleLink]
-		if ok {
-			req := buildCustomStyleRequest(s)
-			req.Fields = "link,bold,italic,underline,foregroundColor,fontFamily,backgroundColor"
-			req.Style.Link = &slides.Link{
-				Url: fragment.Link,
-			}
-			reqs = append(reqs, req)
-		} else {
-			reqs = append(reqs, &slides.UpdateTextStyleRequest{
+// citeFontSize is the font size of citations of block quotes in points, smaller than the 14pt of text boxes.
+const citeFontSize = 12
+
+// defaultParagraphStyles are the styles of paragraphs of the style names that are not defined in the presentation.
+// Unlike defaultStyles, they are not applied to inline text such as <cite>.
+var defaultParagraphStyles = map[string]struct {
+	text      func() *slides.UpdateTextStyleRequest
+	paragraph *slides.ParagraphStyle
+}{
+	styleCite: {
+		text: func() *slides.UpdateTextStyleRequest {
+			return &slides.UpdateTextStyleRequest{
 				Style: &slides.TextStyle{
-					Link: &slides.Link{
-						Url: fragment.Link,
-					},
+					Italic:   true,
+					FontSize: &slides.Dimension{Magnitude: citeFontSize, Unit: "PT"},
 				},
-				Fields: "link",
-			})
-		}
-	}
-
-	if fragment.StyleName != "" {
-		r := d.getRequestForStyle(fragment.StyleName)
-		if r != nil {
-			reqs = append(reqs, r)
-		}
-	}
-
-	if len(reqs) == 0 {
-		return nil
-	}
-
-	var (
-		fields string
-		style  *slides.TextStyle
-	)
-	for _, r := range reqs {
-		// Merge elements with the latter taking priority.
-		fields = mergeFields(fields, r.Fields)
-		style = mergeStyles(style, r.Style, r.Fields)
-	}
-
-	return &slides.UpdateTextStyleRequest{
-		Style:  style,
-		Fields: fields,
-	}
-}
-
-func (d *Deck) getRequestForStyle(styleName string) *slides.UpdateTextStyleRequest {
-	if s, ok := d.styles[styleName]; ok {
-		return buildCustomStyleRequest(s)
-	}
-	if f, ok := defaultStyles[styleName]; ok {
-		return f()
-	}
-	return nil
+				Fields: "italic,fontSize",
+			}
+		},
+		paragraph: &slides.ParagraphStyle{Alignment: "END"},
+	},
 }
 
 func buildCustomStyleRequest(s *slides.TextStyle) *slides.UpdateTextStyleRequest {
@@ -9,7 +9,28 @@ import (
 	"google.golang.org/api/slides/v1"
 )
 
-const descriptionTableFromMarkdown = "Table generated from markdown"
+const (
+	descriptionTableFromMarkdown = "Table generated from markdown"
+	titleTableWithoutHeader      = "Table without header row" // the title of the alt text of tables without a header row
+)
+
+// hasHeader reports whether the first row of the table is the header row.
+func (t *Table) hasHeader() bool {
+	return len(t.Rows) > 0 && len(t.Rows[0].Cells) > 0 && t.Rows[0].Cells[0].IsHeader
+}
+
+// tableTitle returns the title of the alt text of the table element created for t.
+func tableTitle(t *Table) string {
+	if t.hasHeader() {
+		return ""
+	}
+	return titleTableWithoutHeader
+}
+
+// isHeaderTableElement reports whether the first row of the table element is the header row.
+func isHeaderTableElement(element *slides.PageElement) bool {
+	return element.Title != titleTableWithoutHeader
+}
 
 func (d *Deck) handleTableUpdates(slideObjectID string, newTables []*Table, currentTables []*slides.PageElement) ([]*slides.Request, error) {
 	var requests []*slides.Request
@@ -25,7 +46,7 @@ func (d *Deck) handleTableUpdates(slideObjectID string, newTables []*Table, curr
 	// Convert existing deck-created tables to deck Tables for comparison
 	var existingTables []*Table
 	for _, element := range deckTables {
-		table := convertSlidesToTable(element.Table)
+		table := convertSlidesToTable(element.Table, isHeaderTableElement(element))
 		if table != nil {
 			existingTables = append(existingTables, table)
 		}
@@ -51,31 +72,40 @@ func (d *Deck) handleTableUpdates(slideObjectID string, newTables []*Table, curr
 
 	// Case 3: Tables are different, need to update
 
-	// Reuse existing deck-created tables where possible, adjusting their structure
+	// Reuse existing deck-created tables where possible, adjusting their structure.
+	// If the header row of a table is toggled, all tables are recreated since the styles of its first row
+	// cannot be reset, and the tables must stay in the order of creation.
+	reusable := min(len(deckTables), len(newTables))
+	for i := range reusable {
+		if isHeaderTableElement(deckTables[i]) != newTables[i].hasHeader() {
+			reusable = 0
+			break
+		}
+	}
 	maxTables := max(len(deckTables), len(newTables))
 
 	for i := range maxTables {
-		if i < len(deckTables) && i < len(newTables) {
+		if i < reusable {
 			// Reuse existing deck-created table: clear content and adjust structure
 			tableReqs, err := d.reuseTableRequests(deckTables[i], newTables[i])
 			if err != nil {
 				return nil, fmt.Errorf("failed to reuse table %d: %w", i, err)
 			}
 			requests = append(requests, tableReqs...)
-		} else if i < len(deckTables) {
+			continue
+		}
+		if i < len(deckTables) {
 			// Remove excess existing deck-created tables
 			requests = append(requests, &slides.Request{
 				DeleteObject: &slides.DeleteObjectRequest{
 					ObjectId: deckTables[i].ObjectId,
 				},
 			})
-		} else if i < len(newTables) {
+		}
+		if i < len(newTables) {
 			// Create new tables for additional ones needed
-			createReqs, err := d.createTableStructureRequest(slideObjectID, newTables[i], i)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create table structure request: %w", err)
-			}
-			requests = append(requests, createReqs...)
+			tableObjectID := fmt.Sprintf("table-%s", uuid.New().String())
+			requests = append(requests, d.requestBuilder().TableStructureRequests(slideObjectID, tableObjectID, newTables[i], i)...)
 		}
 	}
 
@@ -199,9 +229,43 @@ func (d *Deck) reuseTableRequests(existingElement *slides.PageElement, newTable
 		}
 	}
 
+	// Restore column widths that may have been adjusted manually,
+	// since structural changes redistribute the widths of the remaining columns.
+	if newCols != existingCols {
+		requests = append(requests, restoreColumnWidthRequests(tableObjectID, existingTable, newCols)...)
+	}
+
 	return requests, nil
 }
 
+// restoreColumnWidthRequests creates requests to restore the column widths captured from the existing table
+// for the columns that are kept after structural adjustments.
+func restoreColumnWidthRequests(tableObjectID string, existingTable *slides.Table, newCols int) []*slides.Request {
+	var requests []*slides.Request
+	for i, col := range existingTable.TableColumns {
+		if i >= newCols {
+			break
+		}
+		if col == nil || col.ColumnWidth == nil || col.ColumnWidth.Magnitude == 0 {
+			continue
+		}
+		requests = append(requests, &slides.Request{
+			UpdateTableColumnProperties: &slides.UpdateTableColumnPropertiesRequest{
+				ObjectId:      tableObjectID,
+				ColumnIndices: []int64{int64(i)},
+				TableColumnProperties: &slides.TableColumnProperties{
+					ColumnWidth: &slides.Dimension{
+						Magnitude: col.ColumnWidth.Magnitude,
+						Unit:      col.ColumnWidth.Unit,
+					},
+				},
+				Fields: "columnWidth",
+			},
+		})
+	}
+	return requests
+}
+
 // hasTableCellContent checks if a table cell has any text content.
 func hasTableCellContent(cell *slides.TableCell) bool {
 	if cell == nil || cell.Text == nil {
@@ -226,23 +290,14 @@ func (d *Deck) fillTableContentForActions(ctx context.Context, actions []*action
 
 	for _, action := range actions {
 		if (action.actionType == actionTypeAppend || action.actionType == actionTypeUpdate) && len(action.slide.Tables) > 0 {
-			var slideObjectID string
-			if action.actionType == actionTypeAppend {
-				// For appended slides, we need to find the correct slide
-				if len(d.presentation.Slides) > action.index {
-					slideObjectID = d.presentation.Slides[action.index].ObjectId
-				}
-			} else {
-				// For updated slides
-				if len(d.presentation.Slides) > action.index {
-					slideObjectID = d.presentation.Slides[action.index].ObjectId
-				}
-			}
+			// Pages may have been moved since the requests were prepared, so the page is looked up by its object ID.
+			// Pages not prepared yet have no object ID.
+			slideObjectID := action.objectID
 
 			if slideObjectID != "" {
 				// Only fill content for slides that actually have table changes
 				// This is determined by the handleTableUpdates logic
-				requests, err := d.collectTableContentRequests(slideObjectID, action.slide.Tables)
+				requests, err := d.collectTableContentRequests(slideObjectID, action.slide.Tables, action.slide.Direction)
 				if err != nil {
 					return fmt.Errorf("failed to collect table content requests for slide %d: %w", action.index, err)
 				}
@@ -261,74 +316,9 @@ func (d *Deck) fillTableContentForActions(ctx context.Context, actions []*action
 	return nil
 }
 
-// createTableStructureRequest creates only the table structure without content.
-func (d *Deck) createTableStructureRequest(slideObjectID string, table *Table, index int) ([]*slides.Request, error) {
-	if len(table.Rows) == 0 {
-		return nil, nil
-	}
-
-	tableObjectID := fmt.Sprintf("table-%s", uuid.New().String())
-
-	// Calculate the number of rows and columns
-	rows := int64(len(table.Rows))
-	cols := int64(0)
-	for _, row := range table.Rows {
-		if int64(len(row.Cells)) > cols {
-			cols = int64(len(row.Cells))
-		}
-	}
-
-	if rows == 0 || cols == 0 {
-		return nil, nil
-	}
-
-	// Create table request
-	createTableReq := &slides.CreateTableRequest{
-		ObjectId: tableObjectID,
-		ElementProperties: &slides.PageElementProperties{
-			PageObjectId: slideObjectID,
-			Size: &slides.Size{
-				Height: &slides.Dimension{
-					Magnitude: float64(rows * 100000), // 100,000 EMU per row
-					Unit:      "EMU",
-				},
-				Width: &slides.Dimension{
-					Magnitude: float64(cols * 1000000), // 1,000,000 EMU per column
-					Unit:      "EMU",
-				},
-			},
-			Transform: &slides.AffineTransform{
-				ScaleX:     1.0,
-				ScaleY:     1.0,
-				TranslateX: float64(index * 500000), // offset tables to avoid overlap
-				TranslateY: float64(index * 100000),
-				Unit:       "EMU",
-			},
-		},
-		Rows:    rows,
-		Columns: cols,
-	}
-
-	var requests []*slides.Request
-
-	// Create table
-	requests = append(requests, &slides.Request{
-		CreateTable: createTableReq,
-	})
-
-	// Set description to mark as markdown-generated table
-	requests = append(requests, &slides.Request{
-		UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
-			ObjectId:    tableObjectID,
-			Description: descriptionTableFromMarkdown,
-		},
-	})
-
-	return requests, nil
-}
-
 // collectTableContentRequests collects all table content requests for a slide.
-func (d *Deck) collectTableContentRequests(slideObjectID string, tables []*Table) ([]*slides.Request, error) {
+// direction is the text direction of the slide.
+func (d *Deck) collectTableContentRequests(slideObjectID string, tables []*Table, direction string) ([]*slides.Request, error) {
 	if len(tables) == 0 {
 		return nil, nil
 	}
@@ -378,365 +368,12 @@ func (d *Deck) collectTableContentRequests(slideObjectID string, tables []*Table
 			continue
 		}
 
-		tableReqs, err := d.createTableContentRequests(tableObjectID, table)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create table content requests for table %d: %w", i, err)
-		}
-		requests = append(requests, tableReqs...)
+		requests = append(requests, d.requestBuilder(withDirection(direction)).TableContentRequests(tableObjectID, table)...)
 	}
 
 	return requests, nil
 }
 
-// createTableContentRequests creates requests to fill table content.
-func (d *Deck) createTableContentRequests(tableObjectID string, table *Table) ([]*slides.Request, error) {
-	var requests []*slides.Request
-
-	// Fill table cells with content
-	for rowIdx, row := range table.Rows {
-		for colIdx, cell := range row.Cells {
-			// Create text from fragments
-			var text strings.Builder
-			for _, fragment := range cell.Fragments {
-				text.WriteString(fragment.Value)
-			}
-
-			if text.String() == "" {
-				continue
-			}
-
-			cellLocation := &slides.TableCellLocation{
-				RowIndex:    int64(rowIdx),
-				ColumnIndex: int64(colIdx),
-			}
-
-			// Insert text into cell
-			requests = append(requests, &slides.Request{
-				InsertText: &slides.InsertTextRequest{
-					ObjectId:       tableObjectID,
-					CellLocation:   cellLocation,
-					Text:           text.String(),
-					InsertionIndex: 0,
-				},
-			})
-
-			// Apply base text style from tableStyle (before fragment styles)
-			textLength := int64(countString(text.String()))
-			if cellStyle := d.tableStyle.cellStyle(rowIdx, colIdx); cellStyle != nil && cellStyle.TextStyle != nil && textLength > 0 {
-				req := buildTableCellTextStyleRequest(cellStyle.TextStyle)
-				if req != nil {
-					requests = append(requests, &slides.Request{
-						UpdateTextStyle: &slides.UpdateTextStyleRequest{
-							ObjectId:     tableObjectID,
-							CellLocation: cellLocation,
-							Style:        req.Style,
-							TextRange: &slides.Range{
-								Type:       "FIXED_RANGE",
-								StartIndex: new(int64),
-								EndIndex:   new(textLength),
-							},
-							Fields: req.Fields,
-						},
-					})
-				}
-			}
-
-			// Apply formatting if needed
-			if len(cell.Fragments) > 0 {
-				startIndex := int64(0)
-				for _, fragment := range cell.Fragments {
-					flen := countString(fragment.Value)
-					if flen == 0 {
-						continue
-					}
-					endIndex := startIndex + int64(flen)
-
-					if r := d.getInlineStyleRequest(fragment); r != nil {
-						requests = append(requests, &slides.Request{
-							UpdateTextStyle: &slides.UpdateTextStyleRequest{
-								ObjectId:     tableObjectID,
-								CellLocation: cellLocation,
-								Style:        r.Style,
-								TextRange: &slides.Range{
-									Type:       "FIXED_RANGE",
-									StartIndex: new(startIndex),
-									EndIndex:   new(endIndex),
-								},
-								Fields: r.Fields,
-							},
-						})
-					}
-					startIndex = endIndex
-				}
-			}
-
-			// Set text alignment if specified
-			if cell.Alignment != "" {
-				requests = append(requests, &slides.Request{
-					UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
-						ObjectId:     tableObjectID,
-						CellLocation: cellLocation,
-						Style: &slides.ParagraphStyle{
-							Alignment: cell.Alignment,
-						},
-						Fields: "alignment",
-						TextRange: &slides.Range{
-							Type: "ALL",
-						},
-					},
-				})
-			}
-		}
-	}
-
-	// Apply cell styles from tableStyle
-	requests = append(requests, d.applyTableCellStyles(tableObjectID, table)...)
-
-	// Apply border styles from tableStyle
-	requests = append(requests, d.applyTableBorderStyles(tableObjectID, table)...)
-
-	return requests, nil
-}
-
-// applyTableCellStyles applies cell styles from d.tableStyle.
-func (d *Deck) applyTableCellStyles(tableObjectID string, table *Table) []*slides.Request {
-	var requests []*slides.Request
-
-	rows := len(table.Rows)
-	if rows == 0 {
-		return nil
-	}
-
-	cols := 0
-	for _, row := range table.Rows {
-		if len(row.Cells) > cols {
-			cols = len(row.Cells)
-		}
-	}
-
-	if cols == 0 {
-		return nil
-	}
-
-	for rowIdx := range rows {
-		for colIdx := range cols {
-			cellStyle := d.tableStyle.cellStyle(rowIdx, colIdx)
-			if cellStyle == nil {
-				continue
-			}
-
-			tableRange := &slides.TableRange{
-				Location: &slides.TableCellLocation{
-					RowIndex:    int64(rowIdx),
-					ColumnIndex: int64(colIdx),
-				},
-				RowSpan:    1,
-				ColumnSpan: 1,
-			}
-
-			// Apply background color and content alignment
-			if cellStyle.BackgroundFill != nil || cellStyle.ContentAlignment != "" {
-				props := &slides.TableCellProperties{}
-				var fields []string
-
-				if cellStyle.BackgroundFill != nil {
-					props.TableCellBackgroundFill = cellStyle.BackgroundFill
-					fields = append(fields, "tableCellBackgroundFill")
-				}
-				if cellStyle.ContentAlignment != "" {
-					props.ContentAlignment = cellStyle.ContentAlignment
-					fields = append(fields, "contentAlignment")
-				}
-
-				requests = append(requests, &slides.Request{
-					UpdateTableCellProperties: &slides.UpdateTableCellPropertiesRequest{
-						ObjectId:            tableObjectID,
-						TableRange:          tableRange,
-						TableCellProperties: props,
-						Fields:              strings.Join(fields, ","),
-					},
-				})
-			}
-
-			// Apply paragraph style (horizontal alignment)
-			if cellStyle.ParagraphStyle != nil && cellStyle.ParagraphStyle.Alignment != "" {
-				requests = append(requests, &slides.Request{
-					UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
-						ObjectId: tableObjectID,
-						CellLocation: &slides.TableCellLocation{
-							RowIndex:    int64(rowIdx),
-							ColumnIndex: int64(colIdx),
-						},
-						Style: &slides.ParagraphStyle{
-							Alignment: cellStyle.ParagraphStyle.Alignment,
-						},
-						Fields: "alignment",
-						TextRange: &slides.Range{
-							Type: "ALL",
-						},
-					},
-				})
-			}
-		}
-	}
-
-	return requests
-}
-
-// applyTableBorderStyles applies border styles from d.tableStyle.BorderStyle.
-func (d *Deck) applyTableBorderStyles(tableObjectID string, table *Table) []*slides.Request {
-	if d.tableStyle == nil || d.tableStyle.BorderStyle == nil {
-		return nil
-	}
-
-	var requests []*slides.Request
-	bs := d.tableStyle.BorderStyle
-
-	rows := len(table.Rows)
-	if rows == 0 {
-		return nil
-	}
-
-	cols := 0
-	for _, row := range table.Rows {
-		if len(row.Cells) > cols {
-			cols = len(row.Cells)
-		}
-	}
-
-	if cols == 0 {
-		return nil
-	}
-
-	// Apply outer borders (top/bottom from OuterHorizontal, left/right from OuterVertical)
-	if bs.OuterHorizontal != nil {
-		outerH := prepareBorderProperties(bs.OuterHorizontal)
-		// Top border of entire table
-		requests = append(requests, &slides.Request{
-			UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
-				ObjectId:              tableObjectID,
-				BorderPosition:        "TOP",
-				TableBorderProperties: outerH,
-				Fields:                buildBorderFields(outerH),
-			},
-		})
-		// Bottom border of entire table
-		requests = append(requests, &slides.Request{
-			UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
-				ObjectId:              tableObjectID,
-				BorderPosition:        "BOTTOM",
-				TableBorderProperties: outerH,
-				Fields:                buildBorderFields(outerH),
-			},
-		})
-	}
-
-	if bs.OuterVertical != nil {
-		outerV := prepareBorderProperties(bs.OuterVertical)
-		// Left border of entire table
-		requests = append(requests, &slides.Request{
-			UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
-				ObjectId:              tableObjectID,
-				BorderPosition:        "LEFT",
-				TableBorderProperties: outerV,
-				Fields:                buildBorderFields(outerV),
-			},
-		})
-		// Right border of entire table
-		requests = append(requests, &slides.Request{
-			UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
-				ObjectId:              tableObjectID,
-				BorderPosition:        "RIGHT",
-				TableBorderProperties: outerV,
-				Fields:                buildBorderFields(outerV),
-			},
-		})
-	}
-
-	// Apply inner borders per cell based on position
-	for rowIdx := range rows {
-		for colIdx := range cols {
-			isHeaderRow := rowIdx == 0
-			isFirstCol := colIdx == 0
-			isLastRow := rowIdx == rows-1
-			isLastCol := colIdx == cols-1
-
-			tableRange := &slides.TableRange{
-				Location: &slides.TableCellLocation{
-					RowIndex:    int64(rowIdx),
-					ColumnIndex: int64(colIdx),
-				},
-				RowSpan:    1,
-				ColumnSpan: 1,
-			}
-
-			// Apply right border (skip outer right border)
-			if !isLastCol {
-				var srcProps *slides.TableBorderProperties
-				if isHeaderRow {
-					if isFirstCol {
-						srcProps = bs.HeaderFirstColRight
-					} else {
-						srcProps = bs.HeaderOtherColRight
-					}
-				} else {
-					if isFirstCol {
-						srcProps = bs.DataFirstColRight
-					} else {
-						srcProps = bs.DataOtherColRight
-					}
-				}
-
-				if srcProps != nil {
-					borderProps := prepareBorderProperties(srcProps)
-					requests = append(requests, &slides.Request{
-						UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
-							ObjectId:              tableObjectID,
-							TableRange:            tableRange,
-							BorderPosition:        "RIGHT",
-							TableBorderProperties: borderProps,
-							Fields:                buildBorderFields(borderProps),
-						},
-					})
-				}
-			}
-
-			// Apply bottom border (skip outer bottom border)
-			if !isLastRow {
-				var srcProps *slides.TableBorderProperties
-				if isHeaderRow {
-					if isFirstCol {
-						srcProps = bs.HeaderFirstColBottom
-					} else {
-						srcProps = bs.HeaderOtherColBottom
-					}
-				} else {
-					if isFirstCol {
-						srcProps = bs.DataFirstColBottom
-					} else {
-						srcProps = bs.DataOtherColBottom
-					}
-				}
-
-				if srcProps != nil {
-					borderProps := prepareBorderProperties(srcProps)
-					requests = append(requests, &slides.Request{
-						UpdateTableBorderProperties: &slides.UpdateTableBorderPropertiesRequest{
-							ObjectId:              tableObjectID,
-							TableRange:            tableRange,
-							BorderPosition:        "BOTTOM",
-							TableBorderProperties: borderProps,
-							Fields:                buildBorderFields(borderProps),
-						},
-					})
-				}
-			}
-		}
-	}
-
-	return requests
-}
-
 // buildBorderFields builds the fields string for UpdateTableBorderPropertiesRequest.
 func buildBorderFields(props *slides.TableBorderProperties) string {
 	if props == nil {
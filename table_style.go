@@ -64,6 +64,14 @@ func (ts *TableStyle) cellStyle(rowIdx, colIdx int) *TableCellStyle {
 	return ts.DataOtherCols
 }
 
+// tableCellStyle returns the cell style of the cell of the table. All rows are data rows if the table has no header row.
+func (ts *TableStyle) tableCellStyle(t *Table, rowIdx, colIdx int) *TableCellStyle {
+	if !t.hasHeader() {
+		return ts.cellStyle(rowIdx+1, colIdx)
+	}
+	return ts.cellStyle(rowIdx, colIdx)
+}
+
 // defaultTableStyle returns the default table style (current hardcoded behavior).
 func defaultTableStyle() *TableStyle {
 	// Existing hardcoded header background color RGB(0.95, 0.95, 0.95)
@@ -714,3 +714,45 @@ func TestBuildBorderFields(t *testing.T) {
 		}
 	})
 }
+
+func TestTableCellStyleRequestsVerticalAlignment(t *testing.T) {
+	t.Parallel()
+	b := newRequestBuilder(withTableStyle(&TableStyle{
+		HeaderFirstCol: &TableCellStyle{ContentAlignment: "TOP"},
+		DataFirstCol:   &TableCellStyle{ContentAlignment: "TOP"},
+	}))
+	table := &Table{Rows: []*TableRow{
+		{Cells: []*TableCell{{IsHeader: true}}},
+		{Cells: []*TableCell{{VerticalAlignment: "MIDDLE"}}},
+	}}
+	var got []string
+	for _, r := range b.tableCellStyleRequests("table", table) {
+		if r.UpdateTableCellProperties != nil {
+			got = append(got, r.UpdateTableCellProperties.TableCellProperties.ContentAlignment)
+		}
+	}
+	if diff := cmp.Diff([]string{"TOP", "MIDDLE"}, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestTableCellStyleWithoutHeader(t *testing.T) {
+	t.Parallel()
+	ts := defaultTableStyle()
+	tests := []struct {
+		name  string
+		table *Table
+		want  *TableCellStyle
+	}{
+		{"header row", NewTable([][]string{{"a"}, {"1"}}, true), ts.HeaderFirstCol},
+		{"without header row", NewTable([][]string{{"a"}, {"1"}}, false), ts.DataFirstCol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ts.tableCellStyle(tt.table, 0, 0); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
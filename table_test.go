@@ -0,0 +1,73 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestReuseTableRequestsRestoresColumnWidths(t *testing.T) {
+	existing := &slides.PageElement{
+		ObjectId: "table1",
+		Table: &slides.Table{
+			TableColumns: []*slides.TableColumnProperties{
+				{ColumnWidth: &slides.Dimension{Magnitude: 3000000, Unit: "EMU"}},
+				{ColumnWidth: &slides.Dimension{Magnitude: 1000000, Unit: "EMU"}},
+				{ColumnWidth: &slides.Dimension{Magnitude: 2000000, Unit: "EMU"}},
+			},
+			TableRows: []*slides.TableRow{
+				{TableCells: []*slides.TableCell{{}, {}, {}}},
+				{TableCells: []*slides.TableCell{{}, {}, {}}},
+			},
+		},
+	}
+	tableWithCols := func(cols int) *Table {
+		var cells []*TableCell
+		for range cols {
+			cells = append(cells, &TableCell{Fragments: []*Fragment{{Value: "a"}}})
+		}
+		return &Table{Rows: []*TableRow{{Cells: cells}, {Cells: cells}}}
+	}
+	widthRequests := func(reqs []*slides.Request) []*slides.UpdateTableColumnPropertiesRequest {
+		var got []*slides.UpdateTableColumnPropertiesRequest
+		for _, r := range reqs {
+			if r.UpdateTableColumnProperties != nil {
+				got = append(got, r.UpdateTableColumnProperties)
+			}
+		}
+		return got
+	}
+	widthReq := func(idx int64, w float64) *slides.UpdateTableColumnPropertiesRequest {
+		return &slides.UpdateTableColumnPropertiesRequest{
+			ObjectId:      "table1",
+			ColumnIndices: []int64{idx},
+			TableColumnProperties: &slides.TableColumnProperties{
+				ColumnWidth: &slides.Dimension{Magnitude: w, Unit: "EMU"},
+			},
+			Fields: "columnWidth",
+		}
+	}
+
+	tests := []struct {
+		name string
+		cols int
+		want []*slides.UpdateTableColumnPropertiesRequest
+	}{
+		{"same columns", 3, nil},
+		{"fewer columns", 2, []*slides.UpdateTableColumnPropertiesRequest{widthReq(0, 3000000), widthReq(1, 1000000)}},
+		{"more columns", 4, []*slides.UpdateTableColumnPropertiesRequest{widthReq(0, 3000000), widthReq(1, 1000000), widthReq(2, 2000000)}},
+	}
+	d := &Deck{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, err := d.reuseTableRequests(existing, tableWithCols(tt.cols))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, widthRequests(reqs)); diff != "" {
+				t.Errorf("column width requests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Package textmeasure measures text in the units deck needs: UTF-16 code units for the indexes of the
+// Google Slides API, grapheme clusters for the number of characters a reader sees, and columns for
+// estimating how much space text takes when it wraps.
+package textmeasure
+
+import (
+	"iter"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+const (
+	zwj = '\u200d' // zero width joiner, which joins emoji into a sequence such as 👨‍👩‍👧
+	cr  = '\r'
+	lf  = '\n'
+)
+
+// UTF16Len returns the length of s in UTF-16 code units, which is the unit of the indexes of the
+// Google Slides API. Characters outside the BMP such as most emoji are counted as two.
+func UTF16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// Graphemes returns the grapheme clusters of s, i.e. the characters as the reader sees them.
+// Combining marks, variation selectors, emoji modifiers, emoji tag sequences and sequences
+// joined by ZWJ are kept with their base characters, and regional indicators are paired into flags.
+// It is a simplification of the extended grapheme clusters of UAX #29 that is enough for text in slides.
+func Graphemes(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for s != "" {
+			n := clusterLen(s)
+			if !yield(s[:n]) {
+				return
+			}
+			s = s[n:]
+		}
+	}
+}
+
+// clusterLen returns the length in bytes of the first grapheme cluster of s.
+func clusterLen(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == cr && len(s) > n && s[n] == lf {
+		return n + 1
+	}
+	if isControl(r) {
+		return n
+	}
+	ri := isRegionalIndicator(r)
+	for n < len(s) {
+		next, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case ri && isRegionalIndicator(next):
+			// A flag is a pair of regional indicators.
+			ri = false
+		case isExtend(next):
+			ri = false
+		case r == zwj && !isControl(next):
+			ri = false
+		default:
+			return n
+		}
+		r = next
+		n += size
+	}
+	return n
+}
+
+// GraphemeCount returns the number of grapheme clusters of s.
+func GraphemeCount(s string) int {
+	n := 0
+	for range Graphemes(s) {
+		n++
+	}
+	return n
+}
+
+// Width returns the number of columns s takes in a monospace font. Wide characters such as CJK and
+// emoji take two columns, and combining marks and joined emoji do not add to the width of their base.
+// Line breaks and other control characters take no columns.
+func Width(s string) int {
+	w := 0
+	for g := range Graphemes(s) {
+		w += GraphemeWidth(g)
+	}
+	return w
+}
+
+// GraphemeWidth returns the number of columns the grapheme cluster g takes in a monospace font.
+func GraphemeWidth(g string) int {
+	r, n := utf8.DecodeRuneInString(g)
+	switch {
+	case g == "" || isControl(r):
+		return 0
+	case isRegionalIndicator(r) || isEmojiPresentation(g[n:]):
+		return 2
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	if isExtend(r) || r == zwj {
+		// A combining mark without a base character.
+		return 0
+	}
+	return 1
+}
+
+// Normalize returns s in NFC, so that text with precomposed characters and text with combining marks,
+// e.g. "é" typed on different platforms, are the same string.
+func Normalize(s string) string {
+	return norm.NFC.String(s)
+}
+
+// Equal reports whether a and b are the same text, regardless of the normalization form.
+func Equal(a, b string) bool {
+	if a == b {
+		return true
+	}
+	return Normalize(a) == Normalize(b)
+}
+
+func isControl(r rune) bool {
+	return unicode.Is(unicode.Cc, r) || r == '\u2028' || r == '\u2029' // line and paragraph separators
+}
+
+// isExtend reports whether r extends the preceding character rather than starting a new one.
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == zwj ||
+		(r >= 0xFE00 && r <= 0xFE0F) || // variation selectors
+		(r >= 0xE0100 && r <= 0xE01EF) || // variation selectors supplement
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // emoji modifiers (skin tones)
+		(r >= 0xE0020 && r <= 0xE007F) || // tags of emoji tag sequences such as the flag of Scotland
+		(r >= 0x1160 && r <= 0x11FF) || (r >= 0xD7B0 && r <= 0xD7FF) // vowels and final consonants of decomposed Hangul
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isEmojiPresentation reports whether the rest of a cluster after its base character requests
+// the emoji presentation, e.g. "❤️", which is rendered as wide as other emoji.
+func isEmojiPresentation(rest string) bool {
+	for _, r := range rest {
+		if r == '\uFE0F' || r == zwj || (r >= 0x1F3FB && r <= 0x1F3FF) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package textmeasure
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name          string
+		in            string
+		wantUTF16     int
+		wantGraphemes []string
+		wantWidth     int
+	}{
+		{"empty", "", 0, nil, 0},
+		{"ascii", "deck", 4, []string{"d", "e", "c", "k"}, 4},
+		{"precomposed accent", "café", 4, []string{"c", "a", "f", "é"}, 4},
+		{"combining accent", "cafe\u0301", 5, []string{"c", "a", "f", "e\u0301"}, 4},
+		{"stacked combining marks", "a\u0323\u0302b", 4, []string{"a\u0323\u0302", "b"}, 2},
+		{"combining mark without base", "\u0301a", 2, []string{"\u0301", "a"}, 1},
+		{"CJK", "日本語", 3, []string{"日", "本", "語"}, 6},
+		{"kana and fullwidth", "カナＡ", 3, []string{"カ", "ナ", "Ａ"}, 6},
+		{"halfwidth kana", "ｶﾅ", 2, []string{"ｶ", "ﾅ"}, 2},
+		{"hangul", "한글", 2, []string{"한", "글"}, 4},
+		{"decomposed hangul", "\u1112\u1161\u11ab", 3, []string{"\u1112\u1161\u11ab"}, 2},
+		{"CJK outside BMP", "𠮷野家", 4, []string{"𠮷", "野", "家"}, 6},
+		{"emoji", "👍", 2, []string{"👍"}, 2},
+		{"emoji with skin tone", "👍🏽", 4, []string{"👍🏽"}, 2},
+		{"ZWJ sequence", "👨‍👩‍👧‍👦", 11, []string{"👨‍👩‍👧‍👦"}, 2},
+		{"ZWJ sequence with variation selector", "🏳️‍🌈", 6, []string{"🏳️‍🌈"}, 2},
+		{"emoji presentation", "❤️", 2, []string{"❤️"}, 2},
+		{"text presentation", "❤", 1, []string{"❤"}, 1},
+		{"keycap", "1️⃣", 3, []string{"1️⃣"}, 2},
+		{"flags", "🇯🇵🇺🇸", 8, []string{"🇯🇵", "🇺🇸"}, 4},
+		{"odd regional indicators", "🇯🇵🇺", 6, []string{"🇯🇵", "🇺"}, 4},
+		{"emoji tag sequence", "🏴󠁧󠁢󠁳󠁣󠁴󠁿", 14, []string{"🏴󠁧󠁢󠁳󠁣󠁴󠁿"}, 2},
+		{"mixed", "Hi 👋🏻 日本", 10, []string{"H", "i", " ", "👋🏻", " ", "日", "本"}, 10},
+		{"line breaks", "a\r\nb\nc", 6, []string{"a", "\r\n", "b", "\n", "c"}, 3},
+		{"ZWJ before line break", "👨‍\nb", 5, []string{"👨‍", "\n", "b"}, 3},
+		{"vertical tab", "a\vb", 3, []string{"a", "\v", "b"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UTF16Len(tt.in); got != tt.wantUTF16 {
+				t.Errorf("UTF16Len: got %d, want %d", got, tt.wantUTF16)
+			}
+			if diff := cmp.Diff(tt.wantGraphemes, slices.Collect(Graphemes(tt.in))); diff != "" {
+				t.Errorf("Graphemes: %s", diff)
+			}
+			if got := GraphemeCount(tt.in); got != len(tt.wantGraphemes) {
+				t.Errorf("GraphemeCount: got %d, want %d", got, len(tt.wantGraphemes))
+			}
+			if got := Width(tt.in); got != tt.wantWidth {
+				t.Errorf("Width: got %d, want %d", got, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestGraphemesBreak(t *testing.T) {
+	var got []string
+	for g := range Graphemes("a👨‍👩‍👧b") {
+		got = append(got, g)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if diff := cmp.Diff([]string{"a", "👨‍👩‍👧"}, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"café", "café", true},
+		{"café", "cafe\u0301", true},
+		{"가", "\u1100\u1161", true},
+		{"Å", "A\u030a", true},
+		{"cafe", "café", false},
+		{"👨\u200d👩", "👨👩", false},
+	}
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.want {
+			t.Errorf("Equal(%q, %q): got %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
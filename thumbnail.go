@@ -0,0 +1,111 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/k1LoW/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// ThumbnailSize represents the size of a page thumbnail.
+type ThumbnailSize string
+
+// ThumbnailSize constants. The width of the thumbnail is 200px, 800px and 1600px respectively.
+const (
+	ThumbnailSizeSmall  ThumbnailSize = "SMALL"
+	ThumbnailSizeMedium ThumbnailSize = "MEDIUM"
+	ThumbnailSizeLarge  ThumbnailSize = "LARGE"
+)
+
+// Thumbnail represents a PNG thumbnail of a page.
+type Thumbnail struct {
+	Page       int    // 1-based page number
+	ObjectID   string // Object ID of the page
+	Width      int64
+	Height     int64
+	ContentURL string // URL of the PNG image, valid for 30 minutes
+}
+
+// RevisionID returns the revision ID of the presentation.
+// The revision ID changes whenever the presentation is modified.
+func (d *Deck) RevisionID() string {
+	if d.presentation == nil {
+		return ""
+	}
+	return d.presentation.RevisionId
+}
+
+// PageObjectID returns the object ID of the page (1-based).
+func (d *Deck) PageObjectID(page int) (string, error) {
+	if d.presentation == nil || page < 1 || page > len(d.presentation.Slides) {
+		return "", fmt.Errorf("invalid page number: %d", page)
+	}
+	return d.presentation.Slides[page-1].ObjectId, nil
+}
+
+// Thumbnails returns the thumbnails of the pages (1-based) in the order of pages.
+func (d *Deck) Thumbnails(ctx context.Context, pages []int, size ThumbnailSize) (_ []*Thumbnail, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if size == "" {
+		size = ThumbnailSizeLarge
+	}
+	thumbnails := make([]*Thumbnail, len(pages))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(d.concurrency)
+	for i, page := range pages {
+		objectID, err := d.PageObjectID(page)
+		if err != nil {
+			return nil, err
+		}
+		eg.Go(func() error {
+			res, err := d.srv.Presentations.Pages.GetThumbnail(d.id, objectID).
+				ThumbnailPropertiesMimeType("PNG").
+				ThumbnailPropertiesThumbnailSize(string(size)).
+				Context(ctx).Do()
+			if err != nil {
+				return fmt.Errorf("failed to get thumbnail of page %d: %w", page, err)
+			}
+			thumbnails[i] = &Thumbnail{
+				Page:       page,
+				ObjectID:   objectID,
+				Width:      res.Width,
+				Height:     res.Height,
+				ContentURL: res.ContentUrl,
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return thumbnails, nil
+}
+
+// DownloadThumbnail writes the PNG image of the thumbnail to w.
+func (d *Deck) DownloadThumbnail(ctx context.Context, t *Thumbnail, w io.Writer) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.ContentURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	res, err := http.DefaultClient.Do(req) //nolint:gosec // The URL is returned by the Google Slides API.
+	if err != nil {
+		return fmt.Errorf("failed to download thumbnail of page %d: %w", t.Page, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download thumbnail of page %d: status code %d", t.Page, res.StatusCode)
+	}
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return fmt.Errorf("failed to write thumbnail of page %d: %w", t.Page, err)
+	}
+	return nil
+}
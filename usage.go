@@ -0,0 +1,137 @@
+package deck
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Calls to the Google Slides and Google Drive APIs are counted by the transport of the HTTP client, so that
+// heavy users can see how close an apply gets to the quotas (e.g. 60 write requests per minute per user of
+// the Slides API) and tune batching before hitting them.
+
+// APIUsage is the number of calls to the Google Slides and Google Drive APIs.
+type APIUsage struct {
+	SlidesReads   int `json:"slides_reads"`   // calls to get presentations, pages and thumbnails
+	SlidesWrites  int `json:"slides_writes"`  // calls to create and batch update presentations
+	BatchRequests int `json:"batch_requests"` // requests sent in the batch updates
+	DriveReads    int `json:"drive_reads"`    // calls to get, list, download and export files
+	DriveWrites   int `json:"drive_writes"`   // calls to create, upload, update, copy and delete files and permissions
+}
+
+// String returns a human-readable summary of the usage.
+func (u APIUsage) String() string {
+	return fmt.Sprintf("Slides API: %d reads, %d writes (%d batch requests), Drive API: %d reads, %d writes",
+		u.SlidesReads, u.SlidesWrites, u.BatchRequests, u.DriveReads, u.DriveWrites)
+}
+
+// sub returns the usage from o to u.
+func (u APIUsage) sub(o APIUsage) APIUsage {
+	return APIUsage{
+		SlidesReads:   u.SlidesReads - o.SlidesReads,
+		SlidesWrites:  u.SlidesWrites - o.SlidesWrites,
+		BatchRequests: u.BatchRequests - o.BatchRequests,
+		DriveReads:    u.DriveReads - o.DriveReads,
+		DriveWrites:   u.DriveWrites - o.DriveWrites,
+	}
+}
+
+// APIUsageFunc is called with the API usage of each apply.
+type APIUsageFunc func(APIUsage)
+
+// WithAPIUsageFunc calls fn at the end of each apply, including failed ones, with the number of calls
+// to the Google Slides and Google Drive APIs made by the apply.
+func WithAPIUsageFunc(fn APIUsageFunc) Option {
+	return func(d *Deck) error {
+		d.usageFunc = fn
+		return nil
+	}
+}
+
+// APIUsage returns the number of calls to the Google Slides and Google Drive APIs made by the last apply.
+func (d *Deck) APIUsage() APIUsage {
+	return d.lastUsage
+}
+
+// TotalAPIUsage returns the number of calls to the Google Slides and Google Drive APIs made since the Deck was created.
+func (d *Deck) TotalAPIUsage() APIUsage {
+	return d.usage.get()
+}
+
+// startUsage starts counting the API usage of an apply, and returns the function to end it.
+func (d *Deck) startUsage() func() {
+	start := d.usage.get()
+	return func() {
+		d.lastUsage = d.usage.get().sub(start)
+		d.logger.Info("API usage",
+			slog.Int("slides_reads", d.lastUsage.SlidesReads),
+			slog.Int("slides_writes", d.lastUsage.SlidesWrites),
+			slog.Int("batch_requests", d.lastUsage.BatchRequests),
+			slog.Int("drive_reads", d.lastUsage.DriveReads),
+			slog.Int("drive_writes", d.lastUsage.DriveWrites),
+		)
+		if d.usageFunc != nil {
+			d.usageFunc(d.lastUsage)
+		}
+	}
+}
+
+// usageCounter counts the API calls. It is safe for concurrent use, since images are uploaded concurrently.
+type usageCounter struct {
+	mu    sync.Mutex
+	usage APIUsage
+}
+
+func (c *usageCounter) get() APIUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+func (c *usageCounter) addBatchRequests(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage.BatchRequests += n
+}
+
+// count counts the API call of the request.
+func (c *usageCounter) count(r *http.Request) {
+	read := r.Method == http.MethodGet || r.Method == http.MethodHead
+	drive := strings.Contains(r.URL.Path, "/drive/")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case drive && read:
+		c.usage.DriveReads++
+	case drive:
+		c.usage.DriveWrites++
+	case read:
+		c.usage.SlidesReads++
+	default:
+		c.usage.SlidesWrites++
+	}
+}
+
+// usageTransport is the transport counting the API calls.
+type usageTransport struct {
+	base    http.RoundTripper
+	counter *usageCounter
+}
+
+func (t *usageTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.counter.count(r)
+	return t.base.RoundTrip(r)
+}
+
+// countingClient returns a copy of client counting the API calls with counter.
+func countingClient(client *http.Client, counter *usageCounter) *http.Client {
+	c := *client
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.Transport = &usageTransport{base: base, counter: counter}
+	return &c
+}
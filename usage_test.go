@@ -0,0 +1,71 @@
+package deck
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+)
+
+func TestUsageCounterCount(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   APIUsage
+	}{
+		{http.MethodGet, "https://slides.googleapis.com/v1/presentations/xxx", APIUsage{SlidesReads: 1}},
+		{http.MethodPost, "https://slides.googleapis.com/v1/presentations/xxx:batchUpdate", APIUsage{SlidesWrites: 1}},
+		{http.MethodGet, "https://www.googleapis.com/drive/v3/files/xxx", APIUsage{DriveReads: 1}},
+		{http.MethodPost, "https://www.googleapis.com/upload/drive/v3/files", APIUsage{DriveWrites: 1}},
+		{http.MethodDelete, "https://www.googleapis.com/drive/v3/files/xxx", APIUsage{DriveWrites: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			r, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var c usageCounter
+			c.count(r)
+			if diff := cmp.Diff(tt.want, c.get()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestApplyAPIUsage(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	var got []APIUsage
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")), WithAPIUsageFunc(func(u APIUsage) {
+		got = append(got, u)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := Slides{{Layout: "title-and-body", Titles: []string{"Usage"}, TitleBodies: toBodies([]string{"Usage"})}}
+	for range 2 {
+		if err := d.Apply(ctx, ss); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d calls of the usage func, want 2", len(got))
+	}
+	if u := got[0]; u.SlidesReads == 0 || u.SlidesWrites == 0 || u.BatchRequests < u.SlidesWrites {
+		t.Errorf("unexpected usage of the first apply: %+v", u)
+	}
+	if u := got[1]; u.SlidesWrites != 0 || u.BatchRequests != 0 {
+		t.Errorf("the apply without changes should not write: %+v", u)
+	}
+	if diff := cmp.Diff(got[1], d.APIUsage()); diff != "" {
+		t.Error(diff)
+	}
+	if total := d.TotalAPIUsage(); total.SlidesReads < got[0].SlidesReads+got[1].SlidesReads {
+		t.Errorf("total usage %+v is less than the usage of the applies", total)
+	}
+}
@@ -0,0 +1,153 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// Placeholders represents the number of placeholders of a layout.
+type Placeholders struct {
+	Titles    int `json:"titles"`    // TITLE and CENTERED_TITLE placeholders
+	Subtitles int `json:"subtitles"` // SUBTITLE placeholders
+	Bodies    int `json:"bodies"`    // BODY placeholders
+	Images    int `json:"images"`    // picture placeholders
+}
+
+// ValidationIssue represents a problem of a page found by Validate.
+type ValidationIssue struct {
+	Page    int    `json:"page"`
+	Error   bool   `json:"error,omitempty"` // the page cannot be applied, otherwise it is a warning
+	Message string `json:"message"`
+}
+
+// String returns a human-readable description of the issue.
+func (i *ValidationIssue) String() string {
+	if i.Error {
+		return fmt.Sprintf("page %d: error: %s", i.Page, i.Message)
+	}
+	return fmt.Sprintf("page %d: warning: %s", i.Page, i.Message)
+}
+
+// Validate cross-checks each slide against the placeholders of its layout, in the order of pages.
+// It reports an error for a layout not in layouts, and a warning for content that is dropped or
+// placed outside the placeholders, such as bodies more than the body placeholders of the layout.
+// Slides without a layout and frozen slides are not checked.
+func (ss Slides) Validate(layouts map[string]Placeholders) []*ValidationIssue {
+	var issues []*ValidationIssue
+	for i, s := range ss {
+		if s == nil || s.Layout == "" || s.Freeze {
+			continue
+		}
+		page := i + 1
+		p, ok := layouts[s.Layout]
+		if !ok {
+			issues = append(issues, &ValidationIssue{Page: page, Error: true, Message: fmt.Sprintf("layout not found: %q", s.Layout)})
+			continue
+		}
+		warn := func(n, capacity int, content, placeholder, consequence string) {
+			if n <= capacity {
+				return
+			}
+			issues = append(issues, &ValidationIssue{
+				Page: page,
+				Message: fmt.Sprintf("%d %s but the layout %q has %d %s placeholders, %s",
+					n, content, s.Layout, capacity, placeholder, consequence),
+			})
+		}
+		warn(max(len(s.Titles), len(s.TitleBodies)), p.Titles, "titles", "title", "extra titles are not rendered")
+		warn(max(len(s.Subtitles), len(s.SubtitleBodies)), p.Subtitles, "subtitles", "subtitle", "extra subtitles are not rendered")
+		warn(countBodies(s.Bodies), p.Bodies, "bodies", "body", "extra bodies are not rendered")
+		warn(len(s.Images), p.Images, "images", "picture", "extra images are placed at the top left of the page")
+	}
+	return issues
+}
+
+// countBodies returns the number of bodies up to the last one with paragraphs.
+// Empty bodies before it still take their placeholders.
+func countBodies(bodies []*Body) int {
+	n := 0
+	for i, b := range bodies {
+		if b != nil && len(b.Paragraphs) > 0 {
+			n = i + 1
+		}
+	}
+	return n
+}
+
+// LayoutPlaceholders returns the number of placeholders per layout name of the presentation.
+func (d *Deck) LayoutPlaceholders() map[string]Placeholders {
+	m := map[string]Placeholders{}
+	for name, l := range d.layoutMap() {
+		m[name] = countPlaceholders(l)
+	}
+	return m
+}
+
+// countPlaceholders counts the placeholders of the layout.
+func countPlaceholders(l *slides.Page) Placeholders {
+	var p Placeholders
+	for _, e := range l.PageElements {
+		switch {
+		case e.Shape != nil && e.Shape.Placeholder != nil:
+			switch e.Shape.Placeholder.Type {
+			case "CENTERED_TITLE", "TITLE":
+				p.Titles++
+			case "SUBTITLE":
+				p.Subtitles++
+			case "BODY":
+				p.Bodies++
+			case "PICTURE":
+				p.Images++
+			}
+		case e.Image != nil && e.Image.Placeholder != nil:
+			p.Images++
+		}
+	}
+	return p
+}
+
+// Validate cross-checks the slides against the layouts of the presentation.
+// Slides without a layout are checked against the default layouts.
+func (d *Deck) Validate(ctx context.Context, ss Slides) (_ []*ValidationIssue, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if err := d.refresh(ctx); err != nil {
+		return nil, fmt.Errorf("failed to refresh presentation: %w", err)
+	}
+	return d.validate(ss), nil
+}
+
+// validate cross-checks the slides against the layouts of the presentation without modifying the slides.
+func (d *Deck) validate(ss Slides) []*ValidationIssue {
+	resolved := make(Slides, len(ss))
+	for i, s := range ss {
+		if s == nil {
+			continue
+		}
+		c := *s
+		if c.Layout == "" {
+			if i == 0 {
+				c.Layout = d.defaultTitleLayout
+			} else {
+				c.Layout = d.defaultLayout
+			}
+		}
+		resolved[i] = &c
+	}
+	return resolved.Validate(d.LayoutPlaceholders())
+}
+
+// warnValidationIssues logs the issues of the pages to be applied.
+func (d *Deck) warnValidationIssues(ss Slides, pages []int) {
+	for _, issue := range d.validate(ss) {
+		if slices.Contains(pages, issue.Page) {
+			d.logger.Warn(fmt.Sprintf("page %d: %s", issue.Page, issue.Message), slog.Int("page", issue.Page))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSlidesValidate(t *testing.T) {
+	layouts := map[string]Placeholders{
+		"title":          {Titles: 1, Subtitles: 1},
+		"title-and-body": {Titles: 1, Bodies: 1},
+		"two-columns":    {Titles: 1, Bodies: 2, Images: 1},
+	}
+	body := &Body{Paragraphs: []*Paragraph{NewParagraph("text")}}
+	tests := []struct {
+		name string
+		ss   Slides
+		want []*ValidationIssue
+	}{
+		{
+			"fits",
+			Slides{
+				{Layout: "title", Titles: []string{"Deck"}, Subtitles: []string{"subtitle"}},
+				{Layout: "two-columns", Titles: []string{"A"}, Bodies: []*Body{body, body}, Images: []*Image{{}}},
+			},
+			nil,
+		},
+		{
+			"layout not found",
+			Slides{{Layout: "unknown"}},
+			[]*ValidationIssue{{Page: 1, Error: true, Message: `layout not found: "unknown"`}},
+		},
+		{
+			"too many bodies",
+			Slides{
+				{Layout: "title-and-body", Titles: []string{"A"}},
+				{Layout: "title-and-body", Titles: []string{"B"}, Bodies: []*Body{body, body, {}}},
+			},
+			[]*ValidationIssue{
+				{Page: 2, Message: `2 bodies but the layout "title-and-body" has 1 body placeholders, extra bodies are not rendered`},
+			},
+		},
+		{
+			"too many titles and images",
+			Slides{{Layout: "title", Titles: []string{"A", "B"}, Images: []*Image{{}}}},
+			[]*ValidationIssue{
+				{Page: 1, Message: `2 titles but the layout "title" has 1 title placeholders, extra titles are not rendered`},
+				{Page: 1, Message: `1 images but the layout "title" has 0 picture placeholders, extra images are placed at the top left of the page`},
+			},
+		},
+		{
+			"frozen and unresolved slides are not checked",
+			Slides{{Layout: "title", Freeze: true, Bodies: []*Body{body}}, {Bodies: []*Body{body, body}}},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.ss.Validate(layouts)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Validate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
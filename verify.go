@@ -0,0 +1,217 @@
+package deck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/deck/textmeasure"
+	"github.com/k1LoW/errors"
+)
+
+// Drift is a difference between a slide and the page of the presentation reported by Verify.
+type Drift struct {
+	Page  int    `json:"page"`  // page number in the presentation (1-based), 0 for the number of pages
+	Field string `json:"field"` // field of the slide such as "titles" and "bodies[0]"
+	Want  string `json:"want"`  // value of the slide
+	Got   string `json:"got"`   // value of the page of the presentation
+}
+
+func (d *Drift) String() string {
+	var b strings.Builder
+	if d.Page > 0 {
+		fmt.Fprintf(&b, "page %d: ", d.Page)
+	}
+	b.WriteString(d.Field)
+	b.WriteString("\n")
+	writeLines(&b, "  - ", d.Want)
+	writeLines(&b, "  + ", d.Got)
+	return b.String()
+}
+
+func writeLines(b *strings.Builder, prefix, s string) {
+	for line := range strings.SplitSeq(strings.TrimSuffix(s, "\n"), "\n") {
+		b.WriteString(prefix)
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+}
+
+// Verify reads the pages of the presentation and reports the differences from ss, e.g. to check after
+// applying that the presentation has not drifted from the markdown.
+// Frozen slides are not verified, since their pages are left as they are.
+func (d *Deck) Verify(ctx context.Context, ss Slides) (_ []*Drift, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	applied, err := d.DumpSlides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return DiffSlides(ss, applied), nil
+}
+
+// DiffSlides returns the field-level differences between want and got, compared in the same way as
+// Slides.Equal. Styles that cannot be read from the presentation, such as style names, text colors
+// and highlights, are not compared.
+func DiffSlides(want, got Slides) []*Drift {
+	var drifts []*Drift
+	if len(want) != len(got) {
+		drifts = append(drifts, &Drift{
+			Field: "pages",
+			Want:  strconv.Itoa(len(want)),
+			Got:   strconv.Itoa(len(got)),
+		})
+	}
+	for i := range min(len(want), len(got)) {
+		w, g := want[i], got[i]
+		if w == nil || g == nil || w.Freeze {
+			continue
+		}
+		w, g = verifiable(w), verifiable(g)
+		if w.Equal(g) {
+			continue
+		}
+		add := func(field string, want, got any) {
+			drifts = append(drifts, &Drift{Page: i + 1, Field: field, Want: describe(want), Got: describe(got)})
+		}
+		// The layout of a slide without a layout is the default layout of the presentation.
+		if w.Layout != "" && w.Layout != g.Layout {
+			add("layout", w.Layout, g.Layout)
+		}
+		if !textsEqual(w.Titles, g.Titles) {
+			add("titles", strings.Join(w.Titles, "\n"), strings.Join(g.Titles, "\n"))
+		}
+		if !textsEqual(w.Subtitles, g.Subtitles) {
+			add("subtitles", strings.Join(w.Subtitles, "\n"), strings.Join(g.Subtitles, "\n"))
+		}
+		for j := range max(len(w.Bodies), len(g.Bodies)) {
+			wb, gb := at(w.Bodies, j), at(g.Bodies, j)
+			if wb == nil || gb == nil || !bodiesEqual([]*Body{wb}, []*Body{gb}) {
+				if wb != nil && gb != nil && wb.String() == gb.String() {
+					// Only the styles differ.
+					add(fmt.Sprintf("bodies[%d]", j), wb.Paragraphs, gb.Paragraphs)
+				} else {
+					add(fmt.Sprintf("bodies[%d]", j), wb, gb)
+				}
+			}
+		}
+		if !imagesEquivalent(w.Images, g.Images) {
+			add("images", describeImages(w.Images), describeImages(g.Images))
+		}
+		if !blockQuotesEqual(w.BlockQuotes, g.BlockQuotes) {
+			add("block_quotes", w.BlockQuotes, g.BlockQuotes)
+		}
+		for j := range max(len(w.Tables), len(g.Tables)) {
+			wt, gt := at(w.Tables, j), at(g.Tables, j)
+			if wt == nil || gt == nil || !tablesEqual([]*Table{wt}, []*Table{gt}) {
+				add(fmt.Sprintf("tables[%d]", j), wt, gt)
+			}
+		}
+		if !elementsEqual(w.Elements, g.Elements) {
+			add("elements", w.Elements, g.Elements)
+		}
+		if !textmeasure.Equal(w.SpeakerNote, g.SpeakerNote) {
+			add("speaker_note", w.SpeakerNote, g.SpeakerNote)
+		}
+		if w.Key != g.Key {
+			add("key", w.Key, g.Key)
+		}
+	}
+	return drifts
+}
+
+// verifiable returns a copy of the slide without the styles that cannot be read from the presentation.
+func verifiable(s *Slide) *Slide {
+	c := *s
+	c.Bodies = nil
+	for _, b := range s.Bodies {
+		c.Bodies = append(c.Bodies, &Body{Paragraphs: verifiableParagraphs(b.Paragraphs)})
+	}
+	c.BlockQuotes = nil
+	for _, bq := range s.BlockQuotes {
+		c.BlockQuotes = append(c.BlockQuotes, &BlockQuote{Paragraphs: verifiableParagraphs(bq.Paragraphs), Nesting: bq.Nesting})
+	}
+	c.Tables = nil
+	for _, t := range s.Tables {
+		vt := &Table{}
+		for _, row := range t.Rows {
+			vr := &TableRow{}
+			for _, cell := range row.Cells {
+				if cell != nil {
+					vc := *cell
+					vc.Fragments = verifiableFragments(cell.Fragments)
+					cell = &vc
+				}
+				vr.Cells = append(vr.Cells, cell)
+			}
+			vt.Rows = append(vt.Rows, vr)
+		}
+		c.Tables = append(c.Tables, vt)
+	}
+	return &c
+}
+
+func verifiableParagraphs(paragraphs []*Paragraph) []*Paragraph {
+	var vps []*Paragraph
+	for _, p := range paragraphs {
+		vp := *p
+		vp.StyleName = ""
+		vp.Fragments = verifiableFragments(p.Fragments)
+		vps = append(vps, &vp)
+	}
+	return vps
+}
+
+func verifiableFragments(fragments []*Fragment) []*Fragment {
+	var vfs []*Fragment
+	for _, f := range fragments {
+		vf := *f
+		vf.StyleName = ""
+		vf.Color = ""
+		// Highlighted text is read as code, since code is detected by its background color.
+		vf.Code = f.Code || f.Highlight
+		vf.Highlight = false
+		vfs = append(vfs, &vf)
+	}
+	return vfs
+}
+
+func at[T any](s []*T, i int) *T {
+	if i < len(s) {
+		return s[i]
+	}
+	return nil
+}
+
+// describe returns the readable representation of the value of a field: the text of bodies, or JSON of the other values.
+func describe(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case *Body:
+		if v == nil {
+			return "(none)"
+		}
+		return v.String()
+	}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return "(none)"
+	}
+	return string(b)
+}
+
+func describeImages(images []*Image) string {
+	var descs []string
+	for _, i := range images {
+		desc := fmt.Sprintf("%s (checksum: %08x)", i.mimeType, i.Checksum())
+		if i.alt != "" {
+			desc = fmt.Sprintf("%s (alt: %s)", desc, i.alt)
+		}
+		descs = append(descs, desc)
+	}
+	return strings.Join(descs, "\n")
+}
@@ -0,0 +1,97 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck/fakeslides"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := t.Context()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := Slides{
+		{Layout: "title", Titles: []string{"Deck"}, Subtitles: []string{"subtitle"}},
+		{Layout: "title-and-body", Titles: []string{"Agenda"}, Bodies: []*Body{NewBody(
+			NewBulletParagraph(BulletDash, 0, "one"),
+			&Paragraph{Fragments: []*Fragment{{Value: "two", Bold: true}}, Bullet: BulletDash},
+		)}, SpeakerNote: "note"},
+	}
+	if err := d.Apply(ctx, applied); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		in   Slides
+		want []*Drift
+	}{
+		{"same", applied, nil},
+		{
+			"text changed",
+			Slides{
+				{Layout: "title", Titles: []string{"Deck"}, Subtitles: []string{"subtitle"}},
+				{Layout: "title-and-body", Titles: []string{"Outline"}, Bodies: []*Body{NewBody(
+					NewBulletParagraph(BulletDash, 0, "one"),
+					NewBulletParagraph(BulletDash, 0, "three"),
+				)}, SpeakerNote: "note"},
+			},
+			[]*Drift{
+				{Page: 2, Field: "titles", Want: "Outline", Got: "Agenda"},
+				{Page: 2, Field: "bodies[0]", Want: "- one\n- three\n", Got: "- one\n- two\n"},
+			},
+		},
+		{
+			"style changed",
+			Slides{
+				applied[0],
+				{Layout: "title-and-body", Titles: []string{"Agenda"}, Bodies: []*Body{NewBody(
+					NewBulletParagraph(BulletDash, 0, "one"),
+					NewBulletParagraph(BulletDash, 0, "two"),
+				)}, SpeakerNote: "note"},
+			},
+			[]*Drift{
+				{
+					Page:  2,
+					Field: "bodies[0]",
+					Want:  `[{"fragments":[{"value":"one"}],"bullet":"-"},{"fragments":[{"value":"two"}],"bullet":"-"}]`,
+					Got:   `[{"fragments":[{"value":"one"}],"bullet":"-"},{"fragments":[{"value":"two","bold":true}],"bullet":"-"}]`,
+				},
+			},
+		},
+		{
+			"page missing",
+			Slides{applied[0], applied[1], {Layout: "title", Titles: []string{"End"}}},
+			[]*Drift{{Field: "pages", Want: "3", Got: "2"}},
+		},
+		{
+			"frozen page",
+			Slides{applied[0], {Layout: "title-and-body", Titles: []string{"Outline"}, Freeze: true}},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.Verify(ctx, tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDriftString(t *testing.T) {
+	d := &Drift{Page: 2, Field: "bodies[0]", Want: "- one\n- three\n", Got: "- one\n"}
+	want := "page 2: bodies[0]\n  - - one\n  - - three\n  + - one\n"
+	if got := d.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
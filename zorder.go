@@ -0,0 +1,54 @@
+package deck
+
+import (
+	"strings"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// Images are stacked in front of the other elements of the page, as they are inserted after the placeholders.
+// Images with ZOrderBack are sent behind the placeholders, so that decorative images can sit behind the texts.
+// Whether an image is sent back is stored in the description of its alt text, so that it can be read back
+// without guessing from the order of the elements.
+
+const (
+	// ZOrderBack is the z-order of images sent behind the other elements of the page.
+	ZOrderBack = "back"
+
+	descriptionBackImageFromMarkdown = "Background image generated from markdown"
+)
+
+// SetZOrder sets the z-order of the image. Only ZOrderBack is supported; empty keeps the image in front.
+// The z-order is applied only to images managed by deck, such as the images created by NewImageFromMarkdown.
+func (i *Image) SetZOrder(zOrder string) {
+	i.zOrder = zOrder
+}
+
+// ZOrder returns the z-order of the image, or empty if the image is kept in front.
+func (i *Image) ZOrder() string {
+	return i.zOrder
+}
+
+// zOrderRequest returns the request to move the page elements to the z-order.
+// It returns nil if there are no page elements to move.
+func zOrderRequest(objectIDs []string, zOrder string) *slides.Request {
+	if len(objectIDs) == 0 {
+		return nil
+	}
+	operation := "BRING_TO_FRONT"
+	if zOrder == ZOrderBack {
+		operation = "SEND_TO_BACK"
+	}
+	return &slides.Request{
+		UpdatePageElementsZOrder: &slides.UpdatePageElementsZOrderRequest{
+			PageElementObjectIds: objectIDs,
+			Operation:            operation,
+		},
+	}
+}
+
+// isBackImageFromMarkdown reports whether description is the description of the alt text of an image
+// managed by deck and sent behind the other elements.
+func isBackImageFromMarkdown(description string) bool {
+	return description == descriptionBackImageFromMarkdown || strings.HasSuffix(description, "\n\n"+descriptionBackImageFromMarkdown)
+}
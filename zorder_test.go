@@ -0,0 +1,62 @@
+package deck
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/deck/fakeslides"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestApplyImageZOrder(t *testing.T) {
+	ctx := context.Background()
+	srv := fakeslides.NewServer()
+	t.Cleanup(srv.Close)
+	d, err := New(ctx, WithEndpoint(srv.URL), WithPresentationID(srv.CreatePresentation("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slideWithZOrder := func(zOrder string) Slides {
+		img, err := NewImageFromMarkdown("testdata/test.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		img.SetZOrder(zOrder)
+		return Slides{{Layout: "title-and-body", Titles: []string{"Z-order"}, Bodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "text"}}}}}}, Images: []*Image{img}}}
+	}
+
+	for _, zOrder := range []string{ZOrderBack, "", ZOrderBack} {
+		if err := d.Apply(ctx, slideWithZOrder(zOrder)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := d.DumpSlides(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || len(got[0].Images) != 1 {
+			t.Fatalf("got %d slides, want 1 slide with 1 image", len(got))
+		}
+		if got := got[0].Images[0].ZOrder(); got != zOrder {
+			t.Errorf("got z-order %q, want %q", got, zOrder)
+		}
+
+		elements := srv.Presentation(d.ID()).Slides[0].PageElements
+		image := slices.IndexFunc(elements, func(e *slides.PageElement) bool { return e.Image != nil })
+		placeholder := slices.IndexFunc(elements, func(e *slides.PageElement) bool { return e.Shape != nil && e.Shape.Placeholder != nil })
+		if image < 0 || placeholder < 0 {
+			t.Fatalf("got elements %v, want an image and placeholders", elements)
+		}
+		if back := image < placeholder; back != (zOrder == ZOrderBack) {
+			t.Errorf("got the image behind the placeholders %v, want %v", back, zOrder == ZOrderBack)
+		}
+	}
+
+	// Applying the same slides again changes nothing.
+	if err := d.Apply(ctx, slideWithZOrder(ZOrderBack)); err != nil {
+		t.Fatal(err)
+	}
+	if s := d.Summary(); s.Updated != 0 {
+		t.Errorf("got %+v, want no updates", s)
+	}
+}